@@ -4,6 +4,7 @@ go 1.24.0
 
 require (
 	golang.org/x/crypto v0.36.0
+	gorm.io/driver/sqlite v1.6.0
 	gorm.io/gorm v1.31.0
 )
 
@@ -15,8 +16,10 @@ require (
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
 	github.com/jackc/pgx/v5 v5.7.2 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
+	github.com/mattn/go-sqlite3 v1.14.22 // indirect
 	golang.org/x/sync v0.12.0 // indirect
-	gorm.io/driver/mysql v1.5.6 // indirect
+	golang.org/x/sys v0.31.0 // indirect
+	gorm.io/driver/mysql v1.5.7 // indirect
 )
 
 require (
@@ -26,4 +29,5 @@ require (
 	golang.org/x/text v0.23.0 // indirect
 	gorm.io/datatypes v1.2.6
 	gorm.io/driver/postgres v1.6.0
+	gorm.io/plugin/dbresolver v1.6.2
 )
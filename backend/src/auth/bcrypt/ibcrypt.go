@@ -1,6 +0,0 @@
-package bcrypt
-
-type Bcrypt interface {
-	HashPassword(password string) (string, error)
-	CompareHashAndPassword(hash, password string) error
-}
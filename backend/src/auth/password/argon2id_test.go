@@ -0,0 +1,188 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testArgon2idParams son parámetros baratos para que las pruebas no tarden.
+var testArgon2idParams = Argon2idParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestArgon2idRoundTrip(t *testing.T) {
+	a := NewArgon2id(testArgon2idParams)
+
+	hash, err := a.Hash("correcto caballo batería")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") || !a.Identifies(hash) {
+		t.Fatalf("hash %q is not PHC argon2id", hash)
+	}
+
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		t.Fatalf("decodeArgon2id: %v", err)
+	}
+	if params != testArgon2idParams || len(salt) != 16 || len(key) != 32 {
+		t.Fatalf("decoded params %+v, salt %d bytes, key %d bytes", params, len(salt), len(key))
+	}
+
+	if err := a.Compare(hash, "correcto caballo batería"); err != nil {
+		t.Fatalf("Compare with the right password: %v", err)
+	}
+	if err := a.Compare(hash, "correcto caballo bateria"); !errors.Is(err, ErrMismatch) {
+		t.Fatalf("Compare with a wrong password: err = %v, want ErrMismatch", err)
+	}
+
+	// La sal es aleatoria: la misma contraseña no repite hash
+	if again, _ := a.Hash("correcto caballo batería"); again == hash {
+		t.Fatal("two hashes of the same password are equal")
+	}
+}
+
+// Un hash con otros parámetros se verifica con los suyos, no con los configurados.
+func TestArgon2idCompareUsesHashParams(t *testing.T) {
+	old := NewArgon2id(testArgon2idParams)
+	hash, err := old.Hash("secreto-largo")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	current := testArgon2idParams
+	current.Iterations = 2
+	current.KeyLength = 16
+	if err := NewArgon2id(current).Compare(hash, "secreto-largo"); err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+}
+
+func TestArgon2idRejectsMalformedHash(t *testing.T) {
+	a := NewArgon2id(testArgon2idParams)
+	valid, err := a.Hash("secreto-largo")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	parts := strings.Split(valid, "$")
+	with := func(i int, value string) string {
+		p := append([]string(nil), parts...)
+		p[i] = value
+		return strings.Join(p, "$")
+	}
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"empty", ""},
+		{"bcrypt", "$2a$10$abcdefghijklmnopqrstuuABCDEFGHIJKLMNOPQRSTUVWXYZ01234"},
+		{"argon2i", with(1, "argon2i")},
+		{"missing key", strings.Join(parts[:5], "$")},
+		{"extra segment", valid + "$extra"},
+		{"other version", with(2, "v=16")},
+		{"bad version", with(2, "version")},
+		{"bad params", with(3, "m=1024,t=1")},
+		{"zero memory", with(3, "m=0,t=1,p=1")},
+		{"zero iterations", with(3, "m=1024,t=0,p=1")},
+		{"salt not base64", with(4, "!!!")},
+		{"key not base64", with(5, "!!!")},
+		{"empty key", with(5, "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := decodeArgon2id(tt.hash); !errors.Is(err, ErrMalformedHash) {
+				t.Fatalf("decodeArgon2id: err = %v, want ErrMalformedHash", err)
+			}
+			if err := a.Compare(tt.hash, "secreto-largo"); !errors.Is(err, ErrMalformedHash) {
+				t.Fatalf("Compare: err = %v, want ErrMalformedHash", err)
+			}
+			// Un hash ilegible se regenera en el siguiente login
+			if !a.Outdated(tt.hash) {
+				t.Fatal("Outdated = false for a malformed hash")
+			}
+		})
+	}
+}
+
+func TestArgon2idOutdated(t *testing.T) {
+	hash, err := NewArgon2id(testArgon2idParams).Hash("secreto-largo")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Argon2idParams)
+		want   bool
+	}{
+		{"same params", func(*Argon2idParams) {}, false},
+		{"memory", func(p *Argon2idParams) { p.Memory = 2048 }, true},
+		{"iterations", func(p *Argon2idParams) { p.Iterations = 2 }, true},
+		{"parallelism", func(p *Argon2idParams) { p.Parallelism = 2 }, true},
+		{"salt length", func(p *Argon2idParams) { p.SaltLength = 32 }, true},
+		{"key length", func(p *Argon2idParams) { p.KeyLength = 64 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testArgon2idParams
+			tt.change(&params)
+			if got := NewArgon2id(params).Outdated(hash); got != tt.want {
+				t.Fatalf("Outdated = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasherNeedsRehash(t *testing.T) {
+	argon := NewArgon2id(testArgon2idParams)
+	legacy := NewBcrypt(4)
+	h := NewHasher(argon, legacy)
+
+	current, err := h.HashPassword("secreto-largo")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	bcryptHash, err := legacy.Hash("secreto-largo")
+	if err != nil {
+		t.Fatalf("bcrypt Hash: %v", err)
+	}
+	weaker := testArgon2idParams
+	weaker.Memory = 512
+	oldArgon, err := NewArgon2id(weaker).Hash("secreto-largo")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		hash string
+		want bool
+	}{
+		{"current argon2id", current, false},
+		{"argon2id with old params", oldArgon, true},
+		{"legacy bcrypt", bcryptHash, true},
+		// Un hash que ningún algoritmo reconoce no se puede verificar, y por lo tanto tampoco regenerar
+		{"unknown algorithm", "$scrypt$abc", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.NeedsRehash(tt.hash); got != tt.want {
+				t.Fatalf("NeedsRehash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Los hashes heredados siguen sirviendo para entrar
+	if err := h.CompareHashAndPassword(bcryptHash, "secreto-largo"); err != nil {
+		t.Fatalf("CompareHashAndPassword(bcrypt): %v", err)
+	}
+	if err := h.CompareHashAndPassword("$scrypt$abc", "secreto-largo"); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Fatalf("CompareHashAndPassword(unknown): err = %v, want ErrUnknownAlgorithm", err)
+	}
+}
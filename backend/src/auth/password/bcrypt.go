@@ -0,0 +1,54 @@
+package password
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type bcryptAlgorithm struct {
+	cost int
+}
+
+// NewBcrypt crea el algoritmo bcrypt con el costo indicado (0 = bcrypt.DefaultCost).
+func NewBcrypt(cost int) Algorithm {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &bcryptAlgorithm{cost: cost}
+}
+
+// Name implements Algorithm.
+func (b *bcryptAlgorithm) Name() string {
+	return "bcrypt"
+}
+
+// Identifies implements Algorithm.
+func (b *bcryptAlgorithm) Identifies(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// Hash implements Algorithm.
+func (b *bcryptAlgorithm) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Compare implements Algorithm.
+func (b *bcryptAlgorithm) Compare(hash string, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatch
+	}
+	return err
+}
+
+// Outdated implements Algorithm.
+func (b *bcryptAlgorithm) Outdated(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != b.cost
+}
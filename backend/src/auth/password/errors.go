@@ -0,0 +1,22 @@
+package password
+
+import (
+	"errors"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("password: algoritmo de hash no reconocido")
+	ErrMalformedHash    = errors.New("password: hash con formato inválido")
+
+	// ErrMismatch es el error del cliente al confirmar una acción con una contraseña
+	// incorrecta (el login lo reemplaza por auth.invalid_credentials).
+	ErrMismatch = apperror.New(apperror.Unauthorized, "auth.invalid_password", "password error: la contraseña no es correcta")
+
+	ErrTooShort      = apperror.New(apperror.Validation, "password.too_short", "password error: la contraseña es demasiado corta")
+	ErrTooLong       = apperror.New(apperror.Validation, "password.too_long", "password error: la contraseña es demasiado larga")
+	ErrCommon        = apperror.New(apperror.Validation, "password.common", "password error: la contraseña es demasiado común")
+	ErrContainsUser  = apperror.New(apperror.Validation, "password.contains_user", "password error: la contraseña no puede contener tu nombre de usuario")
+	ErrContainsEmail = apperror.New(apperror.Validation, "password.contains_email", "password error: la contraseña no puede contener tu email")
+)
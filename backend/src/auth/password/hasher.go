@@ -0,0 +1,52 @@
+package password
+
+type hasher struct {
+	preferred Algorithm
+	known     []Algorithm
+}
+
+// NewHasher crea un Hasher que genera hashes con preferred y además acepta los de
+// legacy, para migrar de algoritmo sin invalidar las contraseñas existentes.
+func NewHasher(preferred Algorithm, legacy ...Algorithm) Hasher {
+	return &hasher{
+		preferred: preferred,
+		known:     append([]Algorithm{preferred}, legacy...),
+	}
+}
+
+// NewDefaultHasher crea el Hasher por defecto: argon2id con los parámetros
+// recomendados y bcrypt para los hashes anteriores.
+func NewDefaultHasher() Hasher {
+	return NewHasher(NewArgon2id(DefaultArgon2idParams()), NewBcrypt(0))
+}
+
+// HashPassword implements Hasher.
+func (h *hasher) HashPassword(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+// CompareHashAndPassword implements Hasher.
+func (h *hasher) CompareHashAndPassword(hash string, password string) error {
+	algorithm := h.algorithmFor(hash)
+	if algorithm == nil {
+		return ErrUnknownAlgorithm
+	}
+	return algorithm.Compare(hash, password)
+}
+
+// NeedsRehash implements Hasher.
+func (h *hasher) NeedsRehash(hash string) bool {
+	if !h.preferred.Identifies(hash) {
+		return h.algorithmFor(hash) != nil
+	}
+	return h.preferred.Outdated(hash)
+}
+
+func (h *hasher) algorithmFor(hash string) Algorithm {
+	for _, algorithm := range h.known {
+		if algorithm.Identifies(hash) {
+			return algorithm
+		}
+	}
+	return nil
+}
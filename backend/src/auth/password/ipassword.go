@@ -0,0 +1,23 @@
+package password
+
+// Hasher hashea y compara contraseñas. Reconoce el algoritmo de cada hash por su
+// prefijo, así conviven hashes de distintos algoritmos en la misma tabla.
+type Hasher interface {
+	// HashPassword hashea con el algoritmo preferido y sus parámetros actuales.
+	HashPassword(password string) (string, error)
+	CompareHashAndPassword(hash, password string) error
+	// NeedsRehash indica si el hash usa otro algoritmo o parámetros desactualizados.
+	NeedsRehash(hash string) bool
+}
+
+// Algorithm es un algoritmo de hashing registrable en un Hasher.
+type Algorithm interface {
+	// Name identifica el algoritmo en logs (p. ej. "bcrypt", "argon2id").
+	Name() string
+	// Identifies indica si el hash fue generado por este algoritmo.
+	Identifies(hash string) bool
+	Hash(password string) (string, error)
+	Compare(hash, password string) error
+	// Outdated indica si el hash usa parámetros distintos a los configurados.
+	Outdated(hash string) bool
+}
@@ -0,0 +1,99 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var (
+	commonOnce      sync.Once
+	commonPasswords map[string]struct{}
+)
+
+// minIdentityLength es el largo mínimo del nombre o del email para prohibirlo
+// dentro de la contraseña; con menos caracteres rechazaría contraseñas válidas.
+const minIdentityLength = 3
+
+// Policy define los requisitos de una contraseña nueva. No se aplica a las
+// contraseñas existentes: el login sigue aceptándolas.
+type Policy struct {
+	MinLength int // Caracteres, no bytes
+	MaxLength int // 0 = sin límite
+	DenyList  []string
+	// SkipCommonList desactiva la lista embebida de contraseñas comunes.
+	SkipCommonList bool
+}
+
+// DefaultPolicy devuelve un mínimo de 8 caracteres, un máximo de 128 y la lista
+// embebida de contraseñas comunes.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength: 8,
+		MaxLength: 128,
+	}
+}
+
+// Validate revisa password contra la política. userName y email son los de la
+// cuenta; pueden ir vacíos si no se conocen.
+func (p Policy) Validate(password, userName, email string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return fmt.Errorf("%w (mínimo %d caracteres)", ErrTooShort, p.MinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return fmt.Errorf("%w (máximo %d caracteres)", ErrTooLong, p.MaxLength)
+	}
+
+	lowered := strings.ToLower(password)
+	if p.isCommon(lowered) {
+		return ErrCommon
+	}
+
+	if name := strings.ToLower(strings.TrimSpace(userName)); len(name) >= minIdentityLength && strings.Contains(lowered, name) {
+		return ErrContainsUser
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email != "" && strings.Contains(lowered, email) {
+		return ErrContainsEmail
+	}
+	if local, _, ok := strings.Cut(email, "@"); ok && len(local) >= minIdentityLength && strings.Contains(lowered, local) {
+		return ErrContainsEmail
+	}
+
+	return nil
+}
+
+func (p Policy) isCommon(lowered string) bool {
+	for _, denied := range p.DenyList {
+		if strings.ToLower(denied) == lowered {
+			return true
+		}
+	}
+	if p.SkipCommonList {
+		return false
+	}
+
+	commonOnce.Do(loadCommonPasswords)
+	_, ok := commonPasswords[lowered]
+	return ok
+}
+
+func loadCommonPasswords() {
+	commonPasswords = make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(commonPasswordsFile))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		commonPasswords[strings.ToLower(line)] = struct{}{}
+	}
+}
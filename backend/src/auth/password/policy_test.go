@@ -0,0 +1,53 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		password string
+		userName string
+		email    string
+		want     error
+	}{
+		{"valid", DefaultPolicy(), "tortuga-azul-42", "ana", "ana@example.com", nil},
+		{"too short", DefaultPolicy(), "corta7", "", "", ErrTooShort},
+		{"length counts runes", DefaultPolicy(), "ñandúña", "", "", ErrTooShort},
+		{"too long", DefaultPolicy(), strings.Repeat("x", 129), "", "", ErrTooLong},
+		{"no max", Policy{MinLength: 8}, strings.Repeat("xy", 200), "", "", nil},
+		{"common", DefaultPolicy(), "password1", "", "", ErrCommon},
+		{"common ignores case", DefaultPolicy(), "PassWord1", "", "", ErrCommon},
+		{"common list skipped", Policy{MinLength: 8, SkipCommonList: true}, "password1", "", "", nil},
+		{"deny list", Policy{MinLength: 8, DenyList: []string{"AIEP-2024"}, SkipCommonList: true}, "aiep-2024", "", "", ErrCommon},
+		{"deny list is exact", Policy{MinLength: 8, DenyList: []string{"aiep-2024"}, SkipCommonList: true}, "aiep-2024!", "", "", nil},
+		{"contains user name", DefaultPolicy(), "xxJuanPerezxx", "juanperez", "", ErrContainsUser},
+		{"short user name allowed", DefaultPolicy(), "tortuga-al-sol", "al", "", nil},
+		{"contains email", DefaultPolicy(), "mi-ana.perez@example.com!", "", "Ana.Perez@Example.com", ErrContainsEmail},
+		{"contains email local part", DefaultPolicy(), "soy-ana.perez-2024", "", "ana.perez@example.com", ErrContainsEmail},
+		{"short local part allowed", DefaultPolicy(), "tortuga-al-sol", "", "al@example.com", nil},
+		{"identity unknown", DefaultPolicy(), "tortuga-azul-42", "", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password, tt.userName, tt.email)
+			if tt.want == nil && err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("Validate: err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyLengthMessage(t *testing.T) {
+	err := Policy{MinLength: 12}.Validate("corta", "", "")
+	if !errors.Is(err, ErrTooShort) || !strings.Contains(err.Error(), "mínimo 12") {
+		t.Fatalf("err = %v, want ErrTooShort mentioning the minimum", err)
+	}
+}
@@ -0,0 +1,139 @@
+// Package config carga la configuración de la aplicación desde valores por defecto,
+// un archivo (TOML o YAML), variables de entorno y flags, en ese orden de precedencia.
+package config
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Config agrupa toda la configuración. Cada campo tiene una clave "seccion.campo"
+// que es la misma en el archivo, en el entorno (SECCION_CAMPO) y en los flags
+// (-seccion.campo).
+type Config struct {
+	DB         DBConfig
+	LLM        LLMConfig
+	Embeddings EmbeddingsConfig
+	Auth       AuthConfig
+	Server     ServerConfig
+}
+
+// DBConfig es la conexión a PostgreSQL.
+type DBConfig struct {
+	Host        string `config:"db.host"`
+	Port        int    `config:"db.port"`
+	User        string `config:"db.user"`
+	Password    Secret `config:"db.password"`
+	Name        string `config:"db.name"`
+	SSLMode     string `config:"db.sslmode"`     // disable | allow | prefer | require | verify-ca | verify-full
+	SSLRootCert string `config:"db.sslrootcert"` // CA para verify-ca y verify-full
+	LogLevel    string `config:"db.log_level"`   // silent | error | warn | info
+
+	// Pool de conexiones; las réplicas usan los mismos límites
+	MaxOpenConns       int           `config:"db.max_open_conns"`
+	MaxIdleConns       int           `config:"db.max_idle_conns"`
+	ConnMaxLifetime    time.Duration `config:"db.conn_max_lifetime"`
+	ConnMaxIdleTime    time.Duration `config:"db.conn_max_idle_time"`
+	StatementTimeout   time.Duration `config:"db.statement_timeout"`    // 0 = sin límite
+	SlowQueryThreshold time.Duration `config:"db.slow_query_threshold"` // Consultas más lentas se registran como warn
+
+	// Réplicas de lectura ("host" o "host:puerto", mismas credenciales). Solo reciben
+	// las búsquedas semánticas; vacío = todo va al primario
+	Replicas []string `config:"db.replicas"`
+}
+
+// LLMConfig es el proveedor del modelo de chat.
+type LLMConfig struct {
+	BaseURL             string        `config:"llm.base_url"`
+	APIKey              Secret        `config:"llm.api_key"`
+	Model               string        `config:"llm.model"`
+	MaxCompletionTokens int           `config:"llm.max_completion_tokens"`
+	Temperature         float32       `config:"llm.temperature"`
+	Timeout             time.Duration `config:"llm.timeout"`
+}
+
+// EmbeddingsConfig es el proveedor de embeddings.
+type EmbeddingsConfig struct {
+	BaseURL    string `config:"embeddings.base_url"`
+	APIKey     Secret `config:"embeddings.api_key"` // Vacío = se usa llm.api_key
+	Model      string `config:"embeddings.model"`
+	Dimensions int    `config:"embeddings.dimensions"` // Debe coincidir con la columna vector(1536)
+	Enabled    bool   `config:"embeddings.enabled"`
+}
+
+// AuthConfig agrupa las claves y políticas de las cuentas.
+type AuthConfig struct {
+	VerificationSecret   Secret        `config:"auth.verification_secret"`
+	TwoFactorKey         Secret        `config:"auth.two_factor_key"`
+	RequireVerifiedEmail bool          `config:"auth.require_verified_email"`
+	SessionTTL           time.Duration `config:"auth.session_ttl"`
+
+	// OpenID Connect; sin issuer el inicio de sesión institucional queda deshabilitado
+	OIDCIssuer       string   `config:"auth.oidc_issuer"`
+	OIDCClientID     string   `config:"auth.oidc_client_id"`
+	OIDCClientSecret Secret   `config:"auth.oidc_client_secret"`
+	OIDCRedirectURL  string   `config:"auth.oidc_redirect_url"`
+	OIDCDomains      []string `config:"auth.oidc_domains"`
+	SSOKey           Secret   `config:"auth.sso_key"`
+}
+
+// ServerConfig es el servidor HTTP.
+type ServerConfig struct {
+	Host            string        `config:"server.host"`
+	Port            int           `config:"server.port"`
+	PublicURL       string        `config:"server.public_url"` // Base de los enlaces de los correos
+	AllowedOrigins  []string      `config:"server.allowed_origins"`
+	ReadTimeout     time.Duration `config:"server.read_timeout"`
+	WriteTimeout    time.Duration `config:"server.write_timeout"`
+	ShutdownTimeout time.Duration `config:"server.shutdown_timeout"`
+}
+
+// Default devuelve la configuración por defecto, pensada para desarrollo local.
+func Default() Config {
+	return Config{
+		DB: DBConfig{
+			Host:               "localhost",
+			Port:               5432,
+			SSLMode:            "disable",
+			LogLevel:           "warn",
+			MaxOpenConns:       25,
+			MaxIdleConns:       10,
+			ConnMaxLifetime:    30 * time.Minute,
+			ConnMaxIdleTime:    5 * time.Minute,
+			StatementTimeout:   30 * time.Second,
+			SlowQueryThreshold: 500 * time.Millisecond,
+		},
+		LLM: LLMConfig{
+			BaseURL:             "https://api.openai.com/v1",
+			Model:               "gpt-4o-mini",
+			MaxCompletionTokens: 1024,
+			Temperature:         0.3,
+			Timeout:             60 * time.Second,
+		},
+		Embeddings: EmbeddingsConfig{
+			BaseURL:    "https://api.openai.com/v1",
+			Model:      "text-embedding-3-small",
+			Dimensions: 1536,
+			Enabled:    true,
+		},
+		Auth: AuthConfig{
+			SessionTTL: 30 * 24 * time.Hour,
+		},
+		Server: ServerConfig{
+			Host:            "0.0.0.0",
+			Port:            8080,
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    60 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// LogValue implements slog.LogValuer: los secretos salen como [REDACTED].
+func (c Config) LogValue() slog.Value {
+	var attrs []slog.Attr
+	for _, f := range fields(&c) {
+		attrs = append(attrs, slog.Any(f.key, f.value.Interface()))
+	}
+	return slog.GroupValue(attrs...)
+}
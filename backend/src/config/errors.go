@@ -0,0 +1,33 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownKey       = errors.New("config: clave desconocida")
+	ErrInvalidValue     = errors.New("config: valor inválido")
+	ErrUnsupportedFile  = errors.New("config: formato de archivo no soportado (usa .toml, .yaml o .yml)")
+	ErrMalformedFile    = errors.New("config: archivo mal formado")
+	ErrValidationFailed = errors.New("config: la configuración no es válida")
+)
+
+// FieldError describe un problema con una clave concreta. Source indica de dónde
+// vino el valor (default, file, env o flag).
+type FieldError struct {
+	Key    string
+	Source string
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("%s: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s (%s): %v", e.Key, e.Source, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
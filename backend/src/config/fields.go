@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field es un campo configurable de Config.
+type field struct {
+	key   string
+	value reflect.Value
+}
+
+// fields recorre Config y devuelve sus campos con etiqueta `config`, en orden.
+func fields(c *Config) []field {
+	var result []field
+	sections := reflect.ValueOf(c).Elem()
+	for i := range sections.NumField() {
+		section := sections.Field(i)
+		for j := range section.NumField() {
+			key := section.Type().Field(j).Tag.Get("config")
+			if key == "" {
+				continue
+			}
+			result = append(result, field{key: key, value: section.Field(j)})
+		}
+	}
+	return result
+}
+
+// envName traduce "db.sslmode" a "DB_SSLMODE".
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// set asigna raw al campo según su tipo. Las listas van separadas por comas.
+func (f field) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+	v := f.value
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%w: %q no es una duración (p. ej. 30s, 5m, 24h)", ErrInvalidValue, raw)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%w: %q no es un número entero", ErrInvalidValue, raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %q no es un número", ErrInvalidValue, raw)
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%w: %q no es true ni false", ErrInvalidValue, raw)
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for item := range strings.SplitSeq(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("%w: tipo %s no soportado", ErrInvalidValue, v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readFile lee un archivo de configuración y devuelve sus valores por clave
+// ("seccion.campo"). Se admite un subconjunto de TOML y de YAML: secciones de un
+// nivel con valores escalares o listas de textos.
+func readFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: no se pudo leer %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return parseTOML(string(content))
+	case ".yaml", ".yml":
+		return parseYAML(string(content))
+	default:
+		return nil, ErrUnsupportedFile
+	}
+}
+
+// parseTOML admite:
+//
+//	[db]
+//	host = "localhost"
+//	port = 5432
+//	allowed_origins = ["https://a", "https://b"]
+func parseTOML(content string) (map[string]string, error) {
+	values := make(map[string]string)
+	section := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(stripComment(scanner.Text(), false))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%w: línea %d: sección sin cerrar", ErrMalformedFile, lineNo)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: línea %d: se esperaba clave = valor", ErrMalformedFile, lineNo)
+		}
+		parsed, err := parseScalar(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: línea %d: %v", ErrMalformedFile, lineNo, err)
+		}
+		values[joinKey(section, strings.TrimSpace(key))] = parsed
+	}
+	return values, scanner.Err()
+}
+
+// parseYAML admite:
+//
+//	db:
+//	  host: localhost
+//	  port: 5432
+//	server:
+//	  allowed_origins: [https://a, https://b]
+func parseYAML(content string) (map[string]string, error) {
+	values := make(map[string]string)
+	section := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := stripComment(scanner.Text(), true)
+		line := strings.TrimSpace(raw)
+		if line == "" || line == "---" {
+			continue
+		}
+		if strings.Contains(raw, "\t") {
+			return nil, fmt.Errorf("%w: línea %d: YAML no admite tabuladores", ErrMalformedFile, lineNo)
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: línea %d: se esperaba clave: valor", ErrMalformedFile, lineNo)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		indented := raw[0] == ' '
+		switch {
+		case !indented && value == "":
+			section = key
+		case !indented:
+			return nil, fmt.Errorf("%w: línea %d: %q debe ir dentro de una sección", ErrMalformedFile, lineNo, key)
+		case section == "":
+			return nil, fmt.Errorf("%w: línea %d: valor sin sección", ErrMalformedFile, lineNo)
+		default:
+			parsed, err := parseScalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: línea %d: %v", ErrMalformedFile, lineNo, err)
+			}
+			values[joinKey(section, key)] = parsed
+		}
+	}
+	return values, scanner.Err()
+}
+
+// parseScalar quita las comillas de un texto y convierte las listas [a, b] en "a,b".
+func parseScalar(value string) (string, error) {
+	if strings.HasPrefix(value, "[") {
+		if !strings.HasSuffix(value, "]") {
+			return "", fmt.Errorf("lista sin cerrar")
+		}
+		var items []string
+		for item := range strings.SplitSeq(value[1:len(value)-1], ",") {
+			item, err := parseScalar(strings.TrimSpace(item))
+			if err != nil {
+				return "", err
+			}
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		return strings.Join(items, ","), nil
+	}
+
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if value[len(value)-1] != value[0] {
+			return "", fmt.Errorf("texto sin cerrar")
+		}
+		inner := value[1 : len(value)-1]
+		if value[0] == '"' {
+			inner = strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(inner)
+		}
+		return inner, nil
+	}
+	return value, nil
+}
+
+// stripComment quita lo que sigue a un # fuera de comillas. En YAML el # solo
+// abre un comentario al inicio de la línea o tras un espacio (password: abc#123
+// es un valor), y las comillas solo cuentan al inicio de un valor.
+func stripComment(line string, yaml bool) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		afterSpace := i == 0 || line[i-1] == ' ' || line[i-1] == '\t'
+		valueStart := afterSpace || line[i-1] == '[' || line[i-1] == ','
+		switch c := line[i]; {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\'') && (valueStart || !yaml):
+			quote = c
+		case quote == 0 && c == '#' && (afterSpace || !yaml):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func joinKey(section, key string) string {
+	if section == "" {
+		return key
+	}
+	return section + "." + key
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	content := `# Comentario inicial
+[db]
+host = "localhost"   # comentario tras el valor
+port = 5432
+password = "abc#123"
+quoted = "dice \"hola\" en C:\\temp"
+single = 'sin \escapes'
+replicas = ["r1:5432", "r2", ""]
+
+[server]
+allowed_origins = ["https://a.cl", 'https://b.cl']
+empty = []
+bare = valor sin comillas
+`
+	want := map[string]string{
+		"db.host":                "localhost",
+		"db.port":                "5432",
+		"db.password":            "abc#123",
+		"db.quoted":              `dice "hola" en C:\temp`,
+		"db.single":              `sin \escapes`,
+		"db.replicas":            "r1:5432,r2",
+		"server.allowed_origins": "https://a.cl,https://b.cl",
+		"server.empty":           "",
+		"server.bare":            "valor sin comillas",
+	}
+
+	got, err := parseTOML(content)
+	if err != nil {
+		t.Fatalf("parseTOML: %v", err)
+	}
+	if !maps.Equal(got, want) {
+		t.Fatalf("parseTOML =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	content := `---
+# Comentario inicial
+db:
+  host: localhost  # comentario tras el valor
+  port: 5432
+  password: abc#123
+  quoted: "a # b"
+  escaped: "dice \"hola\""
+  url: postgres://user@host:5432/db
+  replicas: [r1:5432, "r2"]
+
+server:
+  allowed_origins: [https://a.cl, https://b.cl]
+`
+	want := map[string]string{
+		"db.host":                "localhost",
+		"db.port":                "5432",
+		"db.password":            "abc#123",
+		"db.quoted":              "a # b",
+		"db.escaped":             `dice "hola"`,
+		"db.url":                 "postgres://user@host:5432/db",
+		"db.replicas":            "r1:5432,r2",
+		"server.allowed_origins": "https://a.cl,https://b.cl",
+	}
+
+	got, err := parseYAML(content)
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+	if !maps.Equal(got, want) {
+		t.Fatalf("parseYAML =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestParseMalformedFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   func(string) (map[string]string, error)
+		content string
+	}{
+		{"toml unclosed section", parseTOML, "[db\nhost = x"},
+		{"toml missing equals", parseTOML, "[db]\nhost"},
+		{"toml unclosed string", parseTOML, `host = "localhost`},
+		{"toml unclosed list", parseTOML, `origins = ["a", "b"`},
+		{"toml mismatched quotes", parseTOML, `host = "localhost'`},
+		{"yaml tab", parseYAML, "db:\n\thost: x"},
+		{"yaml missing colon", parseYAML, "db:\n  host"},
+		{"yaml value outside section", parseYAML, "host: x"},
+		{"yaml indented without section", parseYAML, "  host: x"},
+		{"yaml unclosed list", parseYAML, "server:\n  origins: [a, b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.parse(tt.content); !errors.Is(err, ErrMalformedFile) {
+				t.Fatalf("err = %v, want ErrMalformedFile", err)
+			}
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return path
+	}
+
+	for _, name := range []string{"app.toml", "app.yaml", "APP.YML"} {
+		content := "[db]\nhost = h\n"
+		if name != "app.toml" {
+			content = "db:\n  host: h\n"
+		}
+		got, err := readFile(write(name, content))
+		if err != nil || got["db.host"] != "h" {
+			t.Errorf("readFile(%s) = %v, %v", name, got, err)
+		}
+	}
+
+	if _, err := readFile(write("app.json", `{}`)); !errors.Is(err, ErrUnsupportedFile) {
+		t.Errorf("json: err = %v, want ErrUnsupportedFile", err)
+	}
+	if _, err := readFile(filepath.Join(dir, "missing.toml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: err = %v, want ErrNotExist", err)
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Fuentes de un valor, de menor a mayor precedencia.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Options indica de dónde se lee la configuración.
+type Options struct {
+	Args      []string                    // Argumentos sin el nombre del programa (os.Args[1:])
+	LookupEnv func(string) (string, bool) // nil = os.LookupEnv
+	File      string                      // Archivo por defecto; -config y CONFIG_FILE tienen prioridad
+}
+
+// Load arma la configuración: valores por defecto, luego el archivo, luego las
+// variables de entorno y por último los flags. Devuelve todos los problemas juntos
+// (errors.Join de *FieldError) en vez de detenerse en el primero.
+func Load(opts Options) (Config, error) {
+	lookupEnv := opts.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	cfg := Default()
+	byKey := make(map[string]field)
+	for _, f := range fields(&cfg) {
+		byKey[f.key] = f
+	}
+
+	flagValues, configFile, err := parseFlags(opts.Args, byKey)
+	if err != nil {
+		return Config{}, err
+	}
+	if configFile == "" {
+		configFile, _ = lookupEnv("CONFIG_FILE")
+	}
+	if configFile == "" {
+		configFile = opts.File
+	}
+
+	var errs []error
+	if configFile != "" {
+		fileValues, err := readFile(configFile)
+		if err != nil {
+			return Config{}, err
+		}
+		for _, key := range sortedKeys(fileValues) {
+			f, ok := byKey[key]
+			if !ok {
+				errs = append(errs, &FieldError{Key: key, Source: SourceFile, Err: ErrUnknownKey})
+				continue
+			}
+			if err := f.set(fileValues[key]); err != nil {
+				errs = append(errs, &FieldError{Key: key, Source: SourceFile, Err: err})
+			}
+		}
+	}
+
+	for _, f := range fields(&cfg) {
+		raw, ok := lookupEnv(envName(f.key))
+		if !ok {
+			continue
+		}
+		if err := f.set(raw); err != nil {
+			errs = append(errs, &FieldError{Key: f.key, Source: SourceEnv + " " + envName(f.key), Err: err})
+		}
+	}
+
+	for _, key := range sortedKeys(flagValues) {
+		if err := byKey[key].set(flagValues[key]); err != nil {
+			errs = append(errs, &FieldError{Key: key, Source: SourceFlag, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// parseFlags lee -config y un flag -seccion.campo por cada clave. Solo devuelve
+// los flags presentes, para no pisar el archivo ni el entorno con valores vacíos.
+func parseFlags(args []string, byKey map[string]field) (map[string]string, string, error) {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configFile := fs.String("config", "", "archivo de configuración (.toml, .yaml o .yml)")
+	raw := make(map[string]*string, len(byKey))
+	for key := range byKey {
+		raw[key] = fs.String(key, "", "")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+
+	values := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if ptr, ok := raw[f.Name]; ok {
+			values[f.Name] = *ptr
+		}
+	})
+	return values, *configFile, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
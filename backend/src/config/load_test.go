@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// baseFile tiene lo mínimo para que Validate pase.
+const baseFile = `[db]
+user = "aiep"
+name = "aiep"
+port = 5433
+
+[llm]
+api_key = "sk-file"
+`
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+// envMap es un LookupEnv sobre un mapa, para no tocar el entorno del proceso.
+func envMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeConfig(t, "app.toml", baseFile)
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want int
+	}{
+		{"file over default", nil, nil, 5433},
+		{"env over file", map[string]string{"DB_PORT": "5434"}, nil, 5434},
+		{"flag over env", map[string]string{"DB_PORT": "5434"}, []string{"-db.port=5435"}, 5435},
+		{"flag over file", nil, []string{"-db.port", "5435"}, 5435},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(Options{Args: tt.args, LookupEnv: envMap(tt.env), File: file})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.DB.Port != tt.want {
+				t.Fatalf("db.port = %d, want %d", cfg.DB.Port, tt.want)
+			}
+			// Lo que ninguna fuente define conserva el valor por defecto
+			if cfg.DB.Host != "localhost" || cfg.Server.ReadTimeout != 15*time.Second {
+				t.Fatalf("defaults lost: host %q, read_timeout %v", cfg.DB.Host, cfg.Server.ReadTimeout)
+			}
+		})
+	}
+}
+
+func TestLoadParsesTypes(t *testing.T) {
+	env := map[string]string{
+		"DB_USER":                "aiep",
+		"DB_NAME":                "aiep",
+		"LLM_API_KEY":            "sk-env",
+		"LLM_TEMPERATURE":        "0.7",
+		"LLM_TIMEOUT":            "2m",
+		"EMBEDDINGS_ENABLED":     "false",
+		"SERVER_ALLOWED_ORIGINS": "https://a.cl, https://b.cl,",
+	}
+	cfg, err := Load(Options{LookupEnv: envMap(env)})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.LLM.APIKey.Reveal() != "sk-env" || cfg.LLM.Temperature != 0.7 || cfg.LLM.Timeout != 2*time.Minute || cfg.Embeddings.Enabled {
+		t.Fatalf("unexpected llm/embeddings config: %+v %+v", cfg.LLM, cfg.Embeddings)
+	}
+	if want := []string{"https://a.cl", "https://b.cl"}; !slices.Equal(cfg.Server.AllowedOrigins, want) {
+		t.Fatalf("allowed_origins = %q, want %q", cfg.Server.AllowedOrigins, want)
+	}
+}
+
+func TestLoadConfigFileSelection(t *testing.T) {
+	fromOpts := writeConfig(t, "opts.toml", baseFile)
+	fromEnv := writeConfig(t, "env.toml", strings.Replace(baseFile, "5433", "6000", 1))
+	fromFlag := writeConfig(t, "flag.yaml", "db:\n  user: aiep\n  name: aiep\n  port: 7000\nllm:\n  api_key: sk-file\n")
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want int
+	}{
+		{"options", nil, nil, 5433},
+		{"CONFIG_FILE over options", map[string]string{"CONFIG_FILE": fromEnv}, nil, 6000},
+		{"-config over CONFIG_FILE", map[string]string{"CONFIG_FILE": fromEnv}, []string{"-config", fromFlag}, 7000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(Options{Args: tt.args, LookupEnv: envMap(tt.env), File: fromOpts})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.DB.Port != tt.want {
+				t.Fatalf("db.port = %d, want %d", cfg.DB.Port, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	file := writeConfig(t, "app.toml", baseFile+"\n[server]\nport = 8080\nunknown_key = 1\n")
+	env := map[string]string{"DB_MAX_OPEN_CONNS": "muchas"}
+	args := []string{"-llm.timeout=pronto"}
+
+	_, err := Load(Options{Args: args, LookupEnv: envMap(env), File: file})
+	if err == nil {
+		t.Fatal("Load succeeded, want errors")
+	}
+
+	var fieldErrs []*FieldError
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			fieldErrs = append(fieldErrs, fe)
+		}
+	}
+	want := []struct {
+		key, source string
+		err         error
+	}{
+		{"server.unknown_key", SourceFile, ErrUnknownKey},
+		{"db.max_open_conns", SourceEnv + " DB_MAX_OPEN_CONNS", ErrInvalidValue},
+		{"llm.timeout", SourceFlag, ErrInvalidValue},
+	}
+	if len(fieldErrs) != len(want) {
+		t.Fatalf("got %d field errors (%v), want %d", len(fieldErrs), err, len(want))
+	}
+	for i, w := range want {
+		got := fieldErrs[i]
+		if got.Key != w.key || got.Source != w.source || !errors.Is(got, w.err) {
+			t.Errorf("error %d = %v, want %s (%s): %v", i, got, w.key, w.source, w.err)
+		}
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	file := writeConfig(t, "app.toml", baseFile)
+
+	tests := []struct {
+		name string
+		opts Options
+		want error
+	}{
+		{"unknown flag", Options{Args: []string{"-db.nope=1"}, File: file}, ErrInvalidValue},
+		{"unsupported file", Options{File: writeConfig(t, "app.ini", "")}, ErrUnsupportedFile},
+		{"malformed file", Options{File: writeConfig(t, "bad.toml", "[db")}, ErrMalformedFile},
+		{"missing file", Options{File: filepath.Join(t.TempDir(), "missing.toml")}, os.ErrNotExist},
+		{"invalid config", Options{File: file, Args: []string{"-db.sslmode=maybe"}}, ErrValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.LookupEnv = envMap(nil)
+			if _, err := Load(tt.opts); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import "log/slog"
+
+const redacted = "[REDACTED]"
+
+// Secret es un valor sensible (contraseñas, claves de API). Al imprimirlo, loguearlo
+// o serializarlo sale como [REDACTED]; el valor real se obtiene con Reveal.
+type Secret string
+
+// Reveal devuelve el valor real.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// IsSet indica si el secreto tiene valor.
+func (s Secret) IsSet() bool {
+	return s != ""
+}
+
+// String implements fmt.Stringer.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString implements fmt.GoStringer (para %#v).
+func (s Secret) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+// LogValue implements slog.LogValuer.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalText implements encoding.TextMarshaler (JSON, YAML, ...).
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+const testSecret = "sk-muy-secreto"
+
+func TestSecretRedaction(t *testing.T) {
+	s := Secret(testSecret)
+	holder := struct{ Key Secret }{s}
+
+	tests := []struct {
+		name string
+		got  string
+	}{
+		{"%v", fmt.Sprintf("%v", s)},
+		{"%s", fmt.Sprintf("%s", s)},
+		{"%+v struct", fmt.Sprintf("%+v", holder)},
+		{"%#v struct", fmt.Sprintf("%#v", holder)},
+		{"json", mustJSON(t, holder)},
+		{"slog", logLine(slog.Any("key", s))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if strings.Contains(tt.got, testSecret) || !strings.Contains(tt.got, redacted) {
+				t.Fatalf("output %q leaks the secret or is not redacted", tt.got)
+			}
+		})
+	}
+
+	if s.Reveal() != testSecret || !s.IsSet() {
+		t.Fatalf("Reveal = %q, IsSet = %v", s.Reveal(), s.IsSet())
+	}
+	// Un secreto vacío sale vacío: así se nota que falta configurarlo
+	if empty := Secret(""); empty.String() != "" || empty.IsSet() {
+		t.Fatalf("empty secret: String = %q, IsSet = %v", empty.String(), empty.IsSet())
+	}
+}
+
+func TestConfigLogValueRedactsSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.DB.Password = testSecret
+	cfg.LLM.APIKey = testSecret
+	cfg.Auth.TwoFactorKey = testSecret
+
+	line := logLine(slog.Any("config", cfg))
+	if strings.Contains(line, testSecret) {
+		t.Fatalf("log line leaks a secret: %s", line)
+	}
+	for _, want := range []string{"config.db.password=" + redacted, "config.llm.api_key=" + redacted, "config.db.host=localhost"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log line %q does not contain %q", line, want)
+		}
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	return string(b)
+}
+
+func logLine(attr slog.Attr) string {
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("test", attr)
+	return buf.String()
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// minSecretLength es el largo mínimo de las claves de firma y cifrado.
+const minSecretLength = 32
+
+var (
+	sslModes  = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	logLevels = []string{"silent", "error", "warn", "info"}
+)
+
+// Validate revisa la configuración completa y devuelve todos los problemas juntos.
+func (c Config) Validate() error {
+	v := &validator{}
+
+	// Base de datos
+	v.required("db.host", c.DB.Host)
+	v.required("db.user", c.DB.User)
+	v.required("db.name", c.DB.Name)
+	v.port("db.port", c.DB.Port)
+	v.oneOf("db.sslmode", c.DB.SSLMode, sslModes)
+	v.oneOf("db.log_level", c.DB.LogLevel, logLevels)
+	v.check("db.max_open_conns", c.DB.MaxOpenConns > 0, "debe ser mayor que 0")
+	v.check("db.max_idle_conns", c.DB.MaxIdleConns >= 0 && c.DB.MaxIdleConns <= c.DB.MaxOpenConns, "debe estar entre 0 y db.max_open_conns")
+	v.check("db.conn_max_lifetime", c.DB.ConnMaxLifetime >= 0, "no puede ser negativo")
+	v.check("db.conn_max_idle_time", c.DB.ConnMaxIdleTime >= 0, "no puede ser negativo")
+	v.check("db.statement_timeout", c.DB.StatementTimeout >= 0, "no puede ser negativo")
+	v.check("db.slow_query_threshold", c.DB.SlowQueryThreshold >= 0, "no puede ser negativo")
+	for _, replica := range c.DB.Replicas {
+		v.hostPort("db.replicas", replica)
+	}
+
+	// LLM
+	v.required("llm.api_key", c.LLM.APIKey.Reveal())
+	v.required("llm.model", c.LLM.Model)
+	v.url("llm.base_url", c.LLM.BaseURL, true)
+	v.check("llm.max_completion_tokens", c.LLM.MaxCompletionTokens > 0, "debe ser mayor que 0")
+	v.check("llm.temperature", c.LLM.Temperature >= 0 && c.LLM.Temperature <= 2, "debe estar entre 0 y 2")
+	v.check("llm.timeout", c.LLM.Timeout > 0, "debe ser mayor que 0")
+
+	// Embeddings
+	if c.Embeddings.Enabled {
+		v.required("embeddings.model", c.Embeddings.Model)
+		v.url("embeddings.base_url", c.Embeddings.BaseURL, true)
+		v.check("embeddings.dimensions", c.Embeddings.Dimensions == 1536, "la columna vector admite 1536 dimensiones")
+	}
+
+	// Cuentas
+	if c.Auth.RequireVerifiedEmail {
+		v.required("auth.verification_secret", c.Auth.VerificationSecret.Reveal())
+	}
+	v.secret("auth.verification_secret", c.Auth.VerificationSecret)
+	v.secret("auth.two_factor_key", c.Auth.TwoFactorKey)
+	v.check("auth.session_ttl", c.Auth.SessionTTL > 0, "debe ser mayor que 0")
+	if c.Auth.OIDCIssuer != "" {
+		v.url("auth.oidc_issuer", c.Auth.OIDCIssuer, true)
+		v.required("auth.oidc_client_id", c.Auth.OIDCClientID)
+		v.url("auth.oidc_redirect_url", c.Auth.OIDCRedirectURL, true)
+		v.required("auth.sso_key", c.Auth.SSOKey.Reveal())
+		v.secret("auth.sso_key", c.Auth.SSOKey)
+	}
+
+	// Servidor
+	v.port("server.port", c.Server.Port)
+	v.url("server.public_url", c.Server.PublicURL, false)
+	v.check("server.read_timeout", c.Server.ReadTimeout > 0, "debe ser mayor que 0")
+	v.check("server.write_timeout", c.Server.WriteTimeout > 0, "debe ser mayor que 0")
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return errors.Join(append([]error{ErrValidationFailed}, v.errs...)...)
+}
+
+// validator acumula los problemas de Validate.
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(key, msg string) {
+	v.errs = append(v.errs, &FieldError{Key: key, Err: errors.New(msg)})
+}
+
+func (v *validator) check(key string, ok bool, msg string) {
+	if !ok {
+		v.fail(key, msg)
+	}
+}
+
+func (v *validator) required(key, value string) {
+	v.check(key, value != "", "es obligatorio")
+}
+
+func (v *validator) port(key string, port int) {
+	v.check(key, port > 0 && port <= 65535, fmt.Sprintf("%d no es un puerto válido", port))
+}
+
+func (v *validator) oneOf(key, value string, allowed []string) {
+	v.check(key, slices.Contains(allowed, value), fmt.Sprintf("%q no es válido (opciones: %v)", value, allowed))
+}
+
+// hostPort exige "host" o "host:puerto" con un puerto válido.
+func (v *validator) hostPort(key, value string) {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		host, port = value, ""
+	}
+	n, convErr := strconv.Atoi(port)
+	v.check(key, host != "" && !strings.ContainsAny(host, " /") && (port == "" || convErr == nil && n > 0 && n <= 65535),
+		fmt.Sprintf("%q no es un host válido (host o host:puerto)", value))
+}
+
+// url exige una URL absoluta http(s); si required es falso, vacío también vale.
+func (v *validator) url(key, value string, required bool) {
+	if value == "" {
+		if required {
+			v.fail(key, "es obligatorio")
+		}
+		return
+	}
+	u, err := url.Parse(value)
+	v.check(key, err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", fmt.Sprintf("%q no es una URL http(s) válida", value))
+}
+
+// secret exige un largo mínimo a las claves definidas (sin mostrar su valor).
+func (v *validator) secret(key string, value Secret) {
+	if value.IsSet() && len(value.Reveal()) < minSecretLength {
+		v.fail(key, fmt.Sprintf("debe tener al menos %d caracteres", minSecretLength))
+	}
+}
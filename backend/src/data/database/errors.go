@@ -0,0 +1,8 @@
+package database
+
+import "errors"
+
+var (
+	ErrDatabaseRequired       = errors.New("database error: se requiere una conexión a la base de datos")
+	ErrVectorExtensionMissing = errors.New("database error: la extensión vector no está instalada")
+)
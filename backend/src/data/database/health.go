@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// Ready comprueba que la base puede atender tráfico: responde al ping, tiene la
+// extensión vector y, si hay réplicas, todas responden. Pensado para el endpoint
+// de readiness; el contexto debe llevar un timeout corto.
+func Ready(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get connection pool: %w", err)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	var installed bool
+	err = db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'vector')").
+		Scan(&installed).Error
+	if err != nil {
+		return fmt.Errorf("failed to check vector extension: %w", err)
+	}
+	if !installed {
+		return ErrVectorExtensionMissing
+	}
+
+	if resolver, ok := db.Config.Plugins[(&dbresolver.DBResolver{}).Name()].(*dbresolver.DBResolver); ok {
+		err := resolver.Call(func(pool gorm.ConnPool) error {
+			pinger, ok := pool.(interface{ PingContext(context.Context) error })
+			if !ok {
+				return nil
+			}
+			return pinger.PingContext(ctx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to ping read replica: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -3,56 +3,141 @@ package database
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"strconv"
 	"strings"
 
+	"github.com/Dieg0Code/aiep-agent/src/config"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
-func GetDB() *gorm.DB {
-	// Get enviroment variables
-	host := os.Getenv("DB_HOST")
-	portStr := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
+// GetDB abre la conexión, asegura la extensión pgvector y ejecuta las migraciones.
+// Si hay réplicas configuradas, registra el resolver de la búsqueda semántica.
+// Los errores se devuelven: decidir si el proceso termina le corresponde a main.
+func GetDB(cfg config.DBConfig) (*gorm.DB, error) {
+	//GORM configuration
+	gormConfig := &gorm.Config{
+		Logger: logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+			SlowThreshold:             cfg.SlowQueryThreshold,
+			LogLevel:                  logLevel(cfg.LogLevel),
+			IgnoreRecordNotFoundError: true, // Los repos ya lo traducen a sus errores NotFound
+		}),
+	}
 
-	port, err := strconv.Atoi(portStr)
+	db, err := gorm.Open(postgres.Open(DSN(cfg)), gormConfig)
 	if err != nil {
-		log.Fatalf("Invalid DB_PORT: %v", err)
+		return nil, fmt.Errorf("failed to connect to database %s:%d: %w", cfg.Host, cfg.Port, err)
 	}
 
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname,
-	)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection pool: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	//GORM configuration
-	config := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	// Create vector extension if not exists
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS vector").Error; err != nil && !strings.Contains(err.Error(), "already exists") {
+		return nil, fmt.Errorf("failed to create vector extension: %w", err)
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), config)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	if err := models.AutoMigrateAll(db); err != nil {
+		return nil, fmt.Errorf("migration error: %w", err)
 	}
 
-	// Create vector extension if not exists
-	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS vector").Error; err != nil {
-		if strings.Contains(err.Error(), "already exists") {
-			log.Println("Vector extension already exists")
-		} else {
-			log.Fatalf("Failed to create vector extension: %v", err)
+	// Las réplicas se registran después de migrar: las migraciones van siempre al primario
+	if len(cfg.Replicas) > 0 {
+		if err := useReplicas(db, cfg); err != nil {
+			return nil, err
 		}
 	}
 
-	if err := models.AutoMigrateAll(db); err != nil {
-		log.Fatalf("migration error: %v", err)
+	return db, nil
+}
+
+// useReplicas registra un resolver con nombre que solo usan las consultas marcadas
+// con SemanticReplica; como no hay resolver global, el resto sigue en el primario.
+func useReplicas(db *gorm.DB, cfg config.DBConfig) error {
+	replicas := make([]gorm.Dialector, 0, len(cfg.Replicas))
+	for _, replica := range cfg.Replicas {
+		replicaCfg, err := replicaConfig(cfg, replica)
+		if err != nil {
+			return err
+		}
+		replicas = append(replicas, postgres.Open(DSN(replicaCfg)))
 	}
 
-	return db
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}, SemanticSearchResolver).
+		SetMaxOpenConns(cfg.MaxOpenConns).
+		SetMaxIdleConns(cfg.MaxIdleConns).
+		SetConnMaxLifetime(cfg.ConnMaxLifetime).
+		SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	if err := db.Use(resolver); err != nil {
+		return fmt.Errorf("failed to register read replicas: %w", err)
+	}
+	return nil
+}
+
+// replicaConfig copia la configuración del primario cambiando host y puerto.
+func replicaConfig(cfg config.DBConfig, replica string) (config.DBConfig, error) {
+	host, port, err := net.SplitHostPort(replica)
+	if err != nil {
+		cfg.Host = replica // Sin puerto: se usa el del primario
+		return cfg, nil
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid replica port %q: %w", replica, err)
+	}
+	cfg.Host, cfg.Port = host, n
+	return cfg, nil
+}
+
+// DSN arma la cadena de conexión clave=valor de PostgreSQL. Los valores van entre
+// comillas para admitir espacios y comillas en la contraseña.
+func DSN(cfg config.DBConfig) string {
+	parts := []string{
+		"host=" + dsnValue(cfg.Host),
+		fmt.Sprintf("port=%d", cfg.Port),
+		"user=" + dsnValue(cfg.User),
+		"password=" + dsnValue(cfg.Password.Reveal()),
+		"dbname=" + dsnValue(cfg.Name),
+		"sslmode=" + dsnValue(cfg.SSLMode),
+	}
+	if cfg.SSLRootCert != "" {
+		parts = append(parts, "sslrootcert="+dsnValue(cfg.SSLRootCert))
+	}
+	// Parámetro de sesión: el servidor corta las consultas que lo superan
+	if cfg.StatementTimeout > 0 {
+		parts = append(parts, fmt.Sprintf("statement_timeout=%d", cfg.StatementTimeout.Milliseconds()))
+	}
+	return strings.Join(parts, " ")
+}
+
+func dsnValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+func logLevel(level string) logger.LogLevel {
+	switch level {
+	case "silent":
+		return logger.Silent
+	case "error":
+		return logger.Error
+	case "info":
+		return logger.Info
+	default:
+		return logger.Warn
+	}
 }
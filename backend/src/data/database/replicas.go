@@ -0,0 +1,17 @@
+package database
+
+import (
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+// SemanticSearchResolver es el nombre del resolver que envía la búsqueda semántica
+// a las réplicas de lectura.
+const SemanticSearchResolver = "semantic_search"
+
+// SemanticReplica marca una consulta para que vaya a una réplica si hay alguna
+// configurada; sin réplicas no tiene efecto. Solo para lecturas que toleran el
+// retraso de replicación (pocos segundos): lo recién escrito puede no aparecer.
+func SemanticReplica() clause.Expression {
+	return dbresolver.Use(SemanticSearchResolver)
+}
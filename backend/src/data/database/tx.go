@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// txKey es la clave del contexto donde viaja la transacción en curso.
+type txKey struct{}
+
+// TxManager ejecuta operaciones de varios repositorios en una sola transacción.
+// La transacción viaja en el contexto: los repos la toman con Conn, así que no
+// hace falta construirlos de nuevo sobre tx.
+type TxManager interface {
+	// Transaction ejecuta fn en una transacción y hace commit si devuelve nil; ante
+	// un error o un panic hace rollback. Si ctx ya trae una transacción, fn corre en
+	// un savepoint: su error deshace solo lo hecho por fn y la transacción externa
+	// decide si continúa. opts solo aplica a la transacción más externa.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error
+}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager crea el TxManager sobre la conexión principal.
+func NewTxManager(db *gorm.DB) (TxManager, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &txManager{db: db}, nil
+}
+
+// Transaction implements TxManager.
+func (m *txManager) Transaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	// Sobre una transacción abierta GORM usa SAVEPOINT / ROLLBACK TO SAVEPOINT
+	return Conn(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(WithTx(ctx, tx))
+	}, opts...)
+}
+
+// WithTx devuelve un contexto que lleva tx como transacción en curso.
+func WithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// InTransaction indica si ctx lleva una transacción en curso.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
+
+// Conn devuelve la conexión que debe usar una consulta: la transacción del
+// contexto si hay una, o db en caso contrario. Siempre ligada a ctx.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -1,5 +1,15 @@
 package dtos
 
+import (
+	"net/http"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/i18n"
+)
+
+// CodeValidationFailed es el código de las respuestas con errores de las reglas `binding`.
+const CodeValidationFailed = "validation.failed"
+
 // BaseResponse represents a standardized response structure for API responses.
 // @Description BaseResponse is the standard response format for all API endpoints.
 type BaseResponse struct {
@@ -7,4 +17,36 @@ type BaseResponse struct {
 	Status  string `json:"status" example:"success" extension:"x-order=1"`
 	Message string `json:"message" example:"Operation completed successfully" extension:"x-order=2"`
 	Data    any    `json:"data,omitempty" extension:"x-order=3"`
+	// ErrorCode es el código estable del error (p. ej. "user.email_conflict"); solo en errores
+	ErrorCode string `json:"error_code,omitempty" example:"user.email_conflict" extension:"x-order=4"`
+}
+
+// NewErrorResponse arma la respuesta de un error en el idioma de la petición: el
+// status sale de su categoría y los errores sin código se informan como error
+// interno, sin exponer su texto.
+func NewErrorResponse(err error, locale i18n.Locale) BaseResponse {
+	appErr := apperror.From(err)
+	return BaseResponse{
+		Code:      apperror.HTTPStatus(appErr),
+		Status:    "error",
+		Message:   i18n.Error(locale, appErr),
+		ErrorCode: appErr.Code(),
+	}
+}
+
+// NewValidationErrorResponse arma la respuesta de una solicitud que no cumple sus
+// reglas `binding`; Data lleva el mensaje de cada campo. Cualquier otro error se
+// responde con NewErrorResponse.
+func NewValidationErrorResponse(err error, locale i18n.Locale) BaseResponse {
+	fields := i18n.Validation(locale, err)
+	if fields == nil {
+		return NewErrorResponse(err, locale)
+	}
+	return BaseResponse{
+		Code:      http.StatusBadRequest,
+		Status:    "error",
+		Message:   i18n.T(locale, CodeValidationFailed, nil),
+		Data:      fields,
+		ErrorCode: CodeValidationFailed,
+	}
 }
@@ -0,0 +1,69 @@
+package calendardto
+
+import (
+	"strings"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// CalendarFeedDTO describe la URL de suscripción al calendario del estudiante.
+// El token solo viaja en la URL al crearlo; después no se puede recuperar.
+type CalendarFeedDTO struct {
+	URL        string `json:"url,omitempty" example:"https://aiep-agent.cl/api/v1/calendar/feed/Xk3...9a.ics"`
+	CreatedAt  string `json:"created_at" example:"2025-03-01T12:00:00Z"`             // RFC3339
+	LastUsedAt string `json:"last_used_at,omitempty" example:"2025-03-02T08:00:00Z"` // RFC3339
+}
+
+// FromFeedTokenModel convierte models.CalendarFeedToken a CalendarFeedDTO (nil-safe).
+func FromFeedTokenModel(t *models.CalendarFeedToken, url string) CalendarFeedDTO {
+	if t == nil {
+		return CalendarFeedDTO{}
+	}
+
+	dto := CalendarFeedDTO{
+		URL:       url,
+		CreatedAt: date.FormatDateTime(t.CreatedAt),
+	}
+	if t.LastUsedAt != nil {
+		dto.LastUsedAt = date.FormatDateTime(*t.LastUsedAt)
+	}
+	return dto
+}
+
+// CalendarImportRequestDTO acompaña al archivo .ics subido por el profesor.
+// @Description CalendarImportRequestDTO is used for creating or shifting topic dates from an ICS file.
+type CalendarImportRequestDTO struct {
+	ModuleID uint `form:"module_id" json:"module_id" example:"2"` // Requerido para eventos que no vienen de nuestro feed
+	DryRun   bool `form:"dry_run" json:"dry_run" example:"true"`  // Validar sin guardar cambios
+}
+
+// CalendarImportErrorDTO describe un problema con un evento del archivo.
+type CalendarImportErrorDTO struct {
+	Event   int    `json:"event" example:"3"` // Posición del evento en el archivo (desde 1)
+	UID     string `json:"uid,omitempty" example:"topic-42@aiep-agent"`
+	Message string `json:"message" example:"el tema no pertenece al módulo indicado"`
+}
+
+// CalendarImportReportDTO resume el resultado (o la simulación) de una importación.
+type CalendarImportReportDTO struct {
+	DryRun           bool                     `json:"dry_run"`
+	Committed        bool                     `json:"committed"`
+	TotalEvents      int                      `json:"total_events"`
+	TopicsCreated    int                      `json:"topics_created"`
+	TopicsShifted    int                      `json:"topics_shifted"` // Cambió la fecha
+	TopicsUpdated    int                      `json:"topics_updated"` // Cambió solo el título o contenido
+	TopicsUnchanged  int                      `json:"topics_unchanged"`
+	EmbeddingsQueued int                      `json:"embeddings_queued"`
+	Errors           []CalendarImportErrorDTO `json:"errors,omitempty"`
+}
+
+// AddError agrega un error de evento al reporte.
+func (r *CalendarImportReportDTO) AddError(event int, uid, message string) {
+	r.Errors = append(r.Errors, CalendarImportErrorDTO{Event: event, UID: strings.TrimSpace(uid), Message: message})
+}
+
+// HasErrors indica si algún evento tiene problemas.
+func (r *CalendarImportReportDTO) HasErrors() bool {
+	return r != nil && len(r.Errors) > 0
+}
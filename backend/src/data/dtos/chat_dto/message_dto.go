@@ -0,0 +1,67 @@
+package chatdto
+
+import (
+	"github.com/Dieg0Code/aiep-agent/src/data/dtos"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
+	chatrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/chat_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// ListMessagesRequestDTO representa los parámetros de consulta del historial de un hilo.
+// Sin cursor devuelve los últimos mensajes; con before "carga los anteriores".
+type ListMessagesRequestDTO struct {
+	UserID         uint `form:"user_id" json:"user_id" binding:"required" example:"1"`
+	ConversationID uint `form:"conversation_id" json:"conversation_id" binding:"required" example:"3"`
+	dtos.ListRequestDTO
+	dtos.PageRequestDTO
+}
+
+// ToRepoFilter convierte el DTO al filtro esperado por el repo.
+func (d *ListMessagesRequestDTO) ToRepoFilter() chatrepo.ChatMessageFilter {
+	return chatrepo.ChatMessageFilter{
+		ConversationID: d.ConversationID,
+		Spec:           d.ToSpec(),
+		Page:           d.ToParams(),
+	}
+}
+
+// ChatMessageDTO representa un mensaje del historial.
+type ChatMessageDTO struct {
+	ID        uint   `json:"id"`
+	Role      string `json:"role"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"` // RFC3339
+}
+
+// FromMessageModel convierte models.ChatMessage a ChatMessageDTO (nil-safe).
+func FromMessageModel(m *models.ChatMessage) ChatMessageDTO {
+	if m == nil {
+		return ChatMessageDTO{}
+	}
+	return ChatMessageDTO{
+		ID:        m.ID,
+		Role:      m.Role,
+		Content:   m.Content,
+		CreatedAt: date.FormatDateTime(m.CreatedAt),
+	}
+}
+
+// ListMessagesResponseDTO envuelve una página del historial en orden cronológico.
+type ListMessagesResponseDTO struct {
+	Items []ChatMessageDTO `json:"items"`
+	dtos.PageInfoDTO
+}
+
+// MakeListMessagesResponse mapea una página del repo a la respuesta DTO.
+func MakeListMessagesResponse(page pagination.Page[models.ChatMessage], req *ListMessagesRequestDTO) ListMessagesResponseDTO {
+	items := make([]ChatMessageDTO, 0, len(page.Items))
+	for i := range page.Items {
+		m := page.Items[i]
+		items = append(items, FromMessageModel(&m))
+	}
+	return ListMessagesResponseDTO{
+		Items:       items,
+		PageInfoDTO: dtos.NewPageInfo(page, req.ToParams()),
+	}
+}
@@ -0,0 +1,53 @@
+package chatdto
+
+import "strings"
+
+// SendMessageRequestDTO represents a student message sent to the agent.
+// @Description SendMessageRequestDTO is used for chatting with the AI agent.
+type SendMessageRequestDTO struct {
+	UserID         uint   `json:"user_id" binding:"required" example:"1"`
+	ConversationID uint   `json:"conversation_id,omitempty" example:"3"` // 0 = hilo activo más reciente
+	ModuleID       uint   `json:"module_id,omitempty" example:"2"`       // 0 = módulo del hilo (si tiene)
+	Message        string `json:"message" binding:"required,min=1,max=4000" example:"¿Qué veremos en la próxima clase?"`
+}
+
+// GetUserID devuelve el id del usuario (helper nil-safe).
+func (d *SendMessageRequestDTO) GetUserID() uint {
+	if d == nil {
+		return 0
+	}
+	return d.UserID
+}
+
+// GetConversationID devuelve el id del hilo (helper nil-safe).
+func (d *SendMessageRequestDTO) GetConversationID() uint {
+	if d == nil {
+		return 0
+	}
+	return d.ConversationID
+}
+
+// GetModuleID devuelve el módulo solicitado (helper nil-safe).
+func (d *SendMessageRequestDTO) GetModuleID() uint {
+	if d == nil {
+		return 0
+	}
+	return d.ModuleID
+}
+
+// GetMessage devuelve el mensaje sin espacios sobrantes (helper nil-safe).
+func (d *SendMessageRequestDTO) GetMessage() string {
+	if d == nil {
+		return ""
+	}
+	return strings.TrimSpace(d.Message)
+}
+
+// SendMessageResponseDTO represents the agent reply.
+// @Description SendMessageResponseDTO contains the assistant answer for a message.
+type SendMessageResponseDTO struct {
+	ConversationID uint   `json:"conversation_id" example:"1"`
+	MessageID      uint   `json:"message_id" example:"42"`
+	Reply          string `json:"reply" example:"La próxima clase veremos punteros en Go."`
+	CreatedAt      string `json:"created_at" example:"2023-09-01T12:00:00Z"` // Formato RFC3339
+}
@@ -0,0 +1,169 @@
+package chatdto
+
+import (
+	"strings"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/dtos"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	chatrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/chat_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// previewLength es el máximo de caracteres de la vista previa del último mensaje.
+const previewLength = 120
+
+// CreateSessionRequestDTO represents the data required to open a new chat thread.
+// @Description CreateSessionRequestDTO is used for creating a titled chat thread.
+type CreateSessionRequestDTO struct {
+	UserID   uint   `json:"user_id" binding:"required" example:"1"`
+	Title    string `json:"title" binding:"omitempty,max=200" example:"Repaso examen final"`
+	ModuleID *uint  `json:"module_id,omitempty" example:"2"` // nil = hilo general
+}
+
+// GetTitle devuelve el título sin espacios sobrantes (helper nil-safe).
+func (d *CreateSessionRequestDTO) GetTitle() string {
+	if d == nil {
+		return ""
+	}
+	return strings.TrimSpace(d.Title)
+}
+
+// RenameSessionRequestDTO represents the data required to rename a chat thread.
+// @Description RenameSessionRequestDTO is used for changing a chat thread title.
+type RenameSessionRequestDTO struct {
+	UserID         uint   `json:"user_id" binding:"required" example:"1"`
+	ConversationID uint   `json:"conversation_id" binding:"required" example:"3"`
+	Title          string `json:"title" binding:"required,min=1,max=200" example:"Dudas de la unidad 2"`
+}
+
+// GetTitle devuelve el título sin espacios sobrantes (helper nil-safe).
+func (d *RenameSessionRequestDTO) GetTitle() string {
+	if d == nil {
+		return ""
+	}
+	return strings.TrimSpace(d.Title)
+}
+
+// ListSessionsRequestDTO representa los parámetros de consulta (query params).
+type ListSessionsRequestDTO struct {
+	UserID          uint `form:"user_id" json:"user_id" binding:"required" example:"1"`
+	ModuleID        uint `form:"module_id" json:"module_id" example:"2"`
+	IncludeArchived bool `form:"include_archived" json:"include_archived" example:"false"`
+	Limit           int  `form:"limit" json:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	Offset          int  `form:"offset" json:"offset" example:"0"`
+	dtos.ListRequestDTO
+}
+
+// GetLimit devuelve el límite normalizado (default 20, máximo 100).
+func (d *ListSessionsRequestDTO) GetLimit() int {
+	if d == nil || d.Limit <= 0 {
+		return 20
+	}
+	if d.Limit > 100 {
+		return 100
+	}
+	return d.Limit
+}
+
+// GetOffset devuelve el offset normalizado.
+func (d *ListSessionsRequestDTO) GetOffset() int {
+	if d == nil || d.Offset < 0 {
+		return 0
+	}
+	return d.Offset
+}
+
+// ToRepoFilter convierte el DTO al filtro esperado por el repo.
+func (d *ListSessionsRequestDTO) ToRepoFilter() chatrepo.ChatSessionFilter {
+	return chatrepo.ChatSessionFilter{
+		UserID:          d.UserID,
+		ModuleID:        d.ModuleID,
+		IncludeArchived: d.IncludeArchived,
+		Spec:            d.ToSpec(),
+		Limit:           d.GetLimit(),
+		Offset:          d.GetOffset(),
+	}
+}
+
+// ChatSessionDTO representa un hilo de conversación en la lista.
+type ChatSessionDTO struct {
+	ID                 uint   `json:"id"`
+	Title              string `json:"title"`
+	AgentName          string `json:"agent_name"`
+	ModuleID           *uint  `json:"module_id,omitempty"`
+	Archived           bool   `json:"archived"`
+	ArchivedAt         string `json:"archived_at,omitempty"`     // RFC3339
+	CreatedAt          string `json:"created_at"`                // RFC3339
+	LastMessageAt      string `json:"last_message_at,omitempty"` // RFC3339
+	LastMessagePreview string `json:"last_message_preview,omitempty"`
+	LastMessageRole    string `json:"last_message_role,omitempty"`
+	MessageCount       int64  `json:"message_count"`
+}
+
+// FromSessionModel convierte models.ChatSession a ChatSessionDTO (nil-safe).
+func FromSessionModel(s *models.ChatSession) ChatSessionDTO {
+	if s == nil {
+		return ChatSessionDTO{}
+	}
+
+	dto := ChatSessionDTO{
+		ID:        s.ID,
+		Title:     s.Title,
+		AgentName: s.AgentName,
+		ModuleID:  s.ModuleID,
+		Archived:  s.ArchivedAt != nil,
+		CreatedAt: date.FormatDateTime(s.CreatedAt),
+	}
+	if s.ArchivedAt != nil {
+		dto.ArchivedAt = date.FormatDateTime(*s.ArchivedAt)
+	}
+	if s.LastMessageAt != nil {
+		dto.LastMessageAt = date.FormatDateTime(*s.LastMessageAt)
+	}
+
+	return dto
+}
+
+// FromSessionPreview convierte una vista previa del repo a ChatSessionDTO (nil-safe).
+func FromSessionPreview(p *chatrepo.ChatSessionPreview) ChatSessionDTO {
+	if p == nil {
+		return ChatSessionDTO{}
+	}
+
+	dto := FromSessionModel(&p.ChatSession)
+	dto.LastMessagePreview = preview(p.LastMessageContent)
+	dto.LastMessageRole = p.LastMessageRole
+	dto.MessageCount = p.MessageCount
+	return dto
+}
+
+// ListSessionsResponseDTO envuelve la respuesta paginada.
+type ListSessionsResponseDTO struct {
+	Items  []ChatSessionDTO `json:"items"`
+	Limit  int              `json:"limit,omitempty"`
+	Offset int              `json:"offset,omitempty"`
+}
+
+// MakeListSessionsResponse mapea las vistas previas a la respuesta DTO.
+func MakeListSessionsResponse(previews []chatrepo.ChatSessionPreview, req *ListSessionsRequestDTO) ListSessionsResponseDTO {
+	items := make([]ChatSessionDTO, 0, len(previews))
+	for i := range previews {
+		p := previews[i]
+		items = append(items, FromSessionPreview(&p))
+	}
+	return ListSessionsResponseDTO{
+		Items:  items,
+		Limit:  req.GetLimit(),
+		Offset: req.GetOffset(),
+	}
+}
+
+// preview recorta el contenido para la lista de hilos.
+func preview(content string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	runes := []rune(content)
+	if len(runes) <= previewLength {
+		return content
+	}
+	return string(runes[:previewLength-1]) + "…"
+}
@@ -0,0 +1,58 @@
+package erasuredto
+
+import (
+	"encoding/json"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// Quién pidió el borrado
+const (
+	InitiatorSelf      = "self"      // El propio usuario
+	InitiatorAdmin     = "admin"     // Un admin sobre otra cuenta
+	InitiatorRetention = "retention" // Purga automática de la papelera
+)
+
+// EraseUserRequestDTO represents a request to erase all personal data of a user.
+// @Description EraseUserRequestDTO is used by a user to erase their own account, or by an admin to erase any account.
+type EraseUserRequestDTO struct {
+	RequestedByID uint   `json:"-"`                                       // Usuario autenticado
+	SessionID     uint   `json:"-"`                                       // Sesión desde la que se pide
+	UserID        uint   `json:"user_id" binding:"required" example:"12"` // Cuenta a borrar
+	Password      string `json:"password,omitempty" example:"secret123"`  // Al borrar la propia cuenta, salvo con un login reciente
+}
+
+// ErasureReceiptDTO is the receipt of a personal data erasure.
+// @Description ErasureReceiptDTO lists how many rows were deleted or anonymized per table. It holds no personal data.
+type ErasureReceiptDTO struct {
+	ID            uint             `json:"id"`
+	UserID        uint             `json:"user_id"`
+	RequestedByID *uint            `json:"requested_by_id,omitempty"`
+	Initiator     string           `json:"initiator"`    // self | admin | retention
+	UserOutcome   string           `json:"user_outcome"` // deleted | anonymized
+	Deleted       map[string]int64 `json:"deleted"`      // Filas borradas por tabla
+	Anonymized    map[string]int64 `json:"anonymized"`   // Filas anonimizadas por tabla
+	ErasedAt      string           `json:"erased_at"`
+}
+
+// FromErasureReceipt convierte un comprobante a ErasureReceiptDTO (nil-safe).
+func FromErasureReceipt(r *models.ErasureReceipt) ErasureReceiptDTO {
+	if r == nil {
+		return ErasureReceiptDTO{}
+	}
+	dto := ErasureReceiptDTO{
+		ID:            r.ID,
+		UserID:        r.UserID,
+		RequestedByID: r.RequestedByID,
+		Initiator:     r.Initiator,
+		UserOutcome:   r.UserOutcome,
+		Deleted:       map[string]int64{},
+		Anonymized:    map[string]int64{},
+		ErasedAt:      date.FormatDateTime(r.CreatedAt),
+	}
+	// Los contadores los escribe el servicio: si no se pueden leer quedan vacíos
+	_ = json.Unmarshal(r.Deleted, &dto.Deleted)
+	_ = json.Unmarshal(r.Anonymized, &dto.Anonymized)
+	return dto
+}
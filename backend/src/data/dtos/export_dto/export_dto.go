@@ -0,0 +1,68 @@
+package exportdto
+
+import (
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	dataexportrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/data_export_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// RequestExportDTO represents a request to export the authenticated user's personal data.
+// @Description RequestExportDTO queues a ZIP export with JSON, Markdown and HTML files.
+type RequestExportDTO struct {
+	UserID            uint `json:"-"`                                  // Usuario autenticado
+	IncludeEmbeddings bool `json:"include_embeddings" example:"false"` // Incluir los vectores de mensajes e insights
+}
+
+// DataExportDTO representa el estado de una exportación.
+type DataExportDTO struct {
+	ID                uint   `json:"id"`
+	Status            string `json:"status"` // pending | processing | ready | failed | expired
+	IncludeEmbeddings bool   `json:"include_embeddings"`
+	SizeBytes         int64  `json:"size_bytes,omitempty"`
+	RequestedAt       string `json:"requested_at"`
+	FinishedAt        string `json:"finished_at,omitempty"`
+	AvailableUntil    string `json:"available_until,omitempty"` // Hasta cuándo se puede pedir un enlace de descarga
+	DownloadedAt      string `json:"downloaded_at,omitempty"`
+	Error             string `json:"error,omitempty"`
+}
+
+// FromDataExport convierte una exportación a DataExportDTO (nil-safe). retention
+// es el tiempo que se guarda el ZIP tras generarse.
+func FromDataExport(e *models.DataExport, retention time.Duration) DataExportDTO {
+	if e == nil {
+		return DataExportDTO{}
+	}
+	dto := DataExportDTO{
+		ID:                e.ID,
+		Status:            e.Status,
+		IncludeEmbeddings: e.IncludeEmbeddings,
+		SizeBytes:         e.SizeBytes,
+		RequestedAt:       date.FormatDateTime(e.CreatedAt),
+		Error:             e.LastError,
+	}
+	if e.FinishedAt != nil {
+		dto.FinishedAt = date.FormatDateTime(*e.FinishedAt)
+		if e.Status == dataexportrepo.StatusReady {
+			dto.AvailableUntil = date.FormatDateTime(e.FinishedAt.Add(retention))
+		}
+	}
+	if e.DownloadedAt != nil {
+		dto.DownloadedAt = date.FormatDateTime(*e.DownloadedAt)
+	}
+	return dto
+}
+
+// DownloadLinkDTO es un enlace de descarga de vigencia limitada.
+type DownloadLinkDTO struct {
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// DownloadDTO es el archivo que se entrega al descargar una exportación.
+type DownloadDTO struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,233 @@
+package exportdto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	dataexportrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/data_export_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+	"gorm.io/gorm"
+)
+
+// PersonalDataDTO es el contenido de una exportación: todo lo que se guarda del
+// usuario, en un formato estable para otros sistemas. Las fechas van en RFC3339.
+type PersonalDataDTO struct {
+	GeneratedAt    string             `json:"generated_at"`
+	Profile        ProfileDTO         `json:"profile"`
+	Enrollments    []EnrollmentDTO    `json:"enrollments"`
+	Conversations  []ConversationDTO  `json:"conversations"`
+	Insights       []InsightDTO       `json:"insights"`
+	Sessions       []SessionDTO       `json:"sessions"`        // Inicios de sesión por dispositivo
+	LinkedAccounts []LinkedAccountDTO `json:"linked_accounts"` // Cuentas institucionales (SSO)
+}
+
+// ProfileDTO son los datos de la cuenta (sin el hash de la contraseña).
+type ProfileDTO struct {
+	ID              uint   `json:"id"`
+	UserName        string `json:"user_name"`
+	Email           string `json:"email"`
+	Role            string `json:"role"`
+	Locale          string `json:"locale,omitempty"`
+	EmailVerifiedAt string `json:"email_verified_at,omitempty"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// EnrollmentDTO es una inscripción con los datos de su módulo.
+type EnrollmentDTO struct {
+	ModuleID   uint   `json:"module_id"`
+	ModuleCode string `json:"module_code"`
+	ModuleName string `json:"module_name"`
+	Status     string `json:"status"`
+	EnrolledAt string `json:"enrolled_at"`
+	DeletedAt  string `json:"deleted_at,omitempty"`
+}
+
+// ConversationDTO es un hilo con su resumen y todos sus mensajes.
+type ConversationDTO struct {
+	ID            uint         `json:"id"`
+	Title         string       `json:"title"`
+	AgentName     string       `json:"agent_name,omitempty"`
+	ModuleID      *uint        `json:"module_id,omitempty"`
+	CreatedAt     string       `json:"created_at"`
+	LastMessageAt string       `json:"last_message_at,omitempty"`
+	ArchivedAt    string       `json:"archived_at,omitempty"`
+	DeletedAt     string       `json:"deleted_at,omitempty"`
+	Summary       string       `json:"summary,omitempty"` // Resumen de los mensajes antiguos que arma la memoria del agente
+	Messages      []MessageDTO `json:"messages"`
+}
+
+// MessageDTO es un mensaje de un hilo.
+type MessageDTO struct {
+	ID         uint            `json:"id"`
+	Role       string          `json:"role"`
+	Name       string          `json:"name,omitempty"`
+	Content    string          `json:"content"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+	ToolCalls  json.RawMessage `json:"tool_calls,omitempty"`
+	CreatedAt  string          `json:"created_at"`
+	DeletedAt  string          `json:"deleted_at,omitempty"`
+	Embedding  []float32       `json:"embedding,omitempty"` // Solo si se pidió incluir embeddings
+}
+
+// InsightDTO es una observación del agente sobre el aprendizaje del estudiante.
+type InsightDTO struct {
+	ID        uint      `json:"id"`
+	Type      string    `json:"type"`
+	Content   string    `json:"content"`
+	CreatedAt string    `json:"created_at"`
+	UpdatedAt string    `json:"updated_at"`
+	DeletedAt string    `json:"deleted_at,omitempty"`
+	Embedding []float32 `json:"embedding,omitempty"` // Solo si se pidió incluir embeddings
+}
+
+// SessionDTO es una sesión abierta desde un dispositivo.
+type SessionDTO struct {
+	Device       string `json:"device,omitempty"`
+	UserAgent    string `json:"user_agent,omitempty"`
+	IPAddress    string `json:"ip_address,omitempty"`
+	CreatedAt    string `json:"created_at"`
+	LastSeenAt   string `json:"last_seen_at"`
+	ExpiresAt    string `json:"expires_at"`
+	RevokedAt    string `json:"revoked_at,omitempty"`
+	RevokeReason string `json:"revoke_reason,omitempty"`
+}
+
+// LinkedAccountDTO es una cuenta de un proveedor institucional vinculada.
+type LinkedAccountDTO struct {
+	Issuer      string `json:"issuer"`
+	Subject     string `json:"subject"`
+	Email       string `json:"email,omitempty"`
+	LinkedAt    string `json:"linked_at"`
+	LastLoginAt string `json:"last_login_at,omitempty"`
+}
+
+// FromUserData arma el contenido de la exportación (nil-safe).
+func FromUserData(data *dataexportrepo.UserData, generatedAt time.Time) PersonalDataDTO {
+	doc := PersonalDataDTO{
+		GeneratedAt:    date.FormatDateTime(generatedAt),
+		Enrollments:    []EnrollmentDTO{},
+		Conversations:  []ConversationDTO{},
+		Insights:       []InsightDTO{},
+		Sessions:       []SessionDTO{},
+		LinkedAccounts: []LinkedAccountDTO{},
+	}
+	if data == nil {
+		return doc
+	}
+
+	u := data.User
+	doc.Profile = ProfileDTO{
+		ID:              u.ID,
+		UserName:        u.UserName,
+		Email:           u.Email,
+		Role:            u.Role,
+		Locale:          u.Locale,
+		EmailVerifiedAt: formatOptional(u.EmailVerifiedAt),
+		CreatedAt:       date.FormatDateTime(u.CreatedAt),
+		UpdatedAt:       date.FormatDateTime(u.UpdatedAt),
+	}
+
+	for _, e := range data.Enrollments {
+		doc.Enrollments = append(doc.Enrollments, EnrollmentDTO{
+			ModuleID:   e.ModuleID,
+			ModuleCode: e.Module.Code,
+			ModuleName: e.Module.Name,
+			Status:     e.Status,
+			EnrolledAt: date.FormatDateTime(e.CreatedAt),
+			DeletedAt:  formatDeleted(e.DeletedAt),
+		})
+	}
+
+	summaries := make(map[uint]string, len(data.Summaries))
+	for _, s := range data.Summaries {
+		summaries[s.ConversationID] = s.Content
+	}
+	for _, s := range data.Sessions {
+		conversation := ConversationDTO{
+			ID:            s.ID,
+			Title:         s.Title,
+			AgentName:     s.AgentName,
+			ModuleID:      s.ModuleID,
+			CreatedAt:     date.FormatDateTime(s.CreatedAt),
+			LastMessageAt: formatOptional(s.LastMessageAt),
+			ArchivedAt:    formatOptional(s.ArchivedAt),
+			DeletedAt:     formatDeleted(s.DeletedAt),
+			Summary:       summaries[s.ID],
+			Messages:      make([]MessageDTO, 0, len(s.Messages)),
+		}
+		for _, m := range s.Messages {
+			conversation.Messages = append(conversation.Messages, fromMessage(m))
+		}
+		doc.Conversations = append(doc.Conversations, conversation)
+	}
+
+	for _, i := range data.Insights {
+		doc.Insights = append(doc.Insights, InsightDTO{
+			ID:        i.ID,
+			Type:      i.InsightType,
+			Content:   i.Content,
+			CreatedAt: date.FormatDateTime(i.CreatedAt),
+			UpdatedAt: date.FormatDateTime(i.UpdatedAt),
+			DeletedAt: formatDeleted(i.DeletedAt),
+			Embedding: i.Embedding.Slice(),
+		})
+	}
+
+	for _, s := range data.Devices {
+		doc.Sessions = append(doc.Sessions, SessionDTO{
+			Device:       s.Device,
+			UserAgent:    s.UserAgent,
+			IPAddress:    s.IPAddress,
+			CreatedAt:    date.FormatDateTime(s.CreatedAt),
+			LastSeenAt:   date.FormatDateTime(s.LastSeenAt),
+			ExpiresAt:    date.FormatDateTime(s.ExpiresAt),
+			RevokedAt:    formatOptional(s.RevokedAt),
+			RevokeReason: s.RevokeReason,
+		})
+	}
+
+	for _, i := range data.Identities {
+		doc.LinkedAccounts = append(doc.LinkedAccounts, LinkedAccountDTO{
+			Issuer:      i.Issuer,
+			Subject:     i.Subject,
+			Email:       i.Email,
+			LinkedAt:    date.FormatDateTime(i.CreatedAt),
+			LastLoginAt: formatOptional(i.LastLoginAt),
+		})
+	}
+
+	return doc
+}
+
+func fromMessage(m models.ChatMessage) MessageDTO {
+	dto := MessageDTO{
+		ID:         m.ID,
+		Role:       m.Role,
+		Name:       m.Name,
+		Content:    m.Content,
+		ToolCallID: m.ToolCallID,
+		CreatedAt:  date.FormatDateTime(m.CreatedAt),
+		DeletedAt:  formatDeleted(m.DeletedAt),
+		Embedding:  m.Embedding.Slice(),
+	}
+	if len(m.ToolCalls) > 0 && json.Valid(m.ToolCalls) {
+		dto.ToolCalls = json.RawMessage(m.ToolCalls)
+	}
+	return dto
+}
+
+func formatOptional(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return date.FormatDateTime(*t)
+}
+
+func formatDeleted(d gorm.DeletedAt) string {
+	if !d.Valid {
+		return ""
+	}
+	return date.FormatDateTime(d.Time)
+}
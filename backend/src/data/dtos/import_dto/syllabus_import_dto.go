@@ -0,0 +1,49 @@
+package importdto
+
+import "strings"
+
+// SyllabusImportRequestDTO acompaña al archivo (CSV o XLSX) subido por el profesor.
+// @Description SyllabusImportRequestDTO is used for importing modules and topics from a syllabus spreadsheet.
+type SyllabusImportRequestDTO struct {
+	FileName string `form:"file_name" json:"file_name" binding:"required" example:"programa_2025.xlsx"`
+	DryRun   bool   `form:"dry_run" json:"dry_run" example:"true"` // Validar sin guardar cambios
+}
+
+// GetFileName devuelve el nombre del archivo sin espacios sobrantes (helper nil-safe).
+func (d *SyllabusImportRequestDTO) GetFileName() string {
+	if d == nil {
+		return ""
+	}
+	return strings.TrimSpace(d.FileName)
+}
+
+// ImportRowErrorDTO describe un problema en una fila del archivo.
+type ImportRowErrorDTO struct {
+	Row     int    `json:"row" example:"7"`                  // Número de fila en el archivo (la cabecera es la 1)
+	Column  string `json:"column,omitempty" example:"fecha"` // Columna afectada, si aplica
+	Message string `json:"message" example:"fecha inválida, formato esperado YYYY-MM-DD"`
+}
+
+// SyllabusImportReportDTO resume el resultado (o la simulación) de una importación.
+type SyllabusImportReportDTO struct {
+	DryRun           bool                `json:"dry_run"`
+	Committed        bool                `json:"committed"` // true solo si los cambios se guardaron
+	TotalRows        int                 `json:"total_rows"`
+	ModulesCreated   int                 `json:"modules_created"`
+	ModulesUpdated   int                 `json:"modules_updated"`
+	TopicsCreated    int                 `json:"topics_created"`
+	TopicsUpdated    int                 `json:"topics_updated"`
+	TopicsUnchanged  int                 `json:"topics_unchanged"`
+	EmbeddingsQueued int                 `json:"embeddings_queued"`
+	Errors           []ImportRowErrorDTO `json:"errors,omitempty"`
+}
+
+// AddError agrega un error de fila al reporte.
+func (r *SyllabusImportReportDTO) AddError(row int, column, message string) {
+	r.Errors = append(r.Errors, ImportRowErrorDTO{Row: row, Column: column, Message: message})
+}
+
+// HasErrors indica si alguna fila tiene problemas.
+func (r *SyllabusImportReportDTO) HasErrors() bool {
+	return r != nil && len(r.Errors) > 0
+}
@@ -0,0 +1,196 @@
+package invitationdto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/dtos"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	invitationrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/invitation_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// Estados calculados de una invitación
+const (
+	StatusActive    = "active"
+	StatusRevoked   = "revoked"
+	StatusExpired   = "expired"
+	StatusExhausted = "exhausted"
+)
+
+// CreateInvitationRequestDTO represents the data required to create an invitation code.
+// @Description CreateInvitationRequestDTO is used by teachers and admins to create sign-up codes.
+type CreateInvitationRequestDTO struct {
+	CreatedByID uint   `json:"-"`                                                                     // Usuario autenticado
+	Code        string `json:"code,omitempty" binding:"omitempty,min=4,max=32" example:"INF101-2025"` // Vacío = se genera
+	ModuleID    *uint  `json:"module_id,omitempty" example:"2"`                                       // Inscribe automáticamente al registrarse
+	Role        string `json:"role,omitempty" binding:"omitempty,oneof=student teacher admin" example:"student"`
+	MaxUses     int    `json:"max_uses" binding:"min=0" example:"40"`               // 0 = sin límite
+	ExpiresAt   string `json:"expires_at,omitempty" example:"2025-03-31T23:59:59Z"` // RFC3339, vacío = no vence
+	Note        string `json:"note,omitempty" binding:"omitempty,max=200" example:"Sección diurna"`
+}
+
+// GetRole devuelve el rol de las cuentas creadas (por defecto student).
+func (d *CreateInvitationRequestDTO) GetRole() string {
+	if d == nil || d.Role == "" {
+		return "student"
+	}
+	return d.Role
+}
+
+// GetModuleID devuelve el módulo asociado o 0 (helper nil-safe).
+func (d *CreateInvitationRequestDTO) GetModuleID() uint {
+	if d == nil || d.ModuleID == nil {
+		return 0
+	}
+	return *d.ModuleID
+}
+
+// GetExpiresAt interpreta ExpiresAt; devuelve nil si no vence.
+func (d *CreateInvitationRequestDTO) GetExpiresAt() (*time.Time, error) {
+	if d == nil || strings.TrimSpace(d.ExpiresAt) == "" {
+		return nil, nil
+	}
+	t, err := date.ParseDateTime(strings.TrimSpace(d.ExpiresAt))
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListInvitationsRequestDTO representa los parámetros de consulta (query params).
+type ListInvitationsRequestDTO struct {
+	CreatedByID uint `form:"created_by_id" json:"created_by_id" example:"3"`
+	ModuleID    uint `form:"module_id" json:"module_id" example:"2"`
+	ActiveOnly  bool `form:"active_only" json:"active_only" example:"true"`
+	Limit       int  `form:"limit" json:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	Offset      int  `form:"offset" json:"offset" example:"0"`
+	dtos.ListRequestDTO
+}
+
+// ToRepoFilter convierte el DTO al filtro esperado por el repo.
+func (d *ListInvitationsRequestDTO) ToRepoFilter() invitationrepo.InvitationFilter {
+	limit := d.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	return invitationrepo.InvitationFilter{
+		CreatedByID: d.CreatedByID,
+		ModuleID:    d.ModuleID,
+		ActiveOnly:  d.ActiveOnly,
+		Spec:        d.ToSpec(),
+		Limit:       limit,
+		Offset:      d.Offset,
+	}
+}
+
+// InvitationDTO es la vista de un código de invitación.
+type InvitationDTO struct {
+	ID          uint   `json:"id"`
+	Code        string `json:"code" example:"K7Q2M9XH"`
+	Status      string `json:"status" example:"active"` // active | revoked | expired | exhausted
+	Role        string `json:"role" example:"student"`
+	ModuleID    uint   `json:"module_id,omitempty"`
+	ModuleCode  string `json:"module_code,omitempty"`
+	ModuleName  string `json:"module_name,omitempty"`
+	MaxUses     int    `json:"max_uses"`
+	Uses        int    `json:"uses"`
+	ExpiresAt   string `json:"expires_at,omitempty"` // RFC3339
+	RevokedAt   string `json:"revoked_at,omitempty"` // RFC3339
+	Note        string `json:"note,omitempty"`
+	CreatedByID uint   `json:"created_by_id"`
+	CreatedAt   string `json:"created_at"` // RFC3339
+}
+
+// FromInvitationModel convierte models.Invitation a InvitationDTO (nil-safe).
+func FromInvitationModel(inv *models.Invitation) InvitationDTO {
+	if inv == nil {
+		return InvitationDTO{}
+	}
+
+	dto := InvitationDTO{
+		ID:          inv.ID,
+		Code:        inv.Code,
+		Status:      status(inv),
+		Role:        inv.Role,
+		MaxUses:     inv.MaxUses,
+		Uses:        inv.Uses,
+		Note:        inv.Note,
+		CreatedByID: inv.CreatedByID,
+		CreatedAt:   date.FormatDateTime(inv.CreatedAt),
+	}
+	if inv.ModuleID != nil {
+		dto.ModuleID = *inv.ModuleID
+	}
+	if inv.Module != nil {
+		dto.ModuleCode = inv.Module.Code
+		dto.ModuleName = inv.Module.Name
+	}
+	if inv.ExpiresAt != nil {
+		dto.ExpiresAt = date.FormatDateTime(*inv.ExpiresAt)
+	}
+	if inv.RevokedAt != nil {
+		dto.RevokedAt = date.FormatDateTime(*inv.RevokedAt)
+	}
+	return dto
+}
+
+// MakeInvitationList convierte una lista de invitaciones.
+func MakeInvitationList(invitations []models.Invitation) []InvitationDTO {
+	out := make([]InvitationDTO, 0, len(invitations))
+	for i := range invitations {
+		out = append(out, FromInvitationModel(&invitations[i]))
+	}
+	return out
+}
+
+// status calcula el estado de la invitación en este momento.
+func status(inv *models.Invitation) string {
+	switch invitationrepo.Usable(inv, time.Now()) {
+	case invitationrepo.ErrInvitationRevoked:
+		return StatusRevoked
+	case invitationrepo.ErrInvitationExpired:
+		return StatusExpired
+	case invitationrepo.ErrInvitationExhausted:
+		return StatusExhausted
+	}
+	return StatusActive
+}
+
+// RedemptionDTO describe una cuenta creada con el código.
+type RedemptionDTO struct {
+	UserID       uint   `json:"user_id"`
+	UserName     string `json:"user_name"`
+	Email        string `json:"email"`
+	EnrollmentID uint   `json:"enrollment_id,omitempty"`
+	IPAddress    string `json:"ip_address,omitempty"`
+	CreatedAt    string `json:"created_at"` // RFC3339
+}
+
+// InvitationAuditDTO reúne la invitación y los registros hechos con ella.
+type InvitationAuditDTO struct {
+	Invitation  InvitationDTO   `json:"invitation"`
+	Redemptions []RedemptionDTO `json:"redemptions"`
+}
+
+// MakeInvitationAudit arma la auditoría de una invitación.
+func MakeInvitationAudit(inv *models.Invitation, redemptions []models.InvitationRedemption) InvitationAuditDTO {
+	audit := InvitationAuditDTO{
+		Invitation:  FromInvitationModel(inv),
+		Redemptions: make([]RedemptionDTO, 0, len(redemptions)),
+	}
+	for _, r := range redemptions {
+		dto := RedemptionDTO{
+			UserID:    r.UserID,
+			UserName:  r.User.UserName,
+			Email:     r.User.Email,
+			IPAddress: r.IPAddress,
+			CreatedAt: date.FormatDateTime(r.CreatedAt),
+		}
+		if r.EnrollmentID != nil {
+			dto.EnrollmentID = *r.EnrollmentID
+		}
+		audit.Redemptions = append(audit.Redemptions, dto)
+	}
+	return audit
+}
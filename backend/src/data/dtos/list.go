@@ -0,0 +1,51 @@
+package dtos
+
+import (
+	"strings"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// ListRequestDTO agrupa los query params comunes de orden y filtrado de los listados.
+type ListRequestDTO struct {
+	Sort           string `form:"sort" json:"sort" example:"-created_at"`                                                        // "campo" o "-campo" (descendente)
+	CreatedFrom    string `form:"created_from" json:"created_from" binding:"omitempty,datetime=2006-01-02" example:"2024-03-01"` // Inclusive
+	CreatedTo      string `form:"created_to" json:"created_to" binding:"omitempty,datetime=2006-01-02" example:"2024-03-31"`     // Inclusive
+	IncludeDeleted bool   `form:"include_deleted" json:"include_deleted" example:"false"`                                        // Solo listados de admin; ver ToAdminSpec
+}
+
+// ToSpec convierte los query params al Spec del repo (nil-safe). Ignora
+// include_deleted: lo eliminado solo lo ve un admin (ver ToAdminSpec).
+func (d *ListRequestDTO) ToSpec() listing.Spec {
+	if d == nil {
+		return listing.Spec{}
+	}
+	return listing.Spec{
+		Sort:    strings.TrimSpace(d.Sort),
+		Created: DateRange(d.CreatedFrom, d.CreatedTo),
+	}
+}
+
+// ToAdminSpec es ToSpec respetando include_deleted. Solo para listados
+// restringidos a admins, como la papelera.
+func (d *ListRequestDTO) ToAdminSpec() listing.Spec {
+	spec := d.ToSpec()
+	if d != nil {
+		spec.IncludeDeleted = d.IncludeDeleted
+	}
+	return spec
+}
+
+// DateRange convierte fechas "2006-01-02" inclusivas al rango [from, to+1día)
+// del repo. Una fecha vacía o inválida no limita ese extremo.
+func DateRange(from, to string) listing.DateRange {
+	var r listing.DateRange
+	if t, err := date.ParseDate(from); err == nil {
+		r.From = t
+	}
+	if t, err := date.ParseDate(to); err == nil {
+		r.To = t.AddDate(0, 0, 1)
+	}
+	return r
+}
@@ -0,0 +1,51 @@
+package dtos
+
+import (
+	"strings"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
+)
+
+// PageRequestDTO agrupa los query params de paginación por cursor de los listados.
+// Los cursores son opacos: el cliente solo reenvía los que recibió en PageInfoDTO.
+type PageRequestDTO struct {
+	Limit        int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	Next         string `form:"next" json:"next" example:""`
+	Prev         string `form:"prev" json:"prev" example:""`
+	IncludeTotal bool   `form:"include_total" json:"include_total" example:"false"`
+}
+
+// ToParams convierte los query params a los parámetros del repo (nil-safe).
+func (d *PageRequestDTO) ToParams() pagination.Params {
+	if d == nil {
+		return pagination.Params{}
+	}
+	return pagination.Params{
+		Limit:        d.Limit,
+		Next:         strings.TrimSpace(d.Next),
+		Prev:         strings.TrimSpace(d.Prev),
+		IncludeTotal: d.IncludeTotal,
+	}
+}
+
+// PageInfoDTO describe la página devuelta y los cursores para moverse desde ella.
+type PageInfoDTO struct {
+	Limit   int    `json:"limit"`
+	Next    string `json:"next,omitempty"` // Cursor para la página siguiente
+	Prev    string `json:"prev,omitempty"` // Cursor para la página anterior
+	HasNext bool   `json:"has_next"`
+	HasPrev bool   `json:"has_prev"`
+	Total   *int64 `json:"total,omitempty"` // Solo con include_total=true
+}
+
+// NewPageInfo arma el PageInfoDTO de una página del repo.
+func NewPageInfo[T any](page pagination.Page[T], params pagination.Params) PageInfoDTO {
+	return PageInfoDTO{
+		Limit:   params.PageSize(),
+		Next:    page.Next,
+		Prev:    page.Prev,
+		HasNext: page.HasNext,
+		HasPrev: page.HasPrev,
+		Total:   page.Total,
+	}
+}
@@ -0,0 +1,153 @@
+package rosterdto
+
+import (
+	"strings"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/dtos"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	rosterrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/roster_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// StartRosterImportRequestDTO acompaña al archivo de nómina (CSV o XLSX).
+// @Description StartRosterImportRequestDTO is used for bulk creating users and enrollments for a module.
+type StartRosterImportRequestDTO struct {
+	ModuleID    uint   `form:"module_id" json:"module_id" binding:"required" example:"2"`
+	FileName    string `form:"file_name" json:"file_name" binding:"required" example:"nomina_inf101.csv"`
+	Credentials string `form:"credentials" json:"credentials" binding:"omitempty,oneof=invite password" example:"invite"`
+	DropMissing bool   `form:"drop_missing" json:"drop_missing" example:"false"` // Dar de baja a quienes no aparecen
+	CreatedByID uint   `form:"-" json:"-"`                                       // Usuario autenticado
+}
+
+// GetCredentials devuelve el modo de credenciales (por defecto invitación).
+func (d *StartRosterImportRequestDTO) GetCredentials() string {
+	if d == nil || strings.TrimSpace(d.Credentials) == "" {
+		return rosterrepo.CredentialsInvite
+	}
+	return strings.TrimSpace(d.Credentials)
+}
+
+// ListRosterImportsRequestDTO representa los parámetros de consulta (query params).
+type ListRosterImportsRequestDTO struct {
+	ModuleID uint     `form:"module_id" json:"module_id" example:"2"`
+	Statuses []string `form:"status" json:"status" binding:"omitempty,dive,oneof=pending running completed failed" example:"failed"` // Repetible
+	Limit    int      `form:"limit" json:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	Offset   int      `form:"offset" json:"offset" example:"0"`
+	dtos.ListRequestDTO
+}
+
+// ToRepoFilter convierte el DTO al filtro esperado por el repo.
+func (d *ListRosterImportsRequestDTO) ToRepoFilter() rosterrepo.RosterImportFilter {
+	limit := d.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	return rosterrepo.RosterImportFilter{
+		ModuleID: d.ModuleID,
+		Statuses: d.Statuses,
+		Spec:     d.ToSpec(),
+		Limit:    limit,
+		Offset:   d.Offset,
+	}
+}
+
+// RosterRowResultDTO es el resultado de una fila de la nómina. InviteURL y
+// TemporaryPassword solo vienen en la respuesta que procesó la fila: no se guardan.
+type RosterRowResultDTO struct {
+	Row               int    `json:"row" example:"4"` // 0 = baja por no aparecer en la nómina
+	Email             string `json:"email" example:"ana.perez@aiep.cl"`
+	UserName          string `json:"user_name,omitempty" example:"ana.perez"`
+	Status            string `json:"status,omitempty" example:"active"`
+	Result            string `json:"result" example:"created"`
+	Action            string `json:"action,omitempty" example:"enrolled"`
+	UserID            uint   `json:"user_id,omitempty" example:"15"`
+	Message           string `json:"message,omitempty"`
+	InviteURL         string `json:"invite_url,omitempty"`
+	TemporaryPassword string `json:"temporary_password,omitempty"`
+}
+
+// RosterImportReportDTO resume el estado de una importación de nómina.
+type RosterImportReportDTO struct {
+	ID             uint                 `json:"id"`
+	ModuleID       uint                 `json:"module_id"`
+	FileName       string               `json:"file_name"`
+	Status         string               `json:"status"`
+	Credentials    string               `json:"credentials"`
+	DropMissing    bool                 `json:"drop_missing"`
+	TotalRows      int                  `json:"total_rows"`
+	ProcessedRows  int                  `json:"processed_rows"`
+	CreatedUsers   int                  `json:"created_users"`
+	MatchedUsers   int                  `json:"matched_users"`
+	Enrolled       int                  `json:"enrolled"`
+	StatusUpdated  int                  `json:"status_updated"`
+	Unchanged      int                  `json:"unchanged"`
+	DroppedMissing int                  `json:"dropped_missing"`
+	Errors         int                  `json:"errors"`
+	StartedAt      string               `json:"started_at,omitempty"`  // RFC3339
+	FinishedAt     string               `json:"finished_at,omitempty"` // RFC3339
+	LastError      string               `json:"last_error,omitempty"`
+	CreatedAt      string               `json:"created_at"` // RFC3339
+	Rows           []RosterRowResultDTO `json:"rows,omitempty"`
+}
+
+// FromRosterImportModel convierte una importación y sus filas al reporte (nil-safe).
+func FromRosterImportModel(job *models.RosterImport, rows []models.RosterImportRow) RosterImportReportDTO {
+	if job == nil {
+		return RosterImportReportDTO{}
+	}
+
+	dto := RosterImportReportDTO{
+		ID:             job.ID,
+		ModuleID:       job.ModuleID,
+		FileName:       job.FileName,
+		Status:         job.Status,
+		Credentials:    job.Credentials,
+		DropMissing:    job.DropMissing,
+		TotalRows:      job.TotalRows,
+		ProcessedRows:  job.ProcessedRows,
+		CreatedUsers:   job.CreatedUsers,
+		MatchedUsers:   job.MatchedUsers,
+		Enrolled:       job.Enrolled,
+		StatusUpdated:  job.StatusUpdated,
+		Unchanged:      job.Unchanged,
+		DroppedMissing: job.DroppedMissing,
+		Errors:         job.Errors,
+		LastError:      job.LastError,
+		CreatedAt:      date.FormatDateTime(job.CreatedAt),
+	}
+	if job.StartedAt != nil {
+		dto.StartedAt = date.FormatDateTime(*job.StartedAt)
+	}
+	if job.FinishedAt != nil {
+		dto.FinishedAt = date.FormatDateTime(*job.FinishedAt)
+	}
+
+	if len(rows) > 0 {
+		dto.Rows = make([]RosterRowResultDTO, 0, len(rows))
+		for i := range rows {
+			dto.Rows = append(dto.Rows, FromRosterRowModel(&rows[i]))
+		}
+	}
+	return dto
+}
+
+// FromRosterRowModel convierte models.RosterImportRow a RosterRowResultDTO (nil-safe).
+func FromRosterRowModel(row *models.RosterImportRow) RosterRowResultDTO {
+	if row == nil {
+		return RosterRowResultDTO{}
+	}
+
+	dto := RosterRowResultDTO{
+		Row:      row.RowNumber,
+		Email:    row.Email,
+		UserName: row.UserName,
+		Status:   row.Status,
+		Result:   row.Result,
+		Action:   row.Action,
+		Message:  row.Message,
+	}
+	if row.UserID != nil {
+		dto.UserID = *row.UserID
+	}
+	return dto
+}
@@ -4,24 +4,30 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/dtos"
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
 )
 
 // TopicFilter representa los filtros para consultar topics en el repositorio.
 type TopicFilter struct {
-	ModuleID uint
-	Search   string
-	Limit    int
-	Offset   int
+	ModuleID  uint
+	Search    string
+	Scheduled listing.DateRange
+	Spec      listing.Spec
+	Page      pagination.Params
 }
 
 // ListTopicsRequestDTO representa los parámetros de consulta (query params).
 type ListTopicsRequestDTO struct {
-	ModuleID uint   `form:"module_id" json:"module_id" example:"1"`
-	Search   string `form:"search" json:"search" example:"historia"`
-	Limit    int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100" example:"20"`
-	Offset   int    `form:"offset" json:"offset" example:"0"`
+	ModuleID      uint   `form:"module_id" json:"module_id" example:"1"`
+	Search        string `form:"search" json:"search" example:"historia"`
+	ScheduledFrom string `form:"scheduled_from" json:"scheduled_from" binding:"omitempty,datetime=2006-01-02" example:"2024-03-01"` // Inclusive
+	ScheduledTo   string `form:"scheduled_to" json:"scheduled_to" binding:"omitempty,datetime=2006-01-02" example:"2024-07-31"`     // Inclusive
+	dtos.ListRequestDTO
+	dtos.PageRequestDTO
 }
 
 // Getters nil-safe / normalización
@@ -39,30 +45,14 @@ func (d *ListTopicsRequestDTO) GetSearch() string {
 	return strings.TrimSpace(d.Search)
 }
 
-func (d *ListTopicsRequestDTO) GetLimit() int {
-	if d == nil || d.Limit <= 0 {
-		return 20 // default
-	}
-	if d.Limit > 100 {
-		return 100 // cap
-	}
-	return d.Limit
-}
-
-func (d *ListTopicsRequestDTO) GetOffset() int {
-	if d == nil || d.Offset < 0 {
-		return 0
-	}
-	return d.Offset
-}
-
 // ToRepoFilter convierte el DTO al filtro esperado por el repo.
 func (d *ListTopicsRequestDTO) ToRepoFilter() TopicFilter {
 	return TopicFilter{
-		ModuleID: d.GetModuleID(),
-		Search:   d.GetSearch(),
-		Limit:    d.GetLimit(),
-		Offset:   d.GetOffset(),
+		ModuleID:  d.GetModuleID(),
+		Search:    d.GetSearch(),
+		Scheduled: dtos.DateRange(d.ScheduledFrom, d.ScheduledTo),
+		Spec:      d.ToSpec(),
+		Page:      d.ToParams(),
 	}
 }
 
@@ -103,22 +93,19 @@ func FromModel(t *models.Topic) TopicListItemDTO {
 
 // ListTopicsResponseDTO envuelve la respuesta paginada.
 type ListTopicsResponseDTO struct {
-	Items  []TopicListItemDTO `json:"items"`
-	Limit  int                `json:"limit,omitempty"`
-	Offset int                `json:"offset,omitempty"`
-	// Total int64 `json:"total,omitempty"` // activa si el repo devuelve total
+	Items []TopicListItemDTO `json:"items"`
+	dtos.PageInfoDTO
 }
 
-// MakeListResponse mapea la lista de modelos a la respuesta DTO.
-func MakeListResponse(topics []models.Topic, req *ListTopicsRequestDTO) ListTopicsResponseDTO {
-	items := make([]TopicListItemDTO, 0, len(topics))
-	for i := range topics {
-		t := topics[i]
+// MakeListResponse mapea una página del repo a la respuesta DTO.
+func MakeListResponse(page pagination.Page[models.Topic], req *ListTopicsRequestDTO) ListTopicsResponseDTO {
+	items := make([]TopicListItemDTO, 0, len(page.Items))
+	for i := range page.Items {
+		t := page.Items[i]
 		items = append(items, FromModel(&t))
 	}
 	return ListTopicsResponseDTO{
-		Items:  items,
-		Limit:  req.GetLimit(),
-		Offset: req.GetOffset(),
+		Items:       items,
+		PageInfoDTO: dtos.NewPageInfo(page, req.ToParams()),
 	}
 }
@@ -0,0 +1,121 @@
+package trashdto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/dtos"
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+	"gorm.io/gorm"
+)
+
+// Entidades con papelera
+const (
+	EntityUsers   = "users"
+	EntityModules = "modules"
+	EntityTopics  = "topics"
+)
+
+// ListTrashRequestDTO representa los parámetros de consulta de la papelera.
+type ListTrashRequestDTO struct {
+	Entity string `form:"entity" json:"entity" binding:"required,oneof=users modules topics" example:"users"`
+	Search string `form:"search" json:"search" example:"juan"`
+	Sort   string `form:"sort" json:"sort" example:"-deleted_at"` // Por defecto, lo eliminado más reciente primero
+	dtos.PageRequestDTO
+}
+
+// GetSearch devuelve el texto de búsqueda sin espacios sobrantes (helper nil-safe).
+func (d *ListTrashRequestDTO) GetSearch() string {
+	if d == nil {
+		return ""
+	}
+	return strings.TrimSpace(d.Search)
+}
+
+// ToSpec devuelve el Spec del repo: solo eliminados, por defecto por fecha de eliminación.
+func (d *ListTrashRequestDTO) ToSpec() listing.Spec {
+	sort := "-deleted_at"
+	if d != nil && strings.TrimSpace(d.Sort) != "" {
+		sort = strings.TrimSpace(d.Sort)
+	}
+	return listing.Spec{Sort: sort, OnlyDeleted: true}
+}
+
+// RestoreRequestDTO identifica el registro a restaurar.
+type RestoreRequestDTO struct {
+	Entity string `json:"entity" binding:"required,oneof=users modules topics" example:"users"`
+	ID     uint   `json:"id" binding:"required" example:"12"`
+}
+
+// TrashItemDTO representa un registro de la papelera.
+type TrashItemDTO struct {
+	Entity    string `json:"entity"`
+	ID        uint   `json:"id"`
+	Label     string `json:"label"`            // Nombre de usuario, código del módulo o título del tema
+	Detail    string `json:"detail,omitempty"` // Email, nombre del módulo o fecha programada del tema
+	DeletedAt string `json:"deleted_at,omitempty"`
+	PurgeAt   string `json:"purge_at,omitempty"` // Desde cuándo puede borrarse definitivamente
+}
+
+// FromUser convierte un usuario a TrashItemDTO (nil-safe).
+func FromUser(u *models.User, retention time.Duration) TrashItemDTO {
+	if u == nil {
+		return TrashItemDTO{}
+	}
+	item := TrashItemDTO{Entity: EntityUsers, ID: u.ID, Label: u.UserName, Detail: u.Email}
+	setDeleted(&item, u.DeletedAt, retention)
+	return item
+}
+
+// FromModule convierte un módulo a TrashItemDTO (nil-safe).
+func FromModule(m *models.Module, retention time.Duration) TrashItemDTO {
+	if m == nil {
+		return TrashItemDTO{}
+	}
+	item := TrashItemDTO{Entity: EntityModules, ID: m.ID, Label: m.Code, Detail: m.Name}
+	setDeleted(&item, m.DeletedAt, retention)
+	return item
+}
+
+// FromTopic convierte un tema a TrashItemDTO (nil-safe).
+func FromTopic(t *models.Topic, retention time.Duration) TrashItemDTO {
+	if t == nil {
+		return TrashItemDTO{}
+	}
+	item := TrashItemDTO{Entity: EntityTopics, ID: t.ID, Label: t.UnitTitle}
+	if !time.Time(t.ScheduledDate).IsZero() {
+		item.Detail = date.FormatDate(time.Time(t.ScheduledDate))
+	}
+	setDeleted(&item, t.DeletedAt, retention)
+	return item
+}
+
+// setDeleted completa las fechas de eliminación y de purga.
+func setDeleted(item *TrashItemDTO, deletedAt gorm.DeletedAt, retention time.Duration) {
+	if !deletedAt.Valid {
+		return
+	}
+	item.DeletedAt = date.FormatDateTime(deletedAt.Time)
+	item.PurgeAt = date.FormatDateTime(deletedAt.Time.Add(retention))
+}
+
+// ListTrashResponseDTO envuelve una página de la papelera.
+type ListTrashResponseDTO struct {
+	Items []TrashItemDTO `json:"items"`
+	dtos.PageInfoDTO
+}
+
+// MakeListTrashResponse mapea una página del repo a la respuesta DTO.
+func MakeListTrashResponse[T any](page pagination.Page[T], req *ListTrashRequestDTO, toItem func(*T) TrashItemDTO) ListTrashResponseDTO {
+	items := make([]TrashItemDTO, 0, len(page.Items))
+	for i := range page.Items {
+		items = append(items, toItem(&page.Items[i]))
+	}
+	return ListTrashResponseDTO{
+		Items:       items,
+		PageInfoDTO: dtos.NewPageInfo(page, req.ToParams()),
+	}
+}
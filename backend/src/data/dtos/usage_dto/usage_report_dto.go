@@ -0,0 +1,114 @@
+package usagedto
+
+import (
+	"fmt"
+	"time"
+
+	usagerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/usage_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// UsageReportRequestDTO representa los parámetros de consulta (query params) del reporte.
+type UsageReportRequestDTO struct {
+	GroupBy  string `form:"group_by" json:"group_by" binding:"required,oneof=user module" example:"module"`
+	UserID   uint   `form:"user_id" json:"user_id" example:"1"`
+	ModuleID uint   `form:"module_id" json:"module_id" example:"2"`
+	From     string `form:"from" json:"from" binding:"omitempty,datetime=2006-01-02" example:"2025-03-01"`
+	To       string `form:"to" json:"to" binding:"omitempty,datetime=2006-01-02" example:"2025-03-31"` // Inclusive
+}
+
+// ToRepoFilter convierte el DTO al filtro del repo. To es inclusivo en el DTO
+// y exclusivo en el repo, por eso se suma un día.
+func (d *UsageReportRequestDTO) ToRepoFilter() (usagerepo.UsageFilter, error) {
+	if d == nil {
+		return usagerepo.UsageFilter{}, fmt.Errorf("empty dto")
+	}
+
+	filter := usagerepo.UsageFilter{
+		UserID:   d.UserID,
+		ModuleID: d.ModuleID,
+		GroupBy:  d.GroupBy,
+	}
+
+	if d.From != "" {
+		from, err := date.ParseDate(d.From)
+		if err != nil {
+			return usagerepo.UsageFilter{}, fmt.Errorf("invalid from: %w", err)
+		}
+		filter.From = from
+	}
+	if d.To != "" {
+		to, err := date.ParseDate(d.To)
+		if err != nil {
+			return usagerepo.UsageFilter{}, fmt.Errorf("invalid to: %w", err)
+		}
+		filter.To = to.Add(24 * time.Hour)
+	}
+
+	return filter, nil
+}
+
+// UsageReportItemDTO es una fila agregada por usuario o por módulo.
+type UsageReportItemDTO struct {
+	Key              uint    `json:"key" example:"2"` // user_id o module_id según group_by
+	Calls            int64   `json:"calls" example:"120"`
+	PromptTokens     int64   `json:"prompt_tokens" example:"95000"`
+	CompletionTokens int64   `json:"completion_tokens" example:"21000"`
+	TotalTokens      int64   `json:"total_tokens" example:"116000"`
+	CostUSD          float64 `json:"cost_usd" example:"0.027"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms" example:"1830.5"`
+}
+
+// UsageReportResponseDTO envuelve el reporte agregado.
+type UsageReportResponseDTO struct {
+	GroupBy string               `json:"group_by"`
+	From    string               `json:"from,omitempty"`
+	To      string               `json:"to,omitempty"`
+	Items   []UsageReportItemDTO `json:"items"`
+	Totals  UsageReportItemDTO   `json:"totals"`
+}
+
+// MakeUsageReportResponse mapea las filas del repo a la respuesta DTO y calcula los totales.
+func MakeUsageReportResponse(rows []usagerepo.UsageAggregate, req *UsageReportRequestDTO) UsageReportResponseDTO {
+	resp := UsageReportResponseDTO{
+		GroupBy: req.GroupBy,
+		From:    req.From,
+		To:      req.To,
+		Items:   make([]UsageReportItemDTO, 0, len(rows)),
+	}
+
+	var latencyWeighted float64
+	for _, row := range rows {
+		resp.Items = append(resp.Items, UsageReportItemDTO{
+			Key:              row.Key,
+			Calls:            row.Calls,
+			PromptTokens:     row.PromptTokens,
+			CompletionTokens: row.CompletionTokens,
+			TotalTokens:      row.TotalTokens,
+			CostUSD:          row.CostUSD,
+			AvgLatencyMs:     row.AvgLatencyMs,
+		})
+		resp.Totals.Calls += row.Calls
+		resp.Totals.PromptTokens += row.PromptTokens
+		resp.Totals.CompletionTokens += row.CompletionTokens
+		resp.Totals.TotalTokens += row.TotalTokens
+		resp.Totals.CostUSD += row.CostUSD
+		latencyWeighted += row.AvgLatencyMs * float64(row.Calls)
+	}
+	if resp.Totals.Calls > 0 {
+		resp.Totals.AvgLatencyMs = latencyWeighted / float64(resp.Totals.Calls)
+	}
+
+	return resp
+}
+
+// QuotaStatusDTO muestra al usuario cuánto le queda de su cuota.
+type QuotaStatusDTO struct {
+	Role            string `json:"role" example:"student"`
+	DailyUsed       int64  `json:"daily_used" example:"12000"`
+	DailyLimit      int64  `json:"daily_limit,omitempty" example:"50000"` // 0 = sin límite
+	DailyResetsAt   string `json:"daily_resets_at" example:"2025-03-02T00:00:00Z"`
+	MonthlyUsed     int64  `json:"monthly_used" example:"240000"`
+	MonthlyLimit    int64  `json:"monthly_limit,omitempty" example:"1000000"` // 0 = sin límite
+	MonthlyResetsAt string `json:"monthly_resets_at" example:"2025-04-01T00:00:00Z"`
+}
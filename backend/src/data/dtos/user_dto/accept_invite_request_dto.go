@@ -0,0 +1,24 @@
+package userdto
+
+// AcceptInviteRequestDTO represents the data required to activate an invited account.
+// @Description AcceptInviteRequestDTO is used for setting the password of an account created by a roster import.
+type AcceptInviteRequestDTO struct {
+	Token    string `json:"token" binding:"required" example:"Q2hhbmdlTWUtSW52aXRlVG9rZW4"`
+	Password string `json:"password" binding:"required,min=8,max=128" example:"securePassword!"`
+}
+
+// GetToken devuelve el token de invitación (helper nil-safe).
+func (d *AcceptInviteRequestDTO) GetToken() string {
+	if d == nil {
+		return ""
+	}
+	return d.Token
+}
+
+// GetPassword devuelve la contraseña elegida (helper nil-safe).
+func (d *AcceptInviteRequestDTO) GetPassword() string {
+	if d == nil {
+		return ""
+	}
+	return d.Password
+}
@@ -8,7 +8,7 @@ import (
 // @Description CreateUserDTO is used for creating a new user in the system.
 type CreateUserDTO struct {
 	UserName string `json:"user_name" binding:"required,min=3,max=50" example:"juan123"`
-	Password string `json:"password" binding:"required,min=6,max=100" example:"securePassword!"`
+	Password string `json:"password" binding:"required,min=8,max=128" example:"securePassword!"`
 	Role     string `json:"role" binding:"required,oneof=student teacher admin" example:"student"`
 	Email    string `json:"email" binding:"required,email" example:"juan@example.com"`
 }
@@ -0,0 +1,21 @@
+package userdto
+
+// VerifyEmailRequestDTO represents the token received in the verification link.
+// @Description VerifyEmailRequestDTO is used for confirming ownership of the account email.
+type VerifyEmailRequestDTO struct {
+	Token string `json:"token" form:"token" binding:"required" example:"eyJwIjoidmVyaWZ5X2VtYWlsIn0.c2lnbmF0dXJl"`
+}
+
+// ResendVerificationRequestDTO represents a request for a new verification link without a session.
+// @Description ResendVerificationRequestDTO is used when login is blocked until the email is verified.
+type ResendVerificationRequestDTO struct {
+	Email string `json:"email" binding:"required,email" example:"juan@example.com"`
+}
+
+// UpdateEmailRequestDTO represents the data required to change the account email.
+// @Description UpdateEmailRequestDTO is used for changing a user's email; the new email must be verified again.
+type UpdateEmailRequestDTO struct {
+	UserID   uint   `json:"user_id" binding:"required" example:"1"`
+	NewEmail string `json:"new_email" binding:"required,email" example:"juan.nuevo@example.com"`
+	Password string `json:"password" binding:"required" example:"securePassword!"` // Contraseña actual
+}
@@ -1,27 +1,29 @@
 package userdto
 
 import (
+	"github.com/Dieg0Code/aiep-agent/src/data/dtos"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
 	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
 )
 
 // ListUsersRequestDTO representa los parámetros de consulta (query params).
 type ListUsersRequestDTO struct {
-	Role   string `form:"role" json:"role" example:"student"`
-	Search string `form:"search" json:"search" example:"juan"`
-	Limit  int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100" example:"20"`
-	Offset int    `form:"offset" json:"offset" example:"0"`
+	Roles  []string `form:"role" json:"role" binding:"omitempty,dive,oneof=student teacher admin" example:"student"` // Repetible: ?role=student&role=teacher
+	Search string   `form:"search" json:"search" example:"juan"`
+	dtos.ListRequestDTO
+	dtos.PageRequestDTO
 }
 
-// ToRepoFilter convierte el DTO al filtro esperado por el repo.
+// ToRepoFilter convierte el DTO al filtro esperado por el repo. El listado de
+// usuarios es de admins, así que respeta include_deleted.
 func (q *ListUsersRequestDTO) ToRepoFilter() userrepo.UserFilter {
 	return userrepo.UserFilter{
-		Role:   q.Role,
+		Roles:  q.Roles,
 		Search: q.Search,
-		Limit:  q.Limit,
-		Offset: q.Offset,
-		// Nota: si userrepo.UserFilter se expande (Order/IncludeDeleted), mapea aquí.
+		Spec:   q.ToAdminSpec(),
+		Page:   q.ToParams(),
 	}
 }
 
@@ -33,40 +35,41 @@ type UserListItemDTO struct {
 	Role      string `json:"role"`
 	CreatedAt string `json:"created_at"`
 	Deleted   bool   `json:"deleted,omitempty"`
+	DeletedAt string `json:"deleted_at,omitempty"` // RFC3339; solo con include_deleted
 }
 
 // FromModel convierte models.User a UserListItemDTO.
 func FromModel(u *models.User) UserListItemDTO {
-	return UserListItemDTO{
+	item := UserListItemDTO{
 		ID:        u.ID,
 		Username:  u.UserName,
 		Email:     u.Email,
 		Role:      u.Role,
 		CreatedAt: date.FormatDateTime(u.CreatedAt),
-		Deleted:   u.DeletedAt.Valid, // asumiendo gorm.DeletedAt en models.User
+		Deleted:   u.DeletedAt.Valid,
 	}
+	if u.DeletedAt.Valid {
+		item.DeletedAt = date.FormatDateTime(u.DeletedAt.Time)
+	}
+	return item
 }
 
 // ListUsersResponseDTO envuelve la lista devuelta al cliente.
-// Nota: el repo actual no devuelve total; si quieres paginación total, agrega total al repo.
 type ListUsersResponseDTO struct {
-	Items  []UserListItemDTO `json:"items"`
-	Limit  int               `json:"limit,omitempty"`
-	Offset int               `json:"offset,omitempty"`
-	// Total int64 `json:"total,omitempty"` // activar si repo devuelve el total
+	Items []UserListItemDTO `json:"items"`
+	dtos.PageInfoDTO
 }
 
-// MakeListUsersResponse crea la respuesta a partir de modelos.
-func MakeListUsersResponse(users []models.User, req *ListUsersRequestDTO) ListUsersResponseDTO {
-	items := make([]UserListItemDTO, 0, len(users))
-	for i := range users {
-		u := users[i] // evitar &users[i] al iterar sobre el slice
+// MakeListUsersResponse crea la respuesta a partir de una página del repo.
+func MakeListUsersResponse(page pagination.Page[models.User], req *ListUsersRequestDTO) ListUsersResponseDTO {
+	items := make([]UserListItemDTO, 0, len(page.Items))
+	for i := range page.Items {
+		u := page.Items[i] // evitar &users[i] al iterar sobre el slice
 		items = append(items, FromModel(&u))
 	}
 	resp := ListUsersResponseDTO{
-		Items:  items,
-		Limit:  req.Limit,
-		Offset: req.Offset,
+		Items:       items,
+		PageInfoDTO: dtos.NewPageInfo(page, req.ToParams()),
 	}
 	return resp
 }
@@ -0,0 +1,45 @@
+package userdto
+
+import (
+	"strings"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	loginthrottlerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/login_throttle_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// UnlockLoginRequestDTO represents the email and/or IP to unlock.
+// @Description UnlockLoginRequestDTO is used by admins to clear failed login attempts.
+type UnlockLoginRequestDTO struct {
+	Email     string `json:"email,omitempty" binding:"omitempty,email" example:"juan@example.com"`
+	IPAddress string `json:"ip_address,omitempty" binding:"omitempty,ip" example:"203.0.113.7"`
+}
+
+// LockoutDTO describe un bloqueo vigente.
+type LockoutDTO struct {
+	Kind        string `json:"kind" example:"account"` // account | ip
+	Value       string `json:"value" example:"juan@example.com"`
+	Lockouts    int    `json:"lockouts" example:"1"`
+	LockedUntil string `json:"locked_until" example:"2025-03-01T12:15:00Z"` // RFC3339
+}
+
+// FromThrottleModel convierte models.LoginThrottle a LockoutDTO (nil-safe).
+func FromThrottleModel(t *models.LoginThrottle) LockoutDTO {
+	if t == nil {
+		return LockoutDTO{}
+	}
+
+	dto := LockoutDTO{Lockouts: t.Lockouts}
+	switch {
+	case strings.HasPrefix(t.Key, loginthrottlerepo.KeyAccount):
+		dto.Kind, dto.Value = "account", strings.TrimPrefix(t.Key, loginthrottlerepo.KeyAccount)
+	case strings.HasPrefix(t.Key, loginthrottlerepo.KeyIP):
+		dto.Kind, dto.Value = "ip", strings.TrimPrefix(t.Key, loginthrottlerepo.KeyIP)
+	default:
+		dto.Value = t.Key
+	}
+	if t.LockedUntil != nil {
+		dto.LockedUntil = date.FormatDateTime(*t.LockedUntil)
+	}
+	return dto
+}
@@ -5,8 +5,12 @@ import "strings"
 // LoginRequestDTO represents the data required for user login.
 // @Description LoginRequestDTO is used for authenticating a user.
 type LoginRequestDTO struct {
-	Email    string `json:"email" binding:"required,email" example:"juan@example.com"`
-	Password string `json:"password" binding:"required,min=6,max=100" example:"securePassword!"`
+	Email     string `json:"email" binding:"required,email" example:"juan@example.com"`
+	Password  string `json:"password" binding:"required,min=6,max=100" example:"securePassword!"`
+	OTP       string `json:"otp,omitempty" example:"123456"`                                          // Código TOTP o de recuperación, si la cuenta tiene doble factor
+	Device    string `json:"device,omitempty" binding:"omitempty,max=100" example:"Notebook de Juan"` // Nombre del dispositivo; si falta se deduce del navegador
+	IPAddress string `json:"-"`                                                                       // Lo completa el handler, para limitar intentos por IP
+	UserAgent string `json:"-"`                                                                       // Lo completa el handler, para describir la sesión
 }
 
 // GetEmail devuelve el email contenido en el DTO (helper nil-safe).
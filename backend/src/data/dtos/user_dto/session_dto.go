@@ -0,0 +1,60 @@
+package userdto
+
+import (
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// ListSessionsRequestDTO identifica al usuario y la sesión desde la que consulta.
+type ListSessionsRequestDTO struct {
+	UserID           uint `json:"-"` // Lo completa el handler desde la sesión
+	CurrentSessionID uint `json:"-"`
+}
+
+// RevokeSessionRequestDTO represents the session to close.
+// @Description RevokeSessionRequestDTO is used to close one of the user's sessions.
+type RevokeSessionRequestDTO struct {
+	UserID    uint `json:"-"`
+	SessionID uint `json:"session_id" binding:"required" example:"12"`
+}
+
+// RevokeAllSessionsRequestDTO represents a logout-everywhere request.
+// @Description RevokeAllSessionsRequestDTO closes every session of the user, optionally keeping the current one.
+type RevokeAllSessionsRequestDTO struct {
+	UserID           uint `json:"-"`
+	CurrentSessionID uint `json:"-"`
+	KeepCurrent      bool `json:"keep_current" example:"true"`
+}
+
+// SessionDTO describe una sesión activa.
+type SessionDTO struct {
+	ID         uint   `json:"id" example:"12"`
+	UserID     uint   `json:"user_id" example:"1"`
+	Device     string `json:"device" example:"Chrome en Windows"`
+	UserAgent  string `json:"user_agent" example:"Mozilla/5.0 (Windows NT 10.0; Win64; x64) ..."`
+	IPAddress  string `json:"ip_address" example:"203.0.113.7"`
+	CreatedAt  string `json:"created_at" example:"2025-03-01T12:00:00Z"`   // RFC3339
+	LastSeenAt string `json:"last_seen_at" example:"2025-03-01T12:30:00Z"` // RFC3339
+	ExpiresAt  string `json:"expires_at" example:"2025-03-31T12:00:00Z"`   // RFC3339
+	Current    bool   `json:"current" example:"true"`                      // Es la sesión desde la que se consulta
+	Scope      string `json:"scope,omitempty" example:"two_factor_setup"`  // Vacío = acceso completo
+}
+
+// FromSessionModel convierte models.UserSession a SessionDTO (nil-safe).
+func FromSessionModel(s *models.UserSession, currentID uint) SessionDTO {
+	if s == nil {
+		return SessionDTO{}
+	}
+	return SessionDTO{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		Device:     s.Device,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		CreatedAt:  date.FormatDateTime(s.CreatedAt),
+		LastSeenAt: date.FormatDateTime(s.LastSeenAt),
+		ExpiresAt:  date.FormatDateTime(s.ExpiresAt),
+		Current:    currentID != 0 && s.ID == currentID,
+		Scope:      s.Scope,
+	}
+}
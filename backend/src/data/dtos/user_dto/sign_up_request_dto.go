@@ -0,0 +1,23 @@
+package userdto
+
+import "github.com/Dieg0Code/aiep-agent/src/data/models"
+
+// SignUpRequestDTO represents the data required to self-register with an invitation code.
+// @Description SignUpRequestDTO is used on the sign-up screen; the code decides the role and module.
+type SignUpRequestDTO struct {
+	Code      string `json:"code" binding:"required,max=32" example:"K7Q2M9XH"`
+	UserName  string `json:"user_name" binding:"required,min=3,max=50" example:"juan123"`
+	Email     string `json:"email" binding:"required,email" example:"juan@example.com"`
+	Password  string `json:"password" binding:"required,min=8,max=128" example:"securePassword!"`
+	IPAddress string `json:"-"` // Lo completa el handler, para auditoría
+}
+
+// ToModelWithHash convierte el DTO a models.User con el rol de la invitación.
+func (d *SignUpRequestDTO) ToModelWithHash(role, hash string) *models.User {
+	return &models.User{
+		UserName:     d.UserName,
+		Email:        d.Email,
+		Role:         role,
+		PasswordHash: hash,
+	}
+}
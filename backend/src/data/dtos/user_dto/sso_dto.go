@@ -0,0 +1,34 @@
+package userdto
+
+// SSOStartDTO es la respuesta al iniciar el login con el proveedor institucional.
+type SSOStartDTO struct {
+	// AuthURL es la URL del proveedor a la que se redirige el navegador.
+	AuthURL string `json:"auth_url" example:"https://idp.example.com/authorize?client_id=..."`
+	// Transaction la guarda el handler en una cookie HttpOnly y la devuelve en el
+	// retorno; va cifrada y contiene el state, el nonce y el verificador PKCE.
+	Transaction string `json:"-"`
+}
+
+// SSOCallbackRequestDTO represents the provider redirect back to the app.
+// @Description SSOCallbackRequestDTO carries the authorization code returned by the identity provider.
+type SSOCallbackRequestDTO struct {
+	Code             string `form:"code" json:"code" example:"SplxlOBeZQQYbYS6WxSbIA"`
+	State            string `form:"state" json:"state" binding:"required" example:"af0ifjsldkj"`
+	Error            string `form:"error" json:"error,omitempty" example:"access_denied"`
+	ErrorDescription string `form:"error_description" json:"error_description,omitempty"`
+
+	Transaction string `json:"-"` // Cookie de SSOStartDTO.Transaction
+	Device      string `json:"-"`
+	IPAddress   string `json:"-"`
+	UserAgent   string `json:"-"`
+}
+
+// SSOTwoFactorRequestDTO represents the local second factor of an SSO login.
+// @Description SSOTwoFactorRequestDTO completes an SSO login of a role that requires two-factor authentication.
+type SSOTwoFactorRequestDTO struct {
+	Challenge string `json:"challenge" binding:"required"`             // UserDetailDTO.TwoFactorChallenge de CompleteSSO
+	Code      string `json:"code" binding:"required" example:"123456"` // Código TOTP o de recuperación
+	Device    string `json:"device,omitempty" binding:"omitempty,max=100" example:"Notebook de Juan"`
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
@@ -0,0 +1,42 @@
+package userdto
+
+// TwoFactorSetupDTO contiene lo necesario para registrar la cuenta en la app de
+// autenticación. El frontend muestra ProvisioningURI como código QR.
+type TwoFactorSetupDTO struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"` // Para ingresarlo a mano
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/AIEP%20Agent:juan@example.com?secret=JBSWY3DPEHPK3PXP&issuer=AIEP+Agent"`
+}
+
+// ConfirmTwoFactorRequestDTO represents the first code generated by the authenticator app.
+// @Description ConfirmTwoFactorRequestDTO is used for activating two-factor authentication.
+type ConfirmTwoFactorRequestDTO struct {
+	UserID uint   `json:"user_id" binding:"required" example:"1"`
+	Code   string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
+
+// RegenerateRecoveryCodesRequestDTO represents a current code needed to issue new recovery codes.
+// @Description RegenerateRecoveryCodesRequestDTO invalidates the previous recovery codes.
+type RegenerateRecoveryCodesRequestDTO struct {
+	UserID uint   `json:"user_id" binding:"required" example:"1"`
+	Code   string `json:"code" binding:"required" example:"123456"` // Código TOTP o de recuperación
+}
+
+// DisableTwoFactorRequestDTO represents the data required to turn off two-factor authentication.
+// @Description DisableTwoFactorRequestDTO requires the password and a current code.
+type DisableTwoFactorRequestDTO struct {
+	UserID   uint   `json:"user_id" binding:"required" example:"1"`
+	Password string `json:"password" binding:"required" example:"securePassword!"`
+	Code     string `json:"code" binding:"required" example:"123456"` // Código TOTP o de recuperación
+}
+
+// RecoveryCodesDTO lista los códigos de recuperación. Solo se muestran al generarlos.
+type RecoveryCodesDTO struct {
+	Codes []string `json:"codes" example:"K7Q2M-9XHPA"`
+}
+
+// TwoFactorStatusDTO resume el estado del doble factor de la cuenta.
+type TwoFactorStatusDTO struct {
+	Enabled           bool  `json:"enabled"`
+	Required          bool  `json:"required"` // El rol de la cuenta lo exige
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
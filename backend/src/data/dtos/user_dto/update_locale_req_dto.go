@@ -0,0 +1,18 @@
+package userdto
+
+import "strings"
+
+// UpdateLocaleRequestDTO represents the user's preferred language.
+// @Description UpdateLocaleRequestDTO sets the language of messages; empty uses the browser's Accept-Language.
+type UpdateLocaleRequestDTO struct {
+	UserID uint   `json:"-"` // Lo completa el handler con el usuario autenticado
+	Locale string `json:"locale" binding:"omitempty,oneof=es en" example:"en"`
+}
+
+// GetLocale devuelve el idioma normalizado (helper nil-safe).
+func (d *UpdateLocaleRequestDTO) GetLocale() string {
+	if d == nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.ToLower(d.Locale))
+}
@@ -5,7 +5,10 @@ package userdto
 type UpdatePasswordRequestDTO struct {
 	UserID      uint   `json:"user_id" binding:"required" example:"1"`
 	OldPassword string `json:"old_password" binding:"required,min=6,max=100" example:"oldPassword!"`
-	NewPassword string `json:"new_password" binding:"required,min=6,max=100" example:"newSecurePassword!"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=128" example:"newSecurePassword!"`
+
+	// CurrentSessionID lo completa el handler: esa sesión se mantiene y el resto se cierra.
+	CurrentSessionID uint `json:"-"`
 }
 
 // GetUserID devuelve el id del usuario (helper nil-safe).
@@ -8,16 +8,33 @@ import (
 // UserDetailDTO representa la vista pública de un usuario individual
 // sin exponer campos sensibles como PasswordHash.
 type UserDetailDTO struct {
-	ID              uint   `json:"id"`
-	UserName        string `json:"user_name"`
-	Email           string `json:"email"`
-	Role            string `json:"role"`
-	CreatedAt       string `json:"created_at"`
-	UpdatedAt       string `json:"updated_at,omitempty"`
-	Deleted         bool   `json:"deleted,omitempty"`
-	ConversationID  *uint  `json:"conversation_id,omitempty"`
-	EnrollmentCount int    `json:"enrollment_count,omitempty"`
-	InsightsCount   int    `json:"insights_count,omitempty"`
+	ID                uint   `json:"id"`
+	UserName          string `json:"user_name"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Role              string `json:"role"`
+	Locale            string `json:"locale,omitempty"` // Idioma preferido; vacío = según el navegador
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at,omitempty"`
+	Deleted           bool   `json:"deleted,omitempty"`
+	ConversationCount int    `json:"conversation_count,omitempty"`
+	EnrollmentCount   int    `json:"enrollment_count,omitempty"`
+	InsightsCount     int    `json:"insights_count,omitempty"`
+
+	// Solo lo completan Authenticate y CompleteSSO: el rol exige doble factor y la
+	// cuenta aún no lo activó. La sesión abierta solo sirve para configurarlo (dura
+	// SetupSessionTTL); después hay que volver a iniciar sesión con el código.
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+
+	// Solo lo completa CompleteSSO cuando el rol exige doble factor, el proveedor no
+	// informó haberlo pedido y la cuenta lo tiene activado: no se abre sesión hasta
+	// enviar este desafío con el código a CompleteSSOTwoFactor.
+	TwoFactorChallenge string `json:"two_factor_challenge,omitempty"`
+
+	// Solo los completan los logins: el token de la sesión abierta (se muestra una
+	// única vez, en la base queda su hash) y su id.
+	SessionToken string `json:"session_token,omitempty"`
+	SessionID    uint   `json:"session_id,omitempty"`
 }
 
 // FromModel convierte models.User a UserDetailDTO (nil-safe).
@@ -26,12 +43,6 @@ func FromModelToDetail(u *models.User) UserDetailDTO {
 		return UserDetailDTO{}
 	}
 
-	var convID *uint
-	if u.Conversation != nil {
-		id := u.Conversation.ID
-		convID = &id
-	}
-
 	created := date.FormatDateTime(u.CreatedAt)
 	var updated string
 	if !u.UpdatedAt.IsZero() {
@@ -39,15 +50,17 @@ func FromModelToDetail(u *models.User) UserDetailDTO {
 	}
 
 	return UserDetailDTO{
-		ID:              u.ID,
-		UserName:        u.UserName,
-		Email:           u.Email,
-		Role:            u.Role,
-		CreatedAt:       created,
-		UpdatedAt:       updated,
-		Deleted:         u.DeletedAt.Valid,
-		ConversationID:  convID,
-		EnrollmentCount: len(u.Enrollments),
-		InsightsCount:   len(u.Insights),
+		ID:                u.ID,
+		UserName:          u.UserName,
+		Email:             u.Email,
+		EmailVerified:     u.EmailVerifiedAt != nil,
+		Role:              u.Role,
+		Locale:            u.Locale,
+		CreatedAt:         created,
+		UpdatedAt:         updated,
+		Deleted:           u.DeletedAt.Valid,
+		ConversationCount: len(u.Conversations),
+		EnrollmentCount:   len(u.Enrollments),
+		InsightsCount:     len(u.Insights),
 	}
 }
@@ -0,0 +1,9 @@
+package listing
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de validación
+	ErrInvalidSort      = apperror.New(apperror.Validation, "listing.invalid_sort", "listing error: no se puede ordenar por ese campo")
+	ErrInvalidDateRange = apperror.New(apperror.Validation, "listing.invalid_date_range", "listing error: la fecha inicial debe ser anterior a la final")
+)
@@ -0,0 +1,77 @@
+// Package listing reúne lo común a los listados de los repos: orden por una
+// lista blanca de columnas, rango de fechas de creación e inclusión de
+// registros eliminados (soft delete). La paginación va en el paquete pagination.
+package listing
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DateRange es un rango de fechas [From, To). Un extremo en cero no limita.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// IsZero indica si el rango no limita en ningún extremo.
+func (r DateRange) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// Validate rechaza rangos vacíos o invertidos.
+func (r DateRange) Validate() error {
+	if !r.From.IsZero() && !r.To.IsZero() && !r.From.Before(r.To) {
+		return ErrInvalidDateRange
+	}
+	return nil
+}
+
+// Apply agrega el rango sobre column.
+func (r DateRange) Apply(query *gorm.DB, column string) *gorm.DB {
+	if !r.From.IsZero() {
+		query = query.Where(column+" >= ?", r.From)
+	}
+	if !r.To.IsZero() {
+		query = query.Where(column+" < ?", r.To)
+	}
+	return query
+}
+
+// Spec son las opciones comunes de un listado.
+type Spec struct {
+	Sort           string    // "campo" o "-campo"; "" = orden por defecto del Schema
+	Created        DateRange // Rango sobre created_at
+	IncludeDeleted bool      // Incluir registros con soft delete
+	OnlyDeleted    bool      // Solo registros con soft delete (papelera); admite ordenar por deleted_at
+}
+
+// Apply valida el Spec contra el Schema, agrega el rango de creación y el
+// soft delete a query, y devuelve el orden a usar. No agrega el ORDER BY: lo
+// hace la paginación o el llamador.
+func (s Spec) Apply(query *gorm.DB, schema Schema) (*gorm.DB, Sort, error) {
+	// En la papelera deleted_at nunca es NULL, así que sirve como orden
+	if s.OnlyDeleted {
+		schema.Sortable = append(slices.Clip(schema.Sortable), "deleted_at")
+	}
+
+	sort, err := schema.ParseSort(s.Sort)
+	if err != nil {
+		return query, Sort{}, err
+	}
+	if err := s.Created.Validate(); err != nil {
+		return query, Sort{}, err
+	}
+
+	switch {
+	case s.OnlyDeleted:
+		query = query.Unscoped().Where(sort.qualify("deleted_at") + " IS NOT NULL")
+	case s.IncludeDeleted:
+		query = query.Unscoped()
+	}
+	query = s.Created.Apply(query, sort.qualify("created_at"))
+
+	return query, sort, nil
+}
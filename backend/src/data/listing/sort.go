@@ -0,0 +1,78 @@
+package listing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Sort es un orden ya validado contra el Schema del listado. El id se agrega
+// siempre como desempate para que el orden sea total.
+type Sort struct {
+	Table string // Prefijo de las columnas ("" si la consulta no tiene joins)
+	Field string // Columna de orden
+	Desc  bool
+}
+
+// Column devuelve la columna de orden, con prefijo si corresponde.
+func (s Sort) Column() string {
+	return s.qualify(s.Field)
+}
+
+// IDColumn devuelve la columna de desempate, con prefijo si corresponde.
+func (s Sort) IDColumn() string {
+	return s.qualify("id")
+}
+
+// OrderBy devuelve la cláusula ORDER BY. Los NULL van siempre al final.
+func (s Sort) OrderBy() string {
+	dir := "ASC"
+	if s.Desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, %s %s", s.Column(), dir, s.IDColumn(), dir)
+}
+
+// Reverse devuelve el mismo orden en sentido contrario.
+func (s Sort) Reverse() Sort {
+	s.Desc = !s.Desc
+	return s
+}
+
+// String devuelve el orden en el formato de los query params ("-campo" = descendente).
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+func (s Sort) qualify(column string) string {
+	if s.Table == "" {
+		return column
+	}
+	return s.Table + "." + column
+}
+
+// Schema describe qué admite el listado de una entidad.
+type Schema struct {
+	Table    string   // Prefijo de las columnas ("" si la consulta no tiene joins)
+	Sortable []string // Columnas permitidas para ordenar (lista blanca)
+	Default  string   // Orden si no se indica, p. ej. "-created_at"
+}
+
+// ParseSort interpreta "campo" (ascendente) o "-campo" (descendente). Solo se
+// aceptan columnas de la lista blanca; "" usa el orden por defecto.
+func (sc Schema) ParseSort(raw string) (Sort, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		raw = sc.Default
+	}
+
+	field, desc := strings.CutPrefix(raw, "-")
+	if !slices.Contains(sc.Sortable, field) {
+		return Sort{}, fmt.Errorf("%w: %q", ErrInvalidSort, field)
+	}
+
+	return Sort{Table: sc.Table, Field: field, Desc: desc}, nil
+}
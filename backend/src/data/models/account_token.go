@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccountToken es un token de un solo uso asociado a una cuenta (p. ej. el enlace de
+// invitación para que un estudiante importado defina su contraseña). Solo se guarda el hash.
+type AccountToken struct {
+	gorm.Model
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	Purpose   string     `json:"purpose" gorm:"type:varchar(30);not null;index"` // invite
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:ux_account_tokens_hash"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+
+	// Relación
+	User User `json:"user,omitzero"`
+}
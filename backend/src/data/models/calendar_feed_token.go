@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CalendarFeedToken permite suscribirse al calendario de un estudiante sin iniciar
+// sesión. Solo se guarda el hash del token; regenerarlo revoca el anterior.
+type CalendarFeedToken struct {
+	gorm.Model
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:ux_calendar_feed_tokens_hash"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" gorm:"index"`
+
+	// Relación
+	User User `json:"user,omitzero"`
+}
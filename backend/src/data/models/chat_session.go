@@ -1,15 +1,22 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
-// Conversation: hilo entre un estudiante y un agente
+// Conversation: hilo entre un estudiante y un agente.
+// Un usuario puede tener varios hilos, opcionalmente asociados a un módulo.
 type ChatSession struct {
 	gorm.Model
-	UserID    uint   `json:"user_id" gorm:"uniqueIndex"`                // Un único hilo por usuario
-	UserName  string `json:"user_name" gorm:"type:varchar(255);index"`  // Cache opcional del nombre de usuario
-	AgentName string `json:"agent_name" gorm:"type:varchar(255);index"` // Nombre del agente
+	UserID        uint       `json:"user_id" gorm:"index"`                      // Dueño del hilo
+	UserName      string     `json:"user_name" gorm:"type:varchar(255);index"`  // Cache opcional del nombre de usuario
+	AgentName     string     `json:"agent_name" gorm:"type:varchar(255);index"` // Nombre del agente
+	Title         string     `json:"title" gorm:"type:varchar(200)"`            // Título visible del hilo
+	ModuleID      *uint      `json:"module_id,omitempty" gorm:"index"`          // Módulo asociado (nil = hilo general)
+	ArchivedAt    *time.Time `json:"archived_at,omitempty" gorm:"index"`        // nil = activo
+	LastMessageAt *time.Time `json:"last_message_at,omitempty" gorm:"index"`    // Para ordenar la lista de hilos
 
-	Messages []ChatMessage `json:"messages"`
+	Messages []ChatMessage `json:"messages" gorm:"foreignKey:ConversationID"`
 }
@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// ChatSummary guarda el resumen acumulado de los mensajes antiguos de una conversación.
+// Se actualiza de forma incremental: cada pasada resume los mensajes posteriores a
+// CoveredUntilMessageID junto con el resumen anterior.
+type ChatSummary struct {
+	gorm.Model
+	ConversationID        uint   `json:"conversation_id" gorm:"uniqueIndex:ux_chat_summaries_conversation"` // Un resumen por conversación
+	Content               string `json:"content" gorm:"type:text"`                                          // Texto del resumen
+	CoveredUntilMessageID uint   `json:"covered_until_message_id" gorm:"index"`                             // Último mensaje incluido en el resumen
+	MessageCount          int    `json:"message_count"`                                                     // Total de mensajes resumidos hasta ahora
+}
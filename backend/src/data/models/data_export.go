@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DataExport es una exportación de los datos personales de un usuario
+// (portabilidad). La genera un worker en segundo plano; el ZIP queda en la fila
+// hasta que vence y se descarga con un token de vigencia limitada.
+type DataExport struct {
+	gorm.Model
+	// Un usuario no puede tener dos exportaciones en curso
+	UserID            uint       `json:"user_id" gorm:"not null;index;uniqueIndex:ux_data_exports_active,where:(status = 'pending' OR status = 'processing') AND deleted_at IS NULL"`
+	Status            string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"` // pending | processing | ready | failed | expired
+	IncludeEmbeddings bool       `json:"include_embeddings"`                                              // Por defecto los vectores no se exportan
+	Attempts          int        `json:"attempts" gorm:"not null;default:0"`
+	LastError         string     `json:"last_error,omitempty" gorm:"type:text"`
+	Archive           []byte     `json:"-" gorm:"type:bytea"` // ZIP generado; se vacía al vencer
+	SizeBytes         int64      `json:"size_bytes"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty" gorm:"index"`
+	// Enlace de descarga: solo se guarda el hash del token
+	TokenHash      string     `json:"-" gorm:"type:varchar(64);index"`
+	TokenExpiresAt *time.Time `json:"token_expires_at,omitempty"`
+	DownloadedAt   *time.Time `json:"downloaded_at,omitempty"` // Última descarga
+}
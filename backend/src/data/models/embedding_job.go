@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// EmbeddingJob es una solicitud pendiente de generar (o regenerar) el embedding de
+// una entidad. Las crea quien escribe el contenido, en la misma transacción, y las
+// procesa un worker en segundo plano.
+type EmbeddingJob struct {
+	gorm.Model
+	EntityType string `json:"entity_type" gorm:"type:varchar(30);not null;uniqueIndex:ux_embedding_jobs_entity"` // topic | chat_message
+	EntityID   uint   `json:"entity_id" gorm:"not null;uniqueIndex:ux_embedding_jobs_entity"`
+	Status     string `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"` // pending | processing | done | failed
+	Attempts   int    `json:"attempts" gorm:"not null;default:0"`
+	LastError  string `json:"last_error,omitempty" gorm:"type:text"`
+}
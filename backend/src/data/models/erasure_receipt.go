@@ -0,0 +1,19 @@
+package models
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ErasureReceipt es el comprobante de un borrado de datos personales. No guarda
+// datos del usuario borrado: solo su id, quién lo pidió y cuántas filas se
+// borraron o anonimizaron en cada tabla.
+type ErasureReceipt struct {
+	gorm.Model
+	UserID        uint           `json:"user_id" gorm:"not null;index"`
+	RequestedByID *uint          `json:"requested_by_id,omitempty" gorm:"index"`        // nil = purga automática de la papelera
+	Initiator     string         `json:"initiator" gorm:"type:varchar(20);not null"`    // self | admin | retention
+	UserOutcome   string         `json:"user_outcome" gorm:"type:varchar(20);not null"` // deleted | anonymized
+	Deleted       datatypes.JSON `json:"deleted" gorm:"type:jsonb"`                     // Filas borradas por tabla
+	Anonymized    datatypes.JSON `json:"anonymized" gorm:"type:jsonb"`                  // Filas anonimizadas por tabla
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ExternalIdentity vincula un User con su cuenta en un proveedor OpenID Connect
+// (issuer + sub). El sub es estable; el email se guarda solo como referencia porque
+// el proveedor puede cambiarlo.
+type ExternalIdentity struct {
+	ID          uint       `json:"id" gorm:"primarykey"`
+	UserID      uint       `json:"user_id" gorm:"not null;index"`
+	Issuer      string     `json:"issuer" gorm:"type:varchar(255);not null;uniqueIndex:ux_external_identities_subject,priority:1"`
+	Subject     string     `json:"subject" gorm:"type:varchar(255);not null;uniqueIndex:ux_external_identities_subject,priority:2"`
+	Email       string     `json:"email" gorm:"type:varchar(255)"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relación
+	User User `json:"user,omitzero"`
+}
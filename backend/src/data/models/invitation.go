@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Invitation es un código de registro creado por un profesor o admin. Puede estar
+// ligado a un módulo (el registro inscribe al estudiante), fijar el rol de la cuenta
+// y limitar la cantidad de usos y la vigencia.
+type Invitation struct {
+	gorm.Model
+	Code        string     `json:"code" gorm:"type:varchar(32);not null;uniqueIndex:ux_invitations_code"` // Siempre en mayúsculas
+	CreatedByID uint       `json:"created_by_id" gorm:"not null;index"`
+	ModuleID    *uint      `json:"module_id,omitempty" gorm:"index"`                        // nil = solo crea la cuenta
+	Role        string     `json:"role" gorm:"type:varchar(50);not null;default:'student'"` // Rol de las cuentas creadas
+	MaxUses     int        `json:"max_uses"`                                                // 0 = sin límite
+	Uses        int        `json:"uses" gorm:"not null;default:0"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // nil = no vence
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	RevokedByID *uint      `json:"revoked_by_id,omitempty"`
+	Note        string     `json:"note,omitempty" gorm:"type:varchar(200)"`
+
+	// Relaciones
+	CreatedBy   User                   `json:"created_by,omitzero"`
+	Module      *Module                `json:"module,omitempty"`
+	Redemptions []InvitationRedemption `json:"redemptions,omitempty" gorm:"foreignKey:InvitationID"`
+}
+
+// InvitationRedemption registra cada cuenta creada con un código (auditoría).
+type InvitationRedemption struct {
+	gorm.Model
+	InvitationID uint   `json:"invitation_id" gorm:"not null;index"`
+	UserID       uint   `json:"user_id" gorm:"not null;uniqueIndex:ux_invitation_redemptions_user"` // Una cuenta se crea con un solo código
+	EnrollmentID *uint  `json:"enrollment_id,omitempty"`
+	IPAddress    string `json:"ip_address,omitempty" gorm:"type:varchar(45)"`
+
+	// Relaciones
+	User User `json:"user,omitzero"`
+}
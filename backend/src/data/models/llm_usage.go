@@ -0,0 +1,18 @@
+package models
+
+import "gorm.io/gorm"
+
+// LLMUsage registra el consumo de una llamada al LLM (chat, resumen o embedding).
+type LLMUsage struct {
+	gorm.Model
+	UserID           uint    `json:"user_id" gorm:"index"`
+	ConversationID   uint    `json:"conversation_id" gorm:"index"`
+	ModuleID         uint    `json:"module_id" gorm:"index"` // 0 = sin módulo asociado
+	Operation        string  `json:"operation" gorm:"type:varchar(30);index"`
+	ModelName        string  `json:"model" gorm:"column:model;type:varchar(100);index"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	LatencyMs        int64   `json:"latency_ms"`
+	CostUSD          float64 `json:"cost_usd" gorm:"type:numeric(12,6)"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// LoginThrottle cuenta los intentos fallidos de inicio de sesión de una clave
+// ("account:<email>" o "ip:<dirección>"). Se indexa por email y no por usuario para
+// que un email inexistente se comporte igual que uno registrado. No usa soft delete:
+// desbloquear borra la fila.
+type LoginThrottle struct {
+	ID            uint       `json:"id" gorm:"primarykey"`
+	Key           string     `json:"key" gorm:"type:varchar(320);not null;uniqueIndex:ux_login_throttles_key"`
+	Failures      int        `json:"failures" gorm:"not null;default:0"` // Fallos dentro de la ventana actual
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty" gorm:"index"`
+	Lockouts      int        `json:"lockouts" gorm:"not null;default:0"` // Bloqueos acumulados (el siguiente dura más)
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
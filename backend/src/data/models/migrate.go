@@ -1,16 +1,180 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DefaultChatSessionTitle es el título que reciben los hilos sin nombre
+// (incluido el hilo único que tenían los usuarios antes de permitir varios).
+const DefaultChatSessionTitle = "Conversación principal"
 
 // AutoMigrateAll ejecuta las migraciones de todos los modelos
 func AutoMigrateAll(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := dropLegacyChatSessionUniqueIndex(db); err != nil {
+		return err
+	}
+	if err := dropFullSoftDeleteIndexes(db); err != nil {
+		return err
+	}
+	if err := dropNonCascadingTopicsConstraint(db); err != nil {
+		return err
+	}
+	// Se revisa antes de migrar: si la columna es nueva, las cuentas existentes se
+	// dan por verificadas para no bloquearlas al activar la verificación.
+	backfillVerified := db.Migrator().HasTable(&User{}) && !db.Migrator().HasColumn(&User{}, "EmailVerifiedAt")
+
+	err := db.AutoMigrate(
 		&User{},
 		&ChatSession{},
 		&ChatMessage{},
+		&ChatSummary{},
 		&Insight{},
 		&Module{},
 		&Topic{},
 		&Enrollment{},
+		&LLMUsage{},
+		&EmbeddingJob{},
+		&CalendarFeedToken{},
+		&AccountToken{},
+		&RosterImport{},
+		&RosterImportRow{},
+		&Invitation{},
+		&InvitationRedemption{},
+		&LoginThrottle{},
+		&TwoFactor{},
+		&RecoveryCode{},
+		&UserSession{},
+		&ExternalIdentity{},
+		&ErasureReceipt{},
+		&DataExport{},
 	)
+	if err != nil {
+		return err
+	}
+
+	if backfillVerified {
+		if err := db.Exec("UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL").Error; err != nil {
+			return fmt.Errorf("backfilling users email_verified_at: %w", err)
+		}
+	}
+
+	return backfillChatSessionThreads(db)
+}
+
+// dropLegacyChatSessionUniqueIndex elimina el índice único sobre chat_sessions.user_id
+// (un hilo por usuario). AutoMigrate no lo cambia por sí solo porque el índice
+// no único conserva el mismo nombre.
+func dropLegacyChatSessionUniqueIndex(db *gorm.DB) error {
+	const indexName = "idx_chat_sessions_user_id"
+
+	if !db.Migrator().HasTable(&ChatSession{}) || !db.Migrator().HasIndex(&ChatSession{}, indexName) {
+		return nil
+	}
+
+	var indexDef string
+	err := db.Raw("SELECT indexdef FROM pg_indexes WHERE indexname = ?", indexName).Scan(&indexDef).Error
+	if err != nil {
+		return fmt.Errorf("reading %s definition: %w", indexName, err)
+	}
+	if !strings.Contains(strings.ToUpper(indexDef), "UNIQUE") {
+		return nil
+	}
+
+	if err := db.Migrator().DropIndex(&ChatSession{}, indexName); err != nil {
+		return fmt.Errorf("dropping %s: %w", indexName, err)
+	}
+	return nil
+}
+
+// softDeleteIndexes son los índices únicos que solo consideran filas no
+// eliminadas, para que un registro en la papelera no retenga su nombre o código.
+var softDeleteIndexes = []struct {
+	model any
+	name  string
+}{
+	{&User{}, "ux_users_username"},
+	{&User{}, "ux_users_email"},
+	{&Module{}, "ux_modules_code"},
+}
+
+// dropFullSoftDeleteIndexes elimina las versiones antiguas (sin WHERE) de
+// softDeleteIndexes. AutoMigrate no las cambia por sí solo porque conservan el
+// nombre; al borrarlas, las recrea parciales.
+func dropFullSoftDeleteIndexes(db *gorm.DB) error {
+	for _, idx := range softDeleteIndexes {
+		if !db.Migrator().HasTable(idx.model) || !db.Migrator().HasIndex(idx.model, idx.name) {
+			continue
+		}
+
+		var indexDef string
+		err := db.Raw("SELECT indexdef FROM pg_indexes WHERE indexname = ?", idx.name).Scan(&indexDef).Error
+		if err != nil {
+			return fmt.Errorf("reading %s definition: %w", idx.name, err)
+		}
+		if strings.Contains(strings.ToUpper(indexDef), " WHERE ") {
+			continue
+		}
+
+		if err := db.Migrator().DropIndex(idx.model, idx.name); err != nil {
+			return fmt.Errorf("dropping %s: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+// dropNonCascadingTopicsConstraint elimina fk_modules_topics si se creó sin ON
+// DELETE CASCADE (antes la regla estaba en Topic.ModuleID, donde GORM la ignora).
+// AutoMigrate no cambia un FK existente; al borrarlo, lo recrea en cascada.
+func dropNonCascadingTopicsConstraint(db *gorm.DB) error {
+	const constraintName = "fk_modules_topics"
+
+	if !db.Migrator().HasTable(&Topic{}) || !db.Migrator().HasConstraint(&Module{}, "Topics") {
+		return nil
+	}
+
+	var onDelete string
+	err := db.Raw("SELECT confdeltype FROM pg_constraint WHERE conname = ?", constraintName).Scan(&onDelete).Error
+	if err != nil {
+		return fmt.Errorf("reading %s definition: %w", constraintName, err)
+	}
+	if onDelete == "c" {
+		return nil
+	}
+
+	if err := db.Migrator().DropConstraint(&Module{}, "Topics"); err != nil {
+		return fmt.Errorf("dropping %s: %w", constraintName, err)
+	}
+	return nil
+}
+
+// backfillChatSessionThreads convierte las sesiones existentes en el primer hilo
+// de cada usuario: les asigna título y la fecha de su último mensaje.
+func backfillChatSessionThreads(db *gorm.DB) error {
+	err := db.Exec(
+		"UPDATE chat_sessions SET title = ? WHERE title IS NULL OR title = ''",
+		DefaultChatSessionTitle,
+	).Error
+	if err != nil {
+		return fmt.Errorf("backfilling chat session titles: %w", err)
+	}
+
+	err = db.Exec(`
+		UPDATE chat_sessions s
+		SET last_message_at = m.last_at
+		FROM (
+			SELECT conversation_id, MAX(created_at) AS last_at
+			FROM chat_messages
+			WHERE deleted_at IS NULL
+			GROUP BY conversation_id
+		) m
+		WHERE m.conversation_id = s.id AND s.last_message_at IS NULL
+	`).Error
+	if err != nil {
+		return fmt.Errorf("backfilling chat session last_message_at: %w", err)
+	}
+
+	return nil
 }
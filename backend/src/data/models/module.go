@@ -5,11 +5,11 @@ import "gorm.io/gorm"
 // Module (módulo académico que cursa el estudiante)
 type Module struct {
 	gorm.Model
-	Code        string `json:"code" gorm:"type:varchar(50);uniqueIndex:ux_modules_code;not null"`
+	Code        string `json:"code" gorm:"type:varchar(50);uniqueIndex:ux_modules_code,where:deleted_at IS NULL;not null"`
 	Name        string `json:"name" gorm:"type:varchar(150);not null"`
 	Description string `json:"description" gorm:"type:varchar(300)"`
 
 	// Relaciones
-	Topics      []Topic      `json:"topics,omitempty"`
+	Topics      []Topic      `json:"topics,omitempty" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	Enrollments []Enrollment `json:"enrollments,omitempty"`
 }
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RosterImport es una importación de nómina (lista de estudiantes) para un módulo.
+// Es idempotente por módulo + hash del archivo y se puede reanudar: cada fila guarda
+// su resultado y solo se procesan las que siguen pendientes.
+type RosterImport struct {
+	gorm.Model
+	ModuleID    uint   `json:"module_id" gorm:"not null;index;uniqueIndex:ux_roster_imports_module_hash"`
+	FileHash    string `json:"file_hash" gorm:"type:varchar(64);not null;uniqueIndex:ux_roster_imports_module_hash"` // SHA-256 del archivo
+	FileName    string `json:"file_name" gorm:"type:varchar(255)"`
+	CreatedByID uint   `json:"created_by_id" gorm:"index"`
+	Credentials string `json:"credentials" gorm:"type:varchar(20);not null;default:'invite'"`   // invite | password
+	DropMissing bool   `json:"drop_missing"`                                                    // Dar de baja a quienes no aparecen en la nómina
+	Status      string `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"` // pending | running | completed | failed
+
+	// Contadores del reporte
+	TotalRows      int `json:"total_rows"`
+	ProcessedRows  int `json:"processed_rows"`
+	CreatedUsers   int `json:"created_users"`
+	MatchedUsers   int `json:"matched_users"`
+	Enrolled       int `json:"enrolled"`        // Inscripciones nuevas
+	StatusUpdated  int `json:"status_updated"`  // Inscripciones con estado modificado
+	Unchanged      int `json:"unchanged"`       // Inscripciones que ya estaban como se pedía
+	DroppedMissing int `json:"dropped_missing"` // Dados de baja por no aparecer en la nómina
+	Errors         int `json:"errors"`
+
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	LastError  string     `json:"last_error,omitempty" gorm:"type:text"`
+
+	// Relaciones
+	Module Module            `json:"module,omitzero"`
+	Rows   []RosterImportRow `json:"rows,omitempty" gorm:"foreignKey:ImportID;constraint:OnDelete:CASCADE"`
+}
+
+// RosterImportRow es el resultado de una fila de la nómina. Las bajas por ausencia
+// se registran como filas con RowNumber 0.
+type RosterImportRow struct {
+	gorm.Model
+	ImportID     uint   `json:"import_id" gorm:"not null;index"`
+	RowNumber    int    `json:"row_number"` // Fila en el archivo (la cabecera es la 1)
+	Email        string `json:"email" gorm:"type:varchar(255);index"`
+	UserName     string `json:"user_name" gorm:"type:varchar(255)"`
+	Status       string `json:"status" gorm:"type:varchar(20)"`                                  // Estado de inscripción pedido
+	Result       string `json:"result" gorm:"type:varchar(20);not null;default:'pending';index"` // pending | created | matched | dropped | error
+	Action       string `json:"action,omitempty" gorm:"type:varchar(20)"`                        // enrolled | updated | unchanged
+	UserID       *uint  `json:"user_id,omitempty" gorm:"index"`
+	EnrollmentID *uint  `json:"enrollment_id,omitempty"`
+	Message      string `json:"message,omitempty" gorm:"type:text"`
+}
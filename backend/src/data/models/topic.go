@@ -9,13 +9,17 @@ import (
 // Topic (tema dentro del módulo)
 type Topic struct {
 	gorm.Model
-	ModuleID      uint           `json:"module_id" gorm:"index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	ScheduledDate datatypes.Date `json:"scheduled_date" gorm:"type:date;not null;index"` // Fecha programada del tema
+	ModuleID      uint           `json:"module_id" gorm:"index;uniqueIndex:ux_topics_module_import_key"` // FK en cascada: ver Module.Topics
+	ScheduledDate datatypes.Date `json:"scheduled_date" gorm:"type:date;not null;index"`                 // Fecha programada del tema
 
 	UnitTitle string          `json:"unit_title" gorm:"type:varchar(200)"` // Título de la unidad
 	Content   string          `json:"content" gorm:"type:text"`            // Contenido del tema
 	Embedding pgvector.Vector `json:"embedding" gorm:"type:vector(1536)"`  // Embedding para búsquedas vectoriales
 
+	// Clave estable del tema dentro del módulo, usada por la importación de programas
+	// para actualizar en lugar de duplicar. Vacía en temas creados a mano.
+	ImportKey string `json:"import_key,omitempty" gorm:"type:varchar(150);uniqueIndex:ux_topics_module_import_key,where:import_key <> '' AND deleted_at IS NULL"`
+
 	// Relación
 	Module Module `json:"module,omitzero"`
 }
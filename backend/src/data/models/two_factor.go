@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TwoFactor es la configuración TOTP de una cuenta. El secreto se guarda cifrado
+// porque hay que recuperarlo para validar los códigos.
+type TwoFactor struct {
+	gorm.Model
+	UserID          uint       `json:"user_id" gorm:"not null;uniqueIndex:ux_two_factors_user"`
+	SecretSealed    string     `json:"-" gorm:"type:text;not null"`
+	ConfirmedAt     *time.Time `json:"confirmed_at,omitempty"`      // nil = enrolamiento sin confirmar
+	LastUsedCounter int64      `json:"-" gorm:"not null;default:0"` // Último paso aceptado (evita reutilizar un código)
+
+	// Relación
+	User User `json:"user,omitzero"`
+}
+
+// RecoveryCode es un código de recuperación de un solo uso. Solo se guarda el hash.
+type RecoveryCode struct {
+	gorm.Model
+	UserID   uint       `json:"user_id" gorm:"not null;index"`
+	CodeHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:ux_recovery_codes_hash"`
+	UsedAt   *time.Time `json:"used_at,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserSession es una sesión abierta al iniciar sesión (un dispositivo o navegador).
+// Solo se guarda el hash del token; revocarla la invalida aunque no haya vencido.
+type UserSession struct {
+	gorm.Model
+	UserID       uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash    string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:ux_user_sessions_token"`
+	Device       string     `json:"device" gorm:"type:varchar(100)"`
+	UserAgent    string     `json:"user_agent" gorm:"type:varchar(500)"`
+	IPAddress    string     `json:"ip_address" gorm:"type:varchar(45)"`
+	LastSeenAt   time.Time  `json:"last_seen_at" gorm:"not null"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null;index"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" gorm:"index"`
+	RevokeReason string     `json:"revoke_reason,omitempty" gorm:"type:varchar(30)"`             // logout, logout_all, admin, password_change
+	Scope        string     `json:"scope,omitempty" gorm:"type:varchar(30);not null;default:''"` // "" = acceso completo; two_factor_setup = solo configurar el doble factor
+
+	// Relación
+	User User `json:"user,omitzero"`
+}
@@ -1,18 +1,31 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // User representa a un estudiante, profesor o admin del sistema.
-// Mantiene un único hilo de conversación asociado (Conversation) vía user_id en esa tabla.
+// Puede tener varios hilos de conversación (Conversations) vía user_id en esa tabla.
 type User struct {
 	gorm.Model
-	UserName     string `json:"user_name" gorm:"type:varchar(255);not null;uniqueIndex:ux_users_username"`
+	UserName     string `json:"user_name" gorm:"type:varchar(255);not null;uniqueIndex:ux_users_username,where:deleted_at IS NULL"`
 	PasswordHash string `json:"password" gorm:"type:varchar(255);not null"`
 	Role         string `json:"role" gorm:"type:varchar(50);not null;default:'student'"` // student | teacher | admin
-	Email        string `json:"email" gorm:"type:varchar(255);not null;uniqueIndex:ux_users_email"`
+	Email        string `json:"email" gorm:"type:varchar(255);not null;uniqueIndex:ux_users_email,where:deleted_at IS NULL"`
+	Locale       string `json:"locale" gorm:"type:varchar(8);not null;default:''"` // "" = según Accept-Language | es | en
+
+	// Verificación del email
+	EmailVerifiedAt    *time.Time `json:"email_verified_at,omitempty"`    // nil = sin verificar
+	VerificationSentAt *time.Time `json:"verification_sent_at,omitempty"` // Último envío, para limitar reenvíos
+
+	// Borrado de datos personales: la fila se conserva anonimizada cuando otras
+	// tablas la referencian (p. ej. invitaciones creadas por un profesor)
+	ErasedAt *time.Time `json:"erased_at,omitempty" gorm:"index"` // nil = cuenta normal
 
 	// Relaciones
-	Conversation *ChatSession `json:"conversation,omitempty"` // Un único hilo por usuario
-	Enrollments  []Enrollment `json:"enrollments,omitempty"`  // Módulos en los que está inscrito
-	Insights     []Insight    `json:"insights,omitempty"`
+	Conversations []ChatSession `json:"conversations,omitempty"` // Hilos de conversación del usuario
+	Enrollments   []Enrollment  `json:"enrollments,omitempty"`   // Módulos en los que está inscrito
+	Insights      []Insight     `json:"insights,omitempty"`
 }
@@ -0,0 +1,138 @@
+package pagination
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Cursor identifica una fila por su clave de orden (valor de la columna de
+// orden, id). Se entrega al cliente codificado y opaco; el id desempata filas
+// con el mismo valor. Field y Desc atan el cursor al orden con que se emitió.
+type Cursor struct {
+	Field string
+	Desc  bool
+	Value any
+	ID    uint
+}
+
+// cursorJSON es la forma serializada del cursor. Kind conserva el tipo del
+// valor para compararlo con la columna sin conversiones de texto.
+type cursorJSON struct {
+	Field string `json:"f"`
+	Desc  bool   `json:"d,omitempty"`
+	Kind  string `json:"k"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Encode serializa el cursor en base64 URL-safe.
+func (c Cursor) Encode() (string, error) {
+	kind, value, err := encodeValue(c.Value)
+	if err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal(cursorJSON{Field: c.Field, Desc: c.Desc, Kind: kind, Value: value, ID: c.ID})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Decode interpreta un cursor producido por Encode.
+func Decode(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var c cursorJSON
+	if err := json.Unmarshal(raw, &c); err != nil || c.Field == "" || c.ID == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	value, err := decodeValue(c.Kind, c.Value)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{Field: c.Field, Desc: c.Desc, Value: value, ID: c.ID}, nil
+}
+
+// encodeValue convierte el valor de la columna a texto según su tipo.
+func encodeValue(v any) (string, string, error) {
+	// Tipos como gorm.DeletedAt o datatypes.Date se leen por su valor SQL
+	if valuer, ok := v.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return "", "", err
+		}
+		v = value
+	}
+	if v == nil {
+		return "", "", fmt.Errorf("%w: la columna de orden es NULL", ErrInvalidCursor)
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "", "", fmt.Errorf("%w: la columna de orden es NULL", ErrInvalidCursor)
+		}
+		rv = rv.Elem()
+	}
+
+	switch {
+	case rv.Type().ConvertibleTo(timeType) && rv.Kind() == reflect.Struct:
+		t := rv.Convert(timeType).Interface().(time.Time)
+		return "t", t.UTC().Format(time.RFC3339Nano), nil
+	case rv.Kind() == reflect.String:
+		return "s", rv.String(), nil
+	case rv.CanInt():
+		return "i", fmt.Sprint(rv.Int()), nil
+	case rv.CanUint():
+		return "u", fmt.Sprint(rv.Uint()), nil
+	case rv.CanFloat():
+		return "f", fmt.Sprint(rv.Float()), nil
+	case rv.Kind() == reflect.Bool:
+		return "b", fmt.Sprint(rv.Bool()), nil
+	}
+	return "", "", fmt.Errorf("%w: tipo %s no soportado", ErrInvalidCursor, rv.Type())
+}
+
+// decodeValue es la inversa de encodeValue.
+func decodeValue(kind, value string) (any, error) {
+	var (
+		out any
+		err error
+	)
+	switch kind {
+	case "t":
+		out, err = time.Parse(time.RFC3339Nano, value)
+	case "s":
+		out = value
+	case "i":
+		var n int64
+		_, err = fmt.Sscan(value, &n)
+		out = n
+	case "u":
+		var n uint64
+		_, err = fmt.Sscan(value, &n)
+		out = n
+	case "f":
+		var n float64
+		_, err = fmt.Sscan(value, &n)
+		out = n
+	case "b":
+		var b bool
+		_, err = fmt.Sscan(value, &b)
+		out = b
+	default:
+		err = ErrInvalidCursor
+	}
+	return out, err
+}
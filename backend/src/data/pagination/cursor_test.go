@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	santiago := time.FixedZone("CLT", -4*3600)
+	at := time.Date(2024, 3, 15, 10, 30, 0, 123456789, santiago)
+	deletedAt := gorm.DeletedAt{Time: at, Valid: true}
+	type level int
+
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{"time in UTC", at, at.UTC()},
+		{"time pointer", &at, at.UTC()},
+		{"valuer", deletedAt, at.UTC()},
+		{"string", "Historia de Chile: ñandú", "Historia de Chile: ñandú"},
+		{"empty string", "", ""},
+		{"int", 42, int64(42)},
+		{"negative int64", int64(-7), int64(-7)},
+		{"named int", level(3), int64(3)},
+		{"uint", uint(9), uint64(9)},
+		{"float", 0.25, 0.25},
+		{"bool", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := Cursor{Field: "created_at", Desc: true, Value: tt.value, ID: 17}
+			encoded, err := in.Encode()
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			out, err := Decode(encoded)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if out.Field != "created_at" || !out.Desc || out.ID != 17 {
+				t.Fatalf("decoded %+v", out)
+			}
+			if got, ok := out.Value.(time.Time); ok {
+				if !got.Equal(tt.want.(time.Time)) {
+					t.Fatalf("value = %v, want %v", got, tt.want)
+				}
+				return
+			}
+			if out.Value != tt.want {
+				t.Fatalf("value = %#v (%T), want %#v (%T)", out.Value, out.Value, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorEncodeRejectsValue(t *testing.T) {
+	var nilTime *time.Time
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"nil", nil},
+		{"nil pointer", nilTime},
+		{"null valuer", gorm.DeletedAt{}},
+		{"unsupported type", []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (Cursor{Field: "f", Value: tt.value, ID: 1}).Encode(); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsMalformed(t *testing.T) {
+	b64 := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "%%%"},
+		{"not json", b64("hola")},
+		{"missing field", b64(`{"k":"i","v":"1","id":1}`)},
+		{"missing id", b64(`{"f":"rank","k":"i","v":"1"}`)},
+		{"unknown kind", b64(`{"f":"rank","k":"x","v":"1","id":1}`)},
+		{"bad int", b64(`{"f":"rank","k":"i","v":"uno","id":1}`)},
+		{"bad time", b64(`{"f":"created_at","k":"t","v":"ayer","id":1}`)},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"f":"rank","k":"i","v":"1","id":1}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package pagination
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de validación
+	ErrInvalidCursor      = apperror.New(apperror.Validation, "pagination.invalid_cursor", "pagination error: cursor inválido")
+	ErrConflictingCursors = apperror.New(apperror.Validation, "pagination.conflicting_cursors", "pagination error: no se pueden usar before y after a la vez")
+)
@@ -0,0 +1,179 @@
+// Package pagination implementa paginación por cursor (keyset) sobre
+// (columna de orden, id). A diferencia de Limit/Offset, el costo no crece con
+// la profundidad de la página y las inserciones concurrentes no desplazan filas.
+package pagination
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 20  // Tamaño de página si no se indica
+	MaxLimit     = 100 // Tamaño máximo de página
+)
+
+// Params son los parámetros de paginación de un listado. Next y Prev avanzan y
+// retroceden en el orden del listado (con el orden por defecto, Next lleva a
+// filas más antiguas).
+type Params struct {
+	Limit        int    // Tamaño de página (0 = DefaultLimit)
+	Next         string // Cursor: filas que siguen a este en el orden del listado
+	Prev         string // Cursor: filas que preceden a este en el orden del listado
+	IncludeTotal bool   // Contar el total de filas del filtro (consulta extra)
+}
+
+// PageSize devuelve el límite normalizado.
+func (p Params) PageSize() int {
+	if p.Limit <= 0 {
+		return DefaultLimit
+	}
+	return min(p.Limit, MaxLimit)
+}
+
+// Page es una página de resultados en el orden del listado.
+type Page[T any] struct {
+	Items   []T
+	Next    string // Cursor para la página siguiente ("" si no hay)
+	Prev    string // Cursor para la página anterior ("" si no hay)
+	HasNext bool
+	HasPrev bool
+	Total   *int64 // Solo si Params.IncludeTotal
+}
+
+// Reverse invierte los ítems (p. ej. para mostrar un chat en orden cronológico).
+// Los cursores no cambian.
+func (p *Page[T]) Reverse() {
+	slices.Reverse(p.Items)
+}
+
+// Find ejecuta query paginada según sort. query debe traer ya los filtros y el
+// Model; Find agrega el orden, el límite y la condición del cursor. La columna
+// de orden no debe admitir NULL.
+func Find[T any](query *gorm.DB, p Params, sort listing.Sort) (Page[T], error) {
+	var page Page[T]
+
+	if p.Next != "" && p.Prev != "" {
+		return page, ErrConflictingCursors
+	}
+
+	if p.IncludeTotal {
+		var total int64
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return page, err
+		}
+		page.Total = &total
+	}
+
+	limit := p.PageSize()
+	query = query.Session(&gorm.Session{})
+	keyset := fmt.Sprintf("(%s, %s)", sort.Column(), sort.IDColumn())
+
+	switch {
+	case p.Next != "":
+		c, err := decodeFor(p.Next, sort)
+		if err != nil {
+			return page, err
+		}
+		query = query.Where(keyset+" "+after(sort)+" (?, ?)", c.Value, c.ID).Order(sort.OrderBy())
+	case p.Prev != "":
+		c, err := decodeFor(p.Prev, sort)
+		if err != nil {
+			return page, err
+		}
+		// Se recorre en sentido inverso para quedarse con las filas contiguas al cursor
+		query = query.Where(keyset+" "+after(sort.Reverse())+" (?, ?)", c.Value, c.ID).Order(sort.Reverse().OrderBy())
+	default:
+		query = query.Order(sort.OrderBy())
+	}
+
+	var rows []T
+	if err := query.Limit(limit + 1).Find(&rows).Error; err != nil {
+		return page, err
+	}
+
+	more := len(rows) > limit
+	if more {
+		rows = rows[:limit]
+	}
+	if p.Prev != "" {
+		slices.Reverse(rows)
+	}
+
+	page.Items = rows
+	switch {
+	case p.Next != "":
+		page.HasNext, page.HasPrev = more, true
+	case p.Prev != "":
+		page.HasNext, page.HasPrev = true, more
+	default:
+		page.HasNext = more
+	}
+
+	if len(rows) == 0 {
+		return page, nil
+	}
+
+	var err error
+	if page.HasNext {
+		if page.Next, err = cursorOf(query, &rows[len(rows)-1], sort); err != nil {
+			return page, err
+		}
+	}
+	if page.HasPrev {
+		if page.Prev, err = cursorOf(query, &rows[0], sort); err != nil {
+			return page, err
+		}
+	}
+
+	return page, nil
+}
+
+// after devuelve el operador que selecciona las filas posteriores en el orden.
+func after(sort listing.Sort) string {
+	if sort.Desc {
+		return "<"
+	}
+	return ">"
+}
+
+// decodeFor decodifica el cursor y verifica que se haya emitido con el mismo orden.
+func decodeFor(s string, sort listing.Sort) (Cursor, error) {
+	c, err := Decode(s)
+	if err != nil {
+		return Cursor{}, err
+	}
+	if c.Field != sort.Field || c.Desc != sort.Desc {
+		return Cursor{}, fmt.Errorf("%w: el cursor es de otro orden", ErrInvalidCursor)
+	}
+	return c, nil
+}
+
+// cursorOf arma el cursor de row leyendo la columna de orden y el id desde el
+// esquema GORM del modelo.
+func cursorOf[T any](db *gorm.DB, row *T, sort listing.Sort) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(row); err != nil {
+		return "", err
+	}
+
+	field := stmt.Schema.LookUpField(sort.Field)
+	id := stmt.Schema.LookUpField("id")
+	if field == nil || id == nil {
+		return "", fmt.Errorf("%w: %s no es una columna de %s", listing.ErrInvalidSort, sort.Field, stmt.Schema.Table)
+	}
+
+	rv := reflect.ValueOf(row).Elem()
+	value, _ := field.ValueOf(db.Statement.Context, rv)
+	idValue, _ := id.ValueOf(db.Statement.Context, rv)
+	rowID, ok := idValue.(uint)
+	if !ok {
+		return "", fmt.Errorf("%w: id de tipo %T", ErrInvalidCursor, idValue)
+	}
+
+	return Cursor{Field: sort.Field, Desc: sort.Desc, Value: value, ID: rowID}.Encode()
+}
@@ -0,0 +1,206 @@
+package pagination
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type item struct {
+	ID        uint
+	Name      string
+	Rank      int
+	CreatedAt time.Time
+}
+
+// newTestDB crea una tabla de ítems en SQLite en memoria. Los ítems 1..7 se
+// crean un minuto aparte; el rango repite valores para probar el desempate por id.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&item{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	names := []string{"delta", "alfa", "golf", "charlie", "echo", "bravo", "foxtrot"}
+	ranks := []int{2, 1, 3, 1, 2, 1, 3}
+	for i := range names {
+		row := item{Name: names[i], Rank: ranks[i], CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := db.Create(&row).Error; err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	return db
+}
+
+func ids(items []item) []uint {
+	out := make([]uint, 0, len(items))
+	for _, it := range items {
+		out = append(out, it.ID)
+	}
+	return out
+}
+
+func find(t *testing.T, db *gorm.DB, p Params, sort listing.Sort) Page[item] {
+	t.Helper()
+	page, err := Find[item](db.Model(&item{}), p, sort)
+	if err != nil {
+		t.Fatalf("Find(%+v): %v", p, err)
+	}
+	return page
+}
+
+func TestFindWalksAllPages(t *testing.T) {
+	db := newTestDB(t)
+
+	tests := []struct {
+		name string
+		sort listing.Sort
+		want []uint
+	}{
+		{"newest first", listing.Sort{Field: "created_at", Desc: true}, []uint{7, 6, 5, 4, 3, 2, 1}},
+		{"oldest first", listing.Sort{Field: "created_at"}, []uint{1, 2, 3, 4, 5, 6, 7}},
+		{"int with ties", listing.Sort{Field: "rank"}, []uint{2, 4, 6, 1, 5, 3, 7}},
+		{"int with ties desc", listing.Sort{Field: "rank", Desc: true}, []uint{7, 3, 5, 1, 6, 4, 2}},
+		{"string", listing.Sort{Field: "name"}, []uint{2, 6, 4, 1, 5, 7, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Hacia adelante con Next
+			var forward []uint
+			var last Page[item]
+			p := Params{Limit: 3}
+			for {
+				last = find(t, db, p, tt.sort)
+				forward = append(forward, ids(last.Items)...)
+				if !last.HasNext {
+					break
+				}
+				p = Params{Limit: 3, Next: last.Next}
+			}
+			if !slices.Equal(forward, tt.want) {
+				t.Fatalf("forward = %v, want %v", forward, tt.want)
+			}
+
+			// Y de vuelta con Prev desde la última página: cada página sale en el orden del listado
+			var backward []uint
+			page := last
+			for page.HasPrev {
+				page = find(t, db, Params{Limit: 3, Prev: page.Prev}, tt.sort)
+				backward = append(ids(page.Items), backward...)
+			}
+			backward = append(backward, ids(last.Items)...)
+			if !slices.Equal(backward, tt.want) {
+				t.Fatalf("backward = %v, want %v", backward, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindPageFlags(t *testing.T) {
+	db := newTestDB(t)
+	sort := listing.Sort{Field: "created_at", Desc: true}
+
+	first := find(t, db, Params{Limit: 3}, sort)
+	if !slices.Equal(ids(first.Items), []uint{7, 6, 5}) || !first.HasNext || first.HasPrev || first.Prev != "" {
+		t.Fatalf("first page = %v next=%v prev=%v", ids(first.Items), first.HasNext, first.HasPrev)
+	}
+
+	second := find(t, db, Params{Limit: 3, Next: first.Next}, sort)
+	if !slices.Equal(ids(second.Items), []uint{4, 3, 2}) || !second.HasNext || !second.HasPrev {
+		t.Fatalf("second page = %v next=%v prev=%v", ids(second.Items), second.HasNext, second.HasPrev)
+	}
+
+	// Prev desde la segunda página vuelve a la primera, en el orden del listado
+	back := find(t, db, Params{Limit: 3, Prev: second.Prev}, sort)
+	if !slices.Equal(ids(back.Items), []uint{7, 6, 5}) || !back.HasNext || back.HasPrev {
+		t.Fatalf("back = %v next=%v prev=%v", ids(back.Items), back.HasNext, back.HasPrev)
+	}
+
+	// Prev con menos filas que el límite: las contiguas al cursor, sin relleno
+	third := find(t, db, Params{Limit: 3, Next: second.Next}, sort)
+	short := find(t, db, Params{Limit: 5, Prev: third.Prev}, sort)
+	if !slices.Equal(ids(short.Items), []uint{6, 5, 4, 3, 2}) || !short.HasPrev {
+		t.Fatalf("prev with limit 5 = %v prev=%v", ids(short.Items), short.HasPrev)
+	}
+
+	exact := find(t, db, Params{Limit: 7}, sort)
+	if len(exact.Items) != 7 || exact.HasNext || exact.Next != "" {
+		t.Fatalf("exact page: %d items, next=%v", len(exact.Items), exact.HasNext)
+	}
+}
+
+func TestFindTotalAndLimit(t *testing.T) {
+	db := newTestDB(t)
+	sort := listing.Sort{Field: "created_at", Desc: true}
+
+	page, err := Find[item](db.Model(&item{}).Where("rank = ?", 1), Params{Limit: 2, IncludeTotal: true}, sort)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if page.Total == nil || *page.Total != 3 || len(page.Items) != 2 {
+		t.Fatalf("total = %v, items = %v", page.Total, ids(page.Items))
+	}
+	if find(t, db, Params{}, sort).Total != nil {
+		t.Fatal("total computed without IncludeTotal")
+	}
+
+	for _, tt := range []struct{ limit, want int }{{0, DefaultLimit}, {-5, DefaultLimit}, {30, 30}, {500, MaxLimit}} {
+		if got := (Params{Limit: tt.limit}).PageSize(); got != tt.want {
+			t.Errorf("PageSize(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestFindRejectsCursor(t *testing.T) {
+	db := newTestDB(t)
+	byDate := listing.Sort{Field: "created_at", Desc: true}
+	first := find(t, db, Params{Limit: 3}, byDate)
+
+	tests := []struct {
+		name string
+		p    Params
+		sort listing.Sort
+		want error
+	}{
+		{"other field", Params{Next: first.Next}, listing.Sort{Field: "name", Desc: true}, ErrInvalidCursor},
+		{"other direction", Params{Next: first.Next}, listing.Sort{Field: "created_at"}, ErrInvalidCursor},
+		{"other direction on prev", Params{Prev: first.Next}, listing.Sort{Field: "created_at"}, ErrInvalidCursor},
+		{"garbage", Params{Next: "no-es-un-cursor"}, byDate, ErrInvalidCursor},
+		{"both cursors", Params{Next: first.Next, Prev: first.Next}, byDate, ErrConflictingCursors},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Find[item](db.Model(&item{}), tt.p, tt.sort); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageReverse(t *testing.T) {
+	db := newTestDB(t)
+	page := find(t, db, Params{Limit: 3}, listing.Sort{Field: "created_at", Desc: true})
+	next, prev := page.Next, page.Prev
+
+	page.Reverse()
+	if !slices.Equal(ids(page.Items), []uint{5, 6, 7}) || page.Next != next || page.Prev != prev {
+		t.Fatalf("reversed = %v; cursors must not change", ids(page.Items))
+	}
+}
@@ -0,0 +1,109 @@
+package accounttokenrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type accountTokenRepo struct {
+	db *gorm.DB
+}
+
+func NewAccountTokenRepo(db *gorm.DB) (AccountTokenRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &accountTokenRepo{
+		db: db,
+	}, nil
+}
+
+// ActiveAccountTokenByHash implements AccountTokenRepo.
+func (a *accountTokenRepo) ActiveAccountTokenByHash(ctx context.Context, purpose string, tokenHash string) (*models.AccountToken, error) {
+	if purpose == "" {
+		return nil, ErrInvalidPurpose
+	}
+	if tokenHash == "" {
+		return nil, ErrTokenHashEmpty
+	}
+
+	var token models.AccountToken
+	err := database.Conn(ctx, a.db).
+		Where("purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ?", purpose, tokenHash, time.Now()).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountTokenNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// CreateAccountToken implements AccountTokenRepo.
+func (a *accountTokenRepo) CreateAccountToken(ctx context.Context, token *models.AccountToken) (*models.AccountToken, error) {
+	if token == nil {
+		return nil, ErrAccountTokenNil
+	}
+	if token.UserID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if token.Purpose == "" {
+		return nil, ErrInvalidPurpose
+	}
+	if token.TokenHash == "" {
+		return nil, ErrTokenHashEmpty
+	}
+	if !token.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiration
+	}
+
+	if err := database.Conn(ctx, a.db).Create(token).Error; err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// MarkAccountTokenUsed implements AccountTokenRepo.
+func (a *accountTokenRepo) MarkAccountTokenUsed(ctx context.Context, id uint) error {
+	if id == 0 {
+		return ErrInvalidTokenID
+	}
+
+	result := database.Conn(ctx, a.db).
+		Model(&models.AccountToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAccountTokenUsed
+	}
+
+	return nil
+}
+
+// RevokeAccountTokens implements AccountTokenRepo.
+func (a *accountTokenRepo) RevokeAccountTokens(ctx context.Context, userID uint, purpose string) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	if purpose == "" {
+		return ErrInvalidPurpose
+	}
+
+	// Un token revocado se marca como usado: deja de ser válido y queda el registro
+	return database.Conn(ctx, a.db).
+		Model(&models.AccountToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,22 @@
+package accounttokenrepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrAccountTokenNotFound = apperror.New(apperror.NotFound, "account_token.not_found", "token de cuenta no encontrado, vencido o ya utilizado")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "account_token.database_required", "account token error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrAccountTokenNil   = apperror.New(apperror.Validation, "account_token.nil", "account token error: el token no puede ser nil")
+	ErrInvalidUserID     = apperror.New(apperror.Validation, "account_token.invalid_user_id", "account token error: id de usuario inválido")
+	ErrInvalidTokenID    = apperror.New(apperror.Validation, "account_token.invalid_token_id", "account token error: id de token inválido")
+	ErrInvalidPurpose    = apperror.New(apperror.Validation, "account_token.invalid_purpose", "account token error: propósito inválido")
+	ErrTokenHashEmpty    = apperror.New(apperror.Validation, "account_token.token_hash_empty", "account token error: el hash del token no puede estar vacío")
+	ErrInvalidExpiration = apperror.New(apperror.Validation, "account_token.invalid_expiration", "account token error: la fecha de vencimiento debe ser futura")
+
+	// Errores de estado
+	ErrAccountTokenUsed = apperror.New(apperror.Conflict, "account_token.used", "account token error: el token ya fue utilizado")
+)
@@ -0,0 +1,33 @@
+package accounttokenrepo
+
+import (
+	"context"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de tokens de cuenta
+type AccountTokenReader interface {
+	// ActiveAccountTokenByHash devuelve el token si no está vencido ni usado.
+	ActiveAccountTokenByHash(ctx context.Context, purpose, tokenHash string) (*models.AccountToken, error)
+}
+
+// Escritura de tokens de cuenta
+type AccountTokenWriter interface {
+	CreateAccountToken(ctx context.Context, token *models.AccountToken) (*models.AccountToken, error)
+	// MarkAccountTokenUsed marca el token como usado; falla si ya lo estaba (evita doble uso concurrente).
+	MarkAccountTokenUsed(ctx context.Context, id uint) error
+	// RevokeAccountTokens invalida los tokens pendientes del usuario para un propósito.
+	RevokeAccountTokens(ctx context.Context, userID uint, purpose string) error
+}
+
+// Interfaz principal
+type AccountTokenRepo interface {
+	AccountTokenReader
+	AccountTokenWriter
+}
+
+// Propósitos de los tokens
+const (
+	PurposeInvite = "invite"
+)
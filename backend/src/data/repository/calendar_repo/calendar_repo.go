@@ -0,0 +1,127 @@
+package calendarrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type calendarRepo struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepo(db *gorm.DB) (CalendarRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &calendarRepo{
+		db: db,
+	}, nil
+}
+
+// ActiveFeedTokenByHash implements CalendarRepo.
+func (c *calendarRepo) ActiveFeedTokenByHash(ctx context.Context, tokenHash string) (*models.CalendarFeedToken, error) {
+	if tokenHash == "" {
+		return nil, ErrTokenHashEmpty
+	}
+
+	var token models.CalendarFeedToken
+	err := database.Conn(ctx, c.db).
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFeedTokenNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// ActiveFeedTokenByUser implements CalendarRepo.
+func (c *calendarRepo) ActiveFeedTokenByUser(ctx context.Context, userID uint) (*models.CalendarFeedToken, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	var token models.CalendarFeedToken
+	err := database.Conn(ctx, c.db).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("id DESC").
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFeedTokenNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// RotateFeedToken implements CalendarRepo.
+func (c *calendarRepo) RotateFeedToken(ctx context.Context, userID uint, tokenHash string) (*models.CalendarFeedToken, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if tokenHash == "" {
+		return nil, ErrTokenHashEmpty
+	}
+
+	token := &models.CalendarFeedToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+	}
+	err := database.Conn(ctx, c.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.CalendarFeedToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// RevokeFeedTokensByUser implements CalendarRepo.
+func (c *calendarRepo) RevokeFeedTokensByUser(ctx context.Context, userID uint) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+
+	result := database.Conn(ctx, c.db).
+		Model(&models.CalendarFeedToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrFeedTokenNotFound
+	}
+
+	return nil
+}
+
+// TouchFeedToken implements CalendarRepo.
+func (c *calendarRepo) TouchFeedToken(ctx context.Context, id uint) error {
+	if id == 0 {
+		return ErrInvalidFeedTokenID
+	}
+
+	return database.Conn(ctx, c.db).
+		Model(&models.CalendarFeedToken{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", time.Now()).Error
+}
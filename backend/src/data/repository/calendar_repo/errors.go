@@ -0,0 +1,16 @@
+package calendarrepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrFeedTokenNotFound = apperror.New(apperror.NotFound, "calendar.feed_token_not_found", "token de calendario no encontrado o revocado")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "calendar.database_required", "calendar error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrInvalidUserID      = apperror.New(apperror.Validation, "calendar.invalid_user_id", "calendar error: id de usuario inválido")
+	ErrInvalidFeedTokenID = apperror.New(apperror.Validation, "calendar.invalid_feed_token_id", "calendar error: id de token inválido")
+	ErrTokenHashEmpty     = apperror.New(apperror.Validation, "calendar.token_hash_empty", "calendar error: el hash del token no puede estar vacío")
+)
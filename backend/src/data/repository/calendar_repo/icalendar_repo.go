@@ -0,0 +1,27 @@
+package calendarrepo
+
+import (
+	"context"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de tokens de suscripción al calendario
+type FeedTokenReader interface {
+	ActiveFeedTokenByHash(ctx context.Context, tokenHash string) (*models.CalendarFeedToken, error)
+	ActiveFeedTokenByUser(ctx context.Context, userID uint) (*models.CalendarFeedToken, error)
+}
+
+// Escritura de tokens de suscripción al calendario
+type FeedTokenWriter interface {
+	// RotateFeedToken revoca los tokens activos del usuario y crea uno nuevo, en una transacción.
+	RotateFeedToken(ctx context.Context, userID uint, tokenHash string) (*models.CalendarFeedToken, error)
+	RevokeFeedTokensByUser(ctx context.Context, userID uint) error
+	TouchFeedToken(ctx context.Context, id uint) error // Registra el último uso
+}
+
+// Interfaz principal
+type CalendarRepo interface {
+	FeedTokenReader
+	FeedTokenWriter
+}
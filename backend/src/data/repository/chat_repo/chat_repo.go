@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	pgvector "github.com/pgvector/pgvector-go"
 	"gorm.io/gorm"
 )
@@ -54,7 +57,7 @@ func (c *chatRepo) BatchCreateMessages(ctx context.Context, messages []models.Ch
 		}
 	}
 
-	err := c.db.WithContext(ctx).Create(&messages).Error
+	err := database.Conn(ctx, c.db).Create(&messages).Error
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +83,7 @@ func (c *chatRepo) BatchUpdateEmbeddings(ctx context.Context, updates []MessageE
 
 	// Actualizar cada embedding individualmente
 	for _, update := range updates {
-		result := c.db.WithContext(ctx).Model(&models.ChatMessage{}).
+		result := database.Conn(ctx, c.db).Model(&models.ChatMessage{}).
 			Where("id = ?", update.ID).
 			Update("embedding", update.Embedding)
 
@@ -103,7 +106,7 @@ func (c *chatRepo) ChatMessageByID(ctx context.Context, id uint) (*models.ChatMe
 	}
 
 	var message models.ChatMessage
-	err := c.db.WithContext(ctx).Where("id = ?", id).First(&message).Error
+	err := database.Conn(ctx, c.db).Where("id = ?", id).First(&message).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrChatMessageNotFound
@@ -121,7 +124,7 @@ func (c *chatRepo) ChatMessageByToolCallID(ctx context.Context, toolCallID strin
 	}
 
 	var message models.ChatMessage
-	err := c.db.WithContext(ctx).Where("tool_call_id = ?", toolCallID).First(&message).Error
+	err := database.Conn(ctx, c.db).Where("tool_call_id = ?", toolCallID).First(&message).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrChatMessageNotFound
@@ -139,7 +142,7 @@ func (c *chatRepo) ChatMessagesByConversationID(ctx context.Context, conversatio
 	}
 
 	var messages []models.ChatMessage
-	err := c.db.WithContext(ctx).Where("conversation_id = ?", conversationID).Order("created_at ASC").Find(&messages).Error
+	err := database.Conn(ctx, c.db).Where("conversation_id = ?", conversationID).Order("created_at ASC").Find(&messages).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrChatMessageNotFound
@@ -159,7 +162,7 @@ func (c *chatRepo) ChatMessagesByRole(ctx context.Context, conversationID uint,
 	}
 
 	var messages []models.ChatMessage
-	err := c.db.WithContext(ctx).
+	err := database.Conn(ctx, c.db).
 		Where("conversation_id = ? AND role = ?", conversationID, role).
 		Order("created_at ASC").
 		Find(&messages).Error
@@ -179,7 +182,7 @@ func (c *chatRepo) ChatSessionByID(ctx context.Context, id uint) (*models.ChatSe
 	}
 
 	var session models.ChatSession
-	err := c.db.WithContext(ctx).
+	err := database.Conn(ctx, c.db).
 		Where("id = ?", id).
 		First(&session).
 		Error
@@ -199,8 +202,9 @@ func (c *chatRepo) ChatSessionByUserID(ctx context.Context, userID uint) (*model
 	}
 
 	var session models.ChatSession
-	err := c.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+	err := database.Conn(ctx, c.db).
+		Where("user_id = ? AND archived_at IS NULL", userID).
+		Order("last_message_at DESC NULLS LAST, id DESC").
 		First(&session).
 		Error
 
@@ -208,6 +212,7 @@ func (c *chatRepo) ChatSessionByUserID(ctx context.Context, userID uint) (*model
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrChatSessionNotFound
 		}
+		return nil, err
 	}
 
 	return &session, nil
@@ -220,7 +225,7 @@ func (c *chatRepo) ChatSessionExists(ctx context.Context, userID uint) (bool, er
 	}
 
 	var count int64
-	err := c.db.WithContext(ctx).
+	err := database.Conn(ctx, c.db).
 		Model(&models.ChatSession{}).
 		Where("user_id = ?", userID).
 		Count(&count).Error
@@ -238,7 +243,7 @@ func (c *chatRepo) ChatSessionWithMessages(ctx context.Context, sessionID uint)
 	}
 
 	var session models.ChatSession
-	err := c.db.WithContext(ctx).
+	err := database.Conn(ctx, c.db).
 		Preload("Messages").
 		First(&session, sessionID).
 		Error
@@ -266,25 +271,37 @@ func (c *chatRepo) CreateChatMessage(ctx context.Context, message *models.ChatMe
 		return nil, ErrInvalidEmbedding
 	}
 
-	// verificar la existencia del chat session
-	var count int64
-	err := c.db.WithContext(ctx).
-		Model(&models.ChatSession{}).
+	// verificar la existencia del chat session y que no esté archivado
+	var session models.ChatSession
+	err := database.Conn(ctx, c.db).
+		Select("id", "archived_at").
 		Where("id = ?", message.ConversationID).
-		Count(&count).Error
+		First(&session).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChatSessionNotFound
+		}
 		return nil, fmt.Errorf("error inesperado verificando la existencia del chat session: %w", err)
 	}
 
-	if count == 0 {
-		return nil, ErrChatSessionNotFound
+	if session.ArchivedAt != nil {
+		return nil, ErrChatSessionArchived
 	}
 
-	err = c.db.WithContext(ctx).Create(message).Error
+	err = database.Conn(ctx, c.db).Create(message).Error
 	if err != nil {
 		return nil, fmt.Errorf("error inesperado creando el chat message: %w", err)
 	}
 
+	// Mantener actualizado el orden de la lista de hilos
+	err = database.Conn(ctx, c.db).
+		Model(&models.ChatSession{}).
+		Where("id = ?", message.ConversationID).
+		UpdateColumn("last_message_at", message.CreatedAt).Error
+	if err != nil {
+		return nil, fmt.Errorf("error inesperado actualizando la fecha del último mensaje: %w", err)
+	}
+
 	return message, nil
 }
 
@@ -298,20 +315,15 @@ func (c *chatRepo) CreateChatSession(ctx context.Context, session *models.ChatSe
 		return nil, ErrMissingRequiredFields
 	}
 
-	// verificar que no exista ya un chat session para el usuario
-	var count int64
-	err := c.db.WithContext(ctx).
-		Model(&models.ChatSession{}).
-		Where("user_id = ?", session.UserID).
-		Count(&count).Error
-	if err != nil {
-		return nil, fmt.Errorf("error inesperado verificando la existencia del chat session: %w", err)
+	if len([]rune(session.Title)) > 200 {
+		return nil, ErrInvalidSessionTitle
 	}
-	if count > 0 {
-		return nil, ErrUserAlreadyHasSession
+
+	if session.Title == "" {
+		session.Title = models.DefaultChatSessionTitle
 	}
 
-	err = c.db.WithContext(ctx).Create(session).Error
+	err := database.Conn(ctx, c.db).Create(session).Error
 	if err != nil {
 		return nil, fmt.Errorf("error inesperado creando el chat session: %w", err)
 	}
@@ -325,7 +337,7 @@ func (c *chatRepo) DeleteChatMessage(ctx context.Context, id uint) error {
 		return ErrInvalidChatMessageID
 	}
 
-	result := c.db.WithContext(ctx).Delete(&models.ChatMessage{}, id)
+	result := database.Conn(ctx, c.db).Delete(&models.ChatMessage{}, id)
 	if result.Error != nil {
 		return fmt.Errorf("error inesperado eliminando el chat message: %w", result.Error)
 	}
@@ -343,7 +355,7 @@ func (c *chatRepo) DeleteChatSession(ctx context.Context, id uint) error {
 		return ErrInvalidChatSessionID
 	}
 
-	result := c.db.WithContext(ctx).Delete(&models.ChatSession{}, id)
+	result := database.Conn(ctx, c.db).Delete(&models.ChatSession{}, id)
 	if result.Error != nil {
 		return fmt.Errorf("error inesperado eliminando el chat session: %w", result.Error)
 	}
@@ -363,7 +375,7 @@ func (c *chatRepo) DeleteChatSessionByUserID(ctx context.Context, userID uint) e
 
 	// Verificar si existe el chat session
 	var count int64
-	err := c.db.WithContext(ctx).
+	err := database.Conn(ctx, c.db).
 		Model(&models.ChatSession{}).
 		Where("user_id = ?", userID).
 		Count(&count).Error
@@ -374,7 +386,9 @@ func (c *chatRepo) DeleteChatSessionByUserID(ctx context.Context, userID uint) e
 		return ErrChatSessionNotFound
 	}
 
-	result := c.db.WithContext(ctx).Delete(&models.ChatSession{}, userID)
+	result := database.Conn(ctx, c.db).
+		Where("user_id = ?", userID).
+		Delete(&models.ChatSession{})
 	if result.Error != nil {
 		return fmt.Errorf("error inesperado eliminando el chat session: %w", result.Error)
 	}
@@ -394,7 +408,7 @@ func (c *chatRepo) DeleteMessagesByConversationID(ctx context.Context, conversat
 
 	// Verificar si existen mensajes en la conversación
 	var count int64
-	err := c.db.WithContext(ctx).
+	err := database.Conn(ctx, c.db).
 		Model(&models.ChatMessage{}).
 		Where("conversation_id = ?", conversationID).
 		Count(&count).Error
@@ -406,7 +420,7 @@ func (c *chatRepo) DeleteMessagesByConversationID(ctx context.Context, conversat
 		return ErrChatMessageNotFound
 	}
 
-	result := c.db.WithContext(ctx).Where("conversation_id = ?", conversationID).Delete(&models.ChatMessage{})
+	result := database.Conn(ctx, c.db).Where("conversation_id = ?", conversationID).Delete(&models.ChatMessage{})
 	if result.Error != nil {
 		return fmt.Errorf("error inesperado eliminando los mensajes de la conversación: %w", result.Error)
 	}
@@ -425,7 +439,7 @@ func (c *chatRepo) FindSimilarMessages(ctx context.Context, messageID uint, limi
 	}
 
 	var referenceMessage models.ChatMessage
-	err := c.db.WithContext(ctx).First(&referenceMessage, messageID).Error
+	err := database.Conn(ctx, c.db).First(&referenceMessage, messageID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrChatMessageNotFound
@@ -442,7 +456,8 @@ func (c *chatRepo) FindSimilarMessages(ctx context.Context, messageID uint, limi
 	}
 
 	var similarMessages []models.ChatMessage
-	result := c.db.WithContext(ctx).
+	result := database.Conn(ctx, c.db).
+		Clauses(database.SemanticReplica()).
 		Model(&models.ChatMessage{}).
 		Select("*, embedding <=> ? AS distance", referenceMessage.Embedding).
 		Where("id != ?", messageID). // Excluir el mensaje de referencia
@@ -472,7 +487,7 @@ func (c *chatRepo) GetConversationHistory(ctx context.Context, conversationID ui
 
 	// Verificar si existen mensajes en la conversación
 	var count int64
-	err := c.db.WithContext(ctx).
+	err := database.Conn(ctx, c.db).
 		Model(&models.ChatMessage{}).
 		Where("conversation_id = ?", conversationID).
 		Count(&count).Error
@@ -485,7 +500,7 @@ func (c *chatRepo) GetConversationHistory(ctx context.Context, conversationID ui
 	}
 
 	var messages []models.ChatMessage
-	err = c.db.WithContext(ctx).
+	err = database.Conn(ctx, c.db).
 		Where("conversation_id = ?", conversationID).
 		Order("created_at DESC").
 		Limit(limit).
@@ -497,47 +512,160 @@ func (c *chatRepo) GetConversationHistory(ctx context.Context, conversationID ui
 	return messages, nil
 }
 
+// MessagesAfterID implements ChatRepo.
+func (c *chatRepo) MessagesAfterID(ctx context.Context, conversationID uint, afterID uint, limit int) ([]models.ChatMessage, error) {
+	if conversationID == 0 {
+		return nil, ErrInvalidConversationID
+	}
+
+	if limit < 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	query := database.Conn(ctx, c.db).
+		Where("conversation_id = ? AND id > ?", conversationID, afterID).
+		Order("id ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var messages []models.ChatMessage
+	if err := query.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("error inesperado obteniendo mensajes posteriores: %w", err)
+	}
+
+	return messages, nil
+}
+
+// CountMessagesAfterID implements ChatRepo.
+func (c *chatRepo) CountMessagesAfterID(ctx context.Context, conversationID uint, afterID uint) (int64, error) {
+	if conversationID == 0 {
+		return 0, ErrInvalidConversationID
+	}
+
+	var count int64
+	err := database.Conn(ctx, c.db).
+		Model(&models.ChatMessage{}).
+		Where("conversation_id = ? AND id > ?", conversationID, afterID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("error inesperado contando mensajes posteriores: %w", err)
+	}
+
+	return count, nil
+}
+
+// ChatSummaryByConversationID implements ChatRepo.
+func (c *chatRepo) ChatSummaryByConversationID(ctx context.Context, conversationID uint) (*models.ChatSummary, error) {
+	if conversationID == 0 {
+		return nil, ErrInvalidConversationID
+	}
+
+	var summary models.ChatSummary
+	err := database.Conn(ctx, c.db).
+		Where("conversation_id = ?", conversationID).
+		First(&summary).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChatSummaryNotFound
+		}
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// UpsertChatSummary implements ChatRepo.
+func (c *chatRepo) UpsertChatSummary(ctx context.Context, summary *models.ChatSummary) (*models.ChatSummary, error) {
+	if summary == nil {
+		return nil, ErrChatSummaryNil
+	}
+
+	if summary.ConversationID == 0 {
+		return nil, ErrInvalidConversationID
+	}
+
+	if summary.Content == "" {
+		return nil, ErrInvalidSummaryText
+	}
+
+	var existing models.ChatSummary
+	err := database.Conn(ctx, c.db).
+		Where("conversation_id = ?", summary.ConversationID).
+		First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error inesperado verificando el resumen de chat: %w", err)
+	}
+
+	// No existe: crear
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := database.Conn(ctx, c.db).Create(summary).Error; err != nil {
+			return nil, fmt.Errorf("error inesperado creando el resumen de chat: %w", err)
+		}
+		return summary, nil
+	}
+
+	// Existe: actualizar el contenido y la marca de avance
+	err = database.Conn(ctx, c.db).
+		Model(&existing).
+		Updates(map[string]any{
+			"content":                  summary.Content,
+			"covered_until_message_id": summary.CoveredUntilMessageID,
+			"message_count":            summary.MessageCount,
+		}).Error
+	if err != nil {
+		return nil, fmt.Errorf("error inesperado actualizando el resumen de chat: %w", err)
+	}
+
+	existing.Content = summary.Content
+	existing.CoveredUntilMessageID = summary.CoveredUntilMessageID
+	existing.MessageCount = summary.MessageCount
+
+	return &existing, nil
+}
+
 // ListChatMessages implements ChatRepo.
-func (c *chatRepo) ListChatMessages(ctx context.Context, filter ChatMessageFilter) ([]models.ChatMessage, error) {
-	query := c.db.WithContext(ctx).Model(&models.ChatMessage{})
+func (c *chatRepo) ListChatMessages(ctx context.Context, filter ChatMessageFilter) (pagination.Page[models.ChatMessage], error) {
+	query := database.Conn(ctx, c.db).Model(&models.ChatMessage{})
 
 	// Aplicar filtros dinámicos
 	if filter.ConversationID != 0 {
 		query = query.Where("conversation_id = ?", filter.ConversationID)
 	}
 
-	if filter.Role != "" {
-		query = query.Where("role = ?", filter.Role)
+	if len(filter.Roles) > 0 {
+		query = query.Where("role IN ?", filter.Roles)
 	}
 
 	if filter.ToolCallID != "" {
 		query = query.Where("tool_call_id = ?", filter.ToolCallID)
 	}
 
-	query = query.Order("created_at desc")
-
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
+	// Orden, rango de creación y eliminados
+	query, sort, err := filter.Spec.Apply(query, ChatMessageListSchema)
+	if err != nil {
+		return pagination.Page[models.ChatMessage]{}, err
 	}
 
-	var messages []models.ChatMessage
-	err := query.Find(&messages).Error
+	// Con el orden por defecto la página se arma de más reciente a más antigua:
+	// sin cursor trae los últimos mensajes y con Next "carga los anteriores"
+	page, err := pagination.Find[models.ChatMessage](query, filter.Page, sort)
 	if err != nil {
-		return nil, fmt.Errorf("error inesperado listando los mensajes de chat: %w", err)
+		return page, fmt.Errorf("error inesperado listando los mensajes de chat: %w", err)
 	}
 
-	// Invertir el slice para devolver en orden cronológico ascendente para el llm
-	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
-		messages[i], messages[j] = messages[j], messages[i]
+	// Invertir la página para devolver en orden cronológico ascendente para el llm
+	if sort.Desc {
+		page.Reverse()
 	}
 
-	return messages, nil
-
+	return page, nil
 }
 
 // ListChatSessions implements ChatRepo.
 func (c *chatRepo) ListChatSessions(ctx context.Context, filter ChatSessionFilter) ([]models.ChatSession, error) {
-	query := c.db.WithContext(ctx).Model(&models.ChatSession{})
+	query := database.Conn(ctx, c.db).Model(&models.ChatSession{})
 
 	// Filtros dinámicos
 	if filter.UserID != 0 {
@@ -548,6 +676,21 @@ func (c *chatRepo) ListChatSessions(ctx context.Context, filter ChatSessionFilte
 		query = query.Where("agent_name = ?", filter.AgentName)
 	}
 
+	if filter.ModuleID != 0 {
+		query = query.Where("module_id = ?", filter.ModuleID)
+	}
+
+	if !filter.IncludeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+
+	// Orden (por defecto, actividad más reciente primero), rango de creación y eliminados
+	query, sort, err := filter.Spec.Apply(query, ChatSessionListSchema)
+	if err != nil {
+		return nil, err
+	}
+	query = query.Order(sort.OrderBy())
+
 	// Paginación
 	if filter.Limit > 0 {
 		query = query.Limit(filter.Limit)
@@ -557,9 +700,6 @@ func (c *chatRepo) ListChatSessions(ctx context.Context, filter ChatSessionFilte
 		query = query.Offset(filter.Offset)
 	}
 
-	// Ordenar por creación más reciente primero
-	query = query.Order("created_at desc")
-
 	var sessions []models.ChatSession
 	if err := query.Find(&sessions).Error; err != nil {
 		return nil, fmt.Errorf("error inesperado listando sesiones de chat: %w", err)
@@ -572,6 +712,120 @@ func (c *chatRepo) ListChatSessions(ctx context.Context, filter ChatSessionFilte
 	return sessions, nil
 }
 
+// ListChatSessionPreviews implements ChatRepo.
+func (c *chatRepo) ListChatSessionPreviews(ctx context.Context, filter ChatSessionFilter) ([]ChatSessionPreview, error) {
+	query := database.Conn(ctx, c.db).
+		Table("chat_sessions").
+		Select(`chat_sessions.*,
+			COALESCE(lm.content, '') AS last_message_content,
+			COALESCE(lm.role, '') AS last_message_role,
+			(SELECT COUNT(*) FROM chat_messages cm
+				WHERE cm.conversation_id = chat_sessions.id AND cm.deleted_at IS NULL) AS message_count`).
+		Joins(`LEFT JOIN LATERAL (
+			SELECT m.content, m.role FROM chat_messages m
+			WHERE m.conversation_id = chat_sessions.id AND m.deleted_at IS NULL
+			ORDER BY m.id DESC
+			LIMIT 1
+		) lm ON true`)
+
+	// Con Table no aplica el soft delete de GORM: se filtra a mano
+	if !filter.Spec.IncludeDeleted {
+		query = query.Where("chat_sessions.deleted_at IS NULL")
+	}
+
+	// Filtros dinámicos
+	if filter.UserID != 0 {
+		query = query.Where("chat_sessions.user_id = ?", filter.UserID)
+	}
+
+	if filter.AgentName != "" {
+		query = query.Where("chat_sessions.agent_name = ?", filter.AgentName)
+	}
+
+	if filter.ModuleID != 0 {
+		query = query.Where("chat_sessions.module_id = ?", filter.ModuleID)
+	}
+
+	if !filter.IncludeArchived {
+		query = query.Where("chat_sessions.archived_at IS NULL")
+	}
+
+	// Orden (por defecto, actividad más reciente primero) y rango de creación
+	query, sort, err := filter.Spec.Apply(query, ChatSessionListSchema)
+	if err != nil {
+		return nil, err
+	}
+	query = query.Order(sort.OrderBy())
+
+	// Paginación
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var previews []ChatSessionPreview
+	if err := query.Scan(&previews).Error; err != nil {
+		return nil, fmt.Errorf("error inesperado listando sesiones de chat con vista previa: %w", err)
+	}
+
+	return previews, nil
+}
+
+// ArchiveChatSession implements ChatRepo.
+func (c *chatRepo) ArchiveChatSession(ctx context.Context, id uint) error {
+	return c.setArchived(ctx, id, true)
+}
+
+// UnarchiveChatSession implements ChatRepo.
+func (c *chatRepo) UnarchiveChatSession(ctx context.Context, id uint) error {
+	return c.setArchived(ctx, id, false)
+}
+
+// setArchived cambia el estado de archivado de una sesión validando la transición.
+func (c *chatRepo) setArchived(ctx context.Context, id uint, archived bool) error {
+	if id == 0 {
+		return ErrInvalidChatSessionID
+	}
+
+	var session models.ChatSession
+	err := database.Conn(ctx, c.db).First(&session, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChatSessionNotFound
+		}
+		return err
+	}
+
+	if archived && session.ArchivedAt != nil {
+		return ErrChatSessionAlreadyArchived
+	}
+	if !archived && session.ArchivedAt == nil {
+		return ErrChatSessionNotArchived
+	}
+
+	var value any
+	if archived {
+		value = time.Now()
+	}
+
+	result := database.Conn(ctx, c.db).
+		Model(&models.ChatSession{}).
+		Where("id = ?", id).
+		Update("archived_at", value)
+	if result.Error != nil {
+		return fmt.Errorf("error inesperado archivando la sesión de chat: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrChatSessionNotFound
+	}
+
+	return nil
+}
+
 // SearchMessagesByContent implements ChatRepo.
 func (c *chatRepo) SearchMessagesByContent(
 	ctx context.Context,
@@ -583,7 +837,7 @@ func (c *chatRepo) SearchMessagesByContent(
 		return nil, ErrInvalidSearchQuery
 	}
 
-	query := c.db.WithContext(ctx).Model(&models.ChatMessage{})
+	query := database.Conn(ctx, c.db).Model(&models.ChatMessage{})
 
 	// Filtrar por conversación si se especifica
 	if conversationID != 0 {
@@ -623,7 +877,8 @@ func (c *chatRepo) SearchMessagesByEmbedding(ctx context.Context, embedding pgve
 	}
 
 	var messages []models.ChatMessage
-	result := c.db.WithContext(ctx).
+	result := database.Conn(ctx, c.db).
+		Clauses(database.SemanticReplica()).
 		Model(&models.ChatMessage{}).
 		Select("*, embedding <=> ? AS distance", embedding).
 		Order("distance").
@@ -662,7 +917,8 @@ func (c *chatRepo) SearchMessagesByEmbeddingWithFilter(
 	}
 
 	// Construir query
-	query := c.db.WithContext(ctx).
+	query := database.Conn(ctx, c.db).
+		Clauses(database.SemanticReplica()).
 		Model(&models.ChatMessage{}).
 		Select("*, embedding <=> ? AS distance", embedding)
 
@@ -674,6 +930,10 @@ func (c *chatRepo) SearchMessagesByEmbeddingWithFilter(
 		query = query.Where("role = ?", filter.Role)
 	}
 
+	if filter.BeforeID != 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	}
+
 	// El operador <=> retorna una distancia (0 = idéntico, mayor = menos parecido).
 	// Si tienes un threshold en similitud (0.0–1.0), se transforma a distancia:
 	// similitud = 1 - distancia  => distancia <= 1 - similitud
@@ -703,10 +963,83 @@ func (c *chatRepo) UpdateChatMessage(ctx context.Context, id uint, updates ChatM
 
 // UpdateChatSession implements ChatRepo.
 func (c *chatRepo) UpdateChatSession(ctx context.Context, id uint, updates ChatSessionUpdates) error {
-	panic("unimplemented")
+	if id == 0 {
+		return ErrInvalidChatSessionID
+	}
+
+	// Construir map de actualizaciones dinámicamente
+	updateMap := make(map[string]any)
+
+	if updates.UserName != nil {
+		if *updates.UserName == "" {
+			return ErrMissingRequiredFields
+		}
+		updateMap["user_name"] = *updates.UserName
+	}
+
+	if updates.AgentName != nil {
+		if *updates.AgentName == "" {
+			return ErrInvalidAgentName
+		}
+		updateMap["agent_name"] = *updates.AgentName
+	}
+
+	if updates.Title != nil {
+		if *updates.Title == "" || len([]rune(*updates.Title)) > 200 {
+			return ErrInvalidSessionTitle
+		}
+		updateMap["title"] = *updates.Title
+	}
+
+	if updates.ModuleID != nil {
+		// 0 desvincula el hilo del módulo (module_id es una FK, 0 no es válido)
+		if *updates.ModuleID == 0 {
+			updateMap["module_id"] = gorm.Expr("NULL")
+		} else {
+			updateMap["module_id"] = *updates.ModuleID
+		}
+	}
+
+	// Si no hay nada que actualizar, no hacer nada
+	if len(updateMap) == 0 {
+		return nil
+	}
+
+	result := database.Conn(ctx, c.db).
+		Model(&models.ChatSession{}).
+		Where("id = ?", id).
+		Updates(updateMap)
+	if result.Error != nil {
+		return fmt.Errorf("error inesperado actualizando la sesión de chat: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrChatSessionNotFound
+	}
+
+	return nil
 }
 
 // UpdateMessageEmbedding implements ChatRepo.
 func (c *chatRepo) UpdateMessageEmbedding(ctx context.Context, id uint, embedding pgvector.Vector) error {
-	panic("unimplemented")
+	if id == 0 {
+		return ErrInvalidChatMessageID
+	}
+	if len(embedding.Slice()) != 1536 {
+		return ErrInvalidEmbedding
+	}
+
+	result := database.Conn(ctx, c.db).
+		Model(&models.ChatMessage{}).
+		Where("id = ?", id).
+		Update("embedding", embedding)
+	if result.Error != nil {
+		return fmt.Errorf("error inesperado actualizando el embedding del mensaje: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrChatMessageNotFound
+	}
+
+	return nil
 }
@@ -1,53 +1,63 @@
 package chatrepo
 
-import "errors"
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
 
 var (
 	// Errores de búsqueda - ChatSession
-	ErrChatSessionNotFound = errors.New("sesión de chat no encontrada")
+	ErrChatSessionNotFound = apperror.New(apperror.NotFound, "chat.session_not_found", "sesión de chat no encontrada")
 
 	// Errores de configuración
-	ErrDatabaseRequired = errors.New("chat error: la conexión a la base de datos es requerida")
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "chat.database_required", "chat error: la conexión a la base de datos es requerida")
 
 	// Errores de validación - ChatSession
-	ErrChatSessionNil        = errors.New("chat error: la sesión de chat no puede ser nil")
-	ErrInvalidChatSessionID  = errors.New("chat error: id de sesión de chat inválido")
-	ErrInvalidUserID         = errors.New("chat error: id de usuario inválido")
-	ErrInvalidAgentName      = errors.New("chat error: nombre del agente inválido")
-	ErrMissingRequiredFields = errors.New("chat error: faltan campos requeridos: user_id/agent_name")
+	ErrChatSessionNil        = apperror.New(apperror.Validation, "chat.session_nil", "chat error: la sesión de chat no puede ser nil")
+	ErrInvalidChatSessionID  = apperror.New(apperror.Validation, "chat.invalid_session_id", "chat error: id de sesión de chat inválido")
+	ErrInvalidUserID         = apperror.New(apperror.Validation, "chat.invalid_user_id", "chat error: id de usuario inválido")
+	ErrInvalidAgentName      = apperror.New(apperror.Validation, "chat.invalid_agent_name", "chat error: nombre del agente inválido")
+	ErrMissingRequiredFields = apperror.New(apperror.Validation, "chat.missing_required_fields", "chat error: faltan campos requeridos: user_id/agent_name")
 
 	// Errores de relación - ChatSession
-	ErrUserNotExists = errors.New("chat error: el usuario especificado no existe")
+	ErrUserNotExists = apperror.New(apperror.NotFound, "chat.user_not_exists", "chat error: el usuario especificado no existe")
 
-	// Errores de unicidad/conflicto - ChatSession
-	ErrUserAlreadyHasSession = errors.New("chat error: el usuario ya tiene una sesión de chat activa")
+	// Errores de estado - ChatSession
+	ErrChatSessionArchived        = apperror.New(apperror.Conflict, "chat.session_archived", "chat error: la sesión de chat está archivada")
+	ErrChatSessionAlreadyArchived = apperror.New(apperror.Conflict, "chat.session_already_archived", "chat error: la sesión de chat ya está archivada")
+	ErrChatSessionNotArchived     = apperror.New(apperror.Conflict, "chat.session_not_archived", "chat error: la sesión de chat no está archivada")
+	ErrInvalidSessionTitle        = apperror.New(apperror.Validation, "chat.invalid_session_title", "chat error: título de sesión inválido (máximo 200 caracteres)")
 
 	// Errores de búsqueda - ChatMessage
-	ErrChatMessageNotFound = errors.New("mensaje de chat no encontrado")
+	ErrChatMessageNotFound = apperror.New(apperror.NotFound, "chat.message_not_found", "mensaje de chat no encontrado")
 
 	// Errores de validación - ChatMessage
-	ErrChatMessageNil        = errors.New("chat error: el mensaje de chat no puede ser nil")
-	ErrChatMessageInvalid    = errors.New("chat error: mensaje invalido")
-	ErrInvalidChatMessageID  = errors.New("chat error: id de mensaje de chat inválido")
-	ErrInvalidConversationID = errors.New("chat error: id de conversación inválido")
-	ErrInvalidMessageRole    = errors.New("chat error: rol de mensaje inválido (debe ser: user, assistant, system, tool)")
-	ErrInvalidMessageContent = errors.New("chat error: contenido del mensaje inválido")
-	ErrInvalidToolCallID     = errors.New("chat error: id de tool call inválido")
-	ErrInvalidToolCalls      = errors.New("chat error: tool calls inválidos")
-	ErrInvalidEmbedding      = errors.New("chat error: embedding inválido (debe tener 1536 dimensiones)")
+	ErrChatMessageNil        = apperror.New(apperror.Validation, "chat.message_nil", "chat error: el mensaje de chat no puede ser nil")
+	ErrChatMessageInvalid    = apperror.New(apperror.Validation, "chat.message_invalid", "chat error: mensaje invalido")
+	ErrInvalidChatMessageID  = apperror.New(apperror.Validation, "chat.invalid_message_id", "chat error: id de mensaje de chat inválido")
+	ErrInvalidConversationID = apperror.New(apperror.Validation, "chat.invalid_conversation_id", "chat error: id de conversación inválido")
+	ErrInvalidMessageRole    = apperror.New(apperror.Validation, "chat.invalid_message_role", "chat error: rol de mensaje inválido (debe ser: user, assistant, system, tool)")
+	ErrInvalidMessageContent = apperror.New(apperror.Validation, "chat.invalid_message_content", "chat error: contenido del mensaje inválido")
+	ErrInvalidToolCallID     = apperror.New(apperror.Validation, "chat.invalid_tool_call_id", "chat error: id de tool call inválido")
+	ErrInvalidToolCalls      = apperror.New(apperror.Validation, "chat.invalid_tool_calls", "chat error: tool calls inválidos")
+	ErrInvalidEmbedding      = apperror.New(apperror.Validation, "chat.invalid_embedding", "chat error: embedding inválido (debe tener 1536 dimensiones)")
 
 	// Errores de relación - ChatMessage
-	ErrConversationNotExists = errors.New("chat error: la conversación especificada no existe")
+	ErrConversationNotExists = apperror.New(apperror.NotFound, "chat.conversation_not_exists", "chat error: la conversación especificada no existe")
 
 	// Errores de negocio/estado
-	ErrConversationHasMessages     = errors.New("chat error: la conversación tiene mensajes y no puede ser eliminada")
-	ErrCannotDeleteSystemMessage   = errors.New("chat error: no se puede eliminar un mensaje del sistema")
-	ErrCannotModifyArchivedMessage = errors.New("chat error: no se puede modificar un mensaje archivado")
+	ErrConversationHasMessages     = apperror.New(apperror.Conflict, "chat.conversation_has_messages", "chat error: la conversación tiene mensajes y no puede ser eliminada")
+	ErrCannotDeleteSystemMessage   = apperror.New(apperror.Conflict, "chat.cannot_delete_system_message", "chat error: no se puede eliminar un mensaje del sistema")
+	ErrCannotModifyArchivedMessage = apperror.New(apperror.Conflict, "chat.cannot_modify_archived_message", "chat error: no se puede modificar un mensaje archivado")
+
+	// Errores de búsqueda - ChatSummary
+	ErrChatSummaryNotFound = apperror.New(apperror.NotFound, "chat.summary_not_found", "resumen de chat no encontrado")
+
+	// Errores de validación - ChatSummary
+	ErrChatSummaryNil     = apperror.New(apperror.Validation, "chat.summary_nil", "chat error: el resumen de chat no puede ser nil")
+	ErrInvalidSummaryText = apperror.New(apperror.Validation, "chat.invalid_summary_text", "chat error: el contenido del resumen no puede estar vacío")
 
 	// Errores de búsqueda semántica
-	ErrInvalidSearchQuery     = errors.New("chat error: consulta de búsqueda inválida")
-	ErrInvalidLimit           = errors.New("chat error: límite inválido (debe ser > 0 y <= 100)")
-	ErrInvalidOffset          = errors.New("chat error: offset inválido (debe ser >= 0)")
-	ErrSimilarityThreshold    = errors.New("chat error: umbral de similitud inválido (debe ser entre 0.0 y 1.0)")
-	ErrNoSimilarMessagesFound = errors.New("chat error: no se encontraron mensajes similares")
+	ErrInvalidSearchQuery     = apperror.New(apperror.Validation, "chat.invalid_search_query", "chat error: consulta de búsqueda inválida")
+	ErrInvalidLimit           = apperror.New(apperror.Validation, "chat.invalid_limit", "chat error: límite inválido (debe ser > 0 y <= 100)")
+	ErrInvalidOffset          = apperror.New(apperror.Validation, "chat.invalid_offset", "chat error: offset inválido (debe ser >= 0)")
+	ErrSimilarityThreshold    = apperror.New(apperror.Validation, "chat.similarity_threshold", "chat error: umbral de similitud inválido (debe ser entre 0.0 y 1.0)")
+	ErrNoSimilarMessagesFound = apperror.New(apperror.NotFound, "chat.no_similar_messages_found", "chat error: no se encontraron mensajes similares")
 )
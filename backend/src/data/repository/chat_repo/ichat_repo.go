@@ -3,7 +3,9 @@ package chatrepo
 import (
 	"context"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	"github.com/pgvector/pgvector-go"
 )
 
@@ -14,9 +16,10 @@ import (
 // Lectura de sesiones de chat
 type ChatSessionReader interface {
 	ChatSessionByID(ctx context.Context, id uint) (*models.ChatSession, error)
-	ChatSessionByUserID(ctx context.Context, userID uint) (*models.ChatSession, error)
+	ChatSessionByUserID(ctx context.Context, userID uint) (*models.ChatSession, error) // Hilo activo más reciente del usuario
 	ListChatSessions(ctx context.Context, filter ChatSessionFilter) ([]models.ChatSession, error)
-	ChatSessionWithMessages(ctx context.Context, sessionID uint) (*models.ChatSession, error) // Con Messages incluidos
+	ListChatSessionPreviews(ctx context.Context, filter ChatSessionFilter) ([]ChatSessionPreview, error) // Con vista previa del último mensaje
+	ChatSessionWithMessages(ctx context.Context, sessionID uint) (*models.ChatSession, error)            // Con Messages incluidos
 	ChatSessionExists(ctx context.Context, userID uint) (bool, error)
 }
 
@@ -24,8 +27,10 @@ type ChatSessionReader interface {
 type ChatSessionWriter interface {
 	CreateChatSession(ctx context.Context, session *models.ChatSession) (*models.ChatSession, error)
 	UpdateChatSession(ctx context.Context, id uint, updates ChatSessionUpdates) error
+	ArchiveChatSession(ctx context.Context, id uint) error
+	UnarchiveChatSession(ctx context.Context, id uint) error
 	DeleteChatSession(ctx context.Context, id uint) error
-	DeleteChatSessionByUserID(ctx context.Context, userID uint) error
+	DeleteChatSessionByUserID(ctx context.Context, userID uint) error // Todos los hilos del usuario
 }
 
 // ============================================================================
@@ -35,11 +40,13 @@ type ChatSessionWriter interface {
 // Lectura de mensajes de chat
 type ChatMessageReader interface {
 	ChatMessageByID(ctx context.Context, id uint) (*models.ChatMessage, error)
-	ListChatMessages(ctx context.Context, filter ChatMessageFilter) ([]models.ChatMessage, error)
+	ListChatMessages(ctx context.Context, filter ChatMessageFilter) (pagination.Page[models.ChatMessage], error) // Página en orden cronológico ascendente
 	ChatMessagesByConversationID(ctx context.Context, conversationID uint) ([]models.ChatMessage, error)
 	ChatMessagesByRole(ctx context.Context, conversationID uint, role string) ([]models.ChatMessage, error)
 	ChatMessageByToolCallID(ctx context.Context, toolCallID string) (*models.ChatMessage, error)
 	GetConversationHistory(ctx context.Context, conversationID uint, limit int) ([]models.ChatMessage, error)
+	MessagesAfterID(ctx context.Context, conversationID, afterID uint, limit int) ([]models.ChatMessage, error) // Orden cronológico ascendente
+	CountMessagesAfterID(ctx context.Context, conversationID, afterID uint) (int64, error)
 
 	// Búsquedas semánticas con embeddings
 	SearchMessagesByEmbedding(ctx context.Context, embedding pgvector.Vector, limit int) ([]models.ChatMessage, error)
@@ -63,6 +70,20 @@ type ChatMessageWriter interface {
 	UpdateMessageEmbedding(ctx context.Context, id uint, embedding pgvector.Vector) error
 }
 
+// ============================================================================
+// ChatSummary Interfaces
+// ============================================================================
+
+// Lectura de resúmenes de conversación
+type ChatSummaryReader interface {
+	ChatSummaryByConversationID(ctx context.Context, conversationID uint) (*models.ChatSummary, error)
+}
+
+// Escritura de resúmenes de conversación
+type ChatSummaryWriter interface {
+	UpsertChatSummary(ctx context.Context, summary *models.ChatSummary) (*models.ChatSummary, error)
+}
+
 // ============================================================================
 // Interfaces principales
 // ============================================================================
@@ -79,10 +100,17 @@ type ChatMessageRepo interface {
 	ChatMessageWriter
 }
 
+// Interfaz principal para ChatSummary
+type ChatSummaryRepo interface {
+	ChatSummaryReader
+	ChatSummaryWriter
+}
+
 // Interfaz combinada para todo el chat
 type ChatRepo interface {
 	ChatSessionRepo
 	ChatMessageRepo
+	ChatSummaryRepo
 }
 
 // ============================================================================
@@ -91,24 +119,50 @@ type ChatRepo interface {
 
 // Filtro para sesiones de chat
 type ChatSessionFilter struct {
-	UserID    uint   // Filtrar por usuario específico
-	AgentName string // Filtrar por nombre del agente
-	Limit     int
-	Offset    int
+	UserID          uint         // Filtrar por usuario específico
+	AgentName       string       // Filtrar por nombre del agente
+	ModuleID        uint         // Filtrar por módulo asociado
+	IncludeArchived bool         // false = solo hilos activos
+	Spec            listing.Spec // Orden, rango de creación y eliminados
+	Limit           int
+	Offset          int
+}
+
+// Columnas por las que se puede ordenar ListChatSessions y ListChatSessionPreviews
+var ChatSessionListSchema = listing.Schema{
+	Table:    "chat_sessions",
+	Sortable: []string{"last_message_at", "created_at", "updated_at", "title"},
+	Default:  "-last_message_at", // Actividad más reciente primero; los hilos sin mensajes al final
+}
+
+// Sesión de chat con la vista previa de su último mensaje
+type ChatSessionPreview struct {
+	models.ChatSession
+	LastMessageContent string // Contenido del último mensaje ("" si no hay mensajes)
+	LastMessageRole    string // Rol del último mensaje
+	MessageCount       int64  // Total de mensajes del hilo
 }
 
 // Filtro para mensajes de chat
 type ChatMessageFilter struct {
-	ConversationID uint   // Filtrar por conversación específica
-	Role           string // Filtrar por rol del mensaje
-	ToolCallID     string // Filtrar por tool call ID
-	Limit          int
+	ConversationID uint              // Filtrar por conversación específica
+	Roles          []string          // Filtrar por uno o varios roles del mensaje
+	ToolCallID     string            // Filtrar por tool call ID
+	Spec           listing.Spec      // Orden, rango de creación y eliminados
+	Page           pagination.Params // Con el orden por defecto, Next = mensajes anteriores al cursor
+}
+
+// Columnas por las que se puede ordenar ListChatMessages
+var ChatMessageListSchema = listing.Schema{
+	Sortable: []string{"created_at"},
+	Default:  "-created_at",
 }
 
 // Filtro para búsquedas semánticas de mensajes
 type SemanticMessageFilter struct {
 	ConversationID uint    // Filtrar por conversación específica
 	Role           string  // Filtrar por rol del mensaje
+	BeforeID       uint    // Solo mensajes con id menor (0 = sin límite); evita repetir los turnos recientes
 	Limit          int     // Límite de resultados
 	MinSimilarity  float32 // Umbral mínimo de similitud (0.0 a 1.0)
 }
@@ -117,6 +171,8 @@ type SemanticMessageFilter struct {
 type ChatSessionUpdates struct {
 	UserName  *string // Nombre del usuario actualizado
 	AgentName *string // Nombre del agente actualizado
+	Title     *string // Título del hilo
+	ModuleID  *uint   // Módulo asociado; 0 = hilo general (sin módulo)
 }
 
 // Estructura para actualizaciones parciales de ChatMessage
@@ -145,14 +201,3 @@ const (
 	RoleSystem    = "system"
 	RoleTool      = "tool"
 )
-
-// ============================================================================
-// Constantes de ordenamiento
-// ============================================================================
-
-const (
-	OrderByCreatedAtASC  = "created_at ASC"
-	OrderByCreatedAtDESC = "created_at DESC"
-	OrderByUpdatedAtASC  = "updated_at ASC"
-	OrderByUpdatedAtDESC = "updated_at DESC"
-)
@@ -0,0 +1,284 @@
+package dataexportrepo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type dataExportRepo struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepo(db *gorm.DB) (DataExportRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &dataExportRepo{
+		db: db,
+	}, nil
+}
+
+// CreateDataExport implements DataExportRepo.
+func (d *dataExportRepo) CreateDataExport(ctx context.Context, export *models.DataExport) (*models.DataExport, error) {
+	if export == nil {
+		return nil, ErrExportNil
+	}
+	if export.UserID == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	export.Status = StatusPending
+	if err := database.Conn(ctx, d.db).Create(export).Error; err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "ux_data_exports_active") {
+			return nil, ErrExportInProgress
+		}
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// DataExportByID implements DataExportRepo.
+func (d *dataExportRepo) DataExportByID(ctx context.Context, id uint) (*models.DataExport, error) {
+	if id == 0 {
+		return nil, ErrInvalidExportID
+	}
+
+	var export models.DataExport
+	err := database.Conn(ctx, d.db).Omit("archive").First(&export, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// DataExportByTokenHash implements DataExportRepo.
+func (d *dataExportRepo) DataExportByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.DataExport, error) {
+	if tokenHash == "" {
+		return nil, ErrDownloadNotFound
+	}
+
+	var export models.DataExport
+	err := database.Conn(ctx, d.db).
+		Where("token_hash = ? AND status = ? AND token_expires_at > ?", tokenHash, StatusReady, now).
+		First(&export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDownloadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// ClaimDataExports implements DataExportRepo.
+func (d *dataExportRepo) ClaimDataExports(ctx context.Context, limit int, staleBefore time.Time) ([]models.DataExport, error) {
+	if limit <= 0 || limit > 100 {
+		return nil, ErrInvalidLimit
+	}
+
+	// FOR UPDATE SKIP LOCKED evita que dos workers tomen la misma exportación
+	var exports []models.DataExport
+	err := database.Conn(ctx, d.db).Raw(`
+		UPDATE data_exports
+		SET status = ?, attempts = attempts + 1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM data_exports
+			WHERE deleted_at IS NULL
+				AND (status = ? OR (status = ? AND updated_at < ?))
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, StatusProcessing, StatusPending, StatusProcessing, staleBefore, limit).Scan(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// CompleteDataExport implements DataExportRepo.
+func (d *dataExportRepo) CompleteDataExport(ctx context.Context, id uint, archive []byte, finishedAt time.Time) error {
+	if id == 0 {
+		return ErrInvalidExportID
+	}
+
+	result := database.Conn(ctx, d.db).
+		Model(&models.DataExport{}).
+		Where("id = ? AND status = ?", id, StatusProcessing).
+		Updates(map[string]any{
+			"status":      StatusReady,
+			"archive":     archive,
+			"size_bytes":  len(archive),
+			"finished_at": finishedAt,
+			"last_error":  "",
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrExportNotFound
+	}
+
+	return nil
+}
+
+// FailDataExport implements DataExportRepo.
+func (d *dataExportRepo) FailDataExport(ctx context.Context, id uint, reason string, maxAttempts int) error {
+	if id == 0 {
+		return ErrInvalidExportID
+	}
+
+	result := database.Conn(ctx, d.db).
+		Model(&models.DataExport{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":     gorm.Expr("CASE WHEN attempts >= ? THEN ? ELSE ? END", maxAttempts, StatusFailed, StatusPending),
+			"last_error": reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrExportNotFound
+	}
+
+	return nil
+}
+
+// SetDownloadToken implements DataExportRepo.
+func (d *dataExportRepo) SetDownloadToken(ctx context.Context, id uint, tokenHash string, expiresAt time.Time) error {
+	if id == 0 {
+		return ErrInvalidExportID
+	}
+
+	// Un enlace nuevo reemplaza al anterior
+	result := database.Conn(ctx, d.db).
+		Model(&models.DataExport{}).
+		Where("id = ? AND status = ?", id, StatusReady).
+		Updates(map[string]any{
+			"token_hash":       tokenHash,
+			"token_expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrExportNotReady
+	}
+
+	return nil
+}
+
+// MarkDownloaded implements DataExportRepo.
+func (d *dataExportRepo) MarkDownloaded(ctx context.Context, id uint, at time.Time) error {
+	if id == 0 {
+		return ErrInvalidExportID
+	}
+
+	return database.Conn(ctx, d.db).
+		Model(&models.DataExport{}).
+		Where("id = ?", id).
+		Update("downloaded_at", at).Error
+}
+
+// ExpireDataExports implements DataExportRepo.
+func (d *dataExportRepo) ExpireDataExports(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	result := database.Conn(ctx, d.db).
+		Model(&models.DataExport{}).
+		Where("status = ? AND finished_at < ?", StatusReady, finishedBefore).
+		Updates(map[string]any{
+			"status":           StatusExpired,
+			"archive":          nil,
+			"token_hash":       "",
+			"token_expires_at": nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// CollectUserData implements DataExportRepo.
+func (d *dataExportRepo) CollectUserData(ctx context.Context, userID uint, includeEmbeddings bool) (*UserData, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	// Se exporta todo lo que se guarda, también lo que está en la papelera.
+	// Session permite reutilizar db en varias consultas.
+	db := database.Conn(ctx, d.db).Unscoped().Session(&gorm.Session{})
+	withoutEmbedding := func(query *gorm.DB) *gorm.DB {
+		if includeEmbeddings {
+			return query
+		}
+		return query.Omit("embedding")
+	}
+
+	data := &UserData{}
+	err := db.First(&data.User, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.
+		Preload("Module", func(query *gorm.DB) *gorm.DB { return query.Unscoped() }).
+		Where("user_id = ?", userID).
+		Order("id").
+		Find(&data.Enrollments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.
+		Preload("Messages", func(query *gorm.DB) *gorm.DB {
+			return withoutEmbedding(query.Unscoped().Order("created_at, id"))
+		}).
+		Where("user_id = ?", userID).
+		Order("id").
+		Find(&data.Sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.
+		Where("conversation_id IN (?)", db.Model(&models.ChatSession{}).Select("id").Where("user_id = ?", userID)).
+		Order("conversation_id").
+		Find(&data.Summaries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = withoutEmbedding(db).
+		Where("user_id = ?", userID).
+		Order("created_at, id").
+		Find(&data.Insights).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Where("user_id = ?", userID).Order("id").Find(&data.Devices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Where("user_id = ?", userID).Order("id").Find(&data.Identities).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,23 @@
+package dataexportrepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrExportNotFound   = apperror.New(apperror.NotFound, "data_export.not_found", "data export error: exportación no encontrada")
+	ErrDownloadNotFound = apperror.New(apperror.NotFound, "data_export.download_not_found", "data export error: el enlace de descarga no existe o venció")
+	ErrUserNotFound     = apperror.New(apperror.NotFound, "data_export.user_not_found", "data export error: usuario no encontrado")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "data_export.database_required", "data export error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrExportNil       = apperror.New(apperror.Validation, "data_export.nil", "data export error: la exportación no puede ser nil")
+	ErrInvalidUserID   = apperror.New(apperror.Validation, "data_export.invalid_user_id", "data export error: id de usuario inválido")
+	ErrInvalidExportID = apperror.New(apperror.Validation, "data_export.invalid_export_id", "data export error: id de exportación inválido")
+	ErrInvalidLimit    = apperror.New(apperror.Validation, "data_export.invalid_limit", "data export error: límite inválido (debe ser > 0 y <= 100)")
+
+	// Errores de conflicto/estado
+	ErrExportInProgress = apperror.New(apperror.Conflict, "data_export.in_progress", "data export error: ya tienes una exportación en curso")
+	ErrExportNotReady   = apperror.New(apperror.Conflict, "data_export.not_ready", "data export error: la exportación todavía no está lista o ya venció")
+)
@@ -0,0 +1,62 @@
+package dataexportrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de exportaciones
+type DataExportReader interface {
+	// DataExportByID devuelve la exportación sin el ZIP.
+	DataExportByID(ctx context.Context, id uint) (*models.DataExport, error)
+	// DataExportByTokenHash devuelve la exportación con el ZIP si el enlace sigue vigente.
+	DataExportByTokenHash(ctx context.Context, tokenHash string, now time.Time) (*models.DataExport, error)
+	// CollectUserData reúne todo lo que se guarda del usuario, esté o no en la papelera.
+	CollectUserData(ctx context.Context, userID uint, includeEmbeddings bool) (*UserData, error)
+}
+
+// Escritura de exportaciones
+type DataExportWriter interface {
+	CreateDataExport(ctx context.Context, export *models.DataExport) (*models.DataExport, error) // Falla si hay otra en curso
+	// ClaimDataExports toma hasta limit exportaciones pendientes (o en proceso desde
+	// antes de staleBefore, por un worker que se cayó) y las marca en proceso.
+	ClaimDataExports(ctx context.Context, limit int, staleBefore time.Time) ([]models.DataExport, error)
+	CompleteDataExport(ctx context.Context, id uint, archive []byte, finishedAt time.Time) error
+	// FailDataExport registra el error; la exportación vuelve a quedar pendiente
+	// hasta agotar maxAttempts, y entonces queda como fallida.
+	FailDataExport(ctx context.Context, id uint, reason string, maxAttempts int) error
+	SetDownloadToken(ctx context.Context, id uint, tokenHash string, expiresAt time.Time) error // Solo si está lista
+	MarkDownloaded(ctx context.Context, id uint, at time.Time) error
+	// ExpireDataExports borra el ZIP de las exportaciones terminadas antes de
+	// finishedBefore y devuelve cuántas vencieron.
+	ExpireDataExports(ctx context.Context, finishedBefore time.Time) (int64, error)
+}
+
+// Interfaz principal
+type DataExportRepo interface {
+	DataExportReader
+	DataExportWriter
+}
+
+// Constantes de estado
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusReady      = "ready"
+	StatusFailed     = "failed"
+	StatusExpired    = "expired"
+)
+
+// UserData es todo lo que se guarda de un usuario. Los hilos traen sus mensajes
+// en orden cronológico y las inscripciones, su módulo.
+type UserData struct {
+	User        models.User
+	Enrollments []models.Enrollment
+	Sessions    []models.ChatSession
+	Summaries   []models.ChatSummary
+	Insights    []models.Insight
+	Devices     []models.UserSession
+	Identities  []models.ExternalIdentity
+}
@@ -0,0 +1,142 @@
+package embeddingjobrepo
+
+import (
+	"context"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type embeddingJobRepo struct {
+	db *gorm.DB
+}
+
+func NewEmbeddingJobRepo(db *gorm.DB) (EmbeddingJobRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &embeddingJobRepo{
+		db: db,
+	}, nil
+}
+
+// EnqueueEmbeddingJobs implements EmbeddingJobRepo.
+func (e *embeddingJobRepo) EnqueueEmbeddingJobs(ctx context.Context, entityType string, entityIDs []uint) error {
+	if entityType != EntityTopic && entityType != EntityChatMessage {
+		return ErrInvalidEntityType
+	}
+	if len(entityIDs) == 0 {
+		return nil
+	}
+
+	jobs := make([]models.EmbeddingJob, 0, len(entityIDs))
+	for _, id := range entityIDs {
+		if id == 0 {
+			return ErrInvalidEntityID
+		}
+		jobs = append(jobs, models.EmbeddingJob{
+			EntityType: entityType,
+			EntityID:   id,
+			Status:     StatusPending,
+		})
+	}
+
+	return database.Conn(ctx, e.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"status":     StatusPending,
+				"attempts":   0,
+				"last_error": "",
+				"updated_at": gorm.Expr("NOW()"),
+				"deleted_at": nil,
+			}),
+		}).
+		CreateInBatches(jobs, 100).Error
+}
+
+// ClaimEmbeddingJobs implements EmbeddingJobRepo.
+func (e *embeddingJobRepo) ClaimEmbeddingJobs(ctx context.Context, limit int) ([]models.EmbeddingJob, error) {
+	if limit <= 0 || limit > 100 {
+		return nil, ErrInvalidLimit
+	}
+
+	// FOR UPDATE SKIP LOCKED evita que dos workers tomen el mismo trabajo
+	var jobs []models.EmbeddingJob
+	err := database.Conn(ctx, e.db).Raw(`
+		UPDATE embedding_jobs
+		SET status = ?, attempts = attempts + 1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM embedding_jobs
+			WHERE status = ? AND deleted_at IS NULL
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, StatusProcessing, StatusPending, limit).Scan(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// CompleteEmbeddingJob implements EmbeddingJobRepo.
+func (e *embeddingJobRepo) CompleteEmbeddingJob(ctx context.Context, id uint) error {
+	if id == 0 {
+		return ErrInvalidJobID
+	}
+
+	result := database.Conn(ctx, e.db).
+		Model(&models.EmbeddingJob{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":     StatusDone,
+			"last_error": "",
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrEmbeddingJobNotFound
+	}
+
+	return nil
+}
+
+// FailEmbeddingJob implements EmbeddingJobRepo.
+func (e *embeddingJobRepo) FailEmbeddingJob(ctx context.Context, id uint, reason string, maxAttempts int) error {
+	if id == 0 {
+		return ErrInvalidJobID
+	}
+
+	result := database.Conn(ctx, e.db).
+		Model(&models.EmbeddingJob{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":     gorm.Expr("CASE WHEN attempts >= ? THEN ? ELSE ? END", maxAttempts, StatusFailed, StatusPending),
+			"last_error": reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrEmbeddingJobNotFound
+	}
+
+	return nil
+}
+
+// CountEmbeddingJobsByStatus implements EmbeddingJobRepo.
+func (e *embeddingJobRepo) CountEmbeddingJobsByStatus(ctx context.Context, status string) (int64, error) {
+	var count int64
+	err := database.Conn(ctx, e.db).
+		Model(&models.EmbeddingJob{}).
+		Where("status = ?", status).
+		Count(&count).Error
+
+	return count, err
+}
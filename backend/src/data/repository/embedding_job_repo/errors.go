@@ -0,0 +1,17 @@
+package embeddingjobrepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "embedding_job.database_required", "embedding job error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrInvalidEntityType = apperror.New(apperror.Validation, "embedding_job.invalid_entity_type", "embedding job error: tipo de entidad inválido (debe ser: topic, chat_message)")
+	ErrInvalidEntityID   = apperror.New(apperror.Validation, "embedding_job.invalid_entity_id", "embedding job error: id de entidad inválido")
+	ErrInvalidJobID      = apperror.New(apperror.Validation, "embedding_job.invalid_job_id", "embedding job error: id de trabajo inválido")
+	ErrInvalidLimit      = apperror.New(apperror.Validation, "embedding_job.invalid_limit", "embedding job error: límite inválido (debe ser > 0 y <= 100)")
+
+	// Errores de búsqueda
+	ErrEmbeddingJobNotFound = apperror.New(apperror.NotFound, "embedding_job.not_found", "trabajo de embedding no encontrado")
+)
@@ -0,0 +1,46 @@
+package embeddingjobrepo
+
+import (
+	"context"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de trabajos de embedding
+type EmbeddingJobReader interface {
+	CountEmbeddingJobsByStatus(ctx context.Context, status string) (int64, error)
+}
+
+// Escritura de trabajos de embedding
+type EmbeddingJobWriter interface {
+	// EnqueueEmbeddingJobs encola (o re-encola) el embedding de cada entidad. Si ya
+	// existe un trabajo para la entidad, vuelve a quedar pendiente.
+	EnqueueEmbeddingJobs(ctx context.Context, entityType string, entityIDs []uint) error
+	// ClaimEmbeddingJobs toma hasta limit trabajos pendientes y los marca en proceso.
+	// Es seguro llamarlo desde varios workers a la vez.
+	ClaimEmbeddingJobs(ctx context.Context, limit int) ([]models.EmbeddingJob, error)
+	CompleteEmbeddingJob(ctx context.Context, id uint) error
+	// FailEmbeddingJob registra el error; el trabajo vuelve a quedar pendiente hasta
+	// agotar maxAttempts, y entonces queda como fallido.
+	FailEmbeddingJob(ctx context.Context, id uint, reason string, maxAttempts int) error
+}
+
+// Interfaz principal
+type EmbeddingJobRepo interface {
+	EmbeddingJobReader
+	EmbeddingJobWriter
+}
+
+// Tipos de entidad con embedding
+const (
+	EntityTopic       = "topic"
+	EntityChatMessage = "chat_message"
+)
+
+// Constantes de estado
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusDone       = "done"
+	StatusFailed     = "failed"
+)
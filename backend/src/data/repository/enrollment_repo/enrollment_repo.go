@@ -4,7 +4,9 @@ import (
 	"context"
 	"strings"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	"gorm.io/gorm"
 )
 
@@ -41,7 +43,7 @@ func (e *enrollmentRepo) CreateEnrollment(ctx context.Context, enrollment *model
 
 	// Verificar que el usuario existe
 	var userCount int64
-	err := e.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", enrollment.UserID).Count(&userCount).Error
+	err := database.Conn(ctx, e.db).Model(&models.User{}).Where("id = ?", enrollment.UserID).Count(&userCount).Error
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +53,7 @@ func (e *enrollmentRepo) CreateEnrollment(ctx context.Context, enrollment *model
 
 	// Verificar que el módulo existe
 	var moduleCount int64
-	err = e.db.WithContext(ctx).Model(&models.Module{}).Where("id = ?", enrollment.ModuleID).Count(&moduleCount).Error
+	err = database.Conn(ctx, e.db).Model(&models.Module{}).Where("id = ?", enrollment.ModuleID).Count(&moduleCount).Error
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +61,7 @@ func (e *enrollmentRepo) CreateEnrollment(ctx context.Context, enrollment *model
 		return nil, ErrModuleNotExists
 	}
 
-	err = e.db.WithContext(ctx).Create(enrollment).Error
+	err = database.Conn(ctx, e.db).Create(enrollment).Error
 	if err != nil {
 		// Detectar error de constraint único
 		errStr := strings.ToLower(err.Error())
@@ -78,7 +80,7 @@ func (e *enrollmentRepo) DeleteEnrollment(ctx context.Context, id uint) error {
 		return ErrInvalidEnrollmentID
 	}
 
-	result := e.db.WithContext(ctx).Delete(&models.Enrollment{}, id)
+	result := database.Conn(ctx, e.db).Delete(&models.Enrollment{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -97,7 +99,7 @@ func (e *enrollmentRepo) EnrollmentByID(ctx context.Context, id uint) (*models.E
 	}
 
 	var enrollment models.Enrollment
-	err := e.db.WithContext(ctx).First(&enrollment, id).Error
+	err := database.Conn(ctx, e.db).First(&enrollment, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrEnrollmentNotFound
@@ -115,7 +117,7 @@ func (e *enrollmentRepo) EnrollmentWithDetails(ctx context.Context, enrollmentID
 	}
 
 	var enrollment models.Enrollment
-	err := e.db.WithContext(ctx).
+	err := database.Conn(ctx, e.db).
 		Preload("User").
 		Preload("Module").
 		First(&enrollment, enrollmentID).Error
@@ -136,7 +138,7 @@ func (e *enrollmentRepo) EnrollmentsByModule(ctx context.Context, moduleID uint)
 	}
 
 	var enrollments []models.Enrollment
-	err := e.db.WithContext(ctx).
+	err := database.Conn(ctx, e.db).
 		Where("module_id = ?", moduleID).
 		Order("created_at DESC").
 		Find(&enrollments).Error
@@ -154,7 +156,7 @@ func (e *enrollmentRepo) EnrollmentsByUser(ctx context.Context, userID uint) ([]
 	}
 
 	var enrollments []models.Enrollment
-	err := e.db.WithContext(ctx).
+	err := database.Conn(ctx, e.db).
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&enrollments).Error
@@ -175,7 +177,7 @@ func (e *enrollmentRepo) GetUserEnrollment(ctx context.Context, userID uint, mod
 	}
 
 	var enrollment models.Enrollment
-	err := e.db.WithContext(ctx).
+	err := database.Conn(ctx, e.db).
 		Where("user_id = ? AND module_id = ?", userID, moduleID).
 		First(&enrollment).Error
 	if err != nil {
@@ -189,8 +191,8 @@ func (e *enrollmentRepo) GetUserEnrollment(ctx context.Context, userID uint, mod
 }
 
 // ListEnrollments implements EnrollmentRepo.
-func (e *enrollmentRepo) ListEnrollments(ctx context.Context, filter EnrollmentFilter) ([]models.Enrollment, error) {
-	query := e.db.WithContext(ctx).Model(&models.Enrollment{})
+func (e *enrollmentRepo) ListEnrollments(ctx context.Context, filter EnrollmentFilter) (pagination.Page[models.Enrollment], error) {
+	query := database.Conn(ctx, e.db).Model(&models.Enrollment{})
 
 	// Aplicar filtros dinámicos
 	if filter.UserID != 0 {
@@ -199,32 +201,24 @@ func (e *enrollmentRepo) ListEnrollments(ctx context.Context, filter EnrollmentF
 	if filter.ModuleID != 0 {
 		query = query.Where("module_id = ?", filter.ModuleID)
 	}
-	if filter.Status != "" {
-		// Validar estado antes de aplicar filtro
-		if filter.Status != StatusActive && filter.Status != StatusDropped && filter.Status != StatusCompleted {
-			return nil, ErrInvalidStatus
+	if len(filter.Statuses) > 0 {
+		// Validar estados antes de aplicar filtro
+		for _, status := range filter.Statuses {
+			if status != StatusActive && status != StatusDropped && status != StatusCompleted {
+				return pagination.Page[models.Enrollment]{}, ErrInvalidStatus
+			}
 		}
-		query = query.Where("status = ?", filter.Status)
-	}
-
-	// Ordenamiento
-	query = query.Order("created_at DESC")
-
-	// Paginación
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
-	}
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
+		query = query.Where("status IN ?", filter.Statuses)
 	}
 
-	var enrollments []models.Enrollment
-	err := query.Find(&enrollments).Error
+	// Orden, rango de creación y eliminados
+	query, sort, err := filter.Spec.Apply(query, EnrollmentListSchema)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Enrollment]{}, err
 	}
 
-	return enrollments, nil
+	// Paginación por cursor sobre (columna de orden, id)
+	return pagination.Find[models.Enrollment](query, filter.Page, sort)
 }
 
 // UpdateEnrollmentStatus implements EnrollmentRepo.
@@ -240,7 +234,7 @@ func (e *enrollmentRepo) UpdateEnrollmentStatus(ctx context.Context, id uint, st
 
 	// Obtener la inscripción actual para validar transiciones
 	var currentEnrollment models.Enrollment
-	err := e.db.WithContext(ctx).First(&currentEnrollment, id).Error
+	err := database.Conn(ctx, e.db).First(&currentEnrollment, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return ErrEnrollmentNotFound
@@ -267,7 +261,7 @@ func (e *enrollmentRepo) UpdateEnrollmentStatus(ctx context.Context, id uint, st
 	}
 
 	// Actualizar el estado
-	result := e.db.WithContext(ctx).
+	result := database.Conn(ctx, e.db).
 		Model(&models.Enrollment{}).
 		Where("id = ?", id).
 		Update("status", status)
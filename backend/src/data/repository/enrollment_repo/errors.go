@@ -1,31 +1,31 @@
 package enrollementrepo
 
-import "errors"
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
 
 var (
 	// Errores de búsqueda
-	ErrEnrollmentNotFound = errors.New("inscripción no encontrada")
+	ErrEnrollmentNotFound = apperror.New(apperror.NotFound, "enrollment.not_found", "inscripción no encontrada")
 
 	// Errores de configuración
-	ErrDatabaseRequired = errors.New("enrollment error: la conexión a la base de datos es requerida")
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "enrollment.database_required", "enrollment error: la conexión a la base de datos es requerida")
 
 	// Errores de validación
-	ErrEnrollmentNil         = errors.New("enrollment error: la inscripción no puede ser nil")
-	ErrInvalidEnrollmentID   = errors.New("enrollment error: id de inscripción inválido")
-	ErrInvalidUserID         = errors.New("enrollment error: id de usuario inválido")
-	ErrInvalidModuleID       = errors.New("enrollment error: id de módulo inválido")
-	ErrMissingRequiredFields = errors.New("enrollment error: faltan campos requeridos: user_id/module_id")
+	ErrEnrollmentNil         = apperror.New(apperror.Validation, "enrollment.nil", "enrollment error: la inscripción no puede ser nil")
+	ErrInvalidEnrollmentID   = apperror.New(apperror.Validation, "enrollment.invalid_enrollment_id", "enrollment error: id de inscripción inválido")
+	ErrInvalidUserID         = apperror.New(apperror.Validation, "enrollment.invalid_user_id", "enrollment error: id de usuario inválido")
+	ErrInvalidModuleID       = apperror.New(apperror.Validation, "enrollment.invalid_module_id", "enrollment error: id de módulo inválido")
+	ErrMissingRequiredFields = apperror.New(apperror.Validation, "enrollment.missing_required_fields", "enrollment error: faltan campos requeridos: user_id/module_id")
 
 	// Errores de relación
-	ErrUserNotExists   = errors.New("enrollment error: el usuario especificado no existe")
-	ErrModuleNotExists = errors.New("enrollment error: el módulo especificado no existe")
+	ErrUserNotExists   = apperror.New(apperror.NotFound, "enrollment.user_not_exists", "enrollment error: el usuario especificado no existe")
+	ErrModuleNotExists = apperror.New(apperror.NotFound, "enrollment.module_not_exists", "enrollment error: el módulo especificado no existe")
 
 	// Errores de unicidad/conflicto
-	ErrUserAlreadyEnrolled = errors.New("enrollment error: el usuario ya está inscrito en este módulo")
+	ErrUserAlreadyEnrolled = apperror.New(apperror.Conflict, "enrollment.user_already_enrolled", "enrollment error: el usuario ya está inscrito en este módulo")
 
 	// Errores de negocio/estado
-	ErrInvalidStatus            = errors.New("enrollment error: estado inválido (debe ser: active, dropped, completed)")
-	ErrEnrollmentAlreadyDropped = errors.New("enrollment error: la inscripción ya está dada de baja")
-	ErrEnrollmentAlreadyActive  = errors.New("enrollment error: la inscripción ya está activa")
-	ErrCannotDropCompleted      = errors.New("enrollment error: no se puede dar de baja una inscripción completada")
+	ErrInvalidStatus            = apperror.New(apperror.Validation, "enrollment.invalid_status", "enrollment error: estado inválido (debe ser: active, dropped, completed)")
+	ErrEnrollmentAlreadyDropped = apperror.New(apperror.Conflict, "enrollment.already_dropped", "enrollment error: la inscripción ya está dada de baja")
+	ErrEnrollmentAlreadyActive  = apperror.New(apperror.Conflict, "enrollment.already_active", "enrollment error: la inscripción ya está activa")
+	ErrCannotDropCompleted      = apperror.New(apperror.Conflict, "enrollment.cannot_drop_completed", "enrollment error: no se puede dar de baja una inscripción completada")
 )
@@ -3,13 +3,15 @@ package enrollementrepo
 import (
 	"context"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 )
 
 // Lectura de inscripciones
 type EnrollmentReader interface {
 	EnrollmentByID(ctx context.Context, id uint) (*models.Enrollment, error)
-	ListEnrollments(ctx context.Context, filter EnrollmentFilter) ([]models.Enrollment, error)
+	ListEnrollments(ctx context.Context, filter EnrollmentFilter) (pagination.Page[models.Enrollment], error)
 	EnrollmentsByUser(ctx context.Context, userID uint) ([]models.Enrollment, error)
 	EnrollmentsByModule(ctx context.Context, moduleID uint) ([]models.Enrollment, error)
 	EnrollmentWithDetails(ctx context.Context, enrollmentID uint) (*models.Enrollment, error) // Con User y Module incluidos
@@ -31,11 +33,17 @@ type EnrollmentRepo interface {
 
 // Filtro para inscripciones
 type EnrollmentFilter struct {
-	UserID   uint   // Filtrar por usuario específico
-	ModuleID uint   // Filtrar por módulo específico
-	Status   string // Filtrar por estado: active, dropped, completed
-	Limit    int
-	Offset   int
+	UserID   uint              // Filtrar por usuario específico
+	ModuleID uint              // Filtrar por módulo específico
+	Statuses []string          // Filtrar por uno o varios estados: active, dropped, completed
+	Spec     listing.Spec      // Orden, rango de creación y eliminados
+	Page     pagination.Params // Cursor, tamaño de página y total opcional
+}
+
+// Columnas por las que se puede ordenar ListEnrollments
+var EnrollmentListSchema = listing.Schema{
+	Sortable: []string{"created_at", "updated_at", "status"},
+	Default:  "-created_at",
 }
 
 // Constantes de estado
@@ -0,0 +1,231 @@
+package erasurerepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+// entityChatMessage es el tipo de los jobs de embedding de mensajes
+// (embeddingjobrepo.EntityChatMessage).
+const entityChatMessage = "chat_message"
+
+// keyAccount es el prefijo de los contadores de intentos por cuenta
+// (loginthrottlerepo.KeyAccount).
+const keyAccount = "account:"
+
+type erasureRepo struct {
+	db *gorm.DB
+}
+
+func NewErasureRepo(db *gorm.DB) (ErasureRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &erasureRepo{
+		db: db,
+	}, nil
+}
+
+// EraseUser implements ErasureRepo.
+func (e *erasureRepo) EraseUser(ctx context.Context, userID uint) (*Erasure, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	erasure := &Erasure{
+		UserID:     userID,
+		Deleted:    map[string]int64{},
+		Anonymized: map[string]int64{},
+	}
+
+	err := database.Conn(ctx, e.db).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Unscoped().First(&user, userID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// Todo se borra con Unscoped: lo que estaba en la papelera también cuenta
+		deleted := func(table string, result *gorm.DB) error {
+			if result.Error != nil {
+				return fmt.Errorf("erasing %s: %w", table, result.Error)
+			}
+			erasure.Deleted[table] += result.RowsAffected
+			return nil
+		}
+		anonymized := func(table string, result *gorm.DB) error {
+			if result.Error != nil {
+				return fmt.Errorf("anonymizing %s: %w", table, result.Error)
+			}
+			erasure.Anonymized[table] += result.RowsAffected
+			return nil
+		}
+		sessionIDs := func() *gorm.DB {
+			return tx.Unscoped().Model(&models.ChatSession{}).Select("id").Where("user_id = ?", userID)
+		}
+		messageIDs := func() *gorm.DB {
+			return tx.Unscoped().Model(&models.ChatMessage{}).Select("id").Where("conversation_id IN (?)", sessionIDs())
+		}
+
+		// Conversaciones: primero lo que cuelga de los mensajes y de los hilos
+		if err := deleted("embedding_jobs", tx.Unscoped().
+			Where("entity_type = ? AND entity_id IN (?)", entityChatMessage, messageIDs()).
+			Delete(&models.EmbeddingJob{})); err != nil {
+			return err
+		}
+		if err := deleted("chat_messages", tx.Unscoped().
+			Where("conversation_id IN (?)", sessionIDs()).
+			Delete(&models.ChatMessage{})); err != nil {
+			return err
+		}
+		if err := deleted("chat_summaries", tx.Unscoped().
+			Where("conversation_id IN (?)", sessionIDs()).
+			Delete(&models.ChatSummary{})); err != nil {
+			return err
+		}
+		if err := deleted("chat_sessions", tx.Unscoped().
+			Where("user_id = ?", userID).
+			Delete(&models.ChatSession{})); err != nil {
+			return err
+		}
+
+		// Filas propias del usuario
+		owned := []struct {
+			table string
+			model any
+		}{
+			{"insights", &models.Insight{}},
+			{"enrollments", &models.Enrollment{}},
+			{"account_tokens", &models.AccountToken{}},
+			{"calendar_feed_tokens", &models.CalendarFeedToken{}},
+			{"recovery_codes", &models.RecoveryCode{}},
+			{"two_factors", &models.TwoFactor{}},
+			{"user_sessions", &models.UserSession{}},
+			{"external_identities", &models.ExternalIdentity{}},
+			{"invitation_redemptions", &models.InvitationRedemption{}},
+			{"data_exports", &models.DataExport{}},
+		}
+		for _, o := range owned {
+			if err := deleted(o.table, tx.Unscoped().Where("user_id = ?", userID).Delete(o.model)); err != nil {
+				return err
+			}
+		}
+
+		email := strings.ToLower(strings.TrimSpace(user.Email))
+		if err := deleted("login_throttles", tx.
+			Where("key IN ?", []string{keyAccount + email, keyAccount + user.Email}).
+			Delete(&models.LoginThrottle{})); err != nil {
+			return err
+		}
+
+		// El consumo se conserva para los reportes de costos, sin usuario ni conversación
+		if err := anonymized("llm_usages", tx.Unscoped().
+			Model(&models.LLMUsage{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]any{"user_id": 0, "conversation_id": 0})); err != nil {
+			return err
+		}
+
+		// Los reportes de nómina conservan el resultado de la fila, no a quién correspondía
+		if err := anonymized("roster_import_rows", tx.Unscoped().
+			Model(&models.RosterImportRow{}).
+			Where("user_id = ? OR LOWER(email) = ?", userID, email).
+			Updates(map[string]any{"user_id": nil, "email": "", "user_name": "", "message": ""})); err != nil {
+			return err
+		}
+		if err := anonymized("roster_imports", tx.Unscoped().
+			Model(&models.RosterImport{}).
+			Where("created_by_id = ?", userID).
+			Update("created_by_id", 0)); err != nil {
+			return err
+		}
+		if err := anonymized("invitations", tx.Unscoped().
+			Model(&models.Invitation{}).
+			Where("revoked_by_id = ?", userID).
+			Update("revoked_by_id", nil)); err != nil {
+			return err
+		}
+
+		// Las invitaciones creadas por el usuario lo referencian (created_by_id no
+		// admite nulos) y sus canjes son de otras cuentas: en ese caso la fila de
+		// users se conserva anonimizada.
+		var createdInvitations int64
+		err = tx.Unscoped().
+			Model(&models.Invitation{}).
+			Where("created_by_id = ?", userID).
+			Count(&createdInvitations).Error
+		if err != nil {
+			return fmt.Errorf("counting invitations created by user: %w", err)
+		}
+
+		if createdInvitations == 0 {
+			erasure.UserOutcome = UserDeleted
+			return deleted("users", tx.Unscoped().Delete(&models.User{}, userID))
+		}
+
+		now := time.Now()
+		erasure.UserOutcome = UserAnonymized
+		return anonymized("users", tx.Unscoped().
+			Model(&models.User{}).
+			Where("id = ?", userID).
+			Updates(map[string]any{
+				"user_name":            fmt.Sprintf("erased-%d", userID),
+				"email":                fmt.Sprintf("erased-%d@erased.invalid", userID),
+				"password_hash":        "",
+				"locale":               "",
+				"email_verified_at":    nil,
+				"verification_sent_at": nil,
+				"erased_at":            now,
+				"deleted_at":           gorm.Expr("COALESCE(deleted_at, ?)", now),
+			}))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return erasure, nil
+}
+
+// CreateErasureReceipt implements ErasureRepo.
+func (e *erasureRepo) CreateErasureReceipt(ctx context.Context, receipt *models.ErasureReceipt) (*models.ErasureReceipt, error) {
+	if receipt == nil {
+		return nil, ErrReceiptNil
+	}
+	if receipt.UserID == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	if err := database.Conn(ctx, e.db).Create(receipt).Error; err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// ErasureReceiptByID implements ErasureRepo.
+func (e *erasureRepo) ErasureReceiptByID(ctx context.Context, id uint) (*models.ErasureReceipt, error) {
+	if id == 0 {
+		return nil, ErrInvalidReceiptID
+	}
+
+	var receipt models.ErasureReceipt
+	err := database.Conn(ctx, e.db).First(&receipt, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReceiptNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &receipt, nil
+}
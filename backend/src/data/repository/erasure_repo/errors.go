@@ -0,0 +1,17 @@
+package erasurerepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrUserNotFound    = apperror.New(apperror.NotFound, "erasure.user_not_found", "erasure error: usuario no encontrado")
+	ErrReceiptNotFound = apperror.New(apperror.NotFound, "erasure.receipt_not_found", "erasure error: comprobante de borrado no encontrado")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "erasure.database_required", "erasure error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrInvalidUserID    = apperror.New(apperror.Validation, "erasure.invalid_user_id", "erasure error: id de usuario inválido")
+	ErrInvalidReceiptID = apperror.New(apperror.Validation, "erasure.invalid_receipt_id", "erasure error: id de comprobante inválido")
+	ErrReceiptNil       = apperror.New(apperror.Validation, "erasure.receipt_nil", "erasure error: el comprobante no puede ser nil")
+)
@@ -0,0 +1,40 @@
+package erasurerepo
+
+import (
+	"context"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de comprobantes
+type ErasureReader interface {
+	ErasureReceiptByID(ctx context.Context, id uint) (*models.ErasureReceipt, error)
+}
+
+// Borrado de datos personales
+type ErasureWriter interface {
+	// EraseUser borra definitivamente o anonimiza todos los datos personales del
+	// usuario, esté o no en la papelera. Se ejecuta en una sola transacción.
+	EraseUser(ctx context.Context, userID uint) (*Erasure, error)
+	CreateErasureReceipt(ctx context.Context, receipt *models.ErasureReceipt) (*models.ErasureReceipt, error)
+}
+
+// Interfaz principal
+type ErasureRepo interface {
+	ErasureReader
+	ErasureWriter
+}
+
+// Qué pasó con la fila en users
+const (
+	UserDeleted    = "deleted"    // Borrada definitivamente
+	UserAnonymized = "anonymized" // Se conserva sin datos personales porque otras filas la referencian
+)
+
+// Erasure resume un borrado: filas afectadas por tabla.
+type Erasure struct {
+	UserID      uint
+	UserOutcome string           // UserDeleted | UserAnonymized
+	Deleted     map[string]int64 // Filas borradas por tabla
+	Anonymized  map[string]int64 // Filas anonimizadas por tabla
+}
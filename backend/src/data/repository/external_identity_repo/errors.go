@@ -0,0 +1,20 @@
+package externalidentityrepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrIdentityNotFound = apperror.New(apperror.NotFound, "external_identity.not_found", "identidad externa no encontrada")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "external_identity.database_required", "external identity error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrIdentityNil       = apperror.New(apperror.Validation, "external_identity.nil", "external identity error: la identidad no puede ser nil")
+	ErrInvalidUserID     = apperror.New(apperror.Validation, "external_identity.invalid_user_id", "external identity error: id de usuario inválido")
+	ErrInvalidIdentityID = apperror.New(apperror.Validation, "external_identity.invalid_id", "external identity error: id de identidad inválido")
+	ErrMissingSubject    = apperror.New(apperror.Validation, "external_identity.missing_subject", "external identity error: faltan el issuer o el sujeto")
+
+	// Errores de conflicto
+	ErrIdentityConflict = apperror.New(apperror.Conflict, "external_identity.conflict", "external identity error: la cuenta externa ya está vinculada a otro usuario")
+)
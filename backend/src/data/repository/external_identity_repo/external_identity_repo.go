@@ -0,0 +1,97 @@
+package externalidentityrepo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type externalIdentityRepo struct {
+	db *gorm.DB
+}
+
+func NewExternalIdentityRepo(db *gorm.DB) (ExternalIdentityRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &externalIdentityRepo{
+		db: db,
+	}, nil
+}
+
+// IdentityBySubject implements ExternalIdentityRepo.
+func (e *externalIdentityRepo) IdentityBySubject(ctx context.Context, issuer string, subject string) (*models.ExternalIdentity, error) {
+	if issuer == "" || subject == "" {
+		return nil, ErrMissingSubject
+	}
+
+	var identity models.ExternalIdentity
+	err := database.Conn(ctx, e.db).Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIdentityNotFound
+		}
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// IdentitiesByUser implements ExternalIdentityRepo.
+func (e *externalIdentityRepo) IdentitiesByUser(ctx context.Context, userID uint) ([]models.ExternalIdentity, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	var identities []models.ExternalIdentity
+	err := database.Conn(ctx, e.db).Where("user_id = ?", userID).Order("id").Find(&identities).Error
+	return identities, err
+}
+
+// CreateIdentity implements ExternalIdentityRepo.
+func (e *externalIdentityRepo) CreateIdentity(ctx context.Context, identity *models.ExternalIdentity) (*models.ExternalIdentity, error) {
+	if identity == nil {
+		return nil, ErrIdentityNil
+	}
+	if identity.UserID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if identity.Issuer == "" || identity.Subject == "" {
+		return nil, ErrMissingSubject
+	}
+
+	if err := database.Conn(ctx, e.db).Create(identity).Error; err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "ux_external_identities_subject") {
+			return nil, ErrIdentityConflict
+		}
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// TouchIdentity implements ExternalIdentityRepo.
+func (e *externalIdentityRepo) TouchIdentity(ctx context.Context, id uint, email string) error {
+	if id == 0 {
+		return ErrInvalidIdentityID
+	}
+
+	result := database.Conn(ctx, e.db).
+		Model(&models.ExternalIdentity{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"last_login_at": time.Now(), "email": email})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrIdentityNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package externalidentityrepo
+
+import (
+	"context"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de identidades externas
+type ExternalIdentityReader interface {
+	IdentityBySubject(ctx context.Context, issuer, subject string) (*models.ExternalIdentity, error)
+	IdentitiesByUser(ctx context.Context, userID uint) ([]models.ExternalIdentity, error)
+}
+
+// Escritura de identidades externas
+type ExternalIdentityWriter interface {
+	CreateIdentity(ctx context.Context, identity *models.ExternalIdentity) (*models.ExternalIdentity, error)
+	// TouchIdentity registra un login y el email que informó el proveedor.
+	TouchIdentity(ctx context.Context, id uint, email string) error
+}
+
+// Interfaz principal
+type ExternalIdentityRepo interface {
+	ExternalIdentityReader
+	ExternalIdentityWriter
+}
@@ -1,38 +1,38 @@
 package insightrepo
 
-import "errors"
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
 
 var (
 	// Errores de búsqueda
-	ErrInsightNotFound = errors.New("insight no encontrado")
+	ErrInsightNotFound = apperror.New(apperror.NotFound, "insight.not_found", "insight no encontrado")
 
 	// Errores de configuración
-	ErrDatabaseRequired = errors.New("insight error: la conexión a la base de datos es requerida")
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "insight.database_required", "insight error: la conexión a la base de datos es requerida")
 
 	// Errores de validación básica
-	ErrInsightNil            = errors.New("insight error: el insight no puede ser nil")
-	ErrInvalidInsightID      = errors.New("insight error: id de insight inválido")
-	ErrInvalidUserID         = errors.New("insight error: id de usuario inválido")
-	ErrMissingRequiredFields = errors.New("insight error: faltan campos requeridos: user_id/insight_type/content")
+	ErrInsightNil            = apperror.New(apperror.Validation, "insight.nil", "insight error: el insight no puede ser nil")
+	ErrInvalidInsightID      = apperror.New(apperror.Validation, "insight.invalid_insight_id", "insight error: id de insight inválido")
+	ErrInvalidUserID         = apperror.New(apperror.Validation, "insight.invalid_user_id", "insight error: id de usuario inválido")
+	ErrMissingRequiredFields = apperror.New(apperror.Validation, "insight.missing_required_fields", "insight error: faltan campos requeridos: user_id/insight_type/content")
 
 	// Errores de relación
-	ErrUserNotExists = errors.New("insight error: el usuario especificado no existe")
+	ErrUserNotExists = apperror.New(apperror.NotFound, "insight.user_not_exists", "insight error: el usuario especificado no existe")
 
 	// Errores de negocio/validación de contenido
-	ErrInvalidInsightType = errors.New("insight error: tipo de insight inválido")
-	ErrEmptyContent       = errors.New("insight error: el contenido del insight no puede estar vacío")
-	ErrContentTooLong     = errors.New("insight error: el contenido excede la longitud máxima permitida")
+	ErrInvalidInsightType = apperror.New(apperror.Validation, "insight.invalid_insight_type", "insight error: tipo de insight inválido")
+	ErrEmptyContent       = apperror.New(apperror.Validation, "insight.empty_content", "insight error: el contenido del insight no puede estar vacío")
+	ErrContentTooLong     = apperror.New(apperror.Validation, "insight.content_too_long", "insight error: el contenido excede la longitud máxima permitida")
 
 	// Errores de embeddings y búsquedas semánticas
-	ErrInvalidEmbedding    = errors.New("insight error: embedding inválido o corrupto")
-	ErrEmbeddingDimensions = errors.New("insight error: dimensiones del embedding incorrectas (debe ser 1536)")
-	ErrEmbeddingRequired   = errors.New("insight error: embedding requerido para búsquedas semánticas")
-	ErrInvalidSimilarity   = errors.New("insight error: umbral de similitud inválido (debe estar entre 0.0 y 1.0)")
-	ErrBatchUpdateEmpty    = errors.New("insight error: lista de actualizaciones batch no puede estar vacía")
-	ErrBatchUpdateTooLarge = errors.New("insight error: batch de actualizaciones excede el límite máximo")
+	ErrInvalidEmbedding    = apperror.New(apperror.Validation, "insight.invalid_embedding", "insight error: embedding inválido o corrupto")
+	ErrEmbeddingDimensions = apperror.New(apperror.Validation, "insight.embedding_dimensions", "insight error: dimensiones del embedding incorrectas (debe ser 1536)")
+	ErrEmbeddingRequired   = apperror.New(apperror.Validation, "insight.embedding_required", "insight error: embedding requerido para búsquedas semánticas")
+	ErrInvalidSimilarity   = apperror.New(apperror.Validation, "insight.invalid_similarity", "insight error: umbral de similitud inválido (debe estar entre 0.0 y 1.0)")
+	ErrBatchUpdateEmpty    = apperror.New(apperror.Validation, "insight.batch_update_empty", "insight error: lista de actualizaciones batch no puede estar vacía")
+	ErrBatchUpdateTooLarge = apperror.New(apperror.Validation, "insight.batch_update_too_large", "insight error: batch de actualizaciones excede el límite máximo")
 
 	// Errores de búsquedas semánticas
-	ErrSemanticSearchFailed = errors.New("insight error: fallo en búsqueda semántica")
-	ErrNoSimilarInsights    = errors.New("insight error: no se encontraron insights similares")
-	ErrInvalidSearchLimit   = errors.New("insight error: límite de búsqueda inválido (debe ser mayor a 0)")
+	ErrSemanticSearchFailed = apperror.New(apperror.Internal, "insight.semantic_search_failed", "insight error: fallo en búsqueda semántica")
+	ErrNoSimilarInsights    = apperror.New(apperror.NotFound, "insight.no_similar_insights", "insight error: no se encontraron insights similares")
+	ErrInvalidSearchLimit   = apperror.New(apperror.Validation, "insight.invalid_search_limit", "insight error: límite de búsqueda inválido (debe ser mayor a 0)")
 )
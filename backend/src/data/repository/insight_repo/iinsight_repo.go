@@ -3,14 +3,16 @@ package insightrepo
 import (
 	"context"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	"github.com/pgvector/pgvector-go"
 )
 
 // Lectura de insights
 type InsightReader interface {
 	InsightByID(ctx context.Context, id uint) (*models.Insight, error)
-	ListInsights(ctx context.Context, filter InsightFilter) ([]models.Insight, error)
+	ListInsights(ctx context.Context, filter InsightFilter) (pagination.Page[models.Insight], error)
 	InsightsByUser(ctx context.Context, userID uint) ([]models.Insight, error)
 	InsightsByType(ctx context.Context, insightType string) ([]models.Insight, error)
 	InsightsByUserAndType(ctx context.Context, userID uint, insightType string) ([]models.Insight, error)
@@ -41,10 +43,16 @@ type InsightRepo interface {
 
 // Filtro para insights tradicional
 type InsightFilter struct {
-	UserID      uint   // Filtrar por usuario específico
-	InsightType string // Filtrar por tipo de insight
-	Limit       int
-	Offset      int
+	UserID       uint              // Filtrar por usuario específico
+	InsightTypes []string          // Filtrar por uno o varios tipos de insight
+	Spec         listing.Spec      // Orden, rango de creación y eliminados
+	Page         pagination.Params // Cursor, tamaño de página y total opcional
+}
+
+// Columnas por las que se puede ordenar ListInsights
+var InsightListSchema = listing.Schema{
+	Sortable: []string{"created_at", "updated_at", "insight_type"},
+	Default:  "-created_at",
 }
 
 // Filtro para búsquedas semánticas
@@ -3,7 +3,9 @@ package insightrepo
 import (
 	"context"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	"github.com/pgvector/pgvector-go"
 	"gorm.io/gorm"
 )
@@ -39,7 +41,7 @@ func (i *insightRepo) CreateInsight(ctx context.Context, insight *models.Insight
 
 	// Verificar que el usuario existe
 	var userCount int64
-	err := i.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", insight.UserID).Count(&userCount).Error
+	err := database.Conn(ctx, i.db).Model(&models.User{}).Where("id = ?", insight.UserID).Count(&userCount).Error
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +49,7 @@ func (i *insightRepo) CreateInsight(ctx context.Context, insight *models.Insight
 		return nil, ErrUserNotExists
 	}
 
-	err = i.db.WithContext(ctx).Create(insight).Error
+	err = database.Conn(ctx, i.db).Create(insight).Error
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +63,7 @@ func (i *insightRepo) DeleteInsight(ctx context.Context, id uint) error {
 		return ErrInvalidInsightID
 	}
 
-	result := i.db.WithContext(ctx).Delete(&models.Insight{}, id)
+	result := database.Conn(ctx, i.db).Delete(&models.Insight{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -80,7 +82,7 @@ func (i *insightRepo) InsightByID(ctx context.Context, id uint) (*models.Insight
 	}
 
 	var insight models.Insight
-	err := i.db.WithContext(ctx).First(&insight, id).Error
+	err := database.Conn(ctx, i.db).First(&insight, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrInsightNotFound
@@ -98,7 +100,7 @@ func (i *insightRepo) InsightWithUser(ctx context.Context, insightID uint) (*mod
 	}
 
 	var insight models.Insight
-	err := i.db.WithContext(ctx).
+	err := database.Conn(ctx, i.db).
 		Preload("User").
 		First(&insight, insightID).Error
 	if err != nil {
@@ -118,7 +120,7 @@ func (i *insightRepo) InsightsByType(ctx context.Context, insightType string) ([
 	}
 
 	var insights []models.Insight
-	err := i.db.WithContext(ctx).
+	err := database.Conn(ctx, i.db).
 		Where("insight_type = ?", insightType).
 		Order("created_at DESC").
 		Find(&insights).Error
@@ -136,7 +138,7 @@ func (i *insightRepo) InsightsByUser(ctx context.Context, userID uint) ([]models
 	}
 
 	var insights []models.Insight
-	err := i.db.WithContext(ctx).
+	err := database.Conn(ctx, i.db).
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&insights).Error
@@ -157,7 +159,7 @@ func (i *insightRepo) InsightsByUserAndType(ctx context.Context, userID uint, in
 	}
 
 	var insights []models.Insight
-	err := i.db.WithContext(ctx).
+	err := database.Conn(ctx, i.db).
 		Where("user_id = ? AND insight_type = ?", userID, insightType).
 		Order("created_at DESC").
 		Find(&insights).Error
@@ -169,35 +171,25 @@ func (i *insightRepo) InsightsByUserAndType(ctx context.Context, userID uint, in
 }
 
 // ListInsights implements InsightRepo.
-func (i *insightRepo) ListInsights(ctx context.Context, filter InsightFilter) ([]models.Insight, error) {
-	query := i.db.WithContext(ctx).Model(&models.Insight{})
+func (i *insightRepo) ListInsights(ctx context.Context, filter InsightFilter) (pagination.Page[models.Insight], error) {
+	query := database.Conn(ctx, i.db).Model(&models.Insight{})
 
 	// Aplicar filtros dinámicos
 	if filter.UserID != 0 {
 		query = query.Where("user_id = ?", filter.UserID)
 	}
-	if filter.InsightType != "" {
-		query = query.Where("insight_type = ?", filter.InsightType)
+	if len(filter.InsightTypes) > 0 {
+		query = query.Where("insight_type IN ?", filter.InsightTypes)
 	}
 
-	// Ordenamiento
-	query = query.Order("created_at DESC")
-
-	// Paginación
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
-	}
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
-	}
-
-	var insights []models.Insight
-	err := query.Find(&insights).Error
+	// Orden, rango de creación y eliminados
+	query, sort, err := filter.Spec.Apply(query, InsightListSchema)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Insight]{}, err
 	}
 
-	return insights, nil
+	// Paginación por cursor sobre (columna de orden, id)
+	return pagination.Find[models.Insight](query, filter.Page, sort)
 }
 
 // UpdateInsight implements InsightRepo.
@@ -236,7 +228,7 @@ func (i *insightRepo) UpdateInsight(ctx context.Context, id uint, updates Insigh
 	}
 
 	// Realizar la actualización
-	result := i.db.WithContext(ctx).
+	result := database.Conn(ctx, i.db).
 		Model(&models.Insight{}).
 		Where("id = ?", id).
 		Updates(updateMap)
@@ -277,7 +269,7 @@ func (i *insightRepo) BatchUpdateEmbeddings(ctx context.Context, updates []Embed
 	}
 
 	// Ejecutar actualizaciones en una transacción
-	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := database.Conn(ctx, i.db).Transaction(func(tx *gorm.DB) error {
 		// Pre-verificar que todos los insights existen
 		var existingCount int64
 		err := tx.Model(&models.Insight{}).Where("id IN ?", ids).Count(&existingCount).Error
@@ -315,7 +307,7 @@ func (i *insightRepo) FindSimilarInsights(ctx context.Context, insightID uint, l
 
 	// Primero obtener el insight de referencia con su embedding
 	var referenceInsight models.Insight
-	err := i.db.WithContext(ctx).First(&referenceInsight, insightID).Error
+	err := database.Conn(ctx, i.db).First(&referenceInsight, insightID).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrInsightNotFound
@@ -336,7 +328,8 @@ func (i *insightRepo) FindSimilarInsights(ctx context.Context, insightID uint, l
 	// Buscar insights similares usando distancia coseno (más estándar para embeddings)
 	var insights []models.Insight
 
-	result := i.db.WithContext(ctx).
+	result := database.Conn(ctx, i.db).
+		Clauses(database.SemanticReplica()).
 		Model(&models.Insight{}).
 		Select("*, embedding <=> ? AS distance", referenceInsight.Embedding).
 		Where("id != ?", insightID). // Excluir el insight de referencia
@@ -370,7 +363,8 @@ func (i *insightRepo) SearchInsightsByEmbedding(ctx context.Context, embedding p
 	// Buscar insights ordenados por similitud usando distancia coseno (estándar para embeddings)
 	var insights []models.Insight
 
-	result := i.db.WithContext(ctx).
+	result := database.Conn(ctx, i.db).
+		Clauses(database.SemanticReplica()).
 		Model(&models.Insight{}).
 		Select("*, embedding <=> ? AS distance", embedding).
 		Order("distance"). // Menor distancia = más similar
@@ -401,7 +395,8 @@ func (i *insightRepo) SearchInsightsByEmbeddingWithFilter(ctx context.Context, e
 	}
 
 	// Construir query base con búsqueda semántica usando distancia coseno (consistente con otras funciones)
-	query := i.db.WithContext(ctx).
+	query := database.Conn(ctx, i.db).
+		Clauses(database.SemanticReplica()).
 		Model(&models.Insight{}).
 		Select("*, embedding <=> ? AS distance", embedding)
 
@@ -447,7 +442,7 @@ func (i *insightRepo) UpdateInsightEmbedding(ctx context.Context, id uint, embed
 	}
 
 	// Actualizar el embedding del insight específico
-	result := i.db.WithContext(ctx).
+	result := database.Conn(ctx, i.db).
 		Model(&models.Insight{}).
 		Where("id = ?", id).
 		Update("embedding", embedding)
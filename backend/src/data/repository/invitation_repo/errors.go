@@ -0,0 +1,31 @@
+package invitationrepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrInvitationNotFound = apperror.New(apperror.NotFound, "invitation.not_found", "código de invitación no encontrado")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "invitation.database_required", "invitation error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrInvitationNil    = apperror.New(apperror.Validation, "invitation.nil", "invitation error: la invitación no puede ser nil")
+	ErrRedemptionNil    = apperror.New(apperror.Validation, "invitation.redemption_nil", "invitation error: el registro de uso no puede ser nil")
+	ErrInvalidID        = apperror.New(apperror.Validation, "invitation.invalid_id", "invitation error: id de invitación inválido")
+	ErrInvalidUserID    = apperror.New(apperror.Validation, "invitation.invalid_user_id", "invitation error: id de usuario inválido")
+	ErrCodeEmpty        = apperror.New(apperror.Validation, "invitation.code_empty", "invitation error: el código no puede estar vacío")
+	ErrInvalidMaxUses   = apperror.New(apperror.Validation, "invitation.invalid_max_uses", "invitation error: el máximo de usos no puede ser negativo")
+	ErrInvalidRole      = apperror.New(apperror.Validation, "invitation.invalid_role", "invitation error: rol inválido: debe ser student, teacher o admin")
+	ErrExpiresInThePast = apperror.New(apperror.Validation, "invitation.expires_in_the_past", "invitation error: la fecha de vencimiento ya pasó")
+	ErrMissingCreatedBy = apperror.New(apperror.Validation, "invitation.missing_created_by", "invitation error: falta el usuario que crea la invitación")
+
+	// Errores de unicidad/conflicto
+	ErrCodeConflict        = apperror.New(apperror.Conflict, "invitation.code_conflict", "invitation error: el código ya está en uso")
+	ErrUserAlreadyRedeemed = apperror.New(apperror.Conflict, "invitation.user_already_redeemed", "invitation error: el usuario ya se registró con una invitación")
+
+	// Errores de negocio/estado
+	ErrInvitationRevoked   = apperror.New(apperror.Conflict, "invitation.revoked", "invitation error: la invitación fue revocada")
+	ErrInvitationExpired   = apperror.New(apperror.Conflict, "invitation.expired", "invitation error: la invitación está vencida")
+	ErrInvitationExhausted = apperror.New(apperror.Conflict, "invitation.exhausted", "invitation error: la invitación alcanzó su máximo de usos")
+)
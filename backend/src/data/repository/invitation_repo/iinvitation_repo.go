@@ -0,0 +1,49 @@
+package invitationrepo
+
+import (
+	"context"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de invitaciones
+type InvitationReader interface {
+	InvitationByID(ctx context.Context, id uint) (*models.Invitation, error)
+	InvitationByCode(ctx context.Context, code string) (*models.Invitation, error) // El código se compara en mayúsculas
+	ListInvitations(ctx context.Context, filter InvitationFilter) ([]models.Invitation, error)
+	RedemptionsByInvitation(ctx context.Context, invitationID uint) ([]models.InvitationRedemption, error) // Con User incluido
+}
+
+// Escritura de invitaciones
+type InvitationWriter interface {
+	CreateInvitation(ctx context.Context, invitation *models.Invitation) (*models.Invitation, error)
+	RevokeInvitation(ctx context.Context, id, revokedByID uint) error
+	// ConsumeInvitation suma un uso solo si la invitación sigue vigente; es atómico
+	// para que dos registros simultáneos no superen MaxUses.
+	ConsumeInvitation(ctx context.Context, id uint) error
+	CreateRedemption(ctx context.Context, redemption *models.InvitationRedemption) (*models.InvitationRedemption, error)
+}
+
+// Interfaz principal
+type InvitationRepo interface {
+	InvitationReader
+	InvitationWriter
+}
+
+// Filtro para invitaciones
+type InvitationFilter struct {
+	CreatedByID uint         // Filtrar por creador
+	ModuleID    uint         // Filtrar por módulo específico
+	ActiveOnly  bool         // Solo vigentes: no revocadas, no vencidas y con usos disponibles
+	Spec        listing.Spec // Orden, rango de creación y eliminados
+	Limit       int
+	Offset      int
+}
+
+// Columnas por las que se puede ordenar ListInvitations
+var InvitationListSchema = listing.Schema{
+	Table:    "invitations",
+	Sortable: []string{"created_at", "expires_at", "uses"},
+	Default:  "-created_at",
+}
@@ -0,0 +1,251 @@
+package invitationrepo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type invitationRepo struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepo(db *gorm.DB) (InvitationRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &invitationRepo{
+		db: db,
+	}, nil
+}
+
+// activeCondition filtra las invitaciones que todavía se pueden usar.
+const activeCondition = "revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?) AND (max_uses = 0 OR uses < max_uses)"
+
+// ConsumeInvitation implements InvitationRepo.
+func (i *invitationRepo) ConsumeInvitation(ctx context.Context, id uint) error {
+	if id == 0 {
+		return ErrInvalidID
+	}
+
+	result := database.Conn(ctx, i.db).
+		Model(&models.Invitation{}).
+		Where("id = ?", id).
+		Where(activeCondition, time.Now()).
+		UpdateColumn("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// No se actualizó: se averigua el motivo para informarlo
+	invitation, err := i.InvitationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return Usable(invitation, time.Now())
+}
+
+// CreateInvitation implements InvitationRepo.
+func (i *invitationRepo) CreateInvitation(ctx context.Context, invitation *models.Invitation) (*models.Invitation, error) {
+	if invitation == nil {
+		return nil, ErrInvitationNil
+	}
+	invitation.Code = NormalizeCode(invitation.Code)
+	if invitation.Code == "" {
+		return nil, ErrCodeEmpty
+	}
+	if invitation.CreatedByID == 0 {
+		return nil, ErrMissingCreatedBy
+	}
+	if invitation.MaxUses < 0 {
+		return nil, ErrInvalidMaxUses
+	}
+	if invitation.Role == "" {
+		invitation.Role = "student"
+	}
+	if invitation.Role != "student" && invitation.Role != "teacher" && invitation.Role != "admin" {
+		return nil, ErrInvalidRole
+	}
+	if invitation.ExpiresAt != nil && !invitation.ExpiresAt.After(time.Now()) {
+		return nil, ErrExpiresInThePast
+	}
+
+	if err := database.Conn(ctx, i.db).Create(invitation).Error; err != nil {
+		if strings.Contains(err.Error(), "ux_invitations_code") {
+			return nil, ErrCodeConflict
+		}
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// CreateRedemption implements InvitationRepo.
+func (i *invitationRepo) CreateRedemption(ctx context.Context, redemption *models.InvitationRedemption) (*models.InvitationRedemption, error) {
+	if redemption == nil {
+		return nil, ErrRedemptionNil
+	}
+	if redemption.InvitationID == 0 {
+		return nil, ErrInvalidID
+	}
+	if redemption.UserID == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	if err := database.Conn(ctx, i.db).Create(redemption).Error; err != nil {
+		if strings.Contains(err.Error(), "ux_invitation_redemptions_user") {
+			return nil, ErrUserAlreadyRedeemed
+		}
+		return nil, err
+	}
+
+	return redemption, nil
+}
+
+// InvitationByCode implements InvitationRepo.
+func (i *invitationRepo) InvitationByCode(ctx context.Context, code string) (*models.Invitation, error) {
+	code = NormalizeCode(code)
+	if code == "" {
+		return nil, ErrCodeEmpty
+	}
+
+	var invitation models.Invitation
+	err := database.Conn(ctx, i.db).
+		Where("code = ?", code).
+		First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// InvitationByID implements InvitationRepo.
+func (i *invitationRepo) InvitationByID(ctx context.Context, id uint) (*models.Invitation, error) {
+	if id == 0 {
+		return nil, ErrInvalidID
+	}
+
+	var invitation models.Invitation
+	err := database.Conn(ctx, i.db).
+		Preload("Module").
+		First(&invitation, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// ListInvitations implements InvitationRepo.
+func (i *invitationRepo) ListInvitations(ctx context.Context, filter InvitationFilter) ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	query := database.Conn(ctx, i.db).
+		Model(&models.Invitation{}).
+		Preload("Module")
+
+	if filter.CreatedByID > 0 {
+		query = query.Where("created_by_id = ?", filter.CreatedByID)
+	}
+	if filter.ModuleID > 0 {
+		query = query.Where("module_id = ?", filter.ModuleID)
+	}
+	if filter.ActiveOnly {
+		query = query.Where(activeCondition, time.Now())
+	}
+
+	// Orden, rango de creación y eliminados
+	query, sort, err := filter.Spec.Apply(query, InvitationListSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	err = query.Order(sort.OrderBy()).Find(&invitations).Error
+	return invitations, err
+}
+
+// RedemptionsByInvitation implements InvitationRepo.
+func (i *invitationRepo) RedemptionsByInvitation(ctx context.Context, invitationID uint) ([]models.InvitationRedemption, error) {
+	if invitationID == 0 {
+		return nil, ErrInvalidID
+	}
+
+	var redemptions []models.InvitationRedemption
+	err := database.Conn(ctx, i.db).
+		Preload("User").
+		Where("invitation_id = ?", invitationID).
+		Order("created_at ASC").
+		Find(&redemptions).Error
+	return redemptions, err
+}
+
+// RevokeInvitation implements InvitationRepo.
+func (i *invitationRepo) RevokeInvitation(ctx context.Context, id, revokedByID uint) error {
+	if id == 0 {
+		return ErrInvalidID
+	}
+
+	now := time.Now()
+	result := database.Conn(ctx, i.db).
+		Model(&models.Invitation{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{
+			"revoked_at":    now,
+			"revoked_by_id": revokedByID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		invitation, err := i.InvitationByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if invitation.RevokedAt != nil {
+			return ErrInvitationRevoked
+		}
+		return ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// NormalizeCode quita espacios y pasa el código a mayúsculas.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Usable indica por qué una invitación no se puede usar en el instante now (nil si se puede).
+func Usable(invitation *models.Invitation, now time.Time) error {
+	switch {
+	case invitation.RevokedAt != nil:
+		return ErrInvitationRevoked
+	case invitation.ExpiresAt != nil && !invitation.ExpiresAt.After(now):
+		return ErrInvitationExpired
+	case invitation.MaxUses > 0 && invitation.Uses >= invitation.MaxUses:
+		return ErrInvitationExhausted
+	}
+	return nil
+}
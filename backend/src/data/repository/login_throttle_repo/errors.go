@@ -0,0 +1,14 @@
+package loginthrottlerepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrThrottleNotFound = apperror.New(apperror.NotFound, "login_throttle.not_found", "registro de intentos de inicio de sesión no encontrado")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "login_throttle.database_required", "login throttle error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrKeyEmpty = apperror.New(apperror.Validation, "login_throttle.key_empty", "login throttle error: la clave no puede estar vacía")
+)
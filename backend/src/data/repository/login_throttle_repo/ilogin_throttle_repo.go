@@ -0,0 +1,40 @@
+package loginthrottlerepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de contadores de intentos
+type LoginThrottleReader interface {
+	ThrottlesByKeys(ctx context.Context, keys []string) ([]models.LoginThrottle, error)
+	ListLockedThrottles(ctx context.Context, now time.Time, limit int) ([]models.LoginThrottle, error) // Bloqueos vigentes
+}
+
+// Escritura de contadores de intentos
+type LoginThrottleWriter interface {
+	// RegisterFailure suma un fallo a la clave; si el último fue antes de windowStart
+	// el contador vuelve a empezar. Es atómico y devuelve el estado resultante.
+	RegisterFailure(ctx context.Context, key string, now, windowStart time.Time) (*models.LoginThrottle, error)
+	// LockThrottle bloquea la clave hasta until y reinicia el contador de fallos.
+	LockThrottle(ctx context.Context, key string, until time.Time) error
+	// ClearFailures reinicia los fallos y el bloqueo de la clave tras un login
+	// correcto. Conserva Lockouts para que el siguiente bloqueo siga escalando.
+	ClearFailures(ctx context.Context, key string) error
+	// ResetThrottle borra la clave (desbloqueo de un admin).
+	ResetThrottle(ctx context.Context, key string) error
+}
+
+// Interfaz principal
+type LoginThrottleRepo interface {
+	LoginThrottleReader
+	LoginThrottleWriter
+}
+
+// Prefijos de las claves
+const (
+	KeyAccount = "account:"
+	KeyIP      = "ip:"
+)
@@ -0,0 +1,125 @@
+package loginthrottlerepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type loginThrottleRepo struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepo(db *gorm.DB) (LoginThrottleRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &loginThrottleRepo{
+		db: db,
+	}, nil
+}
+
+// ListLockedThrottles implements LoginThrottleRepo.
+func (l *loginThrottleRepo) ListLockedThrottles(ctx context.Context, now time.Time, limit int) ([]models.LoginThrottle, error) {
+	query := database.Conn(ctx, l.db).
+		Where("locked_until > ?", now).
+		Order("locked_until DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var throttles []models.LoginThrottle
+	err := query.Find(&throttles).Error
+	return throttles, err
+}
+
+// LockThrottle implements LoginThrottleRepo.
+func (l *loginThrottleRepo) LockThrottle(ctx context.Context, key string, until time.Time) error {
+	if key == "" {
+		return ErrKeyEmpty
+	}
+
+	result := database.Conn(ctx, l.db).
+		Model(&models.LoginThrottle{}).
+		Where("key = ?", key).
+		Updates(map[string]any{
+			"locked_until": until,
+			"failures":     0,
+			"lockouts":     gorm.Expr("lockouts + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrThrottleNotFound
+	}
+
+	return nil
+}
+
+// RegisterFailure implements LoginThrottleRepo.
+func (l *loginThrottleRepo) RegisterFailure(ctx context.Context, key string, now, windowStart time.Time) (*models.LoginThrottle, error) {
+	if key == "" {
+		return nil, ErrKeyEmpty
+	}
+
+	var throttle models.LoginThrottle
+	err := database.Conn(ctx, l.db).Raw(`
+		INSERT INTO login_throttles (key, failures, last_failure_at, lockouts, created_at, updated_at)
+		VALUES (?, 1, ?, 0, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *
+	`, key, now, now, now, windowStart).Scan(&throttle).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &throttle, nil
+}
+
+// ClearFailures implements LoginThrottleRepo.
+func (l *loginThrottleRepo) ClearFailures(ctx context.Context, key string) error {
+	if key == "" {
+		return ErrKeyEmpty
+	}
+
+	// Sin fila no hay nada que reiniciar
+	return database.Conn(ctx, l.db).
+		Model(&models.LoginThrottle{}).
+		Where("key = ?", key).
+		Updates(map[string]any{
+			"failures":     0,
+			"locked_until": nil,
+		}).Error
+}
+
+// ResetThrottle implements LoginThrottleRepo.
+func (l *loginThrottleRepo) ResetThrottle(ctx context.Context, key string) error {
+	if key == "" {
+		return ErrKeyEmpty
+	}
+
+	return database.Conn(ctx, l.db).
+		Where("key = ?", key).
+		Delete(&models.LoginThrottle{}).Error
+}
+
+// ThrottlesByKeys implements LoginThrottleRepo.
+func (l *loginThrottleRepo) ThrottlesByKeys(ctx context.Context, keys []string) ([]models.LoginThrottle, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	var throttles []models.LoginThrottle
+	err := database.Conn(ctx, l.db).
+		Where("key IN ?", keys).
+		Find(&throttles).Error
+	return throttles, err
+}
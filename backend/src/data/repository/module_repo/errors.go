@@ -1,24 +1,27 @@
 package modulerepo
 
-import "errors"
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
 
 var (
 	// Errores de búsqueda
-	ErrModuleNotFound = errors.New("módulo no encontrado")
+	ErrModuleNotFound = apperror.New(apperror.NotFound, "module.not_found", "módulo no encontrado")
 
 	// Errores de configuración
-	ErrDatabaseRequired = errors.New("module error: la conexión a la base de datos es requerida")
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "module.database_required", "module error: la conexión a la base de datos es requerida")
 
 	// Errores de validación
-	ErrModuleNil             = errors.New("module error: el módulo no puede ser nil")
-	ErrInvalidModuleID       = errors.New("module error: id de módulo inválido")
-	ErrCodeEmpty             = errors.New("module error: el código no puede estar vacío")
-	ErrNameEmpty             = errors.New("module error: el nombre no puede estar vacío")
-	ErrMissingRequiredFields = errors.New("module error: faltan campos requeridos: code/name")
+	ErrModuleNil             = apperror.New(apperror.Validation, "module.nil", "module error: el módulo no puede ser nil")
+	ErrInvalidModuleID       = apperror.New(apperror.Validation, "module.invalid_module_id", "module error: id de módulo inválido")
+	ErrCodeEmpty             = apperror.New(apperror.Validation, "module.code_empty", "module error: el código no puede estar vacío")
+	ErrNameEmpty             = apperror.New(apperror.Validation, "module.name_empty", "module error: el nombre no puede estar vacío")
+	ErrMissingRequiredFields = apperror.New(apperror.Validation, "module.missing_required_fields", "module error: faltan campos requeridos: code/name")
 
 	// Errores de conflicto/unicidad
-	ErrModuleCodeConflict = errors.New("module error: el código del módulo ya está en uso")
+	ErrModuleCodeConflict = apperror.New(apperror.Conflict, "module.code_conflict", "module error: el código del módulo ya está en uso")
+
+	// Errores de papelera
+	ErrModuleNotDeleted = apperror.New(apperror.Conflict, "module.not_deleted", "module error: el módulo no está en la papelera")
 
 	// Errores de negocio
-	ErrInvalidModuleCode = errors.New("module error: código de módulo inválido: debe seguir el formato estándar")
+	ErrInvalidModuleCode = apperror.New(apperror.Validation, "module.invalid_module_code", "module error: código de módulo inválido: debe seguir el formato estándar")
 )
@@ -2,15 +2,18 @@ package modulerepo
 
 import (
 	"context"
+	"time"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 )
 
 // Lectura de módulos
 type ModuleReader interface {
 	ModuleByID(ctx context.Context, id uint) (*models.Module, error)
 	ModuleByCode(ctx context.Context, code string) (*models.Module, error)
-	ListModules(ctx context.Context, filter ModuleFilter) ([]models.Module, error)
+	ListModules(ctx context.Context, filter ModuleFilter) (pagination.Page[models.Module], error)
 	ModuleWithTopics(ctx context.Context, moduleID uint) (*models.Module, error)      // Con temas incluidos
 	ModuleWithEnrollments(ctx context.Context, moduleID uint) (*models.Module, error) // Con inscripciones incluidas
 }
@@ -20,6 +23,11 @@ type ModuleWriter interface {
 	CreateModule(ctx context.Context, module *models.Module) (*models.Module, error)
 	UpdateModule(ctx context.Context, id uint, updates ModuleUpdate) error
 	DeleteModule(ctx context.Context, id uint) error
+
+	// Papelera
+	RestoreModule(ctx context.Context, id uint) (*models.Module, error)                                       // Falla si el código se ocupó mientras tanto
+	PurgeableModuleIDs(ctx context.Context, deletedBefore time.Time, afterID uint, limit int) ([]uint, error) // En la papelera desde antes de deletedBefore, por id
+	PurgeModule(ctx context.Context, id uint) error                                                           // Borrado definitivo con temas, inscripciones y nóminas; solo desde la papelera
 }
 
 // Interfaz principal
@@ -30,9 +38,15 @@ type ModuleRepo interface {
 
 // Filtro para módulos
 type ModuleFilter struct {
-	Search string // Buscar en code/name/description
-	Limit  int
-	Offset int
+	Search string            // Buscar en code/name/description
+	Spec   listing.Spec      // Orden, rango de creación y eliminados
+	Page   pagination.Params // Cursor, tamaño de página y total opcional
+}
+
+// Columnas por las que se puede ordenar ListModules
+var ModuleListSchema = listing.Schema{
+	Sortable: []string{"created_at", "updated_at", "code", "name"},
+	Default:  "-created_at",
 }
 
 // Actualización de módulo
@@ -3,12 +3,20 @@ package modulerepo
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	"gorm.io/gorm"
 )
 
+// entityTopic es el tipo de los jobs de embedding de temas
+// (embeddingjobrepo.EntityTopic).
+const entityTopic = "topic"
+
 type moduleRepo struct {
 	db *gorm.DB
 }
@@ -32,7 +40,7 @@ func (m *moduleRepo) CreateModule(ctx context.Context, module *models.Module) (*
 		return nil, ErrMissingRequiredFields
 	}
 
-	err := m.db.WithContext(ctx).Create(module).Error
+	err := database.Conn(ctx, m.db).Create(module).Error
 	if err != nil {
 		errStr := strings.ToLower(err.Error())
 		if strings.Contains(errStr, "ux_modules_code") {
@@ -49,7 +57,7 @@ func (m *moduleRepo) DeleteModule(ctx context.Context, id uint) error {
 		return ErrInvalidModuleID
 	}
 
-	result := m.db.WithContext(ctx).Delete(&models.Module{}, id)
+	result := database.Conn(ctx, m.db).Delete(&models.Module{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -61,9 +69,101 @@ func (m *moduleRepo) DeleteModule(ctx context.Context, id uint) error {
 	return nil
 }
 
+// RestoreModule implements ModuleRepo.
+func (m *moduleRepo) RestoreModule(ctx context.Context, id uint) (*models.Module, error) {
+	if id == 0 {
+		return nil, ErrInvalidModuleID
+	}
+
+	var module models.Module
+	err := database.Conn(ctx, m.db).Unscoped().First(&module, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrModuleNotFound
+		}
+		return nil, err
+	}
+	if !module.DeletedAt.Valid {
+		return nil, ErrModuleNotDeleted
+	}
+
+	// Otro módulo pudo tomar el código mientras este estuvo en la papelera
+	err = database.Conn(ctx, m.db).Unscoped().Model(&module).Update("deleted_at", nil).Error
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "ux_modules_code") {
+			return nil, ErrModuleCodeConflict
+		}
+		return nil, err
+	}
+
+	module.DeletedAt = gorm.DeletedAt{}
+	return &module, nil
+}
+
+// PurgeableModuleIDs implements ModuleRepo.
+func (m *moduleRepo) PurgeableModuleIDs(ctx context.Context, deletedBefore time.Time, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := database.Conn(ctx, m.db).
+		Unscoped().
+		Model(&models.Module{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND id > ?", deletedBefore, afterID).
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// PurgeModule implements ModuleRepo.
+func (m *moduleRepo) PurgeModule(ctx context.Context, id uint) error {
+	if id == 0 {
+		return ErrInvalidModuleID
+	}
+
+	return database.Conn(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		// Solo se borra definitivamente lo que ya está en la papelera
+		var module models.Module
+		err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&module, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrModuleNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// Lo que referencia al módulo se borra (temas, inscripciones, nóminas) o
+		// queda sin módulo (invitaciones, conversaciones). Los temas se borran aquí
+		// aunque el FK ya sea en cascada: las bases migradas antes no lo tienen
+		topicIDs := tx.Unscoped().Model(&models.Topic{}).Select("id").Where("module_id = ?", id)
+		if err := tx.Unscoped().Where("entity_type = ? AND entity_id IN (?)", entityTopic, topicIDs).Delete(&models.EmbeddingJob{}).Error; err != nil {
+			return fmt.Errorf("purging embedding_jobs: %w", err)
+		}
+		if err := tx.Unscoped().Where("module_id = ?", id).Delete(&models.Topic{}).Error; err != nil {
+			return fmt.Errorf("purging topics: %w", err)
+		}
+		importIDs := tx.Unscoped().Model(&models.RosterImport{}).Select("id").Where("module_id = ?", id)
+		if err := tx.Unscoped().Where("import_id IN (?)", importIDs).Delete(&models.RosterImportRow{}).Error; err != nil {
+			return fmt.Errorf("purging roster_import_rows: %w", err)
+		}
+		if err := tx.Unscoped().Where("module_id = ?", id).Delete(&models.RosterImport{}).Error; err != nil {
+			return fmt.Errorf("purging roster_imports: %w", err)
+		}
+		if err := tx.Unscoped().Where("module_id = ?", id).Delete(&models.Enrollment{}).Error; err != nil {
+			return fmt.Errorf("purging enrollments: %w", err)
+		}
+		if err := tx.Unscoped().Model(&models.Invitation{}).Where("module_id = ?", id).UpdateColumn("module_id", nil).Error; err != nil {
+			return fmt.Errorf("detaching invitations: %w", err)
+		}
+		if err := tx.Unscoped().Model(&models.ChatSession{}).Where("module_id = ?", id).UpdateColumn("module_id", nil).Error; err != nil {
+			return fmt.Errorf("detaching chat_sessions: %w", err)
+		}
+
+		return tx.Unscoped().Delete(&module).Error
+	})
+}
+
 // ListModules implements ModuleRepo.
-func (m *moduleRepo) ListModules(ctx context.Context, filter ModuleFilter) ([]models.Module, error) {
-	query := m.db.WithContext(ctx).Model(&models.Module{})
+func (m *moduleRepo) ListModules(ctx context.Context, filter ModuleFilter) (pagination.Page[models.Module], error) {
+	query := database.Conn(ctx, m.db).Model(&models.Module{})
 
 	// Búsqueda por texto en code/name/description
 	if filter.Search != "" {
@@ -71,17 +171,14 @@ func (m *moduleRepo) ListModules(ctx context.Context, filter ModuleFilter) ([]mo
 		query = query.Where("code ILIKE ? OR name ILIKE ? OR description ILIKE ?", searchTerm, searchTerm, searchTerm)
 	}
 
-	// Aplicar limit y offset para paginación
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
-	}
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
+	// Orden, rango de creación y eliminados
+	query, sort, err := filter.Spec.Apply(query, ModuleListSchema)
+	if err != nil {
+		return pagination.Page[models.Module]{}, err
 	}
 
-	var modules []models.Module
-	err := query.Find(&modules).Error
-	return modules, err
+	// Paginación por cursor sobre (columna de orden, id)
+	return pagination.Find[models.Module](query, filter.Page, sort)
 }
 
 // ModuleWithEnrollments implements ModuleRepo.
@@ -91,7 +188,7 @@ func (m *moduleRepo) ModuleWithEnrollments(ctx context.Context, moduleID uint) (
 	}
 
 	var module models.Module
-	err := m.db.WithContext(ctx).Preload("Enrollments").Preload("Enrollments.User").First(&module, moduleID).Error
+	err := database.Conn(ctx, m.db).Preload("Enrollments").Preload("Enrollments.User").First(&module, moduleID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrModuleNotFound
@@ -109,7 +206,7 @@ func (m *moduleRepo) ModuleByCode(ctx context.Context, code string) (*models.Mod
 	}
 
 	var module models.Module
-	err := m.db.WithContext(ctx).Where("code = ?", code).First(&module).Error
+	err := database.Conn(ctx, m.db).Where("code = ?", code).First(&module).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrModuleNotFound
@@ -127,7 +224,7 @@ func (m *moduleRepo) ModuleByID(ctx context.Context, id uint) (*models.Module, e
 	}
 
 	var module models.Module
-	err := m.db.WithContext(ctx).Where("id = ?", id).First(&module).Error
+	err := database.Conn(ctx, m.db).Where("id = ?", id).First(&module).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrModuleNotFound
@@ -145,7 +242,7 @@ func (m *moduleRepo) ModuleWithTopics(ctx context.Context, moduleID uint) (*mode
 	}
 
 	var module models.Module
-	err := m.db.WithContext(ctx).Preload("Topics").First(&module, moduleID).Error
+	err := database.Conn(ctx, m.db).Preload("Topics").First(&module, moduleID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrModuleNotFound
@@ -177,7 +274,7 @@ func (m *moduleRepo) UpdateModule(ctx context.Context, id uint, updates ModuleUp
 		return nil
 	}
 
-	result := m.db.WithContext(ctx).Model(&models.Module{}).Where("id = ?", id).Updates(updateFields)
+	result := database.Conn(ctx, m.db).Model(&models.Module{}).Where("id = ?", id).Updates(updateFields)
 	if result.Error != nil {
 		return result.Error
 	}
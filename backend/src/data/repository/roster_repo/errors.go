@@ -0,0 +1,25 @@
+package rosterrepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrRosterImportNotFound = apperror.New(apperror.NotFound, "roster.import_not_found", "importación de nómina no encontrada")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "roster.database_required", "roster error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrRosterImportNil    = apperror.New(apperror.Validation, "roster.import_nil", "roster error: la importación no puede ser nil")
+	ErrRosterRowNil       = apperror.New(apperror.Validation, "roster.row_nil", "roster error: la fila no puede ser nil")
+	ErrInvalidImportID    = apperror.New(apperror.Validation, "roster.invalid_import_id", "roster error: id de importación inválido")
+	ErrInvalidRowID       = apperror.New(apperror.Validation, "roster.invalid_row_id", "roster error: id de fila inválido")
+	ErrInvalidModuleID    = apperror.New(apperror.Validation, "roster.invalid_module_id", "roster error: id de módulo inválido")
+	ErrFileHashEmpty      = apperror.New(apperror.Validation, "roster.file_hash_empty", "roster error: el hash del archivo no puede estar vacío")
+	ErrInvalidLimit       = apperror.New(apperror.Validation, "roster.invalid_limit", "roster error: límite inválido (debe ser > 0 y <= 500)")
+	ErrInvalidCredentials = apperror.New(apperror.Validation, "roster.invalid_credentials", "roster error: credenciales inválidas (debe ser: invite, password)")
+
+	// Errores de unicidad/conflicto
+	ErrRosterImportExists = apperror.New(apperror.Conflict, "roster.import_exists", "roster error: este archivo ya fue importado para el módulo")
+	ErrRosterImportBusy   = apperror.New(apperror.Conflict, "roster.import_busy", "roster error: la importación ya se está procesando o terminó")
+)
@@ -0,0 +1,83 @@
+package rosterrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de importaciones de nómina
+type RosterImportReader interface {
+	RosterImportByID(ctx context.Context, id uint) (*models.RosterImport, error)
+	RosterImportByFileHash(ctx context.Context, moduleID uint, fileHash string) (*models.RosterImport, error)
+	ListRosterImports(ctx context.Context, filter RosterImportFilter) ([]models.RosterImport, error)
+	RosterRows(ctx context.Context, importID uint) ([]models.RosterImportRow, error)                   // Todas, en orden de fila
+	PendingRosterRows(ctx context.Context, importID uint, limit int) ([]models.RosterImportRow, error) // Pendientes, en orden de fila
+}
+
+// Escritura de importaciones de nómina
+type RosterImportWriter interface {
+	// CreateRosterImport crea la importación junto con sus filas en una transacción.
+	CreateRosterImport(ctx context.Context, rosterImport *models.RosterImport, rows []models.RosterImportRow) (*models.RosterImport, error)
+	// ClaimRosterImport pasa la importación a running en una sola sentencia, salvo
+	// que ya esté running (y haya avanzado después de staleBefore) o completed; en
+	// ese caso devuelve ErrRosterImportBusy. Así dos ejecuciones no procesan las
+	// mismas filas.
+	ClaimRosterImport(ctx context.Context, id uint, staleBefore time.Time) (*models.RosterImport, error)
+	SaveRosterImport(ctx context.Context, rosterImport *models.RosterImport) error // Estado y contadores
+	SaveRosterRow(ctx context.Context, row *models.RosterImportRow) error          // Resultado de la fila
+	CreateRosterRow(ctx context.Context, row *models.RosterImportRow) (*models.RosterImportRow, error)
+}
+
+// Interfaz principal
+type RosterRepo interface {
+	RosterImportReader
+	RosterImportWriter
+}
+
+// Filtro para importaciones
+type RosterImportFilter struct {
+	ModuleID uint         // Filtrar por módulo específico
+	Statuses []string     // Filtrar por uno o varios estados
+	Spec     listing.Spec // Orden, rango de creación y eliminados
+	Limit    int
+	Offset   int
+}
+
+// Columnas por las que se puede ordenar ListRosterImports
+var RosterImportListSchema = listing.Schema{
+	Sortable: []string{"created_at", "status"},
+	Default:  "-created_at",
+}
+
+// Estados de la importación
+const (
+	ImportPending   = "pending"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// Resultados de fila
+const (
+	RowPending = "pending"
+	RowCreated = "created" // Usuario creado
+	RowMatched = "matched" // Usuario existente (por email)
+	RowDropped = "dropped" // Baja por no aparecer en la nómina
+	RowError   = "error"
+)
+
+// Acciones sobre la inscripción
+const (
+	ActionEnrolled  = "enrolled"
+	ActionUpdated   = "updated"
+	ActionUnchanged = "unchanged"
+)
+
+// Modos de credenciales para usuarios nuevos
+const (
+	CredentialsInvite   = "invite"   // Enlace para que el estudiante defina su contraseña
+	CredentialsPassword = "password" // Contraseña generada, se muestra una sola vez
+)
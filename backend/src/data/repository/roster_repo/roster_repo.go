@@ -0,0 +1,256 @@
+package rosterrepo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type rosterRepo struct {
+	db *gorm.DB
+}
+
+func NewRosterRepo(db *gorm.DB) (RosterRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &rosterRepo{
+		db: db,
+	}, nil
+}
+
+// CreateRosterImport implements RosterRepo.
+func (r *rosterRepo) CreateRosterImport(ctx context.Context, rosterImport *models.RosterImport, rows []models.RosterImportRow) (*models.RosterImport, error) {
+	if rosterImport == nil {
+		return nil, ErrRosterImportNil
+	}
+	if rosterImport.ModuleID == 0 {
+		return nil, ErrInvalidModuleID
+	}
+	if rosterImport.FileHash == "" {
+		return nil, ErrFileHashEmpty
+	}
+	if rosterImport.Credentials != CredentialsInvite && rosterImport.Credentials != CredentialsPassword {
+		return nil, ErrInvalidCredentials
+	}
+
+	err := database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Rows").Create(rosterImport).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		for i := range rows {
+			rows[i].ImportID = rosterImport.ID
+		}
+		return tx.CreateInBatches(rows, 200).Error
+	})
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "ux_roster_imports_module_hash") {
+			return nil, ErrRosterImportExists
+		}
+		return nil, err
+	}
+
+	return rosterImport, nil
+}
+
+// ClaimRosterImport implements RosterRepo.
+func (r *rosterRepo) ClaimRosterImport(ctx context.Context, id uint, staleBefore time.Time) (*models.RosterImport, error) {
+	if id == 0 {
+		return nil, ErrInvalidImportID
+	}
+
+	result := database.Conn(ctx, r.db).
+		Model(&models.RosterImport{}).
+		Where("id = ?", id).
+		Where("status IN ? OR (status = ? AND updated_at < ?)", []string{ImportPending, ImportFailed}, ImportRunning, staleBefore).
+		Updates(map[string]any{
+			"status":     ImportRunning,
+			"last_error": "",
+			"started_at": gorm.Expr("COALESCE(started_at, ?)", time.Now()),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if _, err := r.RosterImportByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrRosterImportBusy
+	}
+
+	// Se relee: otra ejecución pudo avanzar los contadores antes del reclamo
+	return r.RosterImportByID(ctx, id)
+}
+
+// CreateRosterRow implements RosterRepo.
+func (r *rosterRepo) CreateRosterRow(ctx context.Context, row *models.RosterImportRow) (*models.RosterImportRow, error) {
+	if row == nil {
+		return nil, ErrRosterRowNil
+	}
+	if row.ImportID == 0 {
+		return nil, ErrInvalidImportID
+	}
+
+	if err := database.Conn(ctx, r.db).Create(row).Error; err != nil {
+		return nil, err
+	}
+
+	return row, nil
+}
+
+// ListRosterImports implements RosterRepo.
+func (r *rosterRepo) ListRosterImports(ctx context.Context, filter RosterImportFilter) ([]models.RosterImport, error) {
+	query := database.Conn(ctx, r.db).Model(&models.RosterImport{})
+
+	if filter.ModuleID != 0 {
+		query = query.Where("module_id = ?", filter.ModuleID)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+
+	// Orden, rango de creación y eliminados
+	query, sort, err := filter.Spec.Apply(query, RosterImportListSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var imports []models.RosterImport
+	err = query.Order(sort.OrderBy()).Find(&imports).Error
+
+	return imports, err
+}
+
+// PendingRosterRows implements RosterRepo.
+func (r *rosterRepo) PendingRosterRows(ctx context.Context, importID uint, limit int) ([]models.RosterImportRow, error) {
+	if importID == 0 {
+		return nil, ErrInvalidImportID
+	}
+	if limit <= 0 || limit > 500 {
+		return nil, ErrInvalidLimit
+	}
+
+	var rows []models.RosterImportRow
+	err := database.Conn(ctx, r.db).
+		Where("import_id = ? AND result = ?", importID, RowPending).
+		Order("row_number ASC").
+		Limit(limit).
+		Find(&rows).Error
+
+	return rows, err
+}
+
+// RosterImportByFileHash implements RosterRepo.
+func (r *rosterRepo) RosterImportByFileHash(ctx context.Context, moduleID uint, fileHash string) (*models.RosterImport, error) {
+	if moduleID == 0 {
+		return nil, ErrInvalidModuleID
+	}
+	if fileHash == "" {
+		return nil, ErrFileHashEmpty
+	}
+
+	var rosterImport models.RosterImport
+	err := database.Conn(ctx, r.db).
+		Where("module_id = ? AND file_hash = ?", moduleID, fileHash).
+		First(&rosterImport).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRosterImportNotFound
+		}
+		return nil, err
+	}
+
+	return &rosterImport, nil
+}
+
+// RosterImportByID implements RosterRepo.
+func (r *rosterRepo) RosterImportByID(ctx context.Context, id uint) (*models.RosterImport, error) {
+	if id == 0 {
+		return nil, ErrInvalidImportID
+	}
+
+	var rosterImport models.RosterImport
+	err := database.Conn(ctx, r.db).First(&rosterImport, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRosterImportNotFound
+		}
+		return nil, err
+	}
+
+	return &rosterImport, nil
+}
+
+// RosterRows implements RosterRepo.
+func (r *rosterRepo) RosterRows(ctx context.Context, importID uint) ([]models.RosterImportRow, error) {
+	if importID == 0 {
+		return nil, ErrInvalidImportID
+	}
+
+	var rows []models.RosterImportRow
+	err := database.Conn(ctx, r.db).
+		Where("import_id = ?", importID).
+		Order("row_number ASC, id ASC").
+		Find(&rows).Error
+
+	return rows, err
+}
+
+// SaveRosterImport implements RosterRepo.
+func (r *rosterRepo) SaveRosterImport(ctx context.Context, rosterImport *models.RosterImport) error {
+	if rosterImport == nil {
+		return ErrRosterImportNil
+	}
+	if rosterImport.ID == 0 {
+		return ErrInvalidImportID
+	}
+
+	result := database.Conn(ctx, r.db).
+		Model(rosterImport).
+		Select(
+			"status", "total_rows", "processed_rows", "created_users", "matched_users",
+			"enrolled", "status_updated", "unchanged", "dropped_missing", "errors",
+			"started_at", "finished_at", "last_error",
+		).
+		Updates(rosterImport)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrRosterImportNotFound
+	}
+
+	return nil
+}
+
+// SaveRosterRow implements RosterRepo.
+func (r *rosterRepo) SaveRosterRow(ctx context.Context, row *models.RosterImportRow) error {
+	if row == nil {
+		return ErrRosterRowNil
+	}
+	if row.ID == 0 {
+		return ErrInvalidRowID
+	}
+
+	return database.Conn(ctx, r.db).
+		Model(row).
+		Select("result", "action", "user_id", "enrollment_id", "user_name", "message").
+		Updates(row).Error
+}
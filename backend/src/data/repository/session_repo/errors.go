@@ -0,0 +1,19 @@
+package sessionrepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrSessionNotFound = apperror.New(apperror.NotFound, "session.not_found", "sesión no encontrada, vencida o revocada")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "session.database_required", "session error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrSessionNil        = apperror.New(apperror.Validation, "session.nil", "session error: la sesión no puede ser nil")
+	ErrInvalidUserID     = apperror.New(apperror.Validation, "session.invalid_user_id", "session error: id de usuario inválido")
+	ErrInvalidSessionID  = apperror.New(apperror.Validation, "session.invalid_session_id", "session error: id de sesión inválido")
+	ErrTokenHashEmpty    = apperror.New(apperror.Validation, "session.token_hash_empty", "session error: el hash del token no puede estar vacío")
+	ErrInvalidExpiration = apperror.New(apperror.Validation, "session.invalid_expiration", "session error: la fecha de vencimiento debe ser futura")
+	ErrInvalidReason     = apperror.New(apperror.Validation, "session.invalid_reason", "session error: motivo de revocación inválido")
+)
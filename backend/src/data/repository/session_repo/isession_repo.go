@@ -0,0 +1,50 @@
+package sessionrepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de sesiones
+type SessionReader interface {
+	// ActiveSessionByHash devuelve la sesión si no está vencida ni revocada.
+	ActiveSessionByHash(ctx context.Context, tokenHash string) (*models.UserSession, error)
+	// ActiveSessionsByUser devuelve las sesiones vigentes, de la más a la menos reciente.
+	ActiveSessionsByUser(ctx context.Context, userID uint) ([]models.UserSession, error)
+}
+
+// Escritura de sesiones
+type SessionWriter interface {
+	CreateSession(ctx context.Context, session *models.UserSession) (*models.UserSession, error)
+	// TouchSession actualiza la última actividad solo si la anterior es de antes de
+	// notAfter, para no escribir en cada petición.
+	TouchSession(ctx context.Context, id uint, ipAddress string, notAfter time.Time) error
+	// RevokeSession revoca una sesión vigente del usuario.
+	RevokeSession(ctx context.Context, userID, id uint, reason string) error
+	// RevokeUserSessions revoca las sesiones vigentes del usuario salvo exceptID (0 = todas)
+	// y devuelve cuántas revocó.
+	RevokeUserSessions(ctx context.Context, userID, exceptID uint, reason string) (int64, error)
+}
+
+// Interfaz principal
+type SessionRepo interface {
+	SessionReader
+	SessionWriter
+}
+
+// Alcance de una sesión. Sin alcance la sesión da acceso completo.
+const (
+	// ScopeTwoFactorSetup es la sesión de un rol que exige doble factor y todavía
+	// no lo configuró: solo sirve para activarlo.
+	ScopeTwoFactorSetup = "two_factor_setup"
+)
+
+// Motivos de revocación
+const (
+	ReasonLogout         = "logout"
+	ReasonLogoutAll      = "logout_all"
+	ReasonAdmin          = "admin"
+	ReasonPasswordChange = "password_change"
+)
@@ -0,0 +1,149 @@
+package sessionrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type sessionRepo struct {
+	db *gorm.DB
+}
+
+func NewSessionRepo(db *gorm.DB) (SessionRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &sessionRepo{
+		db: db,
+	}, nil
+}
+
+// ActiveSessionByHash implements SessionRepo.
+func (s *sessionRepo) ActiveSessionByHash(ctx context.Context, tokenHash string) (*models.UserSession, error) {
+	if tokenHash == "" {
+		return nil, ErrTokenHashEmpty
+	}
+
+	var session models.UserSession
+	err := database.Conn(ctx, s.db).
+		Where("token_hash = ? AND revoked_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+		First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// ActiveSessionsByUser implements SessionRepo.
+func (s *sessionRepo) ActiveSessionsByUser(ctx context.Context, userID uint) ([]models.UserSession, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	var sessions []models.UserSession
+	err := database.Conn(ctx, s.db).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// CreateSession implements SessionRepo.
+func (s *sessionRepo) CreateSession(ctx context.Context, session *models.UserSession) (*models.UserSession, error) {
+	if session == nil {
+		return nil, ErrSessionNil
+	}
+	if session.UserID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if session.TokenHash == "" {
+		return nil, ErrTokenHashEmpty
+	}
+	if !session.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiration
+	}
+	if session.LastSeenAt.IsZero() {
+		session.LastSeenAt = time.Now()
+	}
+
+	if err := database.Conn(ctx, s.db).Create(session).Error; err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// TouchSession implements SessionRepo.
+func (s *sessionRepo) TouchSession(ctx context.Context, id uint, ipAddress string, notAfter time.Time) error {
+	if id == 0 {
+		return ErrInvalidSessionID
+	}
+
+	updates := map[string]any{"last_seen_at": time.Now()}
+	if ipAddress != "" {
+		updates["ip_address"] = ipAddress
+	}
+
+	// Si otra petición ya la actualizó no afecta filas, y no es un error
+	return database.Conn(ctx, s.db).
+		Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL AND last_seen_at <= ?", id, notAfter).
+		Updates(updates).Error
+}
+
+// RevokeSession implements SessionRepo.
+func (s *sessionRepo) RevokeSession(ctx context.Context, userID, id uint, reason string) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	if id == 0 {
+		return ErrInvalidSessionID
+	}
+	if reason == "" {
+		return ErrInvalidReason
+	}
+
+	// El user_id va en la condición para que nadie revoque sesiones ajenas
+	result := database.Conn(ctx, s.db).
+		Model(&models.UserSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Updates(map[string]any{"revoked_at": time.Now(), "revoke_reason": reason})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeUserSessions implements SessionRepo.
+func (s *sessionRepo) RevokeUserSessions(ctx context.Context, userID, exceptID uint, reason string) (int64, error) {
+	if userID == 0 {
+		return 0, ErrInvalidUserID
+	}
+	if reason == "" {
+		return 0, ErrInvalidReason
+	}
+
+	query := database.Conn(ctx, s.db).
+		Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptID != 0 {
+		query = query.Where("id <> ?", exceptID)
+	}
+
+	result := query.Updates(map[string]any{"revoked_at": time.Now(), "revoke_reason": reason})
+	return result.RowsAffected, result.Error
+}
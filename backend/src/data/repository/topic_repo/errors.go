@@ -1,30 +1,36 @@
 package topicrepo
 
-import "errors"
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
 
 var (
 	// Errores de búsqueda
-	ErrTopicNotFound = errors.New("tema no encontrado")
+	ErrTopicNotFound = apperror.New(apperror.NotFound, "topic.not_found", "tema no encontrado")
 
 	// Errores de configuración
-	ErrDatabaseRequired = errors.New("topic error: la conexión a la base de datos es requerida")
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "topic.database_required", "topic error: la conexión a la base de datos es requerida")
 
 	// Errores de validación
-	ErrTopicNil              = errors.New("topic error: el tema no puede ser nil")
-	ErrInvalidTopicID        = errors.New("topic error: id de tema inválido")
-	ErrInvalidModuleID       = errors.New("topic error: id de módulo inválido")
-	ErrTitleEmpty            = errors.New("topic error: el título no puede estar vacío")
-	ErrContentEmpty          = errors.New("topic error: el contenido no puede estar vacío")
-	ErrMissingRequiredFields = errors.New("topic error: faltan campos requeridos: title/content/module_id")
-	ErrInvalidLimit          = errors.New("topic error: límite inválido")
-	ErrEmbeddingRequired     = errors.New("topic error: se requiere embedding")
-	ErrEmbeddingDimensions   = errors.New("topic error: dimensiones de embedding inválidas")
-	ErrSemanticSearchFailed  = errors.New("topic error: la búsqueda semántica falló")
+	ErrTopicNil              = apperror.New(apperror.Validation, "topic.nil", "topic error: el tema no puede ser nil")
+	ErrInvalidTopicID        = apperror.New(apperror.Validation, "topic.invalid_topic_id", "topic error: id de tema inválido")
+	ErrInvalidModuleID       = apperror.New(apperror.Validation, "topic.invalid_module_id", "topic error: id de módulo inválido")
+	ErrTitleEmpty            = apperror.New(apperror.Validation, "topic.title_empty", "topic error: el título no puede estar vacío")
+	ErrContentEmpty          = apperror.New(apperror.Validation, "topic.content_empty", "topic error: el contenido no puede estar vacío")
+	ErrMissingRequiredFields = apperror.New(apperror.Validation, "topic.missing_required_fields", "topic error: faltan campos requeridos: title/content/module_id")
+	ErrInvalidLimit          = apperror.New(apperror.Validation, "topic.invalid_limit", "topic error: límite inválido")
+	ErrImportKeyEmpty        = apperror.New(apperror.Validation, "topic.import_key_empty", "topic error: la clave de importación no puede estar vacía")
+	ErrEmbeddingRequired     = apperror.New(apperror.Validation, "topic.embedding_required", "topic error: se requiere embedding")
+	ErrEmbeddingDimensions   = apperror.New(apperror.Validation, "topic.embedding_dimensions", "topic error: dimensiones de embedding inválidas")
+	ErrSemanticSearchFailed  = apperror.New(apperror.Internal, "topic.semantic_search_failed", "topic error: la búsqueda semántica falló")
 
 	// Errores de relación
-	ErrModuleNotExists = errors.New("topic error: el módulo especificado no existe")
+	ErrModuleNotExists = apperror.New(apperror.NotFound, "topic.module_not_exists", "topic error: el módulo especificado no existe")
+
+	// Errores de papelera
+	ErrTopicNotDeleted   = apperror.New(apperror.Conflict, "topic.not_deleted", "topic error: el tema no está en la papelera")
+	ErrModuleDeleted     = apperror.New(apperror.Conflict, "topic.module_deleted", "topic error: el módulo del tema está en la papelera; restáuralo primero")
+	ErrImportKeyConflict = apperror.New(apperror.Conflict, "topic.import_key_conflict", "topic error: otro tema del módulo ya usa esa clave de importación")
 
 	// Errores de negocio
-	ErrInvalidScheduledDate  = errors.New("topic error: fecha programada inválida")
-	ErrTopicAlreadyCompleted = errors.New("topic error: el tema ya está completado")
+	ErrInvalidScheduledDate  = apperror.New(apperror.Validation, "topic.invalid_scheduled_date", "topic error: fecha programada inválida")
+	ErrTopicAlreadyCompleted = apperror.New(apperror.Conflict, "topic.already_completed", "topic error: el tema ya está completado")
 )
@@ -2,9 +2,12 @@ package topicrepo
 
 import (
 	"context"
+	"time"
 
 	topicdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/topic_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	"github.com/pgvector/pgvector-go"
 	"gorm.io/datatypes"
 )
@@ -12,10 +15,12 @@ import (
 // Lectura de temas
 type TopicReader interface {
 	TopicByID(ctx context.Context, id uint) (*models.Topic, error)
-	ListTopics(ctx context.Context, filter TopicFilter) ([]models.Topic, error)
+	ListTopics(ctx context.Context, filter TopicFilter) (pagination.Page[models.Topic], error)
 	TopicsByModule(ctx context.Context, moduleID uint) ([]models.Topic, error)
-	TopicWithModule(ctx context.Context, topicID uint) (*models.Topic, error)                         // Con módulo incluido
-	TopicsByDateRange(ctx context.Context, startDate, endDate datatypes.Date) ([]models.Topic, error) // Por rango de fechas
+	TopicByImportKey(ctx context.Context, moduleID uint, importKey string) (*models.Topic, error)                      // Tema importado desde un programa
+	TopicWithModule(ctx context.Context, topicID uint) (*models.Topic, error)                                          // Con módulo incluido
+	TopicsByDateRange(ctx context.Context, startDate, endDate datatypes.Date) ([]models.Topic, error)                  // Por rango de fechas
+	UpcomingTopicsByModule(ctx context.Context, moduleID uint, from datatypes.Date, limit int) ([]models.Topic, error) // Próximos temas desde from (inclusive)
 
 	// Búsquedas semánticas con embeddings
 	SearchTopicsByEmbedding(ctx context.Context, embedding pgvector.Vector, limit int) ([]topicdto.VectorSearchResultDTO, error)
@@ -27,7 +32,13 @@ type TopicReader interface {
 type TopicWriter interface {
 	CreateTopic(ctx context.Context, topic *models.Topic) (*models.Topic, error)
 	UpdateTopic(ctx context.Context, id uint, updates TopicUpdate) error
+	UpdateTopicEmbedding(ctx context.Context, id uint, embedding pgvector.Vector) error
 	DeleteTopic(ctx context.Context, id uint) error
+
+	// Papelera
+	RestoreTopic(ctx context.Context, id uint) (*models.Topic, error)                                        // Falla si el módulo sigue eliminado o la clave de importación se ocupó
+	PurgeableTopicIDs(ctx context.Context, deletedBefore time.Time, afterID uint, limit int) ([]uint, error) // En la papelera desde antes de deletedBefore, por id
+	PurgeTopic(ctx context.Context, id uint) error                                                           // Borrado definitivo; solo desde la papelera
 }
 
 // Interfaz principal
@@ -38,10 +49,17 @@ type TopicRepo interface {
 
 // Filtro para temas
 type TopicFilter struct {
-	ModuleID uint   // Filtrar por módulo específico
-	Search   string // Buscar en unit_title/official_content/modernized_content
-	Limit    int
-	Offset   int
+	ModuleID  uint              // Filtrar por módulo específico
+	Search    string            // Buscar en unit_title/official_content/modernized_content
+	Scheduled listing.DateRange // Rango sobre scheduled_date
+	Spec      listing.Spec      // Orden, rango de creación y eliminados
+	Page      pagination.Params // Cursor, tamaño de página y total opcional
+}
+
+// Columnas por las que se puede ordenar ListTopics
+var TopicListSchema = listing.Schema{
+	Sortable: []string{"created_at", "updated_at", "scheduled_date", "unit_title"},
+	Default:  "-created_at",
 }
 
 // Filtro para búsquedas semánticas
@@ -3,9 +3,13 @@ package topicrepo
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
 	topicdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/topic_dto"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 	pgvector "github.com/pgvector/pgvector-go"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
@@ -36,7 +40,7 @@ func (t *topicRepo) FindSimilarTopics(ctx context.Context, topicID uint, limit i
 
 	// Obtener el topic de referencia con su embedding
 	var referenceTopic models.Topic
-	err := t.db.WithContext(ctx).First(&referenceTopic, topicID).Error
+	err := database.Conn(ctx, t.db).First(&referenceTopic, topicID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrTopicNotFound
@@ -58,7 +62,8 @@ func (t *topicRepo) FindSimilarTopics(ctx context.Context, topicID uint, limit i
 
 	// Buscar topics similares directamente en el DTO
 	var results []topicdto.VectorSearchResultDTO
-	result := t.db.WithContext(ctx).
+	result := database.Conn(ctx, t.db).
+		Clauses(database.SemanticReplica()).
 		Model(&models.Topic{}).
 		Preload("Module").
 		Select(`
@@ -99,7 +104,8 @@ func (t *topicRepo) SearchTopicsByEmbedding(ctx context.Context, embedding pgvec
 	}
 
 	var topics []topicdto.VectorSearchResultDTO
-	result := t.db.WithContext(ctx).
+	result := database.Conn(ctx, t.db).
+		Clauses(database.SemanticReplica()).
 		Model(&models.Topic{}).
 		Select(`
 		topics.id, 
@@ -137,7 +143,8 @@ func (t *topicRepo) SearchTopicsByEmbeddingWithFilter(ctx context.Context, embed
 	}
 
 	// Construir la consulta base con JOIN
-	query := t.db.WithContext(ctx).
+	query := database.Conn(ctx, t.db).
+		Clauses(database.SemanticReplica()).
 		Model(&models.Topic{}).
 		Select(`
 			topics.id, 
@@ -183,7 +190,7 @@ func (t *topicRepo) CreateTopic(ctx context.Context, topic *models.Topic) (*mode
 
 	// Verificar que el módulo existe
 	var count int64
-	err := t.db.WithContext(ctx).Model(&models.Module{}).Where("id = ?", topic.ModuleID).Count(&count).Error
+	err := database.Conn(ctx, t.db).Model(&models.Module{}).Where("id = ?", topic.ModuleID).Count(&count).Error
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +198,7 @@ func (t *topicRepo) CreateTopic(ctx context.Context, topic *models.Topic) (*mode
 		return nil, ErrModuleNotExists
 	}
 
-	err = t.db.WithContext(ctx).Create(topic).Error
+	err = database.Conn(ctx, t.db).Create(topic).Error
 	if err != nil {
 		return nil, err
 	}
@@ -205,7 +212,83 @@ func (t *topicRepo) DeleteTopic(ctx context.Context, id uint) error {
 		return ErrInvalidTopicID
 	}
 
-	result := t.db.WithContext(ctx).Delete(&models.Topic{}, id)
+	result := database.Conn(ctx, t.db).Delete(&models.Topic{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTopicNotFound
+	}
+
+	return nil
+}
+
+// RestoreTopic implements TopicRepo.
+func (t *topicRepo) RestoreTopic(ctx context.Context, id uint) (*models.Topic, error) {
+	if id == 0 {
+		return nil, ErrInvalidTopicID
+	}
+
+	var topic models.Topic
+	err := database.Conn(ctx, t.db).Unscoped().First(&topic, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTopicNotFound
+		}
+		return nil, err
+	}
+	if !topic.DeletedAt.Valid {
+		return nil, ErrTopicNotDeleted
+	}
+
+	// Un tema no vuelve a un módulo que sigue en la papelera
+	var modules int64
+	err = database.Conn(ctx, t.db).Model(&models.Module{}).Where("id = ?", topic.ModuleID).Count(&modules).Error
+	if err != nil {
+		return nil, err
+	}
+	if modules == 0 {
+		return nil, ErrModuleDeleted
+	}
+
+	// Una importación posterior pudo crear otro tema con la misma clave
+	err = database.Conn(ctx, t.db).Unscoped().Model(&topic).Update("deleted_at", nil).Error
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "ux_topics_module_import_key") {
+			return nil, ErrImportKeyConflict
+		}
+		return nil, err
+	}
+
+	topic.DeletedAt = gorm.DeletedAt{}
+	return &topic, nil
+}
+
+// PurgeableTopicIDs implements TopicRepo.
+func (t *topicRepo) PurgeableTopicIDs(ctx context.Context, deletedBefore time.Time, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := database.Conn(ctx, t.db).
+		Unscoped().
+		Model(&models.Topic{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND id > ?", deletedBefore, afterID).
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// PurgeTopic implements TopicRepo.
+func (t *topicRepo) PurgeTopic(ctx context.Context, id uint) error {
+	if id == 0 {
+		return ErrInvalidTopicID
+	}
+
+	// Solo se borra definitivamente lo que ya está en la papelera
+	result := database.Conn(ctx, t.db).
+		Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Delete(&models.Topic{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -218,8 +301,8 @@ func (t *topicRepo) DeleteTopic(ctx context.Context, id uint) error {
 }
 
 // ListTopics implements TopicRepo.
-func (t *topicRepo) ListTopics(ctx context.Context, filter TopicFilter) ([]models.Topic, error) {
-	query := t.db.WithContext(ctx).Model(&models.Topic{})
+func (t *topicRepo) ListTopics(ctx context.Context, filter TopicFilter) (pagination.Page[models.Topic], error) {
+	query := database.Conn(ctx, t.db).Model(&models.Topic{})
 
 	// Filtrar por módulo específico
 	if filter.ModuleID != 0 {
@@ -232,17 +315,20 @@ func (t *topicRepo) ListTopics(ctx context.Context, filter TopicFilter) ([]model
 		query = query.Where("unit_title ILIKE ? OR content ILIKE ?", searchTerm, searchTerm)
 	}
 
-	// Aplicar limit y offset para paginación
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
+	// Rango de fechas programadas
+	if err := filter.Scheduled.Validate(); err != nil {
+		return pagination.Page[models.Topic]{}, err
 	}
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
+	query = filter.Scheduled.Apply(query, "scheduled_date")
+
+	// Orden, rango de creación y eliminados
+	query, sort, err := filter.Spec.Apply(query, TopicListSchema)
+	if err != nil {
+		return pagination.Page[models.Topic]{}, err
 	}
 
-	var topics []models.Topic
-	err := query.Find(&topics).Error
-	return topics, err
+	// Paginación por cursor sobre (columna de orden, id)
+	return pagination.Find[models.Topic](query, filter.Page, sort)
 }
 
 // TopicByID implements TopicRepo.
@@ -252,7 +338,7 @@ func (t *topicRepo) TopicByID(ctx context.Context, id uint) (*models.Topic, erro
 	}
 
 	var topic models.Topic
-	err := t.db.WithContext(ctx).Where("id = ?", id).First(&topic).Error
+	err := database.Conn(ctx, t.db).Where("id = ?", id).First(&topic).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrTopicNotFound
@@ -270,7 +356,7 @@ func (t *topicRepo) TopicWithModule(ctx context.Context, topicID uint) (*models.
 	}
 
 	var topic models.Topic
-	err := t.db.WithContext(ctx).Preload("Module").First(&topic, topicID).Error
+	err := database.Conn(ctx, t.db).Preload("Module").First(&topic, topicID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrTopicNotFound
@@ -284,7 +370,7 @@ func (t *topicRepo) TopicWithModule(ctx context.Context, topicID uint) (*models.
 // TopicsByDateRange implements TopicRepo.
 func (t *topicRepo) TopicsByDateRange(ctx context.Context, startDate datatypes.Date, endDate datatypes.Date) ([]models.Topic, error) {
 	var topics []models.Topic
-	err := t.db.WithContext(ctx).
+	err := database.Conn(ctx, t.db).
 		Where("scheduled_date >= ? AND scheduled_date <= ?", startDate, endDate).
 		Order("scheduled_date ASC").
 		Find(&topics).Error
@@ -299,7 +385,7 @@ func (t *topicRepo) TopicsByModule(ctx context.Context, moduleID uint) ([]models
 	}
 
 	var topics []models.Topic
-	err := t.db.WithContext(ctx).
+	err := database.Conn(ctx, t.db).
 		Where("module_id = ?", moduleID).
 		Order("scheduled_date ASC").
 		Find(&topics).Error
@@ -307,6 +393,74 @@ func (t *topicRepo) TopicsByModule(ctx context.Context, moduleID uint) ([]models
 	return topics, err
 }
 
+// UpcomingTopicsByModule implements TopicRepo.
+func (t *topicRepo) UpcomingTopicsByModule(ctx context.Context, moduleID uint, from datatypes.Date, limit int) ([]models.Topic, error) {
+	if moduleID == 0 {
+		return nil, ErrInvalidModuleID
+	}
+	if limit <= 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	var topics []models.Topic
+	err := database.Conn(ctx, t.db).
+		Select("id", "module_id", "scheduled_date", "unit_title").
+		Where("module_id = ? AND scheduled_date >= ?", moduleID, from).
+		Order("scheduled_date ASC").
+		Order("id ASC").
+		Limit(limit).
+		Find(&topics).Error
+
+	return topics, err
+}
+
+// TopicByImportKey implements TopicRepo.
+func (t *topicRepo) TopicByImportKey(ctx context.Context, moduleID uint, importKey string) (*models.Topic, error) {
+	if moduleID == 0 {
+		return nil, ErrInvalidModuleID
+	}
+	if importKey == "" {
+		return nil, ErrImportKeyEmpty
+	}
+
+	var topic models.Topic
+	err := database.Conn(ctx, t.db).
+		Where("module_id = ? AND import_key = ?", moduleID, importKey).
+		First(&topic).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTopicNotFound
+		}
+		return nil, err
+	}
+
+	return &topic, nil
+}
+
+// UpdateTopicEmbedding implements TopicRepo.
+func (t *topicRepo) UpdateTopicEmbedding(ctx context.Context, id uint, embedding pgvector.Vector) error {
+	if id == 0 {
+		return ErrInvalidTopicID
+	}
+	if len(embedding.Slice()) != 1536 {
+		return ErrEmbeddingDimensions
+	}
+
+	result := database.Conn(ctx, t.db).
+		Model(&models.Topic{}).
+		Where("id = ?", id).
+		Update("embedding", embedding)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrTopicNotFound
+	}
+
+	return nil
+}
+
 // UpdateTopic implements TopicRepo.
 func (t *topicRepo) UpdateTopic(ctx context.Context, id uint, updates TopicUpdate) error {
 	if id == 0 {
@@ -331,7 +485,7 @@ func (t *topicRepo) UpdateTopic(ctx context.Context, id uint, updates TopicUpdat
 		return nil
 	}
 
-	result := t.db.WithContext(ctx).Model(&models.Topic{}).Where("id = ?", id).Updates(updateFields)
+	result := database.Conn(ctx, t.db).Model(&models.Topic{}).Where("id = ?", id).Updates(updateFields)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -0,0 +1,23 @@
+package twofactorrepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de búsqueda
+	ErrTwoFactorNotFound = apperror.New(apperror.NotFound, "two_factor.not_found", "configuración de doble factor no encontrada")
+
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "two_factor.database_required", "two factor error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrInvalidUserID   = apperror.New(apperror.Validation, "two_factor.invalid_user_id", "two factor error: id de usuario inválido")
+	ErrSecretEmpty     = apperror.New(apperror.Validation, "two_factor.secret_empty", "two factor error: el secreto no puede estar vacío")
+	ErrCodeHashEmpty   = apperror.New(apperror.Validation, "two_factor.code_hash_empty", "two factor error: el hash del código no puede estar vacío")
+	ErrNoRecoveryCodes = apperror.New(apperror.Validation, "two_factor.no_recovery_codes", "two factor error: se requiere al menos un código de recuperación")
+
+	// Errores de negocio/estado
+	ErrAlreadyConfirmed    = apperror.New(apperror.Conflict, "two_factor.already_confirmed", "two factor error: el doble factor ya está activado")
+	ErrNotConfirmed        = apperror.New(apperror.Conflict, "two_factor.not_confirmed", "two factor error: el doble factor no está activado")
+	ErrCodeReused          = apperror.New(apperror.Validation, "two_factor.code_reused", "two factor error: el código ya fue usado")
+	ErrRecoveryCodeInvalid = apperror.New(apperror.Validation, "two_factor.recovery_code_invalid", "two factor error: código de recuperación inválido o ya usado")
+)
@@ -0,0 +1,35 @@
+package twofactorrepo
+
+import (
+	"context"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de doble factor
+type TwoFactorReader interface {
+	TwoFactorByUser(ctx context.Context, userID uint) (*models.TwoFactor, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+}
+
+// Escritura de doble factor
+type TwoFactorWriter interface {
+	// SavePendingTwoFactor guarda un secreto sin confirmar, reemplazando otro pendiente.
+	// Falla si el usuario ya tiene el doble factor activado.
+	SavePendingTwoFactor(ctx context.Context, userID uint, secretSealed string) (*models.TwoFactor, error)
+	// ConfirmTwoFactor activa el doble factor y reemplaza los códigos de recuperación.
+	ConfirmTwoFactor(ctx context.Context, userID uint, counter int64, recoveryHashes []string) error
+	// UseCounter registra el paso TOTP aceptado; falla si no es posterior al último usado.
+	UseCounter(ctx context.Context, userID uint, counter int64) error
+	// UseRecoveryCode marca el código como usado; falla si no existe o ya se usó.
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, recoveryHashes []string) error
+	// DeleteTwoFactor desactiva el doble factor y borra los códigos de recuperación.
+	DeleteTwoFactor(ctx context.Context, userID uint) error
+}
+
+// Interfaz principal
+type TwoFactorRepo interface {
+	TwoFactorReader
+	TwoFactorWriter
+}
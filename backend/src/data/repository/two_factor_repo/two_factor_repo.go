@@ -0,0 +1,217 @@
+package twofactorrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type twoFactorRepo struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepo(db *gorm.DB) (TwoFactorRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &twoFactorRepo{
+		db: db,
+	}, nil
+}
+
+// ConfirmTwoFactor implements TwoFactorRepo.
+func (t *twoFactorRepo) ConfirmTwoFactor(ctx context.Context, userID uint, counter int64, recoveryHashes []string) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	if len(recoveryHashes) == 0 {
+		return ErrNoRecoveryCodes
+	}
+
+	return database.Conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.TwoFactor{}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Updates(map[string]any{
+				"confirmed_at":      time.Now(),
+				"last_used_counter": counter,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if _, err := (&twoFactorRepo{db: tx}).TwoFactorByUser(ctx, userID); err != nil {
+				return err
+			}
+			return ErrAlreadyConfirmed
+		}
+
+		return replaceRecoveryCodes(tx, userID, recoveryHashes)
+	})
+}
+
+// CountUnusedRecoveryCodes implements TwoFactorRepo.
+func (t *twoFactorRepo) CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	if userID == 0 {
+		return 0, ErrInvalidUserID
+	}
+
+	var count int64
+	err := database.Conn(ctx, t.db).
+		Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteTwoFactor implements TwoFactorRepo.
+func (t *twoFactorRepo) DeleteTwoFactor(ctx context.Context, userID uint) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+
+	return database.Conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.TwoFactor{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTwoFactorNotFound
+		}
+		return tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes implements TwoFactorRepo.
+func (t *twoFactorRepo) ReplaceRecoveryCodes(ctx context.Context, userID uint, recoveryHashes []string) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	if len(recoveryHashes) == 0 {
+		return ErrNoRecoveryCodes
+	}
+
+	return database.Conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, recoveryHashes)
+	})
+}
+
+// SavePendingTwoFactor implements TwoFactorRepo.
+func (t *twoFactorRepo) SavePendingTwoFactor(ctx context.Context, userID uint, secretSealed string) (*models.TwoFactor, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if secretSealed == "" {
+		return nil, ErrSecretEmpty
+	}
+
+	twoFactor := &models.TwoFactor{
+		UserID:       userID,
+		SecretSealed: secretSealed,
+	}
+	err := database.Conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		var existing models.TwoFactor
+		err := tx.Unscoped().Where("user_id = ?", userID).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return tx.Create(twoFactor).Error
+		case err != nil:
+			return err
+		case existing.ConfirmedAt != nil && !existing.DeletedAt.Valid:
+			return ErrAlreadyConfirmed
+		}
+
+		// Se reutiliza la fila (el índice único es por usuario)
+		existing.SecretSealed = secretSealed
+		existing.ConfirmedAt = nil
+		existing.LastUsedCounter = 0
+		existing.DeletedAt = gorm.DeletedAt{}
+		if err := tx.Unscoped().Save(&existing).Error; err != nil {
+			return err
+		}
+		twoFactor = &existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return twoFactor, nil
+}
+
+// TwoFactorByUser implements TwoFactorRepo.
+func (t *twoFactorRepo) TwoFactorByUser(ctx context.Context, userID uint) (*models.TwoFactor, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	var twoFactor models.TwoFactor
+	err := database.Conn(ctx, t.db).Where("user_id = ?", userID).First(&twoFactor).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTwoFactorNotFound
+		}
+		return nil, err
+	}
+
+	return &twoFactor, nil
+}
+
+// UseCounter implements TwoFactorRepo.
+func (t *twoFactorRepo) UseCounter(ctx context.Context, userID uint, counter int64) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+
+	// La condición va en el UPDATE: dos logins simultáneos con el mismo código no pasan ambos
+	result := database.Conn(ctx, t.db).
+		Model(&models.TwoFactor{}).
+		Where("user_id = ? AND confirmed_at IS NOT NULL AND last_used_counter < ?", userID, counter).
+		Update("last_used_counter", counter)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCodeReused
+	}
+
+	return nil
+}
+
+// UseRecoveryCode implements TwoFactorRepo.
+func (t *twoFactorRepo) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	if codeHash == "" {
+		return ErrCodeHashEmpty
+	}
+
+	result := database.Conn(ctx, t.db).
+		Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecoveryCodeInvalid
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes borra los códigos del usuario y crea los nuevos dentro de tx.
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, hashes []string) error {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]models.RecoveryCode, 0, len(hashes))
+	for _, h := range hashes {
+		codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: h})
+	}
+	return tx.Create(&codes).Error
+}
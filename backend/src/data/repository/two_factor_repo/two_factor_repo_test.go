@@ -0,0 +1,176 @@
+package twofactorrepo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRepo abre una base SQLite en memoria con solo las tablas del doble factor.
+func newTestRepo(t *testing.T) (*twoFactorRepo, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger:                                   logger.Discard,
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// Cada conexión a ":memory:" es una base distinta: una sola conexión
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.TwoFactor{}, &models.RecoveryCode{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return &twoFactorRepo{db: db}, db
+}
+
+func TestSavePendingTwoFactor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replaces a pending factor", func(t *testing.T) {
+		repo, _ := newTestRepo(t)
+		first, err := repo.SavePendingTwoFactor(ctx, 1, "sealed-1")
+		if err != nil {
+			t.Fatalf("first save: %v", err)
+		}
+		second, err := repo.SavePendingTwoFactor(ctx, 1, "sealed-2")
+		if err != nil {
+			t.Fatalf("second save: %v", err)
+		}
+		if second.ID != first.ID || second.SecretSealed != "sealed-2" {
+			t.Fatalf("second = %+v, want row %d with the new secret", second, first.ID)
+		}
+	})
+
+	t.Run("refuses to overwrite a confirmed factor", func(t *testing.T) {
+		repo, _ := newTestRepo(t)
+		if _, err := repo.SavePendingTwoFactor(ctx, 1, "sealed-1"); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		if err := repo.ConfirmTwoFactor(ctx, 1, 10, []string{"h1"}); err != nil {
+			t.Fatalf("confirm: %v", err)
+		}
+
+		if _, err := repo.SavePendingTwoFactor(ctx, 1, "sealed-2"); !errors.Is(err, ErrAlreadyConfirmed) {
+			t.Fatalf("err = %v, want ErrAlreadyConfirmed", err)
+		}
+		got, err := repo.TwoFactorByUser(ctx, 1)
+		if err != nil {
+			t.Fatalf("TwoFactorByUser: %v", err)
+		}
+		if got.SecretSealed != "sealed-1" || got.ConfirmedAt == nil || got.LastUsedCounter != 10 {
+			t.Fatalf("confirmed factor changed: %+v", got)
+		}
+	})
+
+	t.Run("revives a soft-deleted factor", func(t *testing.T) {
+		repo, db := newTestRepo(t)
+		if _, err := repo.SavePendingTwoFactor(ctx, 1, "sealed-1"); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		if err := repo.ConfirmTwoFactor(ctx, 1, 10, []string{"h1"}); err != nil {
+			t.Fatalf("confirm: %v", err)
+		}
+		if err := db.Where("user_id = ?", 1).Delete(&models.TwoFactor{}).Error; err != nil {
+			t.Fatalf("soft delete: %v", err)
+		}
+
+		got, err := repo.SavePendingTwoFactor(ctx, 1, "sealed-2")
+		if err != nil {
+			t.Fatalf("save after delete: %v", err)
+		}
+		if got.ConfirmedAt != nil || got.LastUsedCounter != 0 || got.DeletedAt.Valid {
+			t.Fatalf("revived factor = %+v, want pending", got)
+		}
+	})
+
+	t.Run("validates input", func(t *testing.T) {
+		repo, _ := newTestRepo(t)
+		if _, err := repo.SavePendingTwoFactor(ctx, 0, "sealed"); !errors.Is(err, ErrInvalidUserID) {
+			t.Errorf("user 0: err = %v, want ErrInvalidUserID", err)
+		}
+		if _, err := repo.SavePendingTwoFactor(ctx, 1, ""); !errors.Is(err, ErrSecretEmpty) {
+			t.Errorf("empty secret: err = %v, want ErrSecretEmpty", err)
+		}
+	})
+}
+
+func TestUseCounter(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestRepo(t)
+	if _, err := repo.SavePendingTwoFactor(ctx, 1, "sealed"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// Sin confirmar no se acepta ningún paso
+	if err := repo.UseCounter(ctx, 1, 5); !errors.Is(err, ErrCodeReused) {
+		t.Fatalf("pending factor: err = %v, want ErrCodeReused", err)
+	}
+	if err := repo.ConfirmTwoFactor(ctx, 1, 10, []string{"h1"}); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		counter int64
+		want    error
+	}{
+		{"step used to confirm", 10, ErrCodeReused},
+		{"earlier step", 9, ErrCodeReused},
+		{"next step", 11, nil},
+		{"same step again", 11, ErrCodeReused},
+		{"later step", 13, nil},
+		{"step inside the window but before the last", 12, ErrCodeReused},
+	}
+	for _, tt := range tests {
+		if err := repo.UseCounter(ctx, 1, tt.counter); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestRepo(t)
+	if _, err := repo.SavePendingTwoFactor(ctx, 1, "sealed"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := repo.ConfirmTwoFactor(ctx, 1, 1, []string{"h1", "h2"}); err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+
+	if err := repo.UseRecoveryCode(ctx, 1, "h1"); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := repo.UseRecoveryCode(ctx, 1, "h1"); !errors.Is(err, ErrRecoveryCodeInvalid) {
+		t.Fatalf("second use: err = %v, want ErrRecoveryCodeInvalid", err)
+	}
+	if err := repo.UseRecoveryCode(ctx, 2, "h2"); !errors.Is(err, ErrRecoveryCodeInvalid) {
+		t.Fatalf("other user: err = %v, want ErrRecoveryCodeInvalid", err)
+	}
+	if left, err := repo.CountUnusedRecoveryCodes(ctx, 1); err != nil || left != 1 {
+		t.Fatalf("unused codes = %d, %v; want 1", left, err)
+	}
+
+	// Regenerar invalida los códigos anteriores, usados o no
+	if err := repo.ReplaceRecoveryCodes(ctx, 1, []string{"h3"}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if err := repo.UseRecoveryCode(ctx, 1, "h2"); !errors.Is(err, ErrRecoveryCodeInvalid) {
+		t.Fatalf("replaced code: err = %v, want ErrRecoveryCodeInvalid", err)
+	}
+	if err := repo.UseRecoveryCode(ctx, 1, "h3"); err != nil {
+		t.Fatalf("new code: %v", err)
+	}
+}
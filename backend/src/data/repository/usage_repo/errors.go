@@ -0,0 +1,16 @@
+package usagerepo
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de configuración
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "usage.database_required", "usage error: la conexión a la base de datos es requerida")
+
+	// Errores de validación
+	ErrUsageNil        = apperror.New(apperror.Validation, "usage.nil", "usage error: el registro de uso no puede ser nil")
+	ErrInvalidUserID   = apperror.New(apperror.Validation, "usage.invalid_user_id", "usage error: id de usuario inválido")
+	ErrModelEmpty      = apperror.New(apperror.Validation, "usage.model_empty", "usage error: el modelo no puede estar vacío")
+	ErrNegativeTokens  = apperror.New(apperror.Validation, "usage.negative_tokens", "usage error: la cantidad de tokens no puede ser negativa")
+	ErrInvalidGroupBy  = apperror.New(apperror.Validation, "usage.invalid_group_by", "usage error: agrupación inválida (debe ser: user, module)")
+	ErrInvalidDateSpan = apperror.New(apperror.Validation, "usage.invalid_date_span", "usage error: rango de fechas inválido")
+)
@@ -0,0 +1,51 @@
+package usagerepo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+)
+
+// Lectura de consumo
+type UsageReader interface {
+	SumTokensByUser(ctx context.Context, userID uint, since time.Time) (int64, error)
+	AggregateUsage(ctx context.Context, filter UsageFilter) ([]UsageAggregate, error)
+}
+
+// Escritura de consumo
+type UsageWriter interface {
+	CreateUsage(ctx context.Context, usage *models.LLMUsage) (*models.LLMUsage, error)
+}
+
+// Interfaz principal
+type UsageRepo interface {
+	UsageReader
+	UsageWriter
+}
+
+// Filtro para reportes agregados
+type UsageFilter struct {
+	UserID   uint      // Filtrar por usuario específico
+	ModuleID uint      // Filtrar por módulo específico
+	From     time.Time // Desde (inclusive); cero = sin límite
+	To       time.Time // Hasta (exclusivo); cero = sin límite
+	GroupBy  string    // GroupByUser | GroupByModule
+}
+
+// Fila de un reporte agregado
+type UsageAggregate struct {
+	Key              uint // user_id o module_id según GroupBy
+	Calls            int64
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+	CostUSD          float64
+	AvgLatencyMs     float64
+}
+
+// Constantes de agrupación
+const (
+	GroupByUser   = "user"
+	GroupByModule = "module"
+)
@@ -0,0 +1,119 @@
+package usagerepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"gorm.io/gorm"
+)
+
+type usageRepo struct {
+	db *gorm.DB
+}
+
+func NewUsageRepo(db *gorm.DB) (UsageRepo, error) {
+	if db == nil {
+		return nil, ErrDatabaseRequired
+	}
+	return &usageRepo{
+		db: db,
+	}, nil
+}
+
+// CreateUsage implements UsageRepo.
+func (u *usageRepo) CreateUsage(ctx context.Context, usage *models.LLMUsage) (*models.LLMUsage, error) {
+	if usage == nil {
+		return nil, ErrUsageNil
+	}
+	if usage.UserID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if usage.ModelName == "" {
+		return nil, ErrModelEmpty
+	}
+	if usage.PromptTokens < 0 || usage.CompletionTokens < 0 {
+		return nil, ErrNegativeTokens
+	}
+
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+
+	if err := database.Conn(ctx, u.db).Create(usage).Error; err != nil {
+		return nil, fmt.Errorf("error inesperado registrando el consumo: %w", err)
+	}
+
+	return usage, nil
+}
+
+// SumTokensByUser implements UsageRepo.
+func (u *usageRepo) SumTokensByUser(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	if userID == 0 {
+		return 0, ErrInvalidUserID
+	}
+
+	var total int64
+	err := database.Conn(ctx, u.db).
+		Model(&models.LLMUsage{}).
+		Select("COALESCE(SUM(total_tokens), 0)").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("error inesperado sumando los tokens del usuario: %w", err)
+	}
+
+	return total, nil
+}
+
+// AggregateUsage implements UsageRepo.
+func (u *usageRepo) AggregateUsage(ctx context.Context, filter UsageFilter) ([]UsageAggregate, error) {
+	var keyColumn string
+	switch filter.GroupBy {
+	case GroupByUser:
+		keyColumn = "user_id"
+	case GroupByModule:
+		keyColumn = "module_id"
+	default:
+		return nil, ErrInvalidGroupBy
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, ErrInvalidDateSpan
+	}
+
+	query := database.Conn(ctx, u.db).
+		Model(&models.LLMUsage{}).
+		Select(keyColumn + ` AS key,
+			COUNT(*) AS calls,
+			COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+			COALESCE(SUM(completion_tokens), 0) AS completion_tokens,
+			COALESCE(SUM(total_tokens), 0) AS total_tokens,
+			COALESCE(SUM(cost_usd), 0) AS cost_usd,
+			COALESCE(AVG(latency_ms), 0) AS avg_latency_ms`)
+
+	// Aplicar filtros dinámicos
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ModuleID != 0 {
+		query = query.Where("module_id = ?", filter.ModuleID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var rows []UsageAggregate
+	err := query.
+		Group(keyColumn).
+		Order("total_tokens DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error inesperado agregando el consumo: %w", err)
+	}
+
+	return rows, nil
+}
@@ -1,27 +1,36 @@
 package userrepo
 
-import "errors"
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
 
 var (
 	// Errores de búsqueda
-	ErrUserNotFound = errors.New("usuario no encontrado")
+	ErrUserNotFound = apperror.New(apperror.NotFound, "user.not_found", "usuario no encontrado")
 
 	// Errores de configuración
-	ErrDatabaseRequired = errors.New("user error: la conexión a la base de datos es requerida")
+	ErrDatabaseRequired = apperror.New(apperror.Internal, "user.database_required", "user error: la conexión a la base de datos es requerida")
 
 	// Errores de validación
-	ErrUserNil               = errors.New("user error: el usuario no puede ser nil")
-	ErrInvalidUserID         = errors.New("user error: id de usuario inválido")
-	ErrEmailEmpty            = errors.New("user error: el email no puede estar vacío")
-	ErrUsernameEmpty         = errors.New("user error: el nombre de usuario no puede estar vacío")
-	ErrPasswordHashEmpty     = errors.New("user error: el hash de contraseña no puede estar vacío")
-	ErrRoleEmpty             = errors.New("user error: el rol no puede estar vacío")
-	ErrMissingRequiredFields = errors.New("user error: faltan campos requeridos: username/email/password")
+	ErrUserNil               = apperror.New(apperror.Validation, "user.nil", "user error: el usuario no puede ser nil")
+	ErrInvalidUserID         = apperror.New(apperror.Validation, "user.invalid_user_id", "user error: id de usuario inválido")
+	ErrEmailEmpty            = apperror.New(apperror.Validation, "user.email_empty", "user error: el email no puede estar vacío")
+	ErrUsernameEmpty         = apperror.New(apperror.Validation, "user.username_empty", "user error: el nombre de usuario no puede estar vacío")
+	ErrPasswordHashEmpty     = apperror.New(apperror.Validation, "user.password_hash_empty", "user error: el hash de contraseña no puede estar vacío")
+	ErrRoleEmpty             = apperror.New(apperror.Validation, "user.role_empty", "user error: el rol no puede estar vacío")
+	ErrMissingRequiredFields = apperror.New(apperror.Validation, "user.missing_required_fields", "user error: faltan campos requeridos: username/email/password")
 
 	// Errores de conflicto/unicidad
-	ErrUserNameConflict  = errors.New("user error: el nombre de usuario ya está en uso")
-	ErrUserEmailConflict = errors.New("user error: el email ya está en uso")
+	ErrUserNameConflict  = apperror.New(apperror.Conflict, "user.username_conflict", "user error: el nombre de usuario ya está en uso")
+	ErrUserEmailConflict = apperror.New(apperror.Conflict, "user.email_conflict", "user error: el email ya está en uso")
+
+	// Errores de papelera
+	ErrUserNotDeleted = apperror.New(apperror.Conflict, "user.not_deleted", "user error: el usuario no está en la papelera")
+	ErrUserErased     = apperror.New(apperror.Conflict, "user.erased", "user error: los datos del usuario fueron borrados, no se puede restaurar")
 
 	// Errores de negocio
-	ErrInvalidRole = errors.New("user error: rol inválido: debe ser student, teacher o admin")
+	ErrInvalidRole   = apperror.New(apperror.Validation, "user.invalid_role", "user error: rol inválido: debe ser student, teacher o admin")
+	ErrInvalidLocale = apperror.New(apperror.Validation, "user.invalid_locale", "user error: idioma inválido: debe ser es o en")
+
+	// Errores de verificación del email
+	ErrEmailChanged          = apperror.New(apperror.Conflict, "user.email_changed", "user error: el email de la cuenta cambió desde que se envió la verificación")
+	ErrVerificationThrottled = apperror.New(apperror.RateLimited, "user.verification_throttled", "user error: ya se envió un correo de verificación hace poco, espera antes de pedir otro")
 )
@@ -2,8 +2,11 @@ package userrepo
 
 import (
 	"context"
+	"time"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/listing"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
 )
 
 // Lectura
@@ -11,7 +14,7 @@ type UserReader interface {
 	UserByID(ctx context.Context, id uint) (*models.User, error)
 	UserByUsername(ctx context.Context, username string) (*models.User, error)
 	UserByEmail(ctx context.Context, email string) (*models.User, error)
-	ListUsers(ctx context.Context, filter UserFilter) ([]models.User, error)
+	ListUsers(ctx context.Context, filter UserFilter) (pagination.Page[models.User], error)
 }
 
 // Escritura
@@ -19,7 +22,17 @@ type UserWriter interface {
 	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
 	UpdatePassword(ctx context.Context, id uint, newHash string) error
 	UpdateRole(ctx context.Context, id uint, newRole string) error
-	DeleteUser(ctx context.Context, id uint) error // soft delete (gorm)
+	UpdateLocale(ctx context.Context, id uint, locale string) error // "" = sin preferencia
+	DeleteUser(ctx context.Context, id uint) error                  // soft delete (gorm)
+
+	// Papelera
+	RestoreUser(ctx context.Context, id uint) (*models.User, error)                                         // Falla si el nombre o el email se ocuparon mientras tanto
+	PurgeableUserIDs(ctx context.Context, deletedBefore time.Time, afterID uint, limit int) ([]uint, error) // En la papelera desde antes de deletedBefore, por id
+
+	// Verificación del email
+	UpdateEmail(ctx context.Context, id uint, newEmail string) error             // Deja el email nuevo sin verificar
+	MarkEmailVerified(ctx context.Context, id uint, email string) error          // Falla si el email ya no es ese
+	MarkVerificationSent(ctx context.Context, id uint, notAfter time.Time) error // Falla si hubo un envío después de notAfter
 }
 
 type UserRepo interface {
@@ -29,8 +42,14 @@ type UserRepo interface {
 
 // Filtros simples
 type UserFilter struct {
-	Role   string // "" = todos, "student" = solo estudiantes
-	Search string // "" = todos, "juan" = buscar "juan" en username/email
-	Limit  int
-	Offset int
+	Roles  []string          // vacío = todos, ["student", "teacher"] = cualquiera de esos roles
+	Search string            // "" = todos, "juan" = buscar "juan" en username/email
+	Spec   listing.Spec      // Orden, rango de creación y eliminados
+	Page   pagination.Params // Cursor, tamaño de página y total opcional
+}
+
+// Columnas por las que se puede ordenar ListUsers
+var UserListSchema = listing.Schema{
+	Sortable: []string{"created_at", "updated_at", "user_name", "email", "role"},
+	Default:  "-created_at",
 }
@@ -3,9 +3,14 @@ package userrepo
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
 	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/data/pagination"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/i18n"
 	"gorm.io/gorm"
 )
 
@@ -32,7 +37,7 @@ func (u *userRepo) CreateUser(ctx context.Context, user *models.User) (*models.U
 		return nil, ErrMissingRequiredFields
 	}
 
-	err := u.db.WithContext(ctx).Create(user).Error
+	err := database.Conn(ctx, u.db).Create(user).Error
 	if err != nil {
 		errStr := strings.ToLower(err.Error())
 		if strings.Contains(errStr, "ux_users_username") {
@@ -52,7 +57,7 @@ func (u *userRepo) DeleteUser(ctx context.Context, id uint) error {
 		return ErrInvalidUserID
 	}
 
-	result := u.db.WithContext(ctx).Delete(&models.User{}, id)
+	result := database.Conn(ctx, u.db).Delete(&models.User{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -64,13 +69,67 @@ func (u *userRepo) DeleteUser(ctx context.Context, id uint) error {
 	return nil
 }
 
+// RestoreUser implements UserRepo.
+func (u *userRepo) RestoreUser(ctx context.Context, id uint) (*models.User, error) {
+	if id == 0 {
+		return nil, ErrInvalidUserID
+	}
+
+	var user models.User
+	err := database.Conn(ctx, u.db).Unscoped().First(&user, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !user.DeletedAt.Valid {
+		return nil, ErrUserNotDeleted
+	}
+	if user.ErasedAt != nil {
+		return nil, ErrUserErased
+	}
+
+	// Mientras estuvo en la papelera otra cuenta pudo tomar su nombre o email:
+	// los índices únicos parciales lo detectan al restaurar
+	err = database.Conn(ctx, u.db).Unscoped().Model(&user).Update("deleted_at", nil).Error
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "ux_users_username") {
+			return nil, ErrUserNameConflict
+		}
+		if strings.Contains(errStr, "ux_users_email") {
+			return nil, ErrUserEmailConflict
+		}
+		return nil, err
+	}
+
+	user.DeletedAt = gorm.DeletedAt{}
+	return &user, nil
+}
+
+// PurgeableUserIDs implements UserRepo.
+func (u *userRepo) PurgeableUserIDs(ctx context.Context, deletedBefore time.Time, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := database.Conn(ctx, u.db).
+		Unscoped().
+		Model(&models.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND id > ?", deletedBefore, afterID).
+		Where("erased_at IS NULL").
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
 // ListUsers implements UserRepo.
-func (u *userRepo) ListUsers(ctx context.Context, filter UserFilter) ([]models.User, error) {
-	query := u.db.WithContext(ctx).Model(&models.User{})
+func (u *userRepo) ListUsers(ctx context.Context, filter UserFilter) (pagination.Page[models.User], error) {
+	// Las cuentas con los datos borrados no se listan, ni siquiera en la papelera
+	query := database.Conn(ctx, u.db).Model(&models.User{}).Where("erased_at IS NULL")
 
-	// Filtrar por rol si se especifica
-	if filter.Role != "" {
-		query = query.Where("role = ?", filter.Role)
+	// Filtrar por uno o varios roles
+	if len(filter.Roles) > 0 {
+		query = query.Where("role IN ?", filter.Roles)
 	}
 
 	// Búsqueda por texto en username o email
@@ -79,17 +138,14 @@ func (u *userRepo) ListUsers(ctx context.Context, filter UserFilter) ([]models.U
 		query = query.Where("user_name ILIKE ? OR email ILIKE ?", searchTerm, searchTerm)
 	}
 
-	// Aplicar limit y offset para paginación
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
-	}
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
+	// Orden, rango de creación y eliminados
+	query, sort, err := filter.Spec.Apply(query, UserListSchema)
+	if err != nil {
+		return pagination.Page[models.User]{}, err
 	}
 
-	var users []models.User
-	err := query.Find(&users).Error
-	return users, err
+	// Paginación por cursor sobre (columna de orden, id)
+	return pagination.Find[models.User](query, filter.Page, sort)
 }
 
 // UpdatePassword implements UserRepo.
@@ -101,7 +157,7 @@ func (u *userRepo) UpdatePassword(ctx context.Context, id uint, newHash string)
 		return ErrPasswordHashEmpty
 	}
 
-	result := u.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("password_hash", newHash)
+	result := database.Conn(ctx, u.db).Model(&models.User{}).Where("id = ?", id).Update("password_hash", newHash)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -132,7 +188,28 @@ func (u *userRepo) UpdateRole(ctx context.Context, id uint, newRole string) erro
 		return ErrInvalidRole
 	}
 
-	result := u.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("role", newRole)
+	result := database.Conn(ctx, u.db).Model(&models.User{}).Where("id = ?", id).Update("role", newRole)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateLocale implements UserRepo.
+func (u *userRepo) UpdateLocale(ctx context.Context, id uint, locale string) error {
+	if id == 0 {
+		return ErrInvalidUserID
+	}
+	if locale != "" && !slices.Contains(i18n.Supported, i18n.Locale(locale)) {
+		return ErrInvalidLocale
+	}
+
+	result := database.Conn(ctx, u.db).Model(&models.User{}).Where("id = ?", id).Update("locale", locale)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -151,7 +228,7 @@ func (u *userRepo) UserByEmail(ctx context.Context, email string) (*models.User,
 	}
 
 	var user models.User
-	err := u.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := database.Conn(ctx, u.db).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
@@ -169,7 +246,7 @@ func (u *userRepo) UserByID(ctx context.Context, id uint) (*models.User, error)
 	}
 
 	var user models.User
-	err := u.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	err := database.Conn(ctx, u.db).Where("id = ?", id).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
@@ -187,7 +264,7 @@ func (u *userRepo) UserByUsername(ctx context.Context, username string) (*models
 	}
 
 	var user models.User
-	err := u.db.WithContext(ctx).Where("user_name = ?", username).First(&user).Error
+	err := database.Conn(ctx, u.db).Where("user_name = ?", username).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
@@ -197,3 +274,81 @@ func (u *userRepo) UserByUsername(ctx context.Context, username string) (*models
 
 	return &user, nil
 }
+
+// UpdateEmail implements UserRepo.
+func (u *userRepo) UpdateEmail(ctx context.Context, id uint, newEmail string) error {
+	if id == 0 {
+		return ErrInvalidUserID
+	}
+	if newEmail == "" {
+		return ErrEmailEmpty
+	}
+
+	result := database.Conn(ctx, u.db).Model(&models.User{}).Where("id = ?", id).Updates(map[string]any{
+		"email":                newEmail,
+		"email_verified_at":    nil,
+		"verification_sent_at": nil,
+	})
+	if result.Error != nil {
+		if strings.Contains(strings.ToLower(result.Error.Error()), "ux_users_email") {
+			return ErrUserEmailConflict
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// MarkEmailVerified implements UserRepo.
+func (u *userRepo) MarkEmailVerified(ctx context.Context, id uint, email string) error {
+	if id == 0 {
+		return ErrInvalidUserID
+	}
+	if email == "" {
+		return ErrEmailEmpty
+	}
+
+	result := database.Conn(ctx, u.db).Model(&models.User{}).
+		Where("id = ? AND email = ?", id, email).
+		Update("email_verified_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if _, err := u.UserByID(ctx, id); err != nil {
+			return err
+		}
+		return ErrEmailChanged
+	}
+
+	return nil
+}
+
+// MarkVerificationSent implements UserRepo.
+func (u *userRepo) MarkVerificationSent(ctx context.Context, id uint, notAfter time.Time) error {
+	if id == 0 {
+		return ErrInvalidUserID
+	}
+
+	// La condición va en el UPDATE para que dos peticiones simultáneas no envíen ambas
+	result := database.Conn(ctx, u.db).Model(&models.User{}).
+		Where("id = ? AND (verification_sent_at IS NULL OR verification_sent_at <= ?)", id, notAfter).
+		Update("verification_sent_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if _, err := u.UserByID(ctx, id); err != nil {
+			return err
+		}
+		return ErrVerificationThrottled
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/pgvector/pgvector-go"
+)
+
+// ChatCompleter abstrae al proveedor de LLM que genera respuestas de chat.
+type ChatCompleter interface {
+	Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error)
+}
+
+// Embedder abstrae al proveedor que genera embeddings (1536 dimensiones).
+type Embedder interface {
+	Embed(ctx context.Context, input string) (pgvector.Vector, error)
+}
@@ -0,0 +1,231 @@
+package prompt
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/Dieg0Code/aiep-agent/src/llm"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/tokenizer"
+)
+
+const (
+	// messageOverhead son los tokens que el formato de chat agrega por mensaje (rol, separadores).
+	messageOverhead = 4
+	// replyPriming son los tokens que el proveedor agrega para iniciar la respuesta.
+	replyPriming = 3
+)
+
+// Budget define cómo se reparte la ventana de contexto entre las secciones del prompt.
+// El historial recibe todo lo que las demás secciones no usan.
+type Budget struct {
+	ContextWindow    int     // Ventana total del modelo
+	CompletionTokens int     // Tokens reservados para la respuesta
+	SystemShare      float64 // Fracción máxima para las instrucciones de sistema
+	ProfileShare     float64 // Fracción máxima para el perfil/insights del estudiante
+	TopicsShare      float64 // Fracción máxima para los temas recuperados
+}
+
+// DefaultBudget devuelve un reparto pensado para modelos con 16k de contexto.
+func DefaultBudget() Budget {
+	return Budget{
+		ContextWindow:    16000,
+		CompletionTokens: 1024,
+		SystemShare:      0.15,
+		ProfileShare:     0.10,
+		TopicsShare:      0.30,
+	}
+}
+
+// Input son las secciones del prompt, ya ordenadas por prioridad.
+type Input struct {
+	System  string        // Instrucciones del agente
+	Profile []string      // Insights del estudiante, del más al menos importante
+	Topics  []string      // Fragmentos de temas, del más al menos relevante
+	History []llm.Message // Historial en orden cronológico (el último es el mensaje actual)
+}
+
+// SectionReport resume el uso de una sección.
+type SectionReport struct {
+	Allocated int // Tokens asignados
+	Used      int // Tokens usados
+	Dropped   int // Elementos descartados (o truncados) por falta de espacio
+}
+
+// Report resume el reparto del presupuesto para una petición.
+type Report struct {
+	Model     string
+	Encoding  string
+	Available int // Ventana menos la reserva de completado
+	System    SectionReport
+	Profile   SectionReport
+	Topics    SectionReport
+	History   SectionReport
+	Total     int // Tokens totales del prompt
+}
+
+// Prompt es el resultado listo para enviar al LLM.
+type Prompt struct {
+	Messages []llm.Message
+	Report   Report
+}
+
+// Builder arma prompts respetando el presupuesto de tokens del modelo.
+type Builder struct {
+	tokenizers *tokenizer.Registry
+	budget     Budget
+	logger     *slog.Logger
+}
+
+// NewBuilder crea un Builder. Si tokenizers es nil se cuenta por caracteres.
+func NewBuilder(tokenizers *tokenizer.Registry, budget Budget, logger *slog.Logger) *Builder {
+	if tokenizers == nil {
+		tokenizers = tokenizer.NewRegistry(nil)
+	}
+	return &Builder{
+		tokenizers: tokenizers,
+		budget:     budget,
+		logger:     logger,
+	}
+}
+
+// Build arma el prompt para model. El recorte es determinista: ante la misma
+// entrada y presupuesto siempre se descartan los mismos elementos.
+func (b *Builder) Build(ctx context.Context, model string, in Input) Prompt {
+	tok := b.tokenizers.ForModel(model)
+
+	report := Report{
+		Model:     model,
+		Encoding:  tok.Name(),
+		Available: b.budget.ContextWindow - b.budget.CompletionTokens - replyPriming,
+	}
+
+	// 1. Instrucciones de sistema: se truncan por el final
+	report.System.Allocated = share(report.Available, b.budget.SystemShare)
+	system := in.System
+	if tok.Count(system) > report.System.Allocated {
+		system = tok.Truncate(system, report.System.Allocated)
+		report.System.Dropped = 1
+	}
+	report.System.Used = tok.Count(system)
+
+	// 2. Perfil y 3. temas: se descartan los elementos de menor prioridad
+	report.Profile.Allocated = share(report.Available, b.budget.ProfileShare)
+	profile := fitItems(tok, in.Profile, report.Profile.Allocated, &report.Profile)
+
+	report.Topics.Allocated = share(report.Available, b.budget.TopicsShare)
+	topics := fitItems(tok, in.Topics, report.Topics.Allocated, &report.Topics)
+
+	systemContent := composeSystem(system, profile, topics)
+	used := tok.Count(systemContent) + messageOverhead
+
+	// 4. Historial: recibe lo que sobra; se descartan los mensajes más antiguos
+	report.History.Allocated = report.Available - used
+	history := fitHistory(tok, in.History, report.History.Allocated, &report.History)
+
+	messages := make([]llm.Message, 0, len(history)+1)
+	if systemContent != "" {
+		messages = append(messages, llm.Message{Role: llm.RoleSystem, Content: systemContent})
+	}
+	messages = append(messages, history...)
+
+	report.Total = used + report.History.Used + replyPriming
+
+	b.logger.InfoContext(ctx, "Prompt budget",
+		"model", report.Model,
+		"encoding", report.Encoding,
+		"available", report.Available,
+		"system_allocated", report.System.Allocated,
+		"system_used", report.System.Used,
+		"profile_allocated", report.Profile.Allocated,
+		"profile_used", report.Profile.Used,
+		"profile_dropped", report.Profile.Dropped,
+		"topics_allocated", report.Topics.Allocated,
+		"topics_used", report.Topics.Used,
+		"topics_dropped", report.Topics.Dropped,
+		"history_allocated", report.History.Allocated,
+		"history_used", report.History.Used,
+		"history_dropped", report.History.Dropped,
+		"total", report.Total,
+	)
+
+	return Prompt{Messages: messages, Report: report}
+}
+
+// share calcula la porción entera de total.
+func share(total int, fraction float64) int {
+	if fraction <= 0 || total <= 0 {
+		return 0
+	}
+	return int(float64(total) * fraction)
+}
+
+// fitItems conserva los elementos en orden mientras quepan en limit.
+func fitItems(tok tokenizer.Tokenizer, items []string, limit int, report *SectionReport) []string {
+	kept := make([]string, 0, len(items))
+	for i, item := range items {
+		cost := tok.Count(item) + 1 // +1 por el separador de lista
+		if report.Used+cost > limit {
+			report.Dropped = len(items) - i
+			break
+		}
+		report.Used += cost
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// fitHistory conserva los mensajes más recientes que quepan en limit.
+// El último mensaje se incluye siempre (truncado si hace falta).
+func fitHistory(tok tokenizer.Tokenizer, history []llm.Message, limit int, report *SectionReport) []llm.Message {
+	if len(history) == 0 {
+		return nil
+	}
+
+	start := len(history)
+	for i := len(history) - 1; i >= 0; i-- {
+		cost := tok.Count(history[i].Content) + messageOverhead
+		if report.Used+cost > limit {
+			break
+		}
+		report.Used += cost
+		start = i
+	}
+
+	if start == len(history) {
+		last := history[len(history)-1]
+		last.Content = tok.Truncate(last.Content, limit-messageOverhead)
+		report.Used = tok.Count(last.Content) + messageOverhead
+		report.Dropped = len(history)
+		return []llm.Message{last}
+	}
+
+	report.Dropped = start
+	return history[start:]
+}
+
+// composeSystem une las instrucciones con el perfil y los temas en un solo mensaje de sistema.
+func composeSystem(system string, profile, topics []string) string {
+	var b strings.Builder
+	b.WriteString(system)
+
+	if len(profile) > 0 {
+		b.WriteString("\n\n## Perfil del estudiante\n")
+		for _, item := range profile {
+			b.WriteString("- ")
+			b.WriteString(item)
+			b.WriteString("\n")
+		}
+	}
+
+	if len(topics) > 0 {
+		b.WriteString("\n\n## Material del curso\n")
+		for _, item := range topics {
+			b.WriteString("- ")
+			b.WriteString(item)
+			b.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,38 @@
+package llm
+
+import "context"
+
+// Operaciones que consumen tokens del proveedor.
+const (
+	OperationChat      = "chat"
+	OperationSummary   = "summary"
+	OperationEmbedding = "embedding"
+)
+
+// Scope identifica a quién se atribuye el consumo de una llamada al LLM.
+type Scope struct {
+	UserID         uint
+	ConversationID uint
+	ModuleID       uint
+	Operation      string
+}
+
+type scopeKey struct{}
+
+// WithScope adjunta el Scope al contexto de la petición.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFromContext devuelve el Scope del contexto (vacío si no hay).
+func ScopeFromContext(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeKey{}).(Scope)
+	return scope
+}
+
+// WithOperation devuelve un contexto con la misma atribución pero otra operación.
+func WithOperation(ctx context.Context, operation string) context.Context {
+	scope := ScopeFromContext(ctx)
+	scope.Operation = operation
+	return WithScope(ctx, scope)
+}
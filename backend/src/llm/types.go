@@ -0,0 +1,43 @@
+package llm
+
+// Roles de mensaje aceptados por el proveedor (mismos valores que chatrepo).
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleSystem    = "system"
+	RoleTool      = "tool"
+)
+
+// Message representa un mensaje enviado al LLM.
+type Message struct {
+	Role       string
+	Name       string
+	Content    string
+	ToolCallID string
+}
+
+// CompletionRequest agrupa los parámetros de una llamada de completado.
+type CompletionRequest struct {
+	Model       string
+	Messages    []Message
+	MaxTokens   int
+	Temperature float32
+}
+
+// Usage contiene el consumo de tokens reportado por el proveedor.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// TotalTokens devuelve la suma de tokens de prompt y de completado.
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// CompletionResponse es la respuesta del LLM.
+type CompletionResponse struct {
+	Model   string
+	Content string
+	Usage   Usage
+}
@@ -0,0 +1,150 @@
+// Package apperror define el error de dominio compartido: un código estable que los
+// clientes pueden comparar, una categoría que decide el status HTTP y un mensaje
+// apto para mostrar al usuario.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Category agrupa los errores según cómo debe responder la API.
+type Category string
+
+const (
+	NotFound     Category = "not_found"
+	Conflict     Category = "conflict"
+	Validation   Category = "validation"
+	Unauthorized Category = "unauthorized"
+	Forbidden    Category = "forbidden"
+	RateLimited  Category = "rate_limited"
+	Unavailable  Category = "unavailable"
+	Internal     Category = "internal"
+)
+
+// CodeInternal es el código de cualquier error sin categoría conocida.
+const CodeInternal = "internal"
+
+// Error es un error de dominio. Se usa como sentinel de paquete: errors.Is compara
+// por código, así que las copias de WithMessage siguen siendo el mismo error.
+type Error struct {
+	code     string
+	category Category
+	text     string            // Texto completo, con el prefijo del paquete, para los logs
+	message  string            // Texto para el usuario, sin el prefijo
+	detail   string            // Variante del mensaje en los catálogos ("<code>.<detail>")
+	params   map[string]string // Valores de los marcadores {nombre} de esa variante
+}
+
+// New crea un error de dominio. text sigue la convención de los sentinels del
+// repo ("<entidad> error: ..."); el prefijo se omite en Message.
+func New(category Category, code, text string) *Error {
+	return &Error{
+		code:     code,
+		category: category,
+		text:     text,
+		message:  stripPrefix(text),
+	}
+}
+
+// Error implements error.
+func (e *Error) Error() string { return e.text }
+
+// Code devuelve el código estable, p. ej. "user.email_conflict".
+func (e *Error) Code() string { return e.code }
+
+// Category devuelve la categoría del error.
+func (e *Error) Category() Category { return e.category }
+
+// Message devuelve el texto para el usuario.
+func (e *Error) Message() string { return e.message }
+
+// Is compara por código, de modo que errors.Is funciona con copias del sentinel.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.code == e.code
+}
+
+// Detail devuelve la variante del mensaje ("" = el mensaje base del código).
+func (e *Error) Detail() string { return e.detail }
+
+// Params devuelve los valores que completan la variante del mensaje.
+func (e *Error) Params() map[string]string { return e.params }
+
+// WithMessage devuelve una copia con un mensaje más específico (p. ej. con la hora
+// en que termina un bloqueo) que conserva el código y la categoría.
+func (e *Error) WithMessage(message string) *Error {
+	c := *e
+	c.text, c.message = message, message
+	return &c
+}
+
+// WithDetail es WithMessage con una variante y sus parámetros, para que los
+// catálogos de idiomas puedan armar el mismo mensaje (p. ej. "auth.login_locked.until"
+// con {until}).
+func (e *Error) WithDetail(message, detail string, params map[string]string) *Error {
+	c := e.WithMessage(message)
+	c.detail, c.params = detail, params
+	return c
+}
+
+// Coder lo implementan los errores con datos propios que igual deben mapearse a un
+// código estable (p. ej. LoginLockedError).
+type Coder interface {
+	AppError() *Error
+}
+
+// errInternal es lo que ve el cliente ante un error sin código: nunca el texto original.
+var errInternal = New(Internal, CodeInternal, "ocurrió un error interno, intenta nuevamente más tarde")
+
+// From busca el error de dominio en la cadena de err. Si no hay ninguno devuelve el
+// error interno genérico; nil si err es nil.
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.AppError()
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return errInternal
+}
+
+// HTTPStatus devuelve el status HTTP de la categoría del error (200 si err es nil).
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	switch From(err).category {
+	case NotFound:
+		return http.StatusNotFound
+	case Conflict:
+		return http.StatusConflict
+	case Validation:
+		return http.StatusBadRequest
+	case Unauthorized:
+		return http.StatusUnauthorized
+	case Forbidden:
+		return http.StatusForbidden
+	case RateLimited:
+		return http.StatusTooManyRequests
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// stripPrefix quita "<entidad> error: " del inicio del texto.
+func stripPrefix(text string) string {
+	prefix, rest, ok := strings.Cut(text, " error: ")
+	if !ok || strings.ContainsFunc(prefix, func(r rune) bool { return r != ' ' && (r < 'a' || r > 'z') }) {
+		return text
+	}
+	return rest
+}
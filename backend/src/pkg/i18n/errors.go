@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"reflect"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+)
+
+// Error devuelve el mensaje de err para el usuario en locale. Busca primero la
+// variante del error ("<code>.<detail>") y luego su código; si el catálogo no lo
+// tiene, usa el mensaje del propio error (en español).
+func Error(locale Locale, err error) string {
+	appErr := apperror.From(err)
+	if appErr == nil {
+		return ""
+	}
+	if appErr.Detail() != "" {
+		if message, ok := lookup(locale, appErr.Code()+"."+appErr.Detail(), appErr.Params()); ok {
+			return message
+		}
+		return appErr.Message() // El mensaje base del código perdería el detalle
+	}
+	if message, ok := lookup(locale, appErr.Code(), nil); ok {
+		return message
+	}
+	return appErr.Message()
+}
+
+// FieldError es lo que se necesita de un error de validación de un campo. Lo
+// cumplen los errores de las reglas `binding` (validator.FieldError).
+type FieldError interface {
+	Field() string
+	Tag() string
+	Param() string
+	Kind() reflect.Kind
+}
+
+// Validation traduce los errores de las reglas `binding` a un mensaje por campo.
+// err puede ser un FieldError o una lista de ellos (validator.ValidationErrors);
+// si no es ninguno devuelve nil.
+func Validation(locale Locale, err error) map[string]string {
+	var fieldErrors []FieldError
+	if fe, ok := err.(FieldError); ok {
+		fieldErrors = append(fieldErrors, fe)
+	} else if v := reflect.ValueOf(err); v.IsValid() && v.Kind() == reflect.Slice {
+		for i := range v.Len() {
+			if fe, ok := v.Index(i).Interface().(FieldError); ok {
+				fieldErrors = append(fieldErrors, fe)
+			}
+		}
+	}
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		if _, seen := result[fe.Field()]; seen {
+			continue // Se informa la primera regla que falló en cada campo
+		}
+		result[fe.Field()] = ruleMessage(locale, fe)
+	}
+	return result
+}
+
+// ruleMessage busca "validation.<regla>.<tipo>" (string o slice), luego
+// "validation.<regla>" y por último el mensaje genérico.
+func ruleMessage(locale Locale, fe FieldError) string {
+	params := map[string]string{"param": fe.Param()}
+	key := "validation." + fe.Tag()
+
+	switch fe.Kind() {
+	case reflect.String:
+		if message, ok := lookup(locale, key+".string", params); ok {
+			return message
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if message, ok := lookup(locale, key+".slice", params); ok {
+			return message
+		}
+	}
+	if message, ok := lookup(locale, key, params); ok {
+		return message
+	}
+	return T(locale, "validation.invalid", nil)
+}
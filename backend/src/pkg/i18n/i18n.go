@@ -0,0 +1,113 @@
+// Package i18n traduce los mensajes que ve el usuario (errores, validaciones y
+// respuestas) a los idiomas soportados. Los catálogos están en locales/<idioma>.json
+// y se indexan por el código estable de apperror.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Locale es un idioma soportado ("es", "en").
+type Locale string
+
+const (
+	ES Locale = "es"
+	EN Locale = "en"
+
+	// Default es el idioma de la mayoría de los usuarios (estudiantes) y el
+	// respaldo de las claves que faltan en otro catálogo.
+	Default = ES
+)
+
+// Supported son los idiomas con catálogo.
+var Supported = []Locale{ES, EN}
+
+//go:embed locales/*.json
+var files embed.FS
+
+// catalogs se carga al iniciar; un catálogo mal formado es un error de compilación
+// del binario, así que falla de inmediato.
+var catalogs = mustLoad()
+
+func mustLoad() map[Locale]map[string]string {
+	result := make(map[Locale]map[string]string, len(Supported))
+	for _, locale := range Supported {
+		data, err := files.ReadFile("locales/" + string(locale) + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: falta el catálogo %s: %v", locale, err))
+		}
+		catalog := map[string]string{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: catálogo %s mal formado: %v", locale, err))
+		}
+		result[locale] = catalog
+	}
+	return result
+}
+
+// Parse reconoce un idioma por su subetiqueta principal ("en-US" → EN).
+func Parse(tag string) (Locale, bool) {
+	primary, _, _ := strings.Cut(strings.TrimSpace(strings.ToLower(tag)), "-")
+	primary, _, _ = strings.Cut(primary, "_")
+	for _, locale := range Supported {
+		if string(locale) == primary {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// T devuelve el mensaje de key en locale con los marcadores {nombre} reemplazados.
+// Si falta en ese catálogo se usa el de Default, y si tampoco está, la propia clave.
+func T(locale Locale, key string, params map[string]string) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		message, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	return fill(message, params)
+}
+
+// lookup es T sin respaldo: indica si la clave existe en locale o en Default.
+func lookup(locale Locale, key string, params map[string]string) (string, bool) {
+	if message, ok := catalogs[locale][key]; ok {
+		return fill(message, params), true
+	}
+	if message, ok := catalogs[Default][key]; ok {
+		return fill(message, params), true
+	}
+	return "", false
+}
+
+func fill(message string, params map[string]string) string {
+	if len(params) == 0 {
+		return message
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+// localeKey es la clave del contexto donde viaja el idioma de la petición.
+type localeKey struct{}
+
+// WithLocale devuelve un contexto que lleva el idioma de la petición.
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext devuelve el idioma de la petición, o Default si no se negoció.
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(localeKey{}).(Locale); ok {
+		return locale
+	}
+	return Default
+}
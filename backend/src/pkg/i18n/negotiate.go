@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Negotiate elige el idioma de una petición: primero la preferencia guardada del
+// usuario, luego la cabecera Accept-Language (respetando los pesos q) y por último
+// Default.
+func Negotiate(preference, acceptLanguage string) Locale {
+	if locale, ok := Parse(preference); ok {
+		return locale
+	}
+
+	best, bestQ := Locale(""), 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, q := parseRange(part)
+		if q <= bestQ {
+			continue
+		}
+		if locale, ok := Parse(tag); ok {
+			best, bestQ = locale, q
+		}
+	}
+	if best != "" {
+		return best
+	}
+	return Default
+}
+
+// parseRange separa "en-US;q=0.8" en la etiqueta y su peso (1 si no lo indica).
+// Un peso inválido cuenta como 0, igual que q=0: el idioma se descarta.
+func parseRange(part string) (string, float64) {
+	tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+	q := 1.0
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(name) != "q" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return tag, 0
+		}
+		q = parsed
+	}
+	return strings.TrimSpace(tag), q
+}
@@ -0,0 +1,379 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Formatos de fecha de RFC 5545
+const (
+	layoutDate        = "20060102"
+	layoutDateTimeUTC = "20060102T150405Z"
+	layoutDateTime    = "20060102T150405"
+)
+
+// maxLineOctets es el largo máximo de una línea antes de plegarla (RFC 5545 §3.1).
+const maxLineOctets = 75
+
+var (
+	ErrNoCalendar   = errors.New("ical: el archivo no contiene un VCALENDAR")
+	ErrInvalidDate  = errors.New("ical: fecha inválida")
+	ErrUnclosedItem = errors.New("ical: componente sin cerrar")
+)
+
+// Event es un VEVENT. Si AllDay es true solo se usa la fecha de Start y End.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Start        time.Time
+	End          time.Time // Exclusivo; cero = Start + 1 día (todo el día) o Start
+	AllDay       bool
+	LastModified time.Time
+	Sequence     int
+}
+
+// Calendar es un VCALENDAR listo para publicarse como archivo o suscripción.
+type Calendar struct {
+	ProdID          string
+	Name            string        // X-WR-CALNAME
+	Description     string        // X-WR-CALDESC
+	TimeZone        string        // X-WR-TIMEZONE (informativo)
+	RefreshInterval time.Duration // Sugerencia de refresco para los clientes suscritos
+	Events          []Event
+}
+
+// Bytes devuelve el calendario codificado.
+func (c *Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	_ = c.Encode(&buf) // bytes.Buffer no falla al escribir
+	return buf.Bytes()
+}
+
+// Encode escribe el calendario en formato iCalendar (RFC 5545).
+func (c *Calendar) Encode(w io.Writer) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.prop("PRODID", c.ProdID)
+	e.line("CALSCALE:GREGORIAN")
+	e.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		e.prop("X-WR-CALNAME", escape(c.Name))
+	}
+	if c.Description != "" {
+		e.prop("X-WR-CALDESC", escape(c.Description))
+	}
+	if c.TimeZone != "" {
+		e.prop("X-WR-TIMEZONE", c.TimeZone)
+	}
+	if c.RefreshInterval > 0 {
+		duration := formatDuration(c.RefreshInterval)
+		e.prop("REFRESH-INTERVAL;VALUE=DURATION", duration)
+		e.prop("X-PUBLISHED-TTL", duration)
+	}
+
+	for _, ev := range c.Events {
+		e.event(ev)
+	}
+
+	e.line("END:VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) event(ev Event) {
+	stamp := ev.LastModified
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	e.line("BEGIN:VEVENT")
+	e.prop("UID", ev.UID)
+	e.prop("DTSTAMP", stamp.UTC().Format(layoutDateTimeUTC))
+	if ev.AllDay {
+		end := ev.End
+		if end.IsZero() || !end.After(ev.Start) {
+			end = ev.Start.AddDate(0, 0, 1)
+		}
+		e.prop("DTSTART;VALUE=DATE", ev.Start.Format(layoutDate))
+		e.prop("DTEND;VALUE=DATE", end.Format(layoutDate))
+	} else {
+		e.prop("DTSTART", ev.Start.UTC().Format(layoutDateTimeUTC))
+		if !ev.End.IsZero() {
+			e.prop("DTEND", ev.End.UTC().Format(layoutDateTimeUTC))
+		}
+	}
+	if !ev.LastModified.IsZero() {
+		e.prop("LAST-MODIFIED", ev.LastModified.UTC().Format(layoutDateTimeUTC))
+	}
+	if ev.Sequence > 0 {
+		e.prop("SEQUENCE", strconv.Itoa(ev.Sequence))
+	}
+	e.prop("SUMMARY", escape(ev.Summary))
+	if ev.Description != "" {
+		e.prop("DESCRIPTION", escape(ev.Description))
+	}
+	if ev.Location != "" {
+		e.prop("LOCATION", escape(ev.Location))
+	}
+	if ev.URL != "" {
+		e.prop("URL", ev.URL)
+	}
+	e.line("TRANSP:TRANSPARENT")
+	e.line("END:VEVENT")
+}
+
+func (e *encoder) prop(name, value string) {
+	e.line(name + ":" + value)
+}
+
+// line escribe una línea plegada a 75 octetos y terminada en CRLF.
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	first := true
+	for len(s) > 0 {
+		limit := maxLineOctets
+		if !first {
+			limit-- // el espacio inicial cuenta
+		}
+		cut := len(s)
+		if cut > limit {
+			cut = limit
+			for cut > 0 && !utf8.RuneStart(s[cut]) {
+				cut--
+			}
+		}
+		if !first {
+			e.write(" ")
+		}
+		e.write(s[:cut])
+		e.write("\r\n")
+		s = s[cut:]
+		first = false
+	}
+}
+
+func (e *encoder) write(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+// escape escapa un valor de tipo TEXT.
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// unescape revierte escape.
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// formatDuration formatea una duración como DURATION de RFC 5545 (horas y minutos).
+func formatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if minutes == 0 {
+		return fmt.Sprintf("PT%dH", hours)
+	}
+	return fmt.Sprintf("PT%dH%dM", hours, minutes)
+}
+
+// Parse lee los VEVENT de un archivo iCalendar. Las fechas con TZID se interpretan
+// en esa zona si está disponible; si no, en UTC.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events     []Event
+		current    *Event
+		inCalendar bool
+		depth      int // componentes anidados dentro del VEVENT (p. ej. VALARM)
+	)
+	for _, line := range lines {
+		name, params, value := splitLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCALENDAR"):
+			inCalendar = true
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && current == nil:
+			current = &Event{}
+		case name == "BEGIN" && current != nil:
+			depth++
+		case name == "END" && current != nil && depth > 0:
+			depth--
+		case name == "END" && strings.EqualFold(value, "VEVENT") && current != nil:
+			if current.AllDay && current.End.IsZero() {
+				current.End = current.Start.AddDate(0, 0, 1)
+			}
+			events = append(events, *current)
+			current = nil
+		case current != nil && depth == 0:
+			if err := current.set(name, params, value); err != nil {
+				return nil, fmt.Errorf("%w (UID %q)", err, current.UID)
+			}
+		}
+	}
+
+	if !inCalendar {
+		return nil, ErrNoCalendar
+	}
+	if current != nil {
+		return nil, ErrUnclosedItem
+	}
+	return events, nil
+}
+
+func (ev *Event) set(name string, params map[string]string, value string) error {
+	switch name {
+	case "UID":
+		ev.UID = value
+	case "SUMMARY":
+		ev.Summary = unescape(value)
+	case "DESCRIPTION":
+		ev.Description = unescape(value)
+	case "LOCATION":
+		ev.Location = unescape(value)
+	case "URL":
+		ev.URL = value
+	case "SEQUENCE":
+		ev.Sequence, _ = strconv.Atoi(value)
+	case "LAST-MODIFIED":
+		t, _, err := parseDate(value, nil)
+		if err == nil {
+			ev.LastModified = t
+		}
+	case "DTSTART":
+		t, allDay, err := parseDate(value, params)
+		if err != nil {
+			return err
+		}
+		ev.Start, ev.AllDay = t, allDay
+	case "DTEND":
+		t, _, err := parseDate(value, params)
+		if err != nil {
+			return err
+		}
+		ev.End = t
+	}
+	return nil
+}
+
+// parseDate interpreta DATE o DATE-TIME (UTC, local o con TZID).
+func parseDate(value string, params map[string]string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len(layoutDate) {
+		t, err := time.Parse(layoutDate, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %s", ErrInvalidDate, value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(layoutDateTimeUTC, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %s", ErrInvalidDate, value)
+		}
+		return t, false, nil
+	}
+
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation(layoutDateTime, value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %s", ErrInvalidDate, value)
+	}
+	return t, false, nil
+}
+
+// unfold une las líneas plegadas (las que empiezan con espacio o tabulación).
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ical: error leyendo el archivo: %w", err)
+	}
+	return lines, nil
+}
+
+// splitLine separa "NOMBRE;PARAM=VALOR:valor" en sus partes. Los nombres y
+// parámetros se normalizan a mayúsculas.
+func splitLine(line string) (string, map[string]string, string) {
+	colon := -1
+	inQuote := false
+	for i, r := range line {
+		if r == '"' {
+			inQuote = !inQuote
+		}
+		if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
@@ -0,0 +1,15 @@
+package mailer
+
+import "context"
+
+// Message es un correo de texto plano.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer envía correos. Las implementaciones deben ser seguras para uso concurrente.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+type logMailer struct {
+	logger *slog.Logger
+}
+
+// NewLogMailer crea un Mailer que solo registra los correos en el log. Sirve para
+// desarrollo: los enlaces de verificación quedan en la salida del servidor.
+func NewLogMailer(logger *slog.Logger) Mailer {
+	return &logMailer{logger: logger}
+}
+
+// Send implements Mailer.
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return ErrMissingRecipient
+	}
+	m.logger.InfoContext(ctx, "Email not sent (log mailer)",
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", msg.Body,
+	)
+	return nil
+}
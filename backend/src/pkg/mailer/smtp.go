@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingRecipient = errors.New("mailer: falta el destinatario")
+	ErrInvalidHeader    = errors.New("mailer: el destinatario o asunto contiene saltos de línea")
+)
+
+// SMTPConfig agrupa los parámetros del servidor de correo.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Vacío = sin autenticación
+	Password string
+	From     string // Dirección del remitente
+	FromName string // Nombre que se muestra
+}
+
+type smtpMailer struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPMailer crea un Mailer que envía por SMTP (con STARTTLS si el servidor lo ofrece).
+func NewSMTPMailer(cfg SMTPConfig) Mailer {
+	return &smtpMailer{cfg: cfg}
+}
+
+// Send implements Mailer.
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return ErrMissingRecipient
+	}
+	if strings.ContainsAny(msg.To+msg.Subject, "\r\n") {
+		return ErrInvalidHeader
+	}
+
+	from := m.cfg.From
+	if m.cfg.FromName != "" {
+		from = fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", m.cfg.FromName), m.cfg.From)
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	addr := net.JoinHostPort(m.cfg.Host, fmt.Sprint(m.cfg.Port))
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	// net/smtp no recibe contexto; se respeta la cancelación previa al envío
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := smtp.SendMail(addr, auth, m.cfg.From, []string{msg.To}, []byte(b.String())); err != nil {
+		return fmt.Errorf("mailer: error enviando a %s: %w", msg.To, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package oidc
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Claims son los datos del usuario que trae un id_token validado.
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+
+	raw map[string]any
+}
+
+// Values devuelve un claim como lista de textos (p. ej. "groups" o "roles"). Acepta
+// listas JSON y textos separados por espacios o comas.
+func (c *Claims) Values(name string) []string {
+	if c == nil {
+		return nil
+	}
+	switch v := c.raw[name].(type) {
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' })
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// parseClaims lee el payload del id_token.
+func parseClaims(payload []byte) (*Claims, map[string]any, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, nil, ErrMalformedToken
+	}
+
+	claims := &Claims{
+		Issuer:            stringClaim(raw, "iss"),
+		Subject:           stringClaim(raw, "sub"),
+		Email:             strings.ToLower(strings.TrimSpace(stringClaim(raw, "email"))),
+		Name:              stringClaim(raw, "name"),
+		PreferredUsername: stringClaim(raw, "preferred_username"),
+		raw:               raw,
+	}
+	// Algunos proveedores envían email_verified como texto
+	switch v := raw["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = v
+	case string:
+		claims.EmailVerified = v == "true"
+	}
+	return claims, raw, nil
+}
+
+func stringClaim(raw map[string]any, name string) string {
+	s, _ := raw[name].(string)
+	return s
+}
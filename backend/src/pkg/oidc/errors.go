@@ -0,0 +1,21 @@
+package oidc
+
+import "errors"
+
+var (
+	ErrMissingConfig    = errors.New("oidc: faltan el issuer, el client id o la URL de retorno")
+	ErrDiscovery        = errors.New("oidc: no se pudo leer la configuración del proveedor")
+	ErrIssuerMismatch   = errors.New("oidc: el issuer del proveedor no coincide con el configurado")
+	ErrExchange         = errors.New("oidc: el proveedor rechazó el código de autorización")
+	ErrMissingIDToken   = errors.New("oidc: la respuesta del proveedor no incluye id_token")
+	ErrMalformedToken   = errors.New("oidc: id_token mal formado")
+	ErrUnsupportedAlg   = errors.New("oidc: algoritmo de firma no soportado")
+	ErrUnknownKey       = errors.New("oidc: el id_token está firmado con una clave desconocida")
+	ErrInvalidSignature = errors.New("oidc: firma del id_token inválida")
+	ErrInvalidIssuer    = errors.New("oidc: el id_token es de otro issuer")
+	ErrInvalidAudience  = errors.New("oidc: el id_token es para otro cliente")
+	ErrTokenExpired     = errors.New("oidc: el id_token está vencido")
+	ErrTokenNotYetValid = errors.New("oidc: el id_token todavía no es válido")
+	ErrNonceMismatch    = errors.New("oidc: el nonce del id_token no coincide")
+	ErrMissingSubject   = errors.New("oidc: el id_token no trae sujeto (sub)")
+)
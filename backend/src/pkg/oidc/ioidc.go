@@ -0,0 +1,16 @@
+package oidc
+
+import "context"
+
+// Client es el lado "relying party" de OpenID Connect con el flujo authorization
+// code + PKCE. El llamador guarda state, nonce y verifier entre la redirección y
+// el retorno.
+type Client interface {
+	// Issuer identifica al proveedor (se usa para vincular las cuentas).
+	Issuer() string
+	// AuthCodeURL arma la URL del proveedor a la que se redirige al usuario.
+	AuthCodeURL(state, nonce, codeChallenge string) string
+	// Exchange canjea el código por tokens y devuelve los claims del id_token ya
+	// validados (firma, issuer, audiencia, vencimiento y nonce).
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error)
+}
@@ -0,0 +1,148 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// verifyIDToken valida un id_token según OpenID Connect Core §3.1.3.7.
+func (c *client) verifyIDToken(ctx context.Context, raw, nonce string) (*Claims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, ErrMalformedToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	// Solo algoritmos asimétricos: "none" y HS256 permitirían falsificar el token
+	if header.Alg != "RS256" && header.Alg != "ES256" {
+		return nil, ErrUnsupportedAlg
+	}
+	key, err := c.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	claims, rawClaims, err := parseClaims(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Issuer != c.cfg.Issuer {
+		return nil, ErrInvalidIssuer
+	}
+	audiences := audienceClaim(rawClaims["aud"])
+	if !slices.Contains(audiences, c.cfg.ClientID) {
+		return nil, ErrInvalidAudience
+	}
+	if azp := stringClaim(rawClaims, "azp"); len(audiences) > 1 && azp != c.cfg.ClientID {
+		return nil, ErrInvalidAudience
+	}
+
+	now := c.now()
+	exp, ok := numericDate(rawClaims["exp"])
+	if !ok || !now.Before(exp.Add(c.cfg.ClockSkew)) {
+		return nil, ErrTokenExpired
+	}
+	if nbf, ok := numericDate(rawClaims["nbf"]); ok && now.Add(c.cfg.ClockSkew).Before(nbf) {
+		return nil, ErrTokenNotYetValid
+	}
+	if iat, ok := numericDate(rawClaims["iat"]); ok && now.Add(c.cfg.ClockSkew).Before(iat) {
+		return nil, ErrTokenNotYetValid
+	}
+
+	if nonce != "" && stringClaim(rawClaims, "nonce") != nonce {
+		return nil, ErrNonceMismatch
+	}
+	if claims.Subject == "" {
+		return nil, ErrMissingSubject
+	}
+
+	return claims, nil
+}
+
+func verifySignature(alg string, key any, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case "RS256":
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrInvalidSignature
+		}
+		if rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) != nil {
+			return ErrInvalidSignature
+		}
+	case "ES256":
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return ErrInvalidSignature
+		}
+		// JWS usa r||s de 32 bytes cada uno, no DER
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(publicKey, digest[:], r, s) {
+			return ErrInvalidSignature
+		}
+	default:
+		return ErrUnsupportedAlg
+	}
+	return nil
+}
+
+// audienceClaim acepta "aud" como texto o como lista.
+func audienceClaim(v any) []string {
+	switch aud := v.(type) {
+	case string:
+		return []string{aud}
+	case []any:
+		values := make([]string, 0, len(aud))
+		for _, item := range aud {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// numericDate convierte un NumericDate de JWT (segundos Unix) a time.Time.
+func numericDate(v any) (time.Time, bool) {
+	seconds, ok := v.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
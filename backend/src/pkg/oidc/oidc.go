@@ -0,0 +1,167 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxResponseBytes limita lo que se lee de cada respuesta del proveedor.
+const maxResponseBytes = 1 << 20
+
+// Config agrupa los datos del cliente registrado en el proveedor.
+type Config struct {
+	Issuer       string // URL del proveedor; de ahí se lee /.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string   // Vacío para clientes públicos (solo PKCE)
+	RedirectURL  string   // Debe coincidir con la registrada en el proveedor
+	Scopes       []string // Vacío = openid email profile
+	ClockSkew    time.Duration
+	HTTPClient   *http.Client // nil = cliente con timeout de 10 s
+}
+
+// discovery es el subconjunto de la configuración del proveedor que se usa.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type client struct {
+	cfg      Config
+	http     *http.Client
+	endpoint discovery
+	now      func() time.Time
+
+	keysMu sync.Mutex
+	keys   map[string]any // kid -> *rsa.PublicKey | *ecdsa.PublicKey
+}
+
+// NewClient lee la configuración del proveedor (discovery) y crea el Client. Las
+// claves de firma se descargan en el primer login y al aparecer un kid nuevo.
+func NewClient(ctx context.Context, cfg Config) (Client, error) {
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, ErrMissingConfig
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if cfg.ClockSkew == 0 {
+		cfg.ClockSkew = time.Minute
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	c := &client{cfg: cfg, http: httpClient, now: time.Now}
+
+	wellKnown := strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := c.getJSON(ctx, wellKnown, &c.endpoint); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	// OIDC Discovery exige que el issuer publicado sea idéntico al consultado
+	if c.endpoint.Issuer != cfg.Issuer {
+		return nil, ErrIssuerMismatch
+	}
+	if c.endpoint.AuthorizationEndpoint == "" || c.endpoint.TokenEndpoint == "" || c.endpoint.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: faltan endpoints", ErrDiscovery)
+	}
+
+	return c, nil
+}
+
+// Issuer implements Client.
+func (c *client) Issuer() string {
+	return c.cfg.Issuer
+}
+
+// AuthCodeURL implements Client.
+func (c *client) AuthCodeURL(state, nonce, codeChallenge string) string {
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.cfg.ClientID},
+		"redirect_uri":          {c.cfg.RedirectURL},
+		"scope":                 {strings.Join(c.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(c.endpoint.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return c.endpoint.AuthorizationEndpoint + separator + params.Encode()
+}
+
+// Exchange implements Client.
+func (c *client) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+		"client_id":     {c.cfg.ClientID},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.cfg.ClientID), url.QueryEscape(c.cfg.ClientSecret))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: status %d", ErrExchange, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrExchange, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+
+	return c.verifyIDToken(ctx, body.IDToken, nonce)
+}
+
+func (c *client) getJSON(ctx context.Context, endpoint string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(dst)
+}
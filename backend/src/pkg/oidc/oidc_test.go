@@ -0,0 +1,199 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/oidc"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/oidc/oidctest"
+)
+
+const (
+	testClientID     = "aiep-agent"
+	testClientSecret = "s3cr3t"
+	testRedirectURL  = "http://localhost:8080/auth/sso/callback"
+)
+
+func newProvider(t *testing.T) (*oidctest.Server, oidc.Client) {
+	t.Helper()
+	srv, err := oidctest.NewServer(testClientID, testClientSecret)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	client, err := oidc.NewClient(context.Background(), oidc.Config{
+		Issuer:       srv.Issuer(),
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return srv, client
+}
+
+// authorize recorre la redirección al proveedor y devuelve el código y el verificador PKCE.
+func authorize(t *testing.T, srv *oidctest.Server, client oidc.Client, nonce string) (code, verifier string) {
+	t.Helper()
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		t.Fatalf("NewCodeVerifier: %v", err)
+	}
+	code, state, err := srv.Authorize(client.AuthCodeURL("state-1", nonce, oidc.CodeChallenge(verifier)))
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	if state != "state-1" {
+		t.Fatalf("state = %q, want state-1", state)
+	}
+	return code, verifier
+}
+
+func TestExchange(t *testing.T) {
+	srv, client := newProvider(t)
+	srv.SetClaims(map[string]any{
+		"sub":            "abc-123",
+		"email":          "Ana@Example.com",
+		"email_verified": true,
+		"groups":         []any{"docentes", "staff"},
+		"amr":            []any{"pwd", "otp"},
+	})
+
+	code, verifier := authorize(t, srv, client, "nonce-1")
+	claims, err := client.Exchange(context.Background(), code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	if claims.Issuer != srv.Issuer() || claims.Subject != "abc-123" {
+		t.Errorf("iss/sub = %q/%q", claims.Issuer, claims.Subject)
+	}
+	if claims.Email != "ana@example.com" || !claims.EmailVerified {
+		t.Errorf("email = %q verified=%v, want ana@example.com verified", claims.Email, claims.EmailVerified)
+	}
+	if got := claims.Values("groups"); len(got) != 2 || got[0] != "docentes" {
+		t.Errorf("groups = %v", got)
+	}
+	if got := claims.Values("amr"); len(got) != 2 || got[1] != "otp" {
+		t.Errorf("amr = %v", got)
+	}
+}
+
+func TestAuthCodeURLUsesPKCE(t *testing.T) {
+	_, client := newProvider(t)
+
+	authURL, err := url.Parse(client.AuthCodeURL("st", "nn", oidc.CodeChallenge("verifier")))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	q := authURL.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") != oidc.CodeChallenge("verifier") {
+		t.Errorf("challenge = %q (%s)", q.Get("code_challenge"), q.Get("code_challenge_method"))
+	}
+	if q.Get("client_id") != testClientID || q.Get("redirect_uri") != testRedirectURL || q.Get("nonce") != "nn" {
+		t.Errorf("unexpected query %v", q)
+	}
+}
+
+func TestNewClientRejectsIssuerMismatch(t *testing.T) {
+	srv, err := oidctest.NewServer(testClientID, testClientSecret)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	defer srv.Close()
+
+	// El proveedor publica su issuer sin la barra final
+	_, err = oidc.NewClient(context.Background(), oidc.Config{
+		Issuer:      srv.Issuer() + "/",
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+	})
+	if !errors.Is(err, oidc.ErrIssuerMismatch) {
+		t.Fatalf("err = %v, want ErrIssuerMismatch", err)
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	srv, client := newProvider(t)
+
+	code, _ := authorize(t, srv, client, "nonce-1")
+	other, err := oidc.NewCodeVerifier()
+	if err != nil {
+		t.Fatalf("NewCodeVerifier: %v", err)
+	}
+	if _, err := client.Exchange(context.Background(), code, other, "nonce-1"); !errors.Is(err, oidc.ErrExchange) {
+		t.Fatalf("err = %v, want ErrExchange", err)
+	}
+}
+
+func TestExchangeRejectsReusedCode(t *testing.T) {
+	srv, client := newProvider(t)
+
+	code, verifier := authorize(t, srv, client, "nonce-1")
+	if _, err := client.Exchange(context.Background(), code, verifier, "nonce-1"); err != nil {
+		t.Fatalf("first Exchange: %v", err)
+	}
+	if _, err := client.Exchange(context.Background(), code, verifier, "nonce-1"); !errors.Is(err, oidc.ErrExchange) {
+		t.Fatalf("err = %v, want ErrExchange", err)
+	}
+}
+
+func TestExchangeRejectsInvalidTokens(t *testing.T) {
+	past := time.Now().Add(-time.Hour).Unix()
+	future := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name      string
+		overrides map[string]any
+		nonce     string // Nonce que espera el cliente; vacío = el enviado al proveedor
+		want      error
+	}{
+		{"other audience", map[string]any{"aud": "other-client"}, "", oidc.ErrInvalidAudience},
+		{"azp of other client", map[string]any{"aud": []any{testClientID, "other"}, "azp": "other"}, "", oidc.ErrInvalidAudience},
+		{"other issuer", map[string]any{"iss": "https://evil.example.com"}, "", oidc.ErrInvalidIssuer},
+		{"expired", map[string]any{"exp": past}, "", oidc.ErrTokenExpired},
+		{"not yet valid", map[string]any{"nbf": future}, "", oidc.ErrTokenNotYetValid},
+		{"issued in the future", map[string]any{"iat": future}, "", oidc.ErrTokenNotYetValid},
+		{"nonce mismatch", nil, "other-nonce", oidc.ErrNonceMismatch},
+		{"missing subject", map[string]any{"sub": ""}, "", oidc.ErrMissingSubject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newProvider(t)
+			srv.OverrideTokenClaims(tt.overrides)
+
+			code, verifier := authorize(t, srv, client, "nonce-1")
+			nonce := tt.nonce
+			if nonce == "" {
+				nonce = "nonce-1"
+			}
+			if _, err := client.Exchange(context.Background(), code, verifier, nonce); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExchangeFollowsKeyRotation(t *testing.T) {
+	srv, client := newProvider(t)
+
+	// El primer login deja la clave en caché
+	code, verifier := authorize(t, srv, client, "nonce-1")
+	if _, err := client.Exchange(context.Background(), code, verifier, "nonce-1"); err != nil {
+		t.Fatalf("Exchange before rotation: %v", err)
+	}
+
+	// Con un kid desconocido el cliente vuelve a descargar el JWKS
+	if err := srv.RotateKey(); err != nil {
+		t.Fatalf("RotateKey: %v", err)
+	}
+	code, verifier = authorize(t, srv, client, "nonce-2")
+	if _, err := client.Exchange(context.Background(), code, verifier, "nonce-2"); err != nil {
+		t.Fatalf("Exchange after rotation: %v", err)
+	}
+}
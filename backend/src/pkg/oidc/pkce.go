@@ -0,0 +1,21 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+)
+
+// NewCodeVerifier genera un code_verifier de PKCE (RFC 7636): 32 bytes
+// aleatorios en base64url, 43 caracteres.
+func NewCodeVerifier() (string, error) {
+	verifier, _, err := securetoken.Generate(32)
+	return verifier, err
+}
+
+// CodeChallenge calcula el code_challenge S256 de verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
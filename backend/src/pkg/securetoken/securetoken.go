@@ -0,0 +1,89 @@
+package securetoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// DefaultBytes es la entropía por defecto de un token (256 bits).
+const DefaultBytes = 32
+
+// Generate crea un token aleatorio apto para URLs y devuelve también su hash,
+// que es lo único que debe guardarse en la base de datos.
+func Generate(nBytes int) (token, hash string, err error) {
+	if nBytes <= 0 {
+		nBytes = DefaultBytes
+	}
+	buf := make([]byte, nBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("securetoken: no se pudo generar el token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, Hash(token), nil
+}
+
+// Hash devuelve el SHA-256 en hexadecimal del token. Al ser tokens aleatorios de
+// alta entropía no hace falta sal ni un hash lento.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Equal compara dos hashes en tiempo constante.
+func Equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// Alfabetos sin caracteres que se confunden al leerlos (0/O, 1/l/I).
+const (
+	passwordAlphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeAlphabet     = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// Password genera una contraseña aleatoria legible de length caracteres.
+func Password(length int) (string, error) {
+	if length <= 0 {
+		length = 12
+	}
+	s, err := fromAlphabet(passwordAlphabet, length)
+	if err != nil {
+		return "", fmt.Errorf("securetoken: no se pudo generar la contraseña: %w", err)
+	}
+	return s, nil
+}
+
+// Code genera un código aleatorio en mayúsculas, fácil de dictar o copiar a mano
+// (p. ej. códigos de invitación).
+func Code(length int) (string, error) {
+	if length <= 0 {
+		length = 8
+	}
+	s, err := fromAlphabet(codeAlphabet, length)
+	if err != nil {
+		return "", fmt.Errorf("securetoken: no se pudo generar el código: %w", err)
+	}
+	return s, nil
+}
+
+// fromAlphabet elige length caracteres del alfabeto de manera uniforme.
+func fromAlphabet(alphabet string, length int) (string, error) {
+	// 256 no es múltiplo del alfabeto; se descartan los bytes que introducen sesgo
+	limit := 256 - 256%len(alphabet)
+	buf := make([]byte, length)
+	out := make([]byte, 0, length)
+	for len(out) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(out) < length {
+				out = append(out, alphabet[int(b)%len(alphabet)])
+			}
+		}
+	}
+	return string(out), nil
+}
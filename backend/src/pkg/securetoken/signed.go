@@ -0,0 +1,80 @@
+package securetoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMissingSecret    = errors.New("securetoken: falta la clave de firma")
+	ErrMalformedToken   = errors.New("securetoken: token mal formado")
+	ErrInvalidSignature = errors.New("securetoken: firma inválida")
+	ErrTokenExpired     = errors.New("securetoken: token vencido")
+)
+
+// Claims es el contenido de un token firmado. Binding liga el token a un valor que
+// debe seguir vigente al verificarlo (p. ej. el email que se está verificando).
+type Claims struct {
+	Purpose   string `json:"p"`
+	Subject   uint   `json:"s"`
+	Binding   string `json:"b,omitempty"`
+	ExpiresAt int64  `json:"e"` // Unix, segundos
+}
+
+// Sign firma los claims con HMAC-SHA256. El token es "<payload>.<firma>" en
+// base64url, apto para URLs; no necesita guardarse en la base de datos.
+func Sign(secret []byte, claims Claims) (string, error) {
+	if len(secret) == 0 {
+		return "", ErrMissingSecret
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("securetoken: no se pudo codificar el token: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(mac(secret, encoded)), nil
+}
+
+// Verify comprueba la firma y el vencimiento, y devuelve los claims. El llamador
+// debe validar Purpose y Binding.
+func Verify(secret []byte, token string, now time.Time) (Claims, error) {
+	if len(secret) == 0 {
+		return Claims{}, ErrMissingSecret
+	}
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return Claims{}, ErrMalformedToken
+	}
+	gotMAC, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+	if !hmac.Equal(gotMAC, mac(secret, encoded)) {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrTokenExpired
+	}
+	return claims, nil
+}
+
+func mac(secret []byte, data string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
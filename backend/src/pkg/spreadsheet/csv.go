@@ -0,0 +1,45 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// utf8BOM es la marca que Excel agrega al exportar CSV en UTF-8.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ReadCSV lee un CSV separado por comas o por punto y coma (el formato que usa
+// Excel en configuración regional en español). El separador se deduce de la cabecera.
+func ReadCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("spreadsheet: error leyendo CSV: %w", err)
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("spreadsheet: CSV inválido: %w", err)
+	}
+	return rows, nil
+}
+
+// detectDelimiter elige entre ',' y ';' según cuál aparece más en la primera línea.
+func detectDelimiter(data []byte) rune {
+	line, _ := bufio.NewReader(bytes.NewReader(data)).ReadBytes('\n')
+	if bytes.Count(line, []byte{';'}) > bytes.Count(line, []byte{','}) {
+		return ';'
+	}
+	return ','
+}
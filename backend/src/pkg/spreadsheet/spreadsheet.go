@@ -0,0 +1,62 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Formatos soportados
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("spreadsheet: formato no soportado (use .csv o .xlsx)")
+	ErrEmptyFile         = errors.New("spreadsheet: el archivo está vacío")
+)
+
+// FormatFromName deduce el formato a partir de la extensión del archivo.
+func FormatFromName(name string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".txt":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+}
+
+// Read devuelve las filas de la primera hoja de un archivo CSV o XLSX.
+// Cada fila es un slice de celdas ya convertidas a texto; las filas vacías se conservan
+// para que los números de fila coincidan con los del archivo original.
+func Read(name string, data []byte) ([][]string, error) {
+	if len(data) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	format, err := FormatFromName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatXLSX:
+		return ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	default:
+		return ReadCSV(bytes.NewReader(data))
+	}
+}
+
+// IsBlank indica si todas las celdas de la fila están vacías.
+func IsBlank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
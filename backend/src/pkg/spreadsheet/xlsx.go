@@ -0,0 +1,293 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// ErrInvalidXLSX se devuelve cuando el archivo no es un libro de Excel válido.
+var ErrInvalidXLSX = errors.New("spreadsheet: archivo XLSX inválido")
+
+type xlsxWorkbook struct {
+	Properties struct {
+		Date1904 bool `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText es un texto que puede venir plano (<t>) o en fragmentos con formato (<r><t>).
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string   `xml:"r,attr"`
+			T      string   `xml:"t,attr"`
+			S      int      `xml:"s,attr"`
+			V      string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX devuelve las filas de la primera hoja del libro. Las celdas con formato
+// de fecha se devuelven como YYYY-MM-DD.
+func ReadXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeXML(files, "xl/workbook.xml", &workbook, true); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, fmt.Errorf("%w: el libro no tiene hojas", ErrInvalidXLSX)
+	}
+
+	var rels xlsxRelationships
+	if err := decodeXML(files, "xl/_rels/workbook.xml.rels", &rels, true); err != nil {
+		return nil, err
+	}
+	sheetPath := ""
+	for _, rel := range rels.Items {
+		if rel.ID == workbook.Sheets[0].RID {
+			sheetPath = resolveTarget(rel.Target)
+			break
+		}
+	}
+	if sheetPath == "" {
+		return nil, fmt.Errorf("%w: no se encontró la primera hoja", ErrInvalidXLSX)
+	}
+
+	var shared xlsxSharedStrings
+	if err := decodeXML(files, "xl/sharedStrings.xml", &shared, false); err != nil {
+		return nil, err
+	}
+	var styles xlsxStyles
+	if err := decodeXML(files, "xl/styles.xml", &styles, false); err != nil {
+		return nil, err
+	}
+	dateStyles := dateStyleIndexes(styles)
+
+	var sheet xlsxSheet
+	if err := decodeXML(files, sheetPath, &sheet, true); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		rowIdx := row.R - 1
+		if rowIdx < len(rows) {
+			rowIdx = len(rows)
+		}
+		for len(rows) < rowIdx {
+			rows = append(rows, nil) // filas vacías omitidas por Excel
+		}
+
+		var cells []string
+		for _, cell := range row.Cells {
+			col := len(cells)
+			if cell.R != "" {
+				if idx, ok := columnIndex(cell.R); ok {
+					col = idx
+				}
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+
+			switch cell.T {
+			case "s":
+				idx, err := strconv.Atoi(cell.V)
+				if err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, fmt.Errorf("%w: referencia a texto compartido inválida en %s", ErrInvalidXLSX, cell.R)
+				}
+				cells[col] = shared.Items[idx].String()
+			case "inlineStr":
+				cells[col] = cell.Inline.String()
+			case "", "n":
+				cells[col] = cell.V
+				if dateStyles[cell.S] && cell.V != "" {
+					if serial, err := strconv.ParseFloat(cell.V, 64); err == nil {
+						cells[col] = formatSerial(serial, workbook.Properties.Date1904)
+					}
+				}
+			default: // str, b, e
+				cells[col] = cell.V
+			}
+		}
+		rows = append(rows, cells)
+	}
+
+	return rows, nil
+}
+
+// SerialToTime convierte un número de serie de Excel a fecha (UTC).
+func SerialToTime(serial float64, date1904 bool) time.Time {
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 86400)
+	return base.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+}
+
+// formatSerial formatea un serial como fecha, o fecha y hora si tiene fracción.
+func formatSerial(serial float64, date1904 bool) string {
+	t := SerialToTime(serial, date1904)
+	if serial == math.Floor(serial) {
+		return date.FormatDate(t)
+	}
+	return date.FormatDateTime(t)
+}
+
+// dateStyleIndexes devuelve los índices de cellXfs cuyo formato numérico es de fecha.
+func dateStyleIndexes(styles xlsxStyles) map[int]bool {
+	custom := make(map[int]string, len(styles.NumFmts))
+	for _, f := range styles.NumFmts {
+		custom[f.ID] = f.Code
+	}
+
+	result := make(map[int]bool)
+	for i, xf := range styles.CellXfs {
+		if code, ok := custom[xf.NumFmtID]; ok {
+			result[i] = isDateFormat(code)
+			continue
+		}
+		result[i] = isBuiltinDateFormat(xf.NumFmtID)
+	}
+	return result
+}
+
+// isBuiltinDateFormat reconoce los formatos de fecha predefinidos de Excel.
+func isBuiltinDateFormat(id int) bool {
+	return (id >= 14 && id <= 22) || (id >= 27 && id <= 36) || (id >= 45 && id <= 47) || (id >= 50 && id <= 58)
+}
+
+// isDateFormat reconoce un formato personalizado de fecha ignorando textos literales,
+// colores y condiciones.
+func isDateFormat(code string) bool {
+	var b strings.Builder
+	inQuote, inBracket := false, false
+	for _, r := range code {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '[':
+			inBracket = true
+		case r == ']':
+			inBracket = false
+		case inBracket:
+		default:
+			b.WriteRune(r)
+		}
+	}
+	cleaned := strings.ToLower(b.String())
+	return strings.ContainsAny(cleaned, "dy")
+}
+
+// columnIndex convierte una referencia como "C7" en el índice de columna 2.
+func columnIndex(ref string) (int, bool) {
+	idx := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		idx = idx*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return idx - 1, true
+}
+
+// resolveTarget convierte el destino de una relación en una ruta dentro del zip.
+func resolveTarget(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("xl", target)
+}
+
+// decodeXML decodifica una parte del libro. Si required es false, su ausencia no es un error.
+func decodeXML(files map[string]*zip.File, name string, v any, required bool) error {
+	f, ok := files[name]
+	if !ok {
+		if required {
+			return fmt.Errorf("%w: falta %s", ErrInvalidXLSX, name)
+		}
+		return nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidXLSX, name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidXLSX, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package tokenizer
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Nombres de las codificaciones BPE soportadas.
+const (
+	EncodingCL100K = "cl100k_base" // gpt-4, gpt-3.5-turbo, text-embedding-3-*
+	EncodingO200K  = "o200k_base"  // gpt-4o, gpt-4.1, o1, o3...
+)
+
+// Patrones de pre-tokenización de tiktoken. RE2 no soporta lookahead, por lo que
+// se omite la alternativa `\s+(?!\S)`; la diferencia solo afecta a espacios
+// consecutivos y cambia el conteo en ±1 token en casos raros.
+var patterns = map[string]string{
+	EncodingCL100K: `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`,
+	EncodingO200K: `[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+`,
+}
+
+var (
+	ErrUnknownEncoding = errors.New("tokenizer: codificación desconocida")
+	ErrEmptyRanks      = errors.New("tokenizer: el archivo de rangos BPE está vacío")
+)
+
+type bpeTokenizer struct {
+	name    string
+	ranks   map[string]int
+	pattern *regexp.Regexp
+}
+
+// NewBPE crea un Tokenizer BPE a partir de un archivo de rangos en formato
+// tiktoken (una línea por token: "<token en base64> <rango>").
+func NewBPE(encoding string, ranks io.Reader) (Tokenizer, error) {
+	pattern, ok := patterns[encoding]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEncoding, encoding)
+	}
+
+	parsed, err := LoadRanks(ranks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bpeTokenizer{
+		name:    encoding,
+		ranks:   parsed,
+		pattern: regexp.MustCompile(pattern),
+	}, nil
+}
+
+// LoadRanks lee un archivo de rangos en formato tiktoken.
+func LoadRanks(r io.Reader) (map[string]int, error) {
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("tokenizer: línea %d inválida", line)
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("tokenizer: línea %d: token base64 inválido: %w", line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("tokenizer: línea %d: rango inválido: %w", line, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("tokenizer: error leyendo rangos: %w", err)
+	}
+	if len(ranks) == 0 {
+		return nil, ErrEmptyRanks
+	}
+
+	return ranks, nil
+}
+
+// Name implements Tokenizer.
+func (b *bpeTokenizer) Name() string {
+	return b.name
+}
+
+// Count implements Tokenizer.
+func (b *bpeTokenizer) Count(text string) int {
+	total := 0
+	for _, piece := range b.pattern.FindAllString(text, -1) {
+		total += len(b.mergePiece([]byte(piece))) - 1
+	}
+	return total
+}
+
+// Truncate implements Tokenizer.
+func (b *bpeTokenizer) Truncate(text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+
+	used := 0
+	for _, loc := range b.pattern.FindAllStringIndex(text, -1) {
+		bounds := b.mergePiece([]byte(text[loc[0]:loc[1]]))
+		tokens := len(bounds) - 1
+		if used+tokens <= maxTokens {
+			used += tokens
+			continue
+		}
+		// Cortar dentro de la pieza en el último límite de token que cabe
+		cut := loc[0] + bounds[maxTokens-used]
+		return trimInvalidUTF8(text[:cut])
+	}
+
+	return text
+}
+
+// mergePiece aplica el algoritmo byte-pair merge de tiktoken y devuelve los
+// límites (offsets en bytes) de los tokens resultantes dentro de piece.
+func (b *bpeTokenizer) mergePiece(piece []byte) []int {
+	if _, ok := b.ranks[string(piece)]; ok {
+		return []int{0, len(piece)}
+	}
+
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+
+	for len(bounds) > 2 {
+		minRank := math.MaxInt
+		minIdx := -1
+		for i := 0; i < len(bounds)-2; i++ {
+			if rank, ok := b.ranks[string(piece[bounds[i]:bounds[i+2]])]; ok && rank < minRank {
+				minRank = rank
+				minIdx = i
+			}
+		}
+		if minIdx < 0 {
+			break
+		}
+		bounds = append(bounds[:minIdx+1], bounds[minIdx+2:]...)
+	}
+
+	return bounds
+}
+
+// trimInvalidUTF8 elimina bytes finales de un rune cortado a la mitad.
+func trimInvalidUTF8(s string) string {
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
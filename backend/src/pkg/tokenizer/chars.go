@@ -0,0 +1,52 @@
+package tokenizer
+
+import (
+	"math"
+	"unicode/utf8"
+)
+
+// DefaultCharsPerToken es la proporción promedio de caracteres por token en
+// texto en español/inglés para los modelos de OpenAI.
+const DefaultCharsPerToken = 4.0
+
+type charTokenizer struct {
+	charsPerToken float64
+}
+
+// NewCharTokenizer crea un Tokenizer aproximado basado en cantidad de caracteres.
+// Se usa como fallback cuando no hay una codificación BPE cargada para el modelo.
+func NewCharTokenizer(charsPerToken float64) Tokenizer {
+	if charsPerToken <= 0 {
+		charsPerToken = DefaultCharsPerToken
+	}
+	return &charTokenizer{charsPerToken: charsPerToken}
+}
+
+// Name implements Tokenizer.
+func (c *charTokenizer) Name() string {
+	return "chars"
+}
+
+// Count implements Tokenizer.
+func (c *charTokenizer) Count(text string) int {
+	if text == "" {
+		return 0
+	}
+	return int(math.Ceil(float64(utf8.RuneCountInString(text)) / c.charsPerToken))
+}
+
+// Truncate implements Tokenizer.
+func (c *charTokenizer) Truncate(text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+	if c.Count(text) <= maxTokens {
+		return text
+	}
+	maxChars := int(float64(maxTokens) * c.charsPerToken)
+	runes := []rune(text)
+	if maxChars > len(runes) {
+		maxChars = len(runes)
+	}
+	return string(runes[:maxChars])
+}
@@ -0,0 +1,11 @@
+package tokenizer
+
+// Tokenizer cuenta y recorta texto en tokens de un modelo concreto.
+type Tokenizer interface {
+	// Name devuelve el nombre de la codificación (ej: "cl100k_base", "chars").
+	Name() string
+	// Count devuelve el número de tokens de text.
+	Count(text string) int
+	// Truncate recorta text para que no supere maxTokens, cortando en un límite de token.
+	Truncate(text string, maxTokens int) string
+}
@@ -0,0 +1,91 @@
+package tokenizer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// modelFamilies asocia prefijos de nombre de modelo con su codificación.
+// El orden importa: los prefijos más específicos van primero.
+var modelFamilies = []struct {
+	prefix   string
+	encoding string
+}{
+	{"gpt-4o", EncodingO200K},
+	{"gpt-4.1", EncodingO200K},
+	{"gpt-4.5", EncodingO200K},
+	{"gpt-5", EncodingO200K},
+	{"o1", EncodingO200K},
+	{"o3", EncodingO200K},
+	{"o4", EncodingO200K},
+	{"gpt-4", EncodingCL100K},
+	{"gpt-3.5", EncodingCL100K},
+	{"text-embedding-3", EncodingCL100K},
+	{"text-embedding-ada-002", EncodingCL100K},
+}
+
+// EncodingForModel devuelve la codificación BPE de un modelo o "" si no se conoce.
+func EncodingForModel(model string) string {
+	model = strings.ToLower(strings.TrimSpace(model))
+	for _, family := range modelFamilies {
+		if strings.HasPrefix(model, family.prefix) {
+			return family.encoding
+		}
+	}
+	return ""
+}
+
+// Registry mantiene las codificaciones cargadas y resuelve el Tokenizer de cada modelo.
+type Registry struct {
+	mu        sync.RWMutex
+	encodings map[string]Tokenizer
+	fallback  Tokenizer
+}
+
+// NewRegistry crea un registro vacío. Si fallback es nil se usa NewCharTokenizer.
+func NewRegistry(fallback Tokenizer) *Registry {
+	if fallback == nil {
+		fallback = NewCharTokenizer(DefaultCharsPerToken)
+	}
+	return &Registry{
+		encodings: make(map[string]Tokenizer),
+		fallback:  fallback,
+	}
+}
+
+// Register asocia un Tokenizer a una codificación.
+func (r *Registry) Register(encoding string, t Tokenizer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.encodings[encoding] = t
+}
+
+// LoadFile carga una codificación BPE desde un archivo de rangos tiktoken.
+func (r *Registry) LoadFile(encoding, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("tokenizer: no se pudo abrir %s: %w", path, err)
+	}
+	defer f.Close()
+
+	t, err := NewBPE(encoding, f)
+	if err != nil {
+		return err
+	}
+	r.Register(encoding, t)
+	return nil
+}
+
+// ForModel devuelve el Tokenizer del modelo, o el fallback si su codificación no está cargada.
+func (r *Registry) ForModel(model string) Tokenizer {
+	encoding := EncodingForModel(model)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if t, ok := r.encodings[encoding]; ok {
+		return t
+	}
+	return r.fallback
+}
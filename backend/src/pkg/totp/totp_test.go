@@ -0,0 +1,134 @@
+package totp_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/totp"
+)
+
+// rfcSecret es la clave ASCII "12345678901234567890" de RFC 6238 en base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Vectores SHA1 del apéndice B de RFC 6238, recortados a 6 dígitos.
+func TestCodeAtRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got, err := totp.CodeAt(rfcSecret, totp.Counter(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatalf("CodeAt: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("CodeAt(%d) = %q, want %q", tt.unix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeAtAcceptsLooseSecret(t *testing.T) {
+	want, _ := totp.CodeAt(rfcSecret, 1)
+	loose := strings.ToLower(rfcSecret[:16]) + " " + rfcSecret[16:] + "===="
+	got, err := totp.CodeAt(loose, 1)
+	if err != nil || got != want {
+		t.Fatalf("CodeAt(%q) = %q, %v; want %q", loose, got, err, want)
+	}
+}
+
+func TestCodeAtRejectsInvalidSecret(t *testing.T) {
+	for _, secret := range []string{"", "not base32!", "===="} {
+		if _, err := totp.CodeAt(secret, 1); !errors.Is(err, totp.ErrInvalidSecret) {
+			t.Errorf("CodeAt(%q): err = %v, want ErrInvalidSecret", secret, err)
+		}
+	}
+}
+
+func TestValidateWindow(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := totp.Counter(now)
+
+	tests := []struct {
+		name   string
+		offset int64
+		skew   int
+		ok     bool
+	}{
+		{"current step", 0, 0, true},
+		{"previous step without skew", -1, 0, false},
+		{"previous step", -1, 1, true},
+		{"next step", 1, 1, true},
+		{"two steps back", -2, 1, false},
+		{"two steps ahead", 2, 1, false},
+		{"two steps with skew 2", -2, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := totp.CodeAt(rfcSecret, current+tt.offset)
+			if err != nil {
+				t.Fatalf("CodeAt: %v", err)
+			}
+			counter, ok := totp.Validate(rfcSecret, code, now, tt.skew)
+			if ok != tt.ok {
+				t.Fatalf("Validate ok = %v, want %v", ok, tt.ok)
+			}
+			// El paso devuelto es el del código, no el actual: así se detecta la reutilización
+			if ok && counter != current+tt.offset {
+				t.Fatalf("counter = %d, want %d", counter, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsMalformedCode(t *testing.T) {
+	now := time.Unix(59, 0)
+	tests := []struct {
+		code string
+		ok   bool
+	}{
+		{"287 082", true},
+		{" 287082 ", true},
+		{"28708", false},
+		{"2870820", false},
+		{"", false},
+		{"abcdef", false},
+	}
+	for _, tt := range tests {
+		if _, ok := totp.Validate(rfcSecret, tt.code, now, 0); ok != tt.ok {
+			t.Errorf("Validate(%q) ok = %v, want %v", tt.code, ok, tt.ok)
+		}
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	b, _ := totp.GenerateSecret()
+	if len(a) != 32 || a == b {
+		t.Fatalf("secrets %q, %q: want two distinct 32-char secrets", a, b)
+	}
+	if _, err := totp.CodeAt(a, 1); err != nil {
+		t.Fatalf("CodeAt with generated secret: %v", err)
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	got := totp.ProvisioningURI("AIEP Agent", "ana@example.com", rfcSecret)
+	want := "otpauth://totp/AIEP%20Agent:ana@example.com?algorithm=SHA1&digits=6&issuer=AIEP+Agent&period=30&secret=" + rfcSecret
+	if got != want {
+		t.Fatalf("ProvisioningURI =\n%s\nwant\n%s", got, want)
+	}
+}
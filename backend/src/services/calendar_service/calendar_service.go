@@ -0,0 +1,467 @@
+package calendarservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	calendardto "github.com/Dieg0Code/aiep-agent/src/data/dtos/calendar_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	calendarrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/calendar_repo"
+	embeddingjobrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/embedding_job_repo"
+	enrollementrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/enrollment_repo"
+	modulerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/module_repo"
+	topicrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/topic_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/ical"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+	"gorm.io/datatypes"
+)
+
+// maxImportKey es el largo de Topic.ImportKey.
+const maxImportKey = 150
+
+type calendarService struct {
+	txm            database.TxManager
+	enrollmentRepo enrollementrepo.EnrollmentRepo
+	moduleRepo     modulerepo.ModuleRepo
+	topicRepo      topicrepo.TopicRepo
+	jobRepo        embeddingjobrepo.EmbeddingJobRepo
+	calendarRepo   calendarrepo.CalendarRepo
+	location       *time.Location
+	cfg            Config
+	logger         *slog.Logger
+}
+
+// NewCalendarService crea una instancia de ICalendarService. txm agrupa cada
+// ImportCalendar en una sola transacción, que se deshace en la simulación.
+func NewCalendarService(
+	txm database.TxManager,
+	enrollmentRepo enrollementrepo.EnrollmentRepo,
+	moduleRepo modulerepo.ModuleRepo,
+	topicRepo topicrepo.TopicRepo,
+	jobRepo embeddingjobrepo.EmbeddingJobRepo,
+	calendarRepo calendarrepo.CalendarRepo,
+	cfg Config,
+	logger *slog.Logger,
+) ICalendarService {
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		logger.Warn("Unknown calendar time zone, using UTC", "error", err, "time_zone", cfg.TimeZone)
+		location = time.UTC
+	}
+
+	return &calendarService{
+		txm:            txm,
+		enrollmentRepo: enrollmentRepo,
+		moduleRepo:     moduleRepo,
+		topicRepo:      topicRepo,
+		jobRepo:        jobRepo,
+		calendarRepo:   calendarRepo,
+		location:       location,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// ExportUserCalendar implements ICalendarService.
+func (c *calendarService) ExportUserCalendar(ctx context.Context, userID uint) ([]byte, error) {
+	if userID == 0 {
+		return nil, calendarrepo.ErrInvalidUserID
+	}
+
+	enrollments, err := c.enrollmentRepo.EnrollmentsByUser(ctx, userID)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to get user enrollments",
+			"error", err,
+			"user_id", userID,
+		)
+		return nil, fmt.Errorf("failed to get user enrollments: %w", err)
+	}
+
+	var events []ical.Event
+	for _, enrollment := range enrollments {
+		if enrollment.Status != enrollementrepo.StatusActive {
+			continue
+		}
+
+		module, err := c.moduleRepo.ModuleByID(ctx, enrollment.ModuleID)
+		if err != nil {
+			c.logger.ErrorContext(ctx, "Failed to get module",
+				"error", err,
+				"module_id", enrollment.ModuleID,
+			)
+			return nil, fmt.Errorf("failed to get module: %w", err)
+		}
+
+		topics, err := c.topicRepo.TopicsByModule(ctx, module.ID)
+		if err != nil {
+			c.logger.ErrorContext(ctx, "Failed to get module topics",
+				"error", err,
+				"module_id", module.ID,
+			)
+			return nil, fmt.Errorf("failed to get module topics: %w", err)
+		}
+
+		for i := range topics {
+			events = append(events, c.topicEvent(module, &topics[i]))
+		}
+	}
+
+	// Orden estable: por fecha y luego por UID
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].UID < events[j].UID
+	})
+
+	calendar := ical.Calendar{
+		ProdID:          "-//AIEP//aiep-agent//ES",
+		Name:            c.cfg.CalendarName,
+		TimeZone:        c.location.String(),
+		RefreshInterval: c.cfg.RefreshInterval,
+		Events:          events,
+	}
+	return calendar.Bytes(), nil
+}
+
+// FeedByToken implements ICalendarService.
+func (c *calendarService) FeedByToken(ctx context.Context, token string) ([]byte, error) {
+	token = strings.TrimSuffix(strings.TrimSpace(token), ".ics")
+	if token == "" {
+		return nil, calendarrepo.ErrFeedTokenNotFound
+	}
+
+	feed, err := c.calendarRepo.ActiveFeedTokenByHash(ctx, securetoken.Hash(token))
+	if err != nil {
+		if !errors.Is(err, calendarrepo.ErrFeedTokenNotFound) {
+			c.logger.ErrorContext(ctx, "Failed to get calendar feed token", "error", err)
+		}
+		return nil, fmt.Errorf("failed to get calendar feed token: %w", err)
+	}
+
+	if err := c.calendarRepo.TouchFeedToken(ctx, feed.ID); err != nil {
+		c.logger.WarnContext(ctx, "Failed to record calendar feed use",
+			"error", err,
+			"user_id", feed.UserID,
+		)
+	}
+
+	return c.ExportUserCalendar(ctx, feed.UserID)
+}
+
+// GetFeed implements ICalendarService.
+func (c *calendarService) GetFeed(ctx context.Context, userID uint) (calendardto.CalendarFeedDTO, error) {
+	feed, err := c.calendarRepo.ActiveFeedTokenByUser(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, calendarrepo.ErrFeedTokenNotFound) {
+			c.logger.ErrorContext(ctx, "Failed to get calendar feed token",
+				"error", err,
+				"user_id", userID,
+			)
+		}
+		return calendardto.CalendarFeedDTO{}, fmt.Errorf("failed to get calendar feed token: %w", err)
+	}
+
+	// La URL no se puede reconstruir: solo se guarda el hash del token
+	return calendardto.FromFeedTokenModel(feed, ""), nil
+}
+
+// CreateFeed implements ICalendarService.
+func (c *calendarService) CreateFeed(ctx context.Context, userID uint) (calendardto.CalendarFeedDTO, error) {
+	token, hash, err := securetoken.Generate(securetoken.DefaultBytes)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to generate calendar feed token", "error", err)
+		return calendardto.CalendarFeedDTO{}, fmt.Errorf("failed to generate calendar feed token: %w", err)
+	}
+
+	feed, err := c.calendarRepo.RotateFeedToken(ctx, userID, hash)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to create calendar feed token",
+			"error", err,
+			"user_id", userID,
+		)
+		return calendardto.CalendarFeedDTO{}, fmt.Errorf("failed to create calendar feed token: %w", err)
+	}
+
+	c.logger.InfoContext(ctx, "Calendar feed created", "user_id", userID)
+	return calendardto.FromFeedTokenModel(feed, c.feedURL(token)), nil
+}
+
+// RevokeFeed implements ICalendarService.
+func (c *calendarService) RevokeFeed(ctx context.Context, userID uint) error {
+	if err := c.calendarRepo.RevokeFeedTokensByUser(ctx, userID); err != nil {
+		if !errors.Is(err, calendarrepo.ErrFeedTokenNotFound) {
+			c.logger.ErrorContext(ctx, "Failed to revoke calendar feed",
+				"error", err,
+				"user_id", userID,
+			)
+		}
+		return fmt.Errorf("failed to revoke calendar feed: %w", err)
+	}
+
+	c.logger.InfoContext(ctx, "Calendar feed revoked", "user_id", userID)
+	return nil
+}
+
+// ImportCalendar implements ICalendarService.
+func (c *calendarService) ImportCalendar(ctx context.Context, req calendardto.CalendarImportRequestDTO, data []byte) (calendardto.CalendarImportReportDTO, error) {
+	report := calendardto.CalendarImportReportDTO{DryRun: req.DryRun}
+
+	events, err := ical.Parse(bytes.NewReader(data))
+	if err != nil {
+		c.logger.WarnContext(ctx, "Failed to parse calendar file", "error", err)
+		return report, fmt.Errorf("failed to parse calendar file: %w", err)
+	}
+	report.TotalEvents = len(events)
+
+	err = c.txm.Transaction(ctx, func(ctx context.Context) error {
+		if err := c.applyEvents(ctx, req.ModuleID, events, &report); err != nil {
+			return err
+		}
+		if req.DryRun || report.HasErrors() {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		c.logger.ErrorContext(ctx, "Failed to import calendar",
+			"error", err,
+			"module_id", req.ModuleID,
+		)
+		return report, fmt.Errorf("failed to import calendar: %w", err)
+	}
+
+	if req.DryRun {
+		return report, nil
+	}
+	if report.HasErrors() {
+		return report, ErrImportRejected
+	}
+
+	report.Committed = true
+	c.logger.InfoContext(ctx, "Calendar imported",
+		"module_id", req.ModuleID,
+		"events", report.TotalEvents,
+		"topics_created", report.TopicsCreated,
+		"topics_shifted", report.TopicsShifted,
+		"topics_updated", report.TopicsUpdated,
+	)
+	return report, nil
+}
+
+// applyEvents aplica los eventos dentro de la transacción de ctx, acumulando los
+// errores en el reporte.
+func (c *calendarService) applyEvents(ctx context.Context, moduleID uint, events []ical.Event, report *calendardto.CalendarImportReportDTO) error {
+	if moduleID != 0 {
+		if _, err := c.moduleRepo.ModuleByID(ctx, moduleID); err != nil {
+			if errors.Is(err, modulerepo.ErrModuleNotFound) {
+				report.AddError(0, "", "el módulo indicado no existe")
+				return nil
+			}
+			return err
+		}
+	}
+
+	var toEmbed []uint
+	for i, ev := range events {
+		n := i + 1
+		if ev.Start.IsZero() {
+			report.AddError(n, ev.UID, "el evento no tiene fecha de inicio")
+			continue
+		}
+		scheduled := c.eventDate(ev)
+
+		// Eventos de nuestro propio feed: solo se mueve la fecha del tema
+		if topicID, ok := c.parseTopicUID(ev.UID); ok {
+			topic, err := c.topicRepo.TopicByID(ctx, topicID)
+			if err != nil {
+				if errors.Is(err, topicrepo.ErrTopicNotFound) {
+					report.AddError(n, ev.UID, "el tema no existe")
+					continue
+				}
+				return err
+			}
+			if moduleID != 0 && topic.ModuleID != moduleID {
+				report.AddError(n, ev.UID, "el tema no pertenece al módulo indicado")
+				continue
+			}
+			if sameDay(time.Time(topic.ScheduledDate), scheduled) {
+				report.TopicsUnchanged++
+				continue
+			}
+			if err := c.shiftTopic(ctx, topic.ID, scheduled); err != nil {
+				return err
+			}
+			report.TopicsShifted++
+			continue
+		}
+
+		// Eventos externos: crean o actualizan temas del módulo
+		if moduleID == 0 {
+			report.AddError(n, ev.UID, "los eventos externos requieren indicar el módulo")
+			continue
+		}
+		if strings.TrimSpace(ev.UID) == "" {
+			report.AddError(n, "", "el evento no tiene UID")
+			continue
+		}
+		title := strings.TrimSpace(ev.Summary)
+		if title == "" {
+			report.AddError(n, ev.UID, "el evento no tiene título")
+			continue
+		}
+		if len([]rune(title)) > 200 {
+			title = string([]rune(title)[:200])
+		}
+
+		key := externalKey(ev.UID)
+		topic, err := c.topicRepo.TopicByImportKey(ctx, moduleID, key)
+		if err != nil && !errors.Is(err, topicrepo.ErrTopicNotFound) {
+			return err
+		}
+
+		if topic == nil {
+			created, err := c.topicRepo.CreateTopic(ctx, &models.Topic{
+				ModuleID:      moduleID,
+				ScheduledDate: datatypes.Date(scheduled),
+				UnitTitle:     title,
+				Content:       strings.TrimSpace(ev.Description),
+				ImportKey:     key,
+			})
+			if err != nil {
+				return err
+			}
+			report.TopicsCreated++
+			toEmbed = append(toEmbed, created.ID)
+			continue
+		}
+
+		content := strings.TrimSpace(ev.Description)
+		textChanged := topic.UnitTitle != title || (content != "" && topic.Content != content)
+		dateChanged := !sameDay(time.Time(topic.ScheduledDate), scheduled)
+		switch {
+		case dateChanged:
+			report.TopicsShifted++
+		case textChanged:
+			report.TopicsUpdated++
+		default:
+			report.TopicsUnchanged++
+			continue
+		}
+
+		updates := topicrepo.TopicUpdate{UnitTitle: title, Content: content}
+		if dateChanged {
+			date := datatypes.Date(scheduled)
+			updates.ScheduledDate = &date
+		}
+		if err := c.topicRepo.UpdateTopic(ctx, topic.ID, updates); err != nil {
+			return err
+		}
+		if textChanged {
+			toEmbed = append(toEmbed, topic.ID)
+		}
+	}
+
+	if err := c.jobRepo.EnqueueEmbeddingJobs(ctx, embeddingjobrepo.EntityTopic, toEmbed); err != nil {
+		return fmt.Errorf("queueing topic embeddings: %w", err)
+	}
+	report.EmbeddingsQueued = len(toEmbed)
+
+	return nil
+}
+
+// shiftTopic cambia solo la fecha programada de un tema.
+func (c *calendarService) shiftTopic(ctx context.Context, topicID uint, scheduled time.Time) error {
+	date := datatypes.Date(scheduled)
+	return c.topicRepo.UpdateTopic(ctx, topicID, topicrepo.TopicUpdate{ScheduledDate: &date})
+}
+
+// topicEvent convierte un tema en un evento de día completo.
+func (c *calendarService) topicEvent(module *models.Module, topic *models.Topic) ical.Event {
+	description := module.Name
+	if content := preview(topic.Content, c.cfg.ContentPreview); content != "" {
+		description += "\n\n" + content
+	}
+
+	return ical.Event{
+		UID:          c.topicUID(topic.ID),
+		Summary:      fmt.Sprintf("[%s] %s", module.Code, topic.UnitTitle),
+		Description:  description,
+		Start:        time.Time(topic.ScheduledDate),
+		AllDay:       true,
+		LastModified: topic.UpdatedAt,
+	}
+}
+
+// topicUID es el UID estable del evento de un tema: no cambia aunque cambie la
+// fecha o el título, así los clientes reemplazan el evento en lugar de duplicarlo.
+func (c *calendarService) topicUID(topicID uint) string {
+	return fmt.Sprintf("topic-%d@%s", topicID, c.cfg.UIDDomain)
+}
+
+// parseTopicUID extrae el id del tema de un UID generado por topicUID.
+func (c *calendarService) parseTopicUID(uid string) (uint, bool) {
+	rest, ok := strings.CutSuffix(strings.TrimSpace(uid), "@"+c.cfg.UIDDomain)
+	if !ok {
+		return 0, false
+	}
+	rest, ok = strings.CutPrefix(rest, "topic-")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(rest, 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// eventDate devuelve el día del evento en la zona horaria de los cursos.
+func (c *calendarService) eventDate(ev ical.Event) time.Time {
+	if ev.AllDay {
+		return ev.Start
+	}
+	y, m, d := ev.Start.In(c.location).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// feedURL construye la URL pública de suscripción.
+func (c *calendarService) feedURL(token string) string {
+	return strings.TrimRight(c.cfg.FeedBaseURL, "/") + "/" + token + ".ics"
+}
+
+// externalKey deriva Topic.ImportKey de un UID externo.
+func externalKey(uid string) string {
+	key := "ics:" + strings.TrimSpace(uid)
+	if len(key) <= maxImportKey {
+		return key
+	}
+	sum := sha256.Sum256([]byte(uid))
+	return "ics:" + hex.EncodeToString(sum[:])
+}
+
+// preview recorta el contenido para la descripción del evento.
+func preview(content string, limit int) string {
+	content = strings.TrimSpace(content)
+	runes := []rune(content)
+	if limit <= 0 || len(runes) <= limit {
+		return content
+	}
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,15 @@
+package calendarservice
+
+import (
+	"errors"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+)
+
+var (
+	// ErrImportRejected se devuelve cuando el archivo tiene eventos con errores; no se guarda nada.
+	ErrImportRejected = apperror.New(apperror.Validation, "calendar.import_rejected", "calendar error: el archivo tiene eventos con errores, no se importó nada")
+
+	// errRollback fuerza el rollback de la transacción (simulación o eventos con errores).
+	errRollback = errors.New("calendar: rollback")
+)
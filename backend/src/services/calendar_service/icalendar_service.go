@@ -0,0 +1,56 @@
+package calendarservice
+
+import (
+	"context"
+	"time"
+
+	calendardto "github.com/Dieg0Code/aiep-agent/src/data/dtos/calendar_dto"
+)
+
+// CalendarReader agrupa la generación de calendarios.
+type CalendarReader interface {
+	// ExportUserCalendar genera el .ics con los temas de los módulos en que el usuario está inscrito.
+	ExportUserCalendar(ctx context.Context, userID uint) ([]byte, error)
+	// FeedByToken genera el mismo .ics a partir del token de suscripción (sin sesión).
+	FeedByToken(ctx context.Context, token string) ([]byte, error)
+	GetFeed(ctx context.Context, userID uint) (calendardto.CalendarFeedDTO, error)
+}
+
+// CalendarWriter agrupa la gestión de suscripciones y la importación de calendarios.
+type CalendarWriter interface {
+	// CreateFeed genera una URL de suscripción nueva; la anterior deja de funcionar.
+	CreateFeed(ctx context.Context, userID uint) (calendardto.CalendarFeedDTO, error)
+	RevokeFeed(ctx context.Context, userID uint) error
+	// ImportCalendar crea o mueve temas a partir de un .ics. Los eventos de nuestro
+	// propio feed (UID topic-<id>@dominio) mueven la fecha del tema; el resto crea o
+	// actualiza temas del módulo indicado.
+	ImportCalendar(ctx context.Context, req calendardto.CalendarImportRequestDTO, data []byte) (calendardto.CalendarImportReportDTO, error)
+}
+
+// ICalendarService es la composición de lectura y escritura.
+type ICalendarService interface {
+	CalendarReader
+	CalendarWriter
+}
+
+// Config agrupa los parámetros de los calendarios.
+type Config struct {
+	FeedBaseURL     string        // URL pública del feed; se le agrega /<token>.ics
+	UIDDomain       string        // Dominio de los UID de los eventos (no debe cambiar)
+	CalendarName    string        // Nombre que muestran los clientes de calendario
+	TimeZone        string        // Zona horaria de los cursos
+	RefreshInterval time.Duration // Frecuencia de actualización sugerida a los clientes
+	ContentPreview  int           // Caracteres del contenido del tema en la descripción del evento
+}
+
+// DefaultConfig devuelve la configuración por defecto.
+func DefaultConfig() Config {
+	return Config{
+		FeedBaseURL:     "http://localhost:8080/api/v1/calendar/feed",
+		UIDDomain:       "aiep-agent",
+		CalendarName:    "AIEP - Mis clases",
+		TimeZone:        "America/Santiago",
+		RefreshInterval: 6 * time.Hour,
+		ContentPreview:  500,
+	}
+}
@@ -0,0 +1,305 @@
+package chatservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	chatdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/chat_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	chatrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/chat_repo"
+	enrollementrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/enrollment_repo"
+	insightrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/insight_repo"
+	modulerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/module_repo"
+	topicrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/topic_repo"
+	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+	"github.com/Dieg0Code/aiep-agent/src/llm"
+	"github.com/Dieg0Code/aiep-agent/src/llm/prompt"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+	memoryservice "github.com/Dieg0Code/aiep-agent/src/services/memory_service"
+	usageservice "github.com/Dieg0Code/aiep-agent/src/services/usage_service"
+	"github.com/pgvector/pgvector-go"
+)
+
+type chatService struct {
+	chatRepo       chatrepo.ChatRepo
+	userRepo       userrepo.UserRepo
+	topicRepo      topicrepo.TopicRepo
+	insightRepo    insightrepo.InsightRepo
+	enrollmentRepo enrollementrepo.EnrollmentRepo
+	moduleRepo     modulerepo.ModuleRepo
+	memory         memoryservice.IMemoryService
+	usage          usageservice.IUsageService
+	builder        *prompt.Builder
+	completer      llm.ChatCompleter
+	embedder       llm.Embedder
+	cfg            Config
+	logger         *slog.Logger
+}
+
+// NewChatService crea una instancia de IChatService. El embedder es opcional:
+// sin él no se recuperan temas ni se guardan embeddings de los mensajes.
+func NewChatService(
+	chatRepo chatrepo.ChatRepo,
+	userRepo userrepo.UserRepo,
+	topicRepo topicrepo.TopicRepo,
+	insightRepo insightrepo.InsightRepo,
+	enrollmentRepo enrollementrepo.EnrollmentRepo,
+	moduleRepo modulerepo.ModuleRepo,
+	memory memoryservice.IMemoryService,
+	usage usageservice.IUsageService,
+	builder *prompt.Builder,
+	completer llm.ChatCompleter,
+	embedder llm.Embedder,
+	cfg Config,
+	logger *slog.Logger,
+) IChatService {
+	return &chatService{
+		chatRepo:       chatRepo,
+		userRepo:       userRepo,
+		topicRepo:      topicRepo,
+		insightRepo:    insightRepo,
+		enrollmentRepo: enrollmentRepo,
+		moduleRepo:     moduleRepo,
+		memory:         memory,
+		usage:          usage,
+		builder:        builder,
+		completer:      completer,
+		embedder:       embedder,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// SendMessage implements IChatService.
+func (c *chatService) SendMessage(ctx context.Context, req chatdto.SendMessageRequestDTO) (chatdto.SendMessageResponseDTO, error) {
+	userID := req.GetUserID()
+	text := req.GetMessage()
+	if userID == 0 {
+		return chatdto.SendMessageResponseDTO{}, chatrepo.ErrInvalidUserID
+	}
+	if text == "" {
+		return chatdto.SendMessageResponseDTO{}, chatrepo.ErrInvalidMessageContent
+	}
+
+	user, err := c.userRepo.UserByID(ctx, userID)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", userID,
+		)
+		return chatdto.SendMessageResponseDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if c.cfg.RequireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return chatdto.SendMessageResponseDTO{}, ErrEmailNotVerified
+	}
+
+	// Se devuelve tal cual: el mensaje de QuotaExceededError está pensado para el estudiante
+	if err := c.usage.CheckQuota(ctx, user.ID, user.Role); err != nil {
+		return chatdto.SendMessageResponseDTO{}, err
+	}
+
+	session, err := c.resolveSession(ctx, user, req.GetConversationID())
+	if err != nil {
+		return chatdto.SendMessageResponseDTO{}, err
+	}
+
+	moduleID, err := c.moduleScope(ctx, user, session, req.GetModuleID())
+	if err != nil {
+		return chatdto.SendMessageResponseDTO{}, err
+	}
+
+	// Todo el consumo del LLM de esta petición se atribuye al usuario, su conversación y su módulo
+	ctx = llm.WithScope(ctx, llm.Scope{
+		UserID:         user.ID,
+		ConversationID: session.ID,
+		ModuleID:       moduleID,
+		Operation:      llm.OperationChat,
+	})
+
+	// Guardar el mensaje del estudiante (con embedding si está disponible)
+	embedding := c.embed(ctx, text)
+	userMessage := &models.ChatMessage{
+		ConversationID: session.ID,
+		Role:           chatrepo.RoleUser,
+		Content:        text,
+		Embedding:      embedding,
+	}
+	if _, err := c.chatRepo.CreateChatMessage(ctx, userMessage); err != nil {
+		c.logger.ErrorContext(ctx, "Failed to save user message",
+			"error", err,
+			"conversation_id", session.ID,
+		)
+		return chatdto.SendMessageResponseDTO{}, fmt.Errorf("failed to save user message: %w", err)
+	}
+
+	memory, err := c.memory.BuildContext(ctx, session.ID, embedding)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to build conversation context",
+			"error", err,
+			"conversation_id", session.ID,
+		)
+		return chatdto.SendMessageResponseDTO{}, fmt.Errorf("failed to build conversation context: %w", err)
+	}
+
+	built := c.builder.Build(ctx, c.cfg.Model, prompt.Input{
+		System:  c.systemPrompt(ctx, moduleID),
+		Profile: c.profile(ctx, userID),
+		Topics:  c.topics(ctx, embedding, moduleID),
+		History: memory.ToLLMMessages(),
+	})
+
+	resp, err := c.completer.Complete(ctx, llm.CompletionRequest{
+		Model:       c.cfg.Model,
+		Messages:    built.Messages,
+		MaxTokens:   c.cfg.MaxCompletionTokens,
+		Temperature: c.cfg.Temperature,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to get completion",
+			"error", err,
+			"conversation_id", session.ID,
+			"model", c.cfg.Model,
+		)
+		return chatdto.SendMessageResponseDTO{}, fmt.Errorf("failed to get completion: %w", err)
+	}
+
+	assistantMessage := &models.ChatMessage{
+		ConversationID: session.ID,
+		Role:           chatrepo.RoleAssistant,
+		Content:        resp.Content,
+		Embedding:      c.embed(ctx, resp.Content),
+	}
+	if _, err := c.chatRepo.CreateChatMessage(ctx, assistantMessage); err != nil {
+		c.logger.ErrorContext(ctx, "Failed to save assistant message",
+			"error", err,
+			"conversation_id", session.ID,
+		)
+		return chatdto.SendMessageResponseDTO{}, fmt.Errorf("failed to save assistant message: %w", err)
+	}
+
+	// El resumen no debe bloquear la respuesta al estudiante
+	if _, err := c.memory.SummarizeIfNeeded(ctx, session.ID); err != nil {
+		c.logger.WarnContext(ctx, "Failed to summarize conversation",
+			"error", err,
+			"conversation_id", session.ID,
+		)
+	}
+
+	return chatdto.SendMessageResponseDTO{
+		ConversationID: session.ID,
+		MessageID:      assistantMessage.ID,
+		Reply:          assistantMessage.Content,
+		CreatedAt:      date.FormatDateTime(assistantMessage.CreatedAt),
+	}, nil
+}
+
+// resolveSession devuelve el hilo indicado (propio y no archivado) o, si no se
+// indica ninguno, el hilo activo más reciente del usuario.
+func (c *chatService) resolveSession(ctx context.Context, user *models.User, conversationID uint) (*models.ChatSession, error) {
+	if conversationID == 0 {
+		return c.ensureSession(ctx, user)
+	}
+
+	session, err := c.ownedSession(ctx, user.ID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if session.ArchivedAt != nil {
+		return nil, chatrepo.ErrChatSessionArchived
+	}
+	return session, nil
+}
+
+// ensureSession devuelve el hilo activo más reciente del usuario, creándolo si no tiene ninguno.
+func (c *chatService) ensureSession(ctx context.Context, user *models.User) (*models.ChatSession, error) {
+	session, err := c.chatRepo.ChatSessionByUserID(ctx, user.ID)
+	if err == nil {
+		return session, nil
+	}
+	if !errors.Is(err, chatrepo.ErrChatSessionNotFound) {
+		c.logger.ErrorContext(ctx, "Failed to get chat session",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return nil, fmt.Errorf("failed to get chat session: %w", err)
+	}
+
+	session, err = c.chatRepo.CreateChatSession(ctx, &models.ChatSession{
+		UserID:    user.ID,
+		UserName:  user.UserName,
+		AgentName: c.cfg.AgentName,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to create chat session",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return nil, fmt.Errorf("failed to create chat session: %w", err)
+	}
+
+	c.logger.InfoContext(ctx, "Chat session created",
+		"conversation_id", session.ID,
+		"user_id", user.ID,
+	)
+	return session, nil
+}
+
+// embed genera el embedding de text; devuelve un vector vacío si no hay embedder o falla.
+func (c *chatService) embed(ctx context.Context, text string) pgvector.Vector {
+	if c.embedder == nil || text == "" {
+		return pgvector.Vector{}
+	}
+	embedding, err := c.embedder.Embed(ctx, text)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Failed to embed message", "error", err)
+		return pgvector.Vector{}
+	}
+	return embedding
+}
+
+// profile devuelve los insights más recientes del estudiante como texto.
+func (c *chatService) profile(ctx context.Context, userID uint) []string {
+	insights, err := c.insightRepo.InsightsByUser(ctx, userID)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Failed to get user insights",
+			"error", err,
+			"user_id", userID,
+		)
+		return nil
+	}
+
+	items := make([]string, 0, len(insights))
+	for i, insight := range insights {
+		if c.cfg.InsightsLimit > 0 && i >= c.cfg.InsightsLimit {
+			break
+		}
+		items = append(items, fmt.Sprintf("%s: %s", insight.InsightType, insight.Content))
+	}
+	return items
+}
+
+// topics devuelve los temas más cercanos al mensaje, del más al menos relevante.
+// Con moduleID distinto de 0 la búsqueda se limita a los temas de ese módulo.
+func (c *chatService) topics(ctx context.Context, embedding pgvector.Vector, moduleID uint) []string {
+	if len(embedding.Slice()) == 0 || c.cfg.TopicsLimit <= 0 {
+		return nil
+	}
+
+	results, err := c.topicRepo.SearchTopicsByEmbeddingWithFilter(ctx, embedding, topicrepo.SemanticFilter{
+		ModuleID:      moduleID,
+		Limit:         c.cfg.TopicsLimit,
+		MinSimilarity: c.cfg.TopicsMinSimilarity,
+	})
+	if err != nil {
+		c.logger.WarnContext(ctx, "Failed to search topics", "error", err)
+		return nil
+	}
+
+	items := make([]string, 0, len(results))
+	for _, topic := range results {
+		items = append(items, fmt.Sprintf("[%s] %s (%s): %s", topic.ModuleName, topic.UnitTitle, topic.ScheduledDate, topic.Content))
+	}
+	return items
+}
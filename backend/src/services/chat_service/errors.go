@@ -0,0 +1,16 @@
+package chatservice
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// ErrNotEnrolled se devuelve cuando el estudiante intenta conversar en un módulo
+	// en el que no tiene una inscripción activa.
+	ErrNotEnrolled = apperror.New(apperror.Forbidden, "chat.not_enrolled", "chat error: no tienes una inscripción activa en este módulo")
+
+	// ErrModuleScopeMismatch se devuelve cuando la petición indica un módulo distinto
+	// al del hilo de conversación.
+	ErrModuleScopeMismatch = apperror.New(apperror.Validation, "chat.module_scope_mismatch", "chat error: el hilo pertenece a otro módulo")
+
+	// ErrEmailNotVerified se devuelve cuando la política exige email verificado para conversar.
+	ErrEmailNotVerified = apperror.New(apperror.Forbidden, "chat.email_not_verified", "chat error: debes verificar tu email para usar el chat")
+)
@@ -0,0 +1,64 @@
+package chatservice
+
+import (
+	"context"
+
+	chatdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/chat_dto"
+)
+
+// ChatSessionReader agrupa consultas sobre los hilos del estudiante.
+type ChatSessionReader interface {
+	GetSession(ctx context.Context, userID, conversationID uint) (chatdto.ChatSessionDTO, error)
+	ListSessions(ctx context.Context, req chatdto.ListSessionsRequestDTO) (chatdto.ListSessionsResponseDTO, error)
+	ListMessages(ctx context.Context, req chatdto.ListMessagesRequestDTO) (chatdto.ListMessagesResponseDTO, error) // Historial paginado por cursor
+}
+
+// ChatSessionWriter agrupa acciones sobre los hilos del estudiante.
+type ChatSessionWriter interface {
+	CreateSession(ctx context.Context, req chatdto.CreateSessionRequestDTO) (chatdto.ChatSessionDTO, error)
+	RenameSession(ctx context.Context, req chatdto.RenameSessionRequestDTO) error
+	ArchiveSession(ctx context.Context, userID, conversationID uint) error
+	UnarchiveSession(ctx context.Context, userID, conversationID uint) error
+}
+
+// ChatMessenger agrupa el intercambio de mensajes con el agente.
+type ChatMessenger interface {
+	SendMessage(ctx context.Context, req chatdto.SendMessageRequestDTO) (chatdto.SendMessageResponseDTO, error)
+}
+
+// IChatService orquesta la conversación entre un estudiante y el agente.
+type IChatService interface {
+	ChatSessionReader
+	ChatSessionWriter
+	ChatMessenger
+}
+
+// Config agrupa los parámetros del agente.
+type Config struct {
+	Model                string  // Modelo de chat
+	AgentName            string  // Nombre del agente guardado en la sesión
+	SystemPrompt         string  // Instrucciones base del agente
+	MaxCompletionTokens  int     // Largo máximo de la respuesta
+	Temperature          float32 // Temperatura del modelo
+	InsightsLimit        int     // Insights del estudiante a considerar
+	TopicsLimit          int     // Temas a recuperar por similitud
+	TopicsMinSimilarity  float32 // Umbral de similitud para los temas (0.0 a 1.0)
+	UpcomingTopicsLimit  int     // Próximas clases del módulo a incluir en el prompt
+	RequireVerifiedEmail bool    // Solo conversan usuarios con el email verificado
+}
+
+// DefaultConfig devuelve la configuración por defecto del agente.
+func DefaultConfig() Config {
+	return Config{
+		Model:     "gpt-4o-mini",
+		AgentName: "aiep-agent",
+		SystemPrompt: "Eres un tutor del AIEP. Responde en español, de forma clara y breve, " +
+			"usando el material del curso cuando sea pertinente y adaptándote al perfil del estudiante.",
+		MaxCompletionTokens: 1024,
+		Temperature:         0.3,
+		InsightsLimit:       10,
+		TopicsLimit:         5,
+		TopicsMinSimilarity: 0.70,
+		UpcomingTopicsLimit: 5,
+	}
+}
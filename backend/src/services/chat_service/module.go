@@ -0,0 +1,104 @@
+package chatservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	enrollementrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/enrollment_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+	"gorm.io/datatypes"
+)
+
+// moduleScope resuelve el módulo en el que ocurre la conversación: el del hilo o,
+// si el hilo es general, el indicado en la petición. Devuelve 0 si no hay módulo.
+func (c *chatService) moduleScope(ctx context.Context, user *models.User, session *models.ChatSession, requested uint) (uint, error) {
+	moduleID := requested
+	if session.ModuleID != nil {
+		if requested != 0 && requested != *session.ModuleID {
+			return 0, ErrModuleScopeMismatch
+		}
+		moduleID = *session.ModuleID
+	}
+
+	if moduleID == 0 {
+		return 0, nil
+	}
+	if err := c.checkEnrollment(ctx, user, moduleID); err != nil {
+		return 0, err
+	}
+	return moduleID, nil
+}
+
+// checkEnrollment verifica que el estudiante tenga una inscripción activa en el módulo.
+// Profesores y administradores no se inscriben, por lo que no se les exige.
+func (c *chatService) checkEnrollment(ctx context.Context, user *models.User, moduleID uint) error {
+	if user.Role != "student" {
+		return nil
+	}
+
+	enrollment, err := c.enrollmentRepo.GetUserEnrollment(ctx, user.ID, moduleID)
+	if err != nil {
+		if errors.Is(err, enrollementrepo.ErrEnrollmentNotFound) {
+			return ErrNotEnrolled
+		}
+		c.logger.ErrorContext(ctx, "Failed to get user enrollment",
+			"error", err,
+			"user_id", user.ID,
+			"module_id", moduleID,
+		)
+		return fmt.Errorf("failed to get user enrollment: %w", err)
+	}
+
+	if enrollment.Status != enrollementrepo.StatusActive {
+		return ErrNotEnrolled
+	}
+	return nil
+}
+
+// systemPrompt devuelve las instrucciones del agente y, si la conversación ocurre
+// en un módulo, su nombre, descripción y próximas clases.
+func (c *chatService) systemPrompt(ctx context.Context, moduleID uint) string {
+	if moduleID == 0 {
+		return c.cfg.SystemPrompt
+	}
+
+	module, err := c.moduleRepo.ModuleByID(ctx, moduleID)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Failed to get module",
+			"error", err,
+			"module_id", moduleID,
+		)
+		return c.cfg.SystemPrompt
+	}
+
+	var b strings.Builder
+	b.WriteString(c.cfg.SystemPrompt)
+	b.WriteString("\n\n## Módulo actual\n")
+	fmt.Fprintf(&b, "%s (%s)\n", module.Name, module.Code)
+	if module.Description != "" {
+		b.WriteString(module.Description)
+		b.WriteString("\n")
+	}
+
+	if c.cfg.UpcomingTopicsLimit > 0 {
+		upcoming, err := c.topicRepo.UpcomingTopicsByModule(ctx, moduleID, datatypes.Date(time.Now()), c.cfg.UpcomingTopicsLimit)
+		if err != nil {
+			c.logger.WarnContext(ctx, "Failed to get upcoming topics",
+				"error", err,
+				"module_id", moduleID,
+			)
+		} else if len(upcoming) > 0 {
+			b.WriteString("\nPróximas clases:\n")
+			for _, topic := range upcoming {
+				fmt.Fprintf(&b, "- %s: %s\n", date.FormatDate(time.Time(topic.ScheduledDate)), topic.UnitTitle)
+			}
+		}
+	}
+
+	b.WriteString("\nResponde en el contexto de este módulo.")
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package chatservice
+
+import (
+	"context"
+	"fmt"
+
+	chatdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/chat_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	chatrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/chat_repo"
+)
+
+// GetSession implements IChatService.
+func (c *chatService) GetSession(ctx context.Context, userID, conversationID uint) (chatdto.ChatSessionDTO, error) {
+	session, err := c.ownedSession(ctx, userID, conversationID)
+	if err != nil {
+		return chatdto.ChatSessionDTO{}, err
+	}
+	return chatdto.FromSessionModel(session), nil
+}
+
+// ListSessions implements IChatService.
+func (c *chatService) ListSessions(ctx context.Context, req chatdto.ListSessionsRequestDTO) (chatdto.ListSessionsResponseDTO, error) {
+	if req.UserID == 0 {
+		return chatdto.ListSessionsResponseDTO{}, chatrepo.ErrInvalidUserID
+	}
+
+	previews, err := c.chatRepo.ListChatSessionPreviews(ctx, req.ToRepoFilter())
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to list chat sessions",
+			"error", err,
+			"user_id", req.UserID,
+		)
+		return chatdto.ListSessionsResponseDTO{}, fmt.Errorf("failed to list chat sessions: %w", err)
+	}
+
+	return chatdto.MakeListSessionsResponse(previews, &req), nil
+}
+
+// ListMessages implements IChatService.
+func (c *chatService) ListMessages(ctx context.Context, req chatdto.ListMessagesRequestDTO) (chatdto.ListMessagesResponseDTO, error) {
+	if _, err := c.ownedSession(ctx, req.UserID, req.ConversationID); err != nil {
+		return chatdto.ListMessagesResponseDTO{}, err
+	}
+
+	page, err := c.chatRepo.ListChatMessages(ctx, req.ToRepoFilter())
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to list chat messages",
+			"error", err,
+			"conversation_id", req.ConversationID,
+		)
+		return chatdto.ListMessagesResponseDTO{}, fmt.Errorf("failed to list chat messages: %w", err)
+	}
+
+	return chatdto.MakeListMessagesResponse(page, &req), nil
+}
+
+// CreateSession implements IChatService.
+func (c *chatService) CreateSession(ctx context.Context, req chatdto.CreateSessionRequestDTO) (chatdto.ChatSessionDTO, error) {
+	if req.UserID == 0 {
+		return chatdto.ChatSessionDTO{}, chatrepo.ErrInvalidUserID
+	}
+
+	user, err := c.userRepo.UserByID(ctx, req.UserID)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", req.UserID,
+		)
+		return chatdto.ChatSessionDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+
+	moduleID := req.ModuleID
+	if moduleID != nil && *moduleID == 0 {
+		moduleID = nil
+	}
+	if moduleID != nil {
+		if err := c.checkEnrollment(ctx, user, *moduleID); err != nil {
+			return chatdto.ChatSessionDTO{}, err
+		}
+	}
+
+	session, err := c.chatRepo.CreateChatSession(ctx, &models.ChatSession{
+		UserID:    user.ID,
+		UserName:  user.UserName,
+		AgentName: c.cfg.AgentName,
+		Title:     req.GetTitle(),
+		ModuleID:  moduleID,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to create chat session",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return chatdto.ChatSessionDTO{}, fmt.Errorf("failed to create chat session: %w", err)
+	}
+
+	c.logger.InfoContext(ctx, "Chat session created",
+		"conversation_id", session.ID,
+		"user_id", user.ID,
+	)
+	return chatdto.FromSessionModel(session), nil
+}
+
+// RenameSession implements IChatService.
+func (c *chatService) RenameSession(ctx context.Context, req chatdto.RenameSessionRequestDTO) error {
+	if _, err := c.ownedSession(ctx, req.UserID, req.ConversationID); err != nil {
+		return err
+	}
+
+	title := req.GetTitle()
+	if err := c.chatRepo.UpdateChatSession(ctx, req.ConversationID, chatrepo.ChatSessionUpdates{Title: &title}); err != nil {
+		c.logger.ErrorContext(ctx, "Failed to rename chat session",
+			"error", err,
+			"conversation_id", req.ConversationID,
+		)
+		return fmt.Errorf("failed to rename chat session: %w", err)
+	}
+	return nil
+}
+
+// ArchiveSession implements IChatService.
+func (c *chatService) ArchiveSession(ctx context.Context, userID, conversationID uint) error {
+	if _, err := c.ownedSession(ctx, userID, conversationID); err != nil {
+		return err
+	}
+
+	if err := c.chatRepo.ArchiveChatSession(ctx, conversationID); err != nil {
+		c.logger.ErrorContext(ctx, "Failed to archive chat session",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return fmt.Errorf("failed to archive chat session: %w", err)
+	}
+	return nil
+}
+
+// UnarchiveSession implements IChatService.
+func (c *chatService) UnarchiveSession(ctx context.Context, userID, conversationID uint) error {
+	if _, err := c.ownedSession(ctx, userID, conversationID); err != nil {
+		return err
+	}
+
+	if err := c.chatRepo.UnarchiveChatSession(ctx, conversationID); err != nil {
+		c.logger.ErrorContext(ctx, "Failed to unarchive chat session",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return fmt.Errorf("failed to unarchive chat session: %w", err)
+	}
+	return nil
+}
+
+// ownedSession devuelve la sesión solo si pertenece al usuario. Un hilo ajeno
+// se reporta como no encontrado para no revelar su existencia.
+func (c *chatService) ownedSession(ctx context.Context, userID, conversationID uint) (*models.ChatSession, error) {
+	if userID == 0 {
+		return nil, chatrepo.ErrInvalidUserID
+	}
+
+	session, err := c.chatRepo.ChatSessionByID(ctx, conversationID)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to get chat session",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return nil, fmt.Errorf("failed to get chat session: %w", err)
+	}
+
+	if session.UserID != userID {
+		c.logger.WarnContext(ctx, "Chat session belongs to another user",
+			"conversation_id", conversationID,
+			"user_id", userID,
+		)
+		return nil, fmt.Errorf("failed to get chat session: %w", chatrepo.ErrChatSessionNotFound)
+	}
+
+	return session, nil
+}
@@ -0,0 +1,148 @@
+package embeddingservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	chatrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/chat_repo"
+	embeddingjobrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/embedding_job_repo"
+	topicrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/topic_repo"
+	"github.com/Dieg0Code/aiep-agent/src/llm"
+)
+
+type embeddingService struct {
+	jobRepo   embeddingjobrepo.EmbeddingJobRepo
+	topicRepo topicrepo.TopicRepo
+	chatRepo  chatrepo.ChatRepo
+	embedder  llm.Embedder
+	cfg       Config
+	logger    *slog.Logger
+}
+
+// NewEmbeddingService crea una instancia de IEmbeddingService.
+func NewEmbeddingService(
+	jobRepo embeddingjobrepo.EmbeddingJobRepo,
+	topicRepo topicrepo.TopicRepo,
+	chatRepo chatrepo.ChatRepo,
+	embedder llm.Embedder,
+	cfg Config,
+	logger *slog.Logger,
+) IEmbeddingService {
+	return &embeddingService{
+		jobRepo:   jobRepo,
+		topicRepo: topicRepo,
+		chatRepo:  chatRepo,
+		embedder:  embedder,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// Run implements IEmbeddingService.
+func (e *embeddingService) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Mientras haya trabajos se procesan lotes seguidos; con la cola vacía se espera
+		processed, err := e.ProcessPending(ctx)
+		if err == nil && processed > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessPending implements IEmbeddingService.
+func (e *embeddingService) ProcessPending(ctx context.Context) (int, error) {
+	jobs, err := e.jobRepo.ClaimEmbeddingJobs(ctx, e.cfg.BatchSize)
+	if err != nil {
+		e.logger.ErrorContext(ctx, "Failed to claim embedding jobs", "error", err)
+		return 0, fmt.Errorf("failed to claim embedding jobs: %w", err)
+	}
+
+	ctx = llm.WithOperation(ctx, llm.OperationEmbedding)
+
+	done := 0
+	for _, job := range jobs {
+		if err := e.process(ctx, job); err != nil {
+			e.logger.WarnContext(ctx, "Embedding job failed",
+				"error", err,
+				"job_id", job.ID,
+				"entity_type", job.EntityType,
+				"entity_id", job.EntityID,
+				"attempts", job.Attempts,
+			)
+			if err := e.jobRepo.FailEmbeddingJob(ctx, job.ID, err.Error(), e.cfg.MaxAttempts); err != nil {
+				e.logger.ErrorContext(ctx, "Failed to mark embedding job as failed",
+					"error", err,
+					"job_id", job.ID,
+				)
+			}
+			continue
+		}
+
+		if err := e.jobRepo.CompleteEmbeddingJob(ctx, job.ID); err != nil {
+			e.logger.ErrorContext(ctx, "Failed to complete embedding job",
+				"error", err,
+				"job_id", job.ID,
+			)
+			continue
+		}
+		done++
+	}
+
+	if len(jobs) > 0 {
+		e.logger.InfoContext(ctx, "Embedding jobs processed",
+			"claimed", len(jobs),
+			"completed", done,
+		)
+	}
+	return done, nil
+}
+
+// process genera y guarda el embedding de la entidad del trabajo.
+func (e *embeddingService) process(ctx context.Context, job models.EmbeddingJob) error {
+	switch job.EntityType {
+	case embeddingjobrepo.EntityTopic:
+		topic, err := e.topicRepo.TopicByID(ctx, job.EntityID)
+		if err != nil {
+			return fmt.Errorf("getting topic: %w", err)
+		}
+		ctx = llm.WithScope(ctx, llm.Scope{ModuleID: topic.ModuleID, Operation: llm.OperationEmbedding})
+
+		embedding, err := e.embedder.Embed(ctx, topicText(topic))
+		if err != nil {
+			return fmt.Errorf("embedding topic: %w", err)
+		}
+		return e.topicRepo.UpdateTopicEmbedding(ctx, topic.ID, embedding)
+
+	case embeddingjobrepo.EntityChatMessage:
+		message, err := e.chatRepo.ChatMessageByID(ctx, job.EntityID)
+		if err != nil {
+			return fmt.Errorf("getting chat message: %w", err)
+		}
+
+		embedding, err := e.embedder.Embed(ctx, message.Content)
+		if err != nil {
+			return fmt.Errorf("embedding chat message: %w", err)
+		}
+		return e.chatRepo.UpdateMessageEmbedding(ctx, message.ID, embedding)
+	}
+
+	return fmt.Errorf("unknown entity type %q", job.EntityType)
+}
+
+// topicText es el texto que representa a un tema en las búsquedas semánticas.
+func topicText(topic *models.Topic) string {
+	return strings.TrimSpace(topic.UnitTitle + "\n\n" + topic.Content)
+}
@@ -0,0 +1,31 @@
+package embeddingservice
+
+import (
+	"context"
+	"time"
+)
+
+// IEmbeddingService procesa la cola de embeddings pendientes (temas importados,
+// mensajes sin embedding, etc.).
+type IEmbeddingService interface {
+	// ProcessPending procesa un lote de trabajos y devuelve cuántos se completaron.
+	ProcessPending(ctx context.Context) (int, error)
+	// Run procesa la cola periódicamente hasta que ctx se cancele.
+	Run(ctx context.Context)
+}
+
+// Config agrupa los parámetros del worker.
+type Config struct {
+	BatchSize    int           // Trabajos tomados por lote
+	MaxAttempts  int           // Intentos antes de marcar un trabajo como fallido
+	PollInterval time.Duration // Espera entre lotes cuando la cola está vacía
+}
+
+// DefaultConfig devuelve la configuración por defecto del worker.
+func DefaultConfig() Config {
+	return Config{
+		BatchSize:    20,
+		MaxAttempts:  5,
+		PollInterval: 10 * time.Second,
+	}
+}
@@ -0,0 +1,191 @@
+package erasureservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/auth/password"
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	erasuredto "github.com/Dieg0Code/aiep-agent/src/data/dtos/erasure_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	erasurerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/erasure_repo"
+	sessionrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/session_repo"
+	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+)
+
+// recentLoginWindow es la antigüedad máxima de la sesión que reemplaza a la
+// contraseña al borrar la propia cuenta.
+const recentLoginWindow = 10 * time.Minute
+
+type erasureService struct {
+	txm         database.TxManager
+	userRepo    userrepo.UserRepo
+	sessionRepo sessionrepo.SessionRepo
+	erasureRepo erasurerepo.ErasureRepo
+	hasher      password.Hasher
+	logger      *slog.Logger
+}
+
+// NewErasureService crea una instancia de IErasureService. txm agrupa el borrado
+// y su comprobante en una sola transacción.
+func NewErasureService(
+	txm database.TxManager,
+	userRepo userrepo.UserRepo,
+	sessionRepo sessionrepo.SessionRepo,
+	erasureRepo erasurerepo.ErasureRepo,
+	hasher password.Hasher,
+	logger *slog.Logger,
+) IErasureService {
+	return &erasureService{
+		txm:         txm,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		erasureRepo: erasureRepo,
+		hasher:      hasher,
+		logger:      logger,
+	}
+}
+
+// EraseUser implements IErasureService.
+func (e *erasureService) EraseUser(ctx context.Context, req erasuredto.EraseUserRequestDTO) (erasuredto.ErasureReceiptDTO, error) {
+	if req.UserID == 0 {
+		return erasuredto.ErasureReceiptDTO{}, erasurerepo.ErrInvalidUserID
+	}
+
+	requester, err := e.userRepo.UserByID(ctx, req.RequestedByID)
+	if err != nil {
+		e.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", req.RequestedByID,
+		)
+		return erasuredto.ErasureReceiptDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+
+	initiator := erasuredto.InitiatorAdmin
+	switch {
+	case requester.ID == req.UserID:
+		// Borrar la propia cuenta no se puede deshacer: se confirma con la contraseña
+		// o, sin ella, con un login reciente (SSO o contraseña)
+		if req.Password != "" {
+			if err := e.hasher.CompareHashAndPassword(requester.PasswordHash, req.Password); err != nil {
+				return erasuredto.ErasureReceiptDTO{}, fmt.Errorf("password is incorrect: %w", err)
+			}
+		} else if err := e.checkRecentLogin(ctx, requester.ID, req.SessionID); err != nil {
+			return erasuredto.ErasureReceiptDTO{}, err
+		}
+		initiator = erasuredto.InitiatorSelf
+	case requester.Role != "admin":
+		return erasuredto.ErasureReceiptDTO{}, ErrNotAllowed
+	}
+
+	return e.erase(ctx, req.UserID, &requester.ID, initiator)
+}
+
+// checkRecentLogin devuelve ErrPasswordRequired salvo que sessionID sea una sesión
+// vigente y completa del usuario abierta hace menos de recentLoginWindow.
+func (e *erasureService) checkRecentLogin(ctx context.Context, userID, sessionID uint) error {
+	if sessionID == 0 {
+		return ErrPasswordRequired
+	}
+	sessions, err := e.sessionRepo.ActiveSessionsByUser(ctx, userID)
+	if err != nil {
+		e.logger.ErrorContext(ctx, "Failed to get active sessions",
+			"error", err,
+			"user_id", userID,
+		)
+		return fmt.Errorf("failed to get active sessions: %w", err)
+	}
+
+	for _, session := range sessions {
+		if session.ID != sessionID {
+			continue
+		}
+		if session.Scope != "" || time.Since(session.CreatedAt) > recentLoginWindow {
+			return ErrPasswordRequired
+		}
+		return nil
+	}
+	return ErrPasswordRequired
+}
+
+// GetReceipt implements IErasureService.
+func (e *erasureService) GetReceipt(ctx context.Context, id, actorID uint) (erasuredto.ErasureReceiptDTO, error) {
+	actor, err := e.userRepo.UserByID(ctx, actorID)
+	if err != nil {
+		e.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", actorID,
+		)
+		return erasuredto.ErasureReceiptDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if actor.Role != "admin" {
+		return erasuredto.ErasureReceiptDTO{}, ErrNotAllowed
+	}
+
+	receipt, err := e.erasureRepo.ErasureReceiptByID(ctx, id)
+	if err != nil {
+		e.logger.ErrorContext(ctx, "Failed to get erasure receipt",
+			"error", err,
+			"receipt_id", id,
+		)
+		return erasuredto.ErasureReceiptDTO{}, fmt.Errorf("failed to get erasure receipt: %w", err)
+	}
+
+	return erasuredto.FromErasureReceipt(receipt), nil
+}
+
+// PurgeUser implements IErasureService.
+func (e *erasureService) PurgeUser(ctx context.Context, userID uint) error {
+	_, err := e.erase(ctx, userID, nil, erasuredto.InitiatorRetention)
+	return err
+}
+
+// erase borra los datos del usuario y guarda el comprobante en la misma
+// transacción: si el comprobante falla, no se borra nada.
+func (e *erasureService) erase(ctx context.Context, userID uint, requestedByID *uint, initiator string) (erasuredto.ErasureReceiptDTO, error) {
+	var receipt *models.ErasureReceipt
+	err := e.txm.Transaction(ctx, func(ctx context.Context) error {
+		erasure, err := e.erasureRepo.EraseUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := json.Marshal(erasure.Deleted)
+		if err != nil {
+			return err
+		}
+		anonymized, err := json.Marshal(erasure.Anonymized)
+		if err != nil {
+			return err
+		}
+
+		receipt, err = e.erasureRepo.CreateErasureReceipt(ctx, &models.ErasureReceipt{
+			UserID:        userID,
+			RequestedByID: requestedByID,
+			Initiator:     initiator,
+			UserOutcome:   erasure.UserOutcome,
+			Deleted:       deleted,
+			Anonymized:    anonymized,
+		})
+		return err
+	})
+	if err != nil {
+		e.logger.ErrorContext(ctx, "Failed to erase user data",
+			"error", err,
+			"user_id", userID,
+			"initiator", initiator,
+		)
+		return erasuredto.ErasureReceiptDTO{}, fmt.Errorf("failed to erase user data: %w", err)
+	}
+
+	e.logger.InfoContext(ctx, "User data erased",
+		"user_id", userID,
+		"receipt_id", receipt.ID,
+		"initiator", initiator,
+		"user_outcome", receipt.UserOutcome,
+	)
+	return erasuredto.FromErasureReceipt(receipt), nil
+}
@@ -0,0 +1,8 @@
+package erasureservice
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	ErrNotAllowed       = apperror.New(apperror.Forbidden, "erasure.not_allowed", "erasure error: solo puedes borrar tu propia cuenta, salvo que seas admin")
+	ErrPasswordRequired = apperror.New(apperror.Validation, "erasure.password_required", "erasure error: confirma tu contraseña o vuelve a iniciar sesión para borrar tu cuenta")
+)
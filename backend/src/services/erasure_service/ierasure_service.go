@@ -0,0 +1,22 @@
+package erasureservice
+
+import (
+	"context"
+
+	erasuredto "github.com/Dieg0Code/aiep-agent/src/data/dtos/erasure_dto"
+)
+
+// IErasureService borra definitivamente los datos personales de una cuenta
+// (derecho de supresión) y deja un comprobante sin datos personales.
+type IErasureService interface {
+	// EraseUser borra la cuenta indicada y todo lo asociado. Un usuario puede
+	// borrar la suya confirmando la contraseña o desde una sesión recién abierta
+	// (las cuentas creadas por SSO no tienen contraseña conocida); un admin,
+	// cualquier cuenta.
+	EraseUser(ctx context.Context, req erasuredto.EraseUserRequestDTO) (erasuredto.ErasureReceiptDTO, error)
+	// GetReceipt devuelve un comprobante de borrado; solo para admins.
+	GetReceipt(ctx context.Context, id, actorID uint) (erasuredto.ErasureReceiptDTO, error)
+
+	// PurgeUser borra los datos de una cuenta cuya retención en la papelera venció.
+	PurgeUser(ctx context.Context, userID uint) error
+}
@@ -0,0 +1,213 @@
+package exportservice
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	htmltemplate "html/template"
+	"io"
+	"text/template"
+
+	exportdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/export_dto"
+)
+
+// Archivos del ZIP: el JSON para otros sistemas y dos versiones legibles.
+const (
+	archiveJSON     = "datos.json"
+	archiveMarkdown = "datos.md"
+	archiveHTML     = "datos.html"
+)
+
+// roleLabels nombra a los participantes de una conversación en las versiones legibles.
+var roleLabels = map[string]string{
+	"user":      "Tú",
+	"assistant": "Asistente",
+	"system":    "Sistema",
+	"tool":      "Herramienta",
+}
+
+func roleLabel(role string) string {
+	if label, ok := roleLabels[role]; ok {
+		return label
+	}
+	return role
+}
+
+var markdownTemplate = template.Must(
+	template.New(archiveMarkdown).Funcs(template.FuncMap{"role": roleLabel}).Parse(markdownSource),
+)
+
+var htmlTemplate = htmltemplate.Must(
+	htmltemplate.New(archiveHTML).Funcs(htmltemplate.FuncMap{"role": roleLabel}).Parse(htmlSource),
+)
+
+// buildArchive arma el ZIP de una exportación. Los embeddings, si se pidieron,
+// solo van en el JSON.
+func buildArchive(doc exportdto.PersonalDataDTO) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	files := []struct {
+		name  string
+		write func(w io.Writer) error
+	}{
+		{archiveJSON, func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			enc.SetEscapeHTML(false)
+			return enc.Encode(doc)
+		}},
+		{archiveMarkdown, func(w io.Writer) error { return markdownTemplate.Execute(w, doc) }},
+		{archiveHTML, func(w io.Writer) error { return htmlTemplate.Execute(w, doc) }},
+	}
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.write(w); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+const markdownSource = `# Mis datos personales
+
+Generado el {{.GeneratedAt}}. El archivo datos.json tiene la misma información en un formato legible por otros sistemas.
+
+## Perfil
+
+- Usuario: {{.Profile.UserName}}
+- Email: {{.Profile.Email}}
+- Rol: {{.Profile.Role}}
+- Cuenta creada: {{.Profile.CreatedAt}}
+{{- if .Profile.EmailVerifiedAt}}
+- Email verificado: {{.Profile.EmailVerifiedAt}}
+{{- end}}
+
+## Inscripciones
+{{range .Enrollments}}
+- {{.ModuleCode}} · {{.ModuleName}} ({{.Status}}, desde {{.EnrolledAt}})
+{{- else}}
+Sin inscripciones.
+{{- end}}
+
+## Conversaciones
+{{range .Conversations}}
+### {{.Title}}
+
+Creada el {{.CreatedAt}}
+{{- if .ArchivedAt}} · archivada el {{.ArchivedAt}}{{end}}
+{{- if .DeletedAt}} · eliminada el {{.DeletedAt}}{{end}}
+{{if .Summary}}
+Resumen de los mensajes antiguos:
+
+{{.Summary}}
+{{end}}
+{{- range .Messages}}{{if .Content}}
+**{{role .Role}}** · {{.CreatedAt}}
+
+{{.Content}}
+{{end}}{{end}}
+{{- else}}
+Sin conversaciones.
+{{end}}
+## Insights
+{{range .Insights}}
+- {{.Type}} ({{.CreatedAt}}): {{.Content}}
+{{- else}}
+Sin insights.
+{{- end}}
+
+## Sesiones
+{{range .Sessions}}
+- {{if .Device}}{{.Device}}{{else}}Dispositivo desconocido{{end}}, IP {{.IPAddress}}, desde {{.CreatedAt}}, último uso {{.LastSeenAt}}
+{{- if .RevokedAt}}, cerrada el {{.RevokedAt}}{{end}}
+{{- else}}
+Sin sesiones.
+{{- end}}
+
+## Cuentas vinculadas
+{{range .LinkedAccounts}}
+- {{.Issuer}}{{if .Email}} ({{.Email}}){{end}}, vinculada el {{.LinkedAt}}
+{{- else}}
+Sin cuentas vinculadas.
+{{- end}}
+`
+
+const htmlSource = `<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>Mis datos personales</title>
+<style>
+body { font-family: sans-serif; max-width: 52rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+.message { border-left: 3px solid #ccc; margin: 1rem 0; padding-left: 1rem; }
+.message p { white-space: pre-wrap; margin: 0.25rem 0; }
+.meta { color: #666; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Mis datos personales</h1>
+<p class="meta">Generado el {{.GeneratedAt}}. El archivo datos.json tiene la misma información en un formato legible por otros sistemas.</p>
+
+<h2>Perfil</h2>
+<ul>
+<li>Usuario: {{.Profile.UserName}}</li>
+<li>Email: {{.Profile.Email}}</li>
+<li>Rol: {{.Profile.Role}}</li>
+<li>Cuenta creada: {{.Profile.CreatedAt}}</li>
+{{- if .Profile.EmailVerifiedAt}}
+<li>Email verificado: {{.Profile.EmailVerifiedAt}}</li>
+{{- end}}
+</ul>
+
+<h2>Inscripciones</h2>
+{{if .Enrollments}}<ul>
+{{- range .Enrollments}}
+<li>{{.ModuleCode}} · {{.ModuleName}} ({{.Status}}, desde {{.EnrolledAt}})</li>
+{{- end}}
+</ul>{{else}}<p>Sin inscripciones.</p>{{end}}
+
+<h2>Conversaciones</h2>
+{{range .Conversations}}
+<h3>{{.Title}}</h3>
+<p class="meta">Creada el {{.CreatedAt}}
+{{- if .ArchivedAt}} · archivada el {{.ArchivedAt}}{{end}}
+{{- if .DeletedAt}} · eliminada el {{.DeletedAt}}{{end}}</p>
+{{- if .Summary}}
+<details><summary>Resumen de los mensajes antiguos</summary><p>{{.Summary}}</p></details>
+{{- end}}
+{{- range .Messages}}{{if .Content}}
+<div class="message"><span class="meta"><strong>{{role .Role}}</strong> · {{.CreatedAt}}</span><p>{{.Content}}</p></div>
+{{- end}}{{end}}
+{{else}}<p>Sin conversaciones.</p>
+{{end}}
+<h2>Insights</h2>
+{{if .Insights}}<ul>
+{{- range .Insights}}
+<li>{{.Type}} ({{.CreatedAt}}): {{.Content}}</li>
+{{- end}}
+</ul>{{else}}<p>Sin insights.</p>{{end}}
+
+<h2>Sesiones</h2>
+{{if .Sessions}}<ul>
+{{- range .Sessions}}
+<li>{{if .Device}}{{.Device}}{{else}}Dispositivo desconocido{{end}}, IP {{.IPAddress}}, desde {{.CreatedAt}}, último uso {{.LastSeenAt}}{{if .RevokedAt}}, cerrada el {{.RevokedAt}}{{end}}</li>
+{{- end}}
+</ul>{{else}}<p>Sin sesiones.</p>{{end}}
+
+<h2>Cuentas vinculadas</h2>
+{{if .LinkedAccounts}}<ul>
+{{- range .LinkedAccounts}}
+<li>{{.Issuer}}{{if .Email}} ({{.Email}}){{end}}, vinculada el {{.LinkedAt}}</li>
+{{- end}}
+</ul>{{else}}<p>Sin cuentas vinculadas.</p>{{end}}
+</body>
+</html>
+`
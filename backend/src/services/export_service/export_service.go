@@ -0,0 +1,250 @@
+package exportservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	exportdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/export_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	dataexportrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/data_export_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+)
+
+type exportService struct {
+	exportRepo dataexportrepo.DataExportRepo
+	cfg        Config
+	logger     *slog.Logger
+}
+
+// NewExportService crea una instancia de IExportService.
+func NewExportService(
+	exportRepo dataexportrepo.DataExportRepo,
+	cfg Config,
+	logger *slog.Logger,
+) IExportService {
+	return &exportService{
+		exportRepo: exportRepo,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+// RequestExport implements IExportService.
+func (s *exportService) RequestExport(ctx context.Context, req exportdto.RequestExportDTO) (exportdto.DataExportDTO, error) {
+	export, err := s.exportRepo.CreateDataExport(ctx, &models.DataExport{
+		UserID:            req.UserID,
+		IncludeEmbeddings: req.IncludeEmbeddings,
+	})
+	if err != nil {
+		if !errors.Is(err, dataexportrepo.ErrExportInProgress) {
+			s.logger.ErrorContext(ctx, "Failed to create data export",
+				"error", err,
+				"user_id", req.UserID,
+			)
+		}
+		return exportdto.DataExportDTO{}, fmt.Errorf("failed to create data export: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "Data export requested",
+		"export_id", export.ID,
+		"user_id", export.UserID,
+		"include_embeddings", export.IncludeEmbeddings,
+	)
+	return exportdto.FromDataExport(export, s.cfg.ArchiveRetention), nil
+}
+
+// GetExport implements IExportService.
+func (s *exportService) GetExport(ctx context.Context, id, userID uint) (exportdto.DataExportDTO, error) {
+	export, err := s.ownedExport(ctx, id, userID)
+	if err != nil {
+		return exportdto.DataExportDTO{}, err
+	}
+	return exportdto.FromDataExport(export, s.cfg.ArchiveRetention), nil
+}
+
+// CreateDownloadLink implements IExportService.
+func (s *exportService) CreateDownloadLink(ctx context.Context, id, userID uint) (exportdto.DownloadLinkDTO, error) {
+	export, err := s.ownedExport(ctx, id, userID)
+	if err != nil {
+		return exportdto.DownloadLinkDTO{}, err
+	}
+	if export.Status != dataexportrepo.StatusReady || export.FinishedAt == nil {
+		return exportdto.DownloadLinkDTO{}, dataexportrepo.ErrExportNotReady
+	}
+
+	// El enlace no dura más que el archivo
+	expiresAt := time.Now().Add(s.cfg.DownloadTTL)
+	if availableUntil := export.FinishedAt.Add(s.cfg.ArchiveRetention); availableUntil.Before(expiresAt) {
+		expiresAt = availableUntil
+	}
+
+	token, hash, err := securetoken.Generate(securetoken.DefaultBytes)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to generate download token", "error", err)
+		return exportdto.DownloadLinkDTO{}, fmt.Errorf("failed to generate download token: %w", err)
+	}
+	if err := s.exportRepo.SetDownloadToken(ctx, export.ID, hash, expiresAt); err != nil {
+		if !errors.Is(err, dataexportrepo.ErrExportNotReady) {
+			s.logger.ErrorContext(ctx, "Failed to save download token",
+				"error", err,
+				"export_id", export.ID,
+			)
+		}
+		return exportdto.DownloadLinkDTO{}, fmt.Errorf("failed to save download token: %w", err)
+	}
+
+	return exportdto.DownloadLinkDTO{
+		URL:       s.cfg.DownloadBaseURL + "?token=" + url.QueryEscape(token),
+		ExpiresAt: date.FormatDateTime(expiresAt),
+	}, nil
+}
+
+// Download implements IExportService.
+func (s *exportService) Download(ctx context.Context, token string) (exportdto.DownloadDTO, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return exportdto.DownloadDTO{}, dataexportrepo.ErrDownloadNotFound
+	}
+
+	now := time.Now()
+	export, err := s.exportRepo.DataExportByTokenHash(ctx, securetoken.Hash(token), now)
+	if err != nil {
+		if !errors.Is(err, dataexportrepo.ErrDownloadNotFound) {
+			s.logger.ErrorContext(ctx, "Failed to get data export by token", "error", err)
+		}
+		return exportdto.DownloadDTO{}, fmt.Errorf("failed to get data export: %w", err)
+	}
+
+	if err := s.exportRepo.MarkDownloaded(ctx, export.ID, now); err != nil {
+		s.logger.WarnContext(ctx, "Failed to record data export download",
+			"error", err,
+			"export_id", export.ID,
+		)
+	}
+
+	s.logger.InfoContext(ctx, "Data export downloaded",
+		"export_id", export.ID,
+		"user_id", export.UserID,
+	)
+	return exportdto.DownloadDTO{
+		FileName:    fmt.Sprintf("datos-personales-%d.zip", export.ID),
+		ContentType: "application/zip",
+		Data:        export.Archive,
+	}, nil
+}
+
+// ownedExport devuelve la exportación si pertenece al usuario. La de otro
+// usuario se informa como inexistente.
+func (s *exportService) ownedExport(ctx context.Context, id, userID uint) (*models.DataExport, error) {
+	export, err := s.exportRepo.DataExportByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, dataexportrepo.ErrExportNotFound) {
+			s.logger.ErrorContext(ctx, "Failed to get data export",
+				"error", err,
+				"export_id", id,
+			)
+		}
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+	if export.UserID != userID {
+		return nil, dataexportrepo.ErrExportNotFound
+	}
+	return export, nil
+}
+
+// Run implements IExportService.
+func (s *exportService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Mientras haya pendientes se procesan lotes seguidos; sin pendientes se espera
+		processed, err := s.ProcessPending(ctx)
+		if err == nil && processed > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessPending implements IExportService.
+func (s *exportService) ProcessPending(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	expired, err := s.exportRepo.ExpireDataExports(ctx, now.Add(-s.cfg.ArchiveRetention))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to expire data exports", "error", err)
+		return 0, fmt.Errorf("failed to expire data exports: %w", err)
+	}
+	if expired > 0 {
+		s.logger.InfoContext(ctx, "Data exports expired", "expired", expired)
+	}
+
+	exports, err := s.exportRepo.ClaimDataExports(ctx, s.cfg.BatchSize, now.Add(-s.cfg.StaleAfter))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to claim data exports", "error", err)
+		return 0, fmt.Errorf("failed to claim data exports: %w", err)
+	}
+
+	completed := 0
+	for i := range exports {
+		if err := s.process(ctx, &exports[i]); err != nil {
+			s.logger.WarnContext(ctx, "Failed to generate data export",
+				"error", err,
+				"export_id", exports[i].ID,
+				"attempts", exports[i].Attempts,
+			)
+			if failErr := s.exportRepo.FailDataExport(ctx, exports[i].ID, err.Error(), s.cfg.MaxAttempts); failErr != nil {
+				s.logger.ErrorContext(ctx, "Failed to record data export failure",
+					"error", failErr,
+					"export_id", exports[i].ID,
+				)
+			}
+			continue
+		}
+		completed++
+	}
+
+	return completed, nil
+}
+
+// process genera el ZIP de una exportación tomada por el worker.
+func (s *exportService) process(ctx context.Context, export *models.DataExport) error {
+	// Una exportación retomada muchas veces (el worker se cae al generarla) no se reintenta más
+	if export.Attempts > s.cfg.MaxAttempts {
+		return fmt.Errorf("exceeded %d attempts", s.cfg.MaxAttempts)
+	}
+
+	data, err := s.exportRepo.CollectUserData(ctx, export.UserID, export.IncludeEmbeddings)
+	if err != nil {
+		return fmt.Errorf("collecting user data: %w", err)
+	}
+
+	now := time.Now()
+	archive, err := buildArchive(exportdto.FromUserData(data, now))
+	if err != nil {
+		return fmt.Errorf("building archive: %w", err)
+	}
+
+	if err := s.exportRepo.CompleteDataExport(ctx, export.ID, archive, now); err != nil {
+		return fmt.Errorf("saving archive: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "Data export ready",
+		"export_id", export.ID,
+		"user_id", export.UserID,
+		"size_bytes", len(archive),
+	)
+	return nil
+}
@@ -0,0 +1,57 @@
+package exportservice
+
+import (
+	"context"
+	"time"
+
+	exportdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/export_dto"
+)
+
+// ExportRequester agrupa lo que usa el estudiante para exportar sus datos.
+type ExportRequester interface {
+	// RequestExport encola una exportación de los datos del usuario; la genera el worker.
+	RequestExport(ctx context.Context, req exportdto.RequestExportDTO) (exportdto.DataExportDTO, error)
+	GetExport(ctx context.Context, id, userID uint) (exportdto.DataExportDTO, error)
+	// CreateDownloadLink genera un enlace de descarga de vigencia limitada; reemplaza al anterior.
+	CreateDownloadLink(ctx context.Context, id, userID uint) (exportdto.DownloadLinkDTO, error)
+	// Download entrega el ZIP de un enlace vigente. No requiere sesión: el token es la credencial.
+	Download(ctx context.Context, token string) (exportdto.DownloadDTO, error)
+}
+
+// ExportWorker agrupa la generación en segundo plano.
+type ExportWorker interface {
+	// ProcessPending genera un lote de exportaciones y devuelve cuántas quedaron listas.
+	ProcessPending(ctx context.Context) (int, error)
+	// Run genera las exportaciones pendientes periódicamente hasta que ctx se cancele.
+	Run(ctx context.Context)
+}
+
+// IExportService es la composición de solicitud y worker.
+type IExportService interface {
+	ExportRequester
+	ExportWorker
+}
+
+// Config agrupa los parámetros de la exportación de datos.
+type Config struct {
+	DownloadBaseURL  string        // URL pública de descarga; se le agrega ?token=<token>
+	DownloadTTL      time.Duration // Vigencia de un enlace de descarga
+	ArchiveRetention time.Duration // Tiempo que se guarda el ZIP tras generarse
+	MaxAttempts      int           // Intentos antes de marcar una exportación como fallida
+	StaleAfter       time.Duration // Una exportación en proceso por más tiempo se retoma
+	BatchSize        int           // Exportaciones tomadas por lote
+	PollInterval     time.Duration // Espera entre lotes cuando no hay pendientes
+}
+
+// DefaultConfig devuelve la configuración por defecto.
+func DefaultConfig() Config {
+	return Config{
+		DownloadBaseURL:  "http://localhost:8080/api/v1/exports/download",
+		DownloadTTL:      24 * time.Hour,
+		ArchiveRetention: 7 * 24 * time.Hour,
+		MaxAttempts:      3,
+		StaleAfter:       15 * time.Minute,
+		BatchSize:        2,
+		PollInterval:     30 * time.Second,
+	}
+}
@@ -0,0 +1,22 @@
+package importservice
+
+import (
+	"errors"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+)
+
+var (
+	// ErrImportRejected se devuelve cuando el archivo tiene filas con errores; el
+	// reporte que acompaña al error detalla cada una y no se guarda ningún cambio.
+	ErrImportRejected = apperror.New(apperror.Validation, "import.rejected", "import error: el archivo tiene filas con errores, no se importó nada")
+
+	// ErrMissingColumns se devuelve cuando la cabecera no tiene las columnas obligatorias.
+	ErrMissingColumns = apperror.New(apperror.Validation, "import.missing_columns", "import error: faltan columnas obligatorias")
+
+	// ErrTooManyRows se devuelve cuando el archivo supera el máximo de filas permitido.
+	ErrTooManyRows = apperror.New(apperror.Validation, "import.too_many_rows", "import error: el archivo supera el máximo de filas permitido")
+
+	// errRollback fuerza el rollback de la transacción (simulación o filas con errores).
+	errRollback = errors.New("import: rollback")
+)
@@ -0,0 +1,28 @@
+package importservice
+
+import (
+	"context"
+
+	importdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/import_dto"
+)
+
+// IImportService importa módulos y temas desde los programas (syllabus) de los profesores.
+type IImportService interface {
+	// ImportSyllabus lee un CSV o XLSX y crea o actualiza los módulos (por código) y
+	// sus temas (por clave). Todo ocurre en una sola transacción: si alguna fila tiene
+	// errores no se guarda nada. Con DryRun se simula la importación completa y se
+	// devuelve el mismo reporte sin guardar cambios.
+	ImportSyllabus(ctx context.Context, req importdto.SyllabusImportRequestDTO, data []byte) (importdto.SyllabusImportReportDTO, error)
+}
+
+// Config agrupa los límites de la importación.
+type Config struct {
+	MaxRows int // Filas de datos permitidas por archivo
+}
+
+// DefaultConfig devuelve la configuración por defecto.
+func DefaultConfig() Config {
+	return Config{
+		MaxRows: 5000,
+	}
+}
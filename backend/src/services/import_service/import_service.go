@@ -0,0 +1,237 @@
+package importservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	importdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/import_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	embeddingjobrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/embedding_job_repo"
+	modulerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/module_repo"
+	topicrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/topic_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/spreadsheet"
+	"gorm.io/datatypes"
+)
+
+type importService struct {
+	txm        database.TxManager
+	moduleRepo modulerepo.ModuleRepo
+	topicRepo  topicrepo.TopicRepo
+	jobRepo    embeddingjobrepo.EmbeddingJobRepo
+	cfg        Config
+	logger     *slog.Logger
+}
+
+// NewImportService crea una instancia de IImportService. txm agrupa cada
+// importación en una sola transacción, que se deshace en la simulación.
+func NewImportService(
+	txm database.TxManager,
+	moduleRepo modulerepo.ModuleRepo,
+	topicRepo topicrepo.TopicRepo,
+	jobRepo embeddingjobrepo.EmbeddingJobRepo,
+	cfg Config,
+	logger *slog.Logger,
+) IImportService {
+	return &importService{
+		txm:        txm,
+		moduleRepo: moduleRepo,
+		topicRepo:  topicRepo,
+		jobRepo:    jobRepo,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+// ImportSyllabus implements IImportService.
+func (s *importService) ImportSyllabus(ctx context.Context, req importdto.SyllabusImportRequestDTO, data []byte) (importdto.SyllabusImportReportDTO, error) {
+	report := importdto.SyllabusImportReportDTO{DryRun: req.DryRun}
+	fileName := req.GetFileName()
+
+	rows, err := spreadsheet.Read(fileName, data)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to read syllabus file",
+			"error", err,
+			"file_name", fileName,
+		)
+		return report, fmt.Errorf("failed to read syllabus file: %w", err)
+	}
+
+	parsed, err := parseSyllabus(rows, s.cfg.MaxRows, &report)
+	if err != nil {
+		return report, fmt.Errorf("failed to parse syllabus file: %w", err)
+	}
+
+	// Se aplica todo aunque haya errores de validación: así la simulación también
+	// reporta los problemas que solo se detectan contra la base de datos.
+	err = s.txm.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.apply(ctx, parsed, &report); err != nil {
+			return err
+		}
+		if req.DryRun || report.HasErrors() {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		s.logger.ErrorContext(ctx, "Failed to import syllabus",
+			"error", err,
+			"file_name", fileName,
+		)
+		return report, fmt.Errorf("failed to import syllabus: %w", err)
+	}
+
+	if req.DryRun {
+		return report, nil
+	}
+	if report.HasErrors() {
+		return report, ErrImportRejected
+	}
+
+	report.Committed = true
+	s.logger.InfoContext(ctx, "Syllabus imported",
+		"file_name", fileName,
+		"rows", report.TotalRows,
+		"modules_created", report.ModulesCreated,
+		"modules_updated", report.ModulesUpdated,
+		"topics_created", report.TopicsCreated,
+		"topics_updated", report.TopicsUpdated,
+		"embeddings_queued", report.EmbeddingsQueued,
+	)
+	return report, nil
+}
+
+// apply crea o actualiza módulos y temas dentro de la transacción de ctx y encola
+// los embeddings.
+func (s *importService) apply(ctx context.Context, rows []syllabusRow, report *importdto.SyllabusImportReportDTO) error {
+	modules := make(map[string]*models.Module) // código -> módulo resuelto (nil = no se pudo crear)
+	var toEmbed []uint
+
+	for _, row := range rows {
+		module, seen := modules[row.ModuleCode]
+		if !seen {
+			var err error
+			module, err = s.upsertModule(ctx, row, report)
+			if err != nil {
+				return err
+			}
+			modules[row.ModuleCode] = module
+		}
+		if module == nil {
+			continue
+		}
+
+		topicID, embed, err := s.upsertTopic(ctx, module.ID, row, report)
+		if err != nil {
+			return err
+		}
+		if embed {
+			toEmbed = append(toEmbed, topicID)
+		}
+	}
+
+	if err := s.jobRepo.EnqueueEmbeddingJobs(ctx, embeddingjobrepo.EntityTopic, toEmbed); err != nil {
+		return fmt.Errorf("queueing topic embeddings: %w", err)
+	}
+	report.EmbeddingsQueued = len(toEmbed)
+
+	return nil
+}
+
+// upsertModule busca el módulo por código y lo crea o actualiza. Devuelve nil (sin
+// error) si la fila no permite crearlo; el motivo queda en el reporte.
+func (s *importService) upsertModule(ctx context.Context, row syllabusRow, report *importdto.SyllabusImportReportDTO) (*models.Module, error) {
+	module, err := s.moduleRepo.ModuleByCode(ctx, row.ModuleCode)
+	if err != nil && !errors.Is(err, modulerepo.ErrModuleNotFound) {
+		return nil, fmt.Errorf("getting module %s: %w", row.ModuleCode, err)
+	}
+
+	if module == nil {
+		if row.ModuleName == "" {
+			report.AddError(row.Line, colModuleName, fmt.Sprintf("el módulo %s no existe; indique su nombre para crearlo", row.ModuleCode))
+			return nil, nil
+		}
+		module, err = s.moduleRepo.CreateModule(ctx, &models.Module{
+			Code:        row.ModuleCode,
+			Name:        row.ModuleName,
+			Description: row.ModuleDescription,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating module %s: %w", row.ModuleCode, err)
+		}
+		report.ModulesCreated++
+		return module, nil
+	}
+
+	updates := modulerepo.ModuleUpdate{}
+	if row.ModuleName != "" && row.ModuleName != module.Name {
+		updates.Name = row.ModuleName
+	}
+	if row.ModuleDescription != "" && row.ModuleDescription != module.Description {
+		updates.Description = row.ModuleDescription
+	}
+	if updates.Name != "" || updates.Description != "" {
+		if err := s.moduleRepo.UpdateModule(ctx, module.ID, updates); err != nil {
+			return nil, fmt.Errorf("updating module %s: %w", row.ModuleCode, err)
+		}
+		report.ModulesUpdated++
+	}
+
+	return module, nil
+}
+
+// upsertTopic crea o actualiza el tema identificado por su clave. Devuelve si su
+// embedding debe (re)generarse: temas nuevos o con título/contenido modificado.
+func (s *importService) upsertTopic(ctx context.Context, moduleID uint, row syllabusRow, report *importdto.SyllabusImportReportDTO) (uint, bool, error) {
+	scheduled := datatypes.Date(row.ScheduledDate)
+
+	topic, err := s.topicRepo.TopicByImportKey(ctx, moduleID, row.Key)
+	if err != nil && !errors.Is(err, topicrepo.ErrTopicNotFound) {
+		return 0, false, fmt.Errorf("getting topic %q: %w", row.Key, err)
+	}
+
+	if topic == nil {
+		created, err := s.topicRepo.CreateTopic(ctx, &models.Topic{
+			ModuleID:      moduleID,
+			ScheduledDate: scheduled,
+			UnitTitle:     row.UnitTitle,
+			Content:       row.Content,
+			ImportKey:     row.Key,
+		})
+		if err != nil {
+			return 0, false, fmt.Errorf("creating topic %q: %w", row.Key, err)
+		}
+		report.TopicsCreated++
+		return created.ID, true, nil
+	}
+
+	textChanged := topic.UnitTitle != row.UnitTitle || (row.Content != "" && topic.Content != row.Content)
+	dateChanged := !sameDay(time.Time(topic.ScheduledDate), row.ScheduledDate)
+	if !textChanged && !dateChanged {
+		report.TopicsUnchanged++
+		return topic.ID, false, nil
+	}
+
+	updates := topicrepo.TopicUpdate{
+		UnitTitle: row.UnitTitle,
+		Content:   row.Content,
+	}
+	if dateChanged {
+		updates.ScheduledDate = &scheduled
+	}
+	if err := s.topicRepo.UpdateTopic(ctx, topic.ID, updates); err != nil {
+		return 0, false, fmt.Errorf("updating topic %q: %w", row.Key, err)
+	}
+	report.TopicsUpdated++
+
+	return topic.ID, textChanged, nil
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
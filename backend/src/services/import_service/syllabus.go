@@ -0,0 +1,266 @@
+package importservice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	importdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/import_dto"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/spreadsheet"
+)
+
+// Columnas reconocidas en la cabecera
+const (
+	colModuleCode        = "module_code"
+	colModuleName        = "module_name"
+	colModuleDescription = "module_description"
+	colUnitTitle         = "unit_title"
+	colContent           = "content"
+	colScheduledDate     = "scheduled_date"
+	colKey               = "key"
+)
+
+// Largos máximos, iguales a los de los modelos
+const (
+	maxModuleCode        = 50
+	maxModuleName        = 150
+	maxModuleDescription = 300
+	maxUnitTitle         = 200
+	maxImportKey         = 150
+)
+
+// headerAliases asocia los nombres de cabecera aceptados (normalizados) con cada columna.
+// Los profesores reciben los programas en español, así que se aceptan ambos idiomas.
+var headerAliases = map[string]string{
+	"module_code":        colModuleCode,
+	"codigo_modulo":      colModuleCode,
+	"codigo":             colModuleCode,
+	"code":               colModuleCode,
+	"module_name":        colModuleName,
+	"nombre_modulo":      colModuleName,
+	"modulo":             colModuleName,
+	"module":             colModuleName,
+	"module_description": colModuleDescription,
+	"descripcion_modulo": colModuleDescription,
+	"descripcion":        colModuleDescription,
+	"unit_title":         colUnitTitle,
+	"titulo_unidad":      colUnitTitle,
+	"unidad":             colUnitTitle,
+	"titulo":             colUnitTitle,
+	"tema":               colUnitTitle,
+	"content":            colContent,
+	"contenido":          colContent,
+	"scheduled_date":     colScheduledDate,
+	"fecha_programada":   colScheduledDate,
+	"fecha":              colScheduledDate,
+	"date":               colScheduledDate,
+	"key":                colKey,
+	"topic_key":          colKey,
+	"clave":              colKey,
+	"clave_tema":         colKey,
+}
+
+// requiredColumns son las columnas sin las cuales no se puede importar.
+var requiredColumns = []string{colModuleCode, colUnitTitle, colScheduledDate}
+
+// syllabusRow es una fila válida del programa.
+type syllabusRow struct {
+	Line              int // Número de fila en el archivo
+	ModuleCode        string
+	ModuleName        string
+	ModuleDescription string
+	UnitTitle         string
+	Content           string
+	ScheduledDate     time.Time
+	Key               string
+}
+
+// parseSyllabus valida las filas del archivo y devuelve las válidas. Los problemas
+// de cada fila se agregan al reporte; un error solo se devuelve si el archivo
+// completo es inutilizable.
+func parseSyllabus(rows [][]string, maxRows int, report *importdto.SyllabusImportReportDTO) ([]syllabusRow, error) {
+	headerIdx := -1
+	for i, row := range rows {
+		if !spreadsheet.IsBlank(row) {
+			headerIdx = i
+			break
+		}
+	}
+	if headerIdx < 0 {
+		return nil, spreadsheet.ErrEmptyFile
+	}
+
+	columns := make(map[string]int)
+	for i, cell := range rows[headerIdx] {
+		if col, ok := headerAliases[normalizeHeader(cell)]; ok {
+			if _, seen := columns[col]; !seen {
+				columns[col] = i
+			}
+		}
+	}
+
+	var missing []string
+	for _, col := range requiredColumns {
+		if _, ok := columns[col]; !ok {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		report.AddError(headerIdx+1, "", fmt.Sprintf("faltan columnas obligatorias: %s", strings.Join(missing, ", ")))
+		return nil, fmt.Errorf("%w: %s", ErrMissingColumns, strings.Join(missing, ", "))
+	}
+
+	cell := func(row []string, col string) string {
+		idx, ok := columns[col]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[idx])
+	}
+
+	type firstSeen struct {
+		line int
+		name string
+	}
+	moduleNames := make(map[string]firstSeen) // código -> primer nombre visto
+	keys := make(map[string]int)              // código + clave -> fila
+
+	var parsed []syllabusRow
+	for i := headerIdx + 1; i < len(rows); i++ {
+		row := rows[i]
+		if spreadsheet.IsBlank(row) {
+			continue
+		}
+		report.TotalRows++
+		if maxRows > 0 && report.TotalRows > maxRows {
+			return nil, fmt.Errorf("%w (%d)", ErrTooManyRows, maxRows)
+		}
+
+		line := i + 1
+		r := syllabusRow{
+			Line:              line,
+			ModuleCode:        strings.ToUpper(cell(row, colModuleCode)),
+			ModuleName:        cell(row, colModuleName),
+			ModuleDescription: cell(row, colModuleDescription),
+			UnitTitle:         cell(row, colUnitTitle),
+			Content:           cell(row, colContent),
+			Key:               cell(row, colKey),
+		}
+		errorsBefore := len(report.Errors)
+
+		switch {
+		case r.ModuleCode == "":
+			report.AddError(line, colModuleCode, "el código de módulo es obligatorio")
+		case runeLen(r.ModuleCode) > maxModuleCode:
+			report.AddError(line, colModuleCode, fmt.Sprintf("el código de módulo supera %d caracteres", maxModuleCode))
+		}
+		if runeLen(r.ModuleName) > maxModuleName {
+			report.AddError(line, colModuleName, fmt.Sprintf("el nombre de módulo supera %d caracteres", maxModuleName))
+		}
+		if runeLen(r.ModuleDescription) > maxModuleDescription {
+			report.AddError(line, colModuleDescription, fmt.Sprintf("la descripción supera %d caracteres", maxModuleDescription))
+		}
+		switch {
+		case r.UnitTitle == "":
+			report.AddError(line, colUnitTitle, "el título de la unidad es obligatorio")
+		case runeLen(r.UnitTitle) > maxUnitTitle:
+			report.AddError(line, colUnitTitle, fmt.Sprintf("el título de la unidad supera %d caracteres", maxUnitTitle))
+		}
+		if runeLen(r.Key) > maxImportKey {
+			report.AddError(line, colKey, fmt.Sprintf("la clave supera %d caracteres", maxImportKey))
+		}
+
+		rawDate := cell(row, colScheduledDate)
+		scheduled, err := date.ParseDate(rawDate)
+		if err != nil {
+			report.AddError(line, colScheduledDate, fmt.Sprintf("fecha inválida %q, formato esperado YYYY-MM-DD", rawDate))
+		}
+		r.ScheduledDate = scheduled
+
+		if len(report.Errors) > errorsBefore {
+			continue
+		}
+
+		if r.ModuleName != "" {
+			if seen, ok := moduleNames[r.ModuleCode]; ok && seen.name != r.ModuleName {
+				report.AddError(line, colModuleName, fmt.Sprintf("el módulo %s tiene otro nombre en la fila %d (%q)", r.ModuleCode, seen.line, seen.name))
+				continue
+			} else if !ok {
+				moduleNames[r.ModuleCode] = firstSeen{line: line, name: r.ModuleName}
+			}
+		}
+
+		if r.Key == "" {
+			r.Key = deriveKey(r.ScheduledDate, r.UnitTitle)
+		}
+		dupKey := r.ModuleCode + "\x00" + r.Key
+		if first, ok := keys[dupKey]; ok {
+			report.AddError(line, colKey, fmt.Sprintf("tema duplicado: coincide con la fila %d", first))
+			continue
+		}
+		keys[dupKey] = line
+
+		parsed = append(parsed, r)
+	}
+
+	return parsed, nil
+}
+
+// deriveKey genera la clave de un tema sin clave explícita a partir de su fecha y
+// título, de modo que reimportar el mismo programa actualice en lugar de duplicar.
+func deriveKey(scheduled time.Time, title string) string {
+	key := date.FormatDate(scheduled) + ":" + slug(title)
+	if len(key) <= maxImportKey {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return date.FormatDate(scheduled) + ":" + hex.EncodeToString(sum[:16])
+}
+
+// normalizeHeader pasa la cabecera a minúsculas, sin tildes y con guiones bajos.
+func normalizeHeader(s string) string {
+	return strings.ReplaceAll(slug(s), "-", "_")
+}
+
+// slug pasa el texto a minúsculas sin tildes, separando las palabras con guiones.
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		r = stripAccent(r)
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// stripAccent reemplaza las vocales con tilde y la ü por su forma simple.
+func stripAccent(r rune) rune {
+	switch r {
+	case 'á', 'à', 'ä', 'â':
+		return 'a'
+	case 'é', 'è', 'ë', 'ê':
+		return 'e'
+	case 'í', 'ì', 'ï', 'î':
+		return 'i'
+	case 'ó', 'ò', 'ö', 'ô':
+		return 'o'
+	case 'ú', 'ù', 'ü', 'û':
+		return 'u'
+	}
+	return r
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
@@ -0,0 +1,11 @@
+package invitationservice
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	ErrNotAllowed     = apperror.New(apperror.Forbidden, "invitation.not_allowed", "invitation error: solo profesores y admins pueden gestionar invitaciones")
+	ErrRoleNotAllowed = apperror.New(apperror.Forbidden, "invitation.role_not_allowed", "invitation error: un profesor solo puede invitar estudiantes")
+	ErrNotOwner       = apperror.New(apperror.Forbidden, "invitation.not_owner", "invitation error: la invitación pertenece a otro usuario")
+	ErrInvalidCode    = apperror.New(apperror.Validation, "invitation.invalid_code", "invitation error: el código solo puede tener letras, números y guiones")
+	ErrInvalidExpiry  = apperror.New(apperror.Validation, "invitation.invalid_expiry", "invitation error: fecha de vencimiento inválida, formato esperado RFC3339")
+)
@@ -0,0 +1,40 @@
+package invitationservice
+
+import (
+	"context"
+
+	invitationdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/invitation_dto"
+)
+
+// InvitationReader agrupa la consulta y auditoría de códigos de invitación.
+type InvitationReader interface {
+	GetInvitation(ctx context.Context, id uint) (invitationdto.InvitationDTO, error)
+	ListInvitations(ctx context.Context, req invitationdto.ListInvitationsRequestDTO) ([]invitationdto.InvitationDTO, error)
+	// AuditInvitation devuelve la invitación con las cuentas creadas a partir de ella.
+	AuditInvitation(ctx context.Context, id uint) (invitationdto.InvitationAuditDTO, error)
+}
+
+// InvitationWriter agrupa la creación y revocación de códigos.
+type InvitationWriter interface {
+	CreateInvitation(ctx context.Context, req invitationdto.CreateInvitationRequestDTO) (invitationdto.InvitationDTO, error)
+	// RevokeInvitation invalida el código. Un profesor solo puede revocar los suyos.
+	RevokeInvitation(ctx context.Context, id, actorID uint) error
+}
+
+// IInvitationService es la composición de lectura y escritura.
+type IInvitationService interface {
+	InvitationReader
+	InvitationWriter
+}
+
+// Config agrupa los parámetros de las invitaciones.
+type Config struct {
+	CodeLength int // Largo de los códigos generados
+}
+
+// DefaultConfig devuelve la configuración por defecto.
+func DefaultConfig() Config {
+	return Config{
+		CodeLength: 8,
+	}
+}
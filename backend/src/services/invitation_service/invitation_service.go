@@ -0,0 +1,236 @@
+package invitationservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	invitationdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/invitation_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	invitationrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/invitation_repo"
+	modulerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/module_repo"
+	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+)
+
+// maxCodeAttempts es la cantidad de códigos generados que se prueban ante un choque.
+const maxCodeAttempts = 5
+
+type invitationService struct {
+	invitationRepo invitationrepo.InvitationRepo
+	userRepo       userrepo.UserRepo
+	moduleRepo     modulerepo.ModuleRepo
+	cfg            Config
+	logger         *slog.Logger
+}
+
+// NewInvitationService crea una instancia de IInvitationService con los repositorios inyectados.
+func NewInvitationService(
+	invitationRepo invitationrepo.InvitationRepo,
+	userRepo userrepo.UserRepo,
+	moduleRepo modulerepo.ModuleRepo,
+	cfg Config,
+	logger *slog.Logger,
+) IInvitationService {
+	return &invitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		moduleRepo:     moduleRepo,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// CreateInvitation implements IInvitationService.
+func (s *invitationService) CreateInvitation(ctx context.Context, req invitationdto.CreateInvitationRequestDTO) (invitationdto.InvitationDTO, error) {
+	creator, err := s.staff(ctx, req.CreatedByID)
+	if err != nil {
+		return invitationdto.InvitationDTO{}, err
+	}
+	role := req.GetRole()
+	if creator.Role != "admin" && role != "student" {
+		return invitationdto.InvitationDTO{}, ErrRoleNotAllowed
+	}
+
+	expiresAt, err := req.GetExpiresAt()
+	if err != nil {
+		return invitationdto.InvitationDTO{}, ErrInvalidExpiry
+	}
+
+	var module *models.Module
+	if moduleID := req.GetModuleID(); moduleID != 0 {
+		module, err = s.moduleRepo.ModuleByID(ctx, moduleID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to get module for invitation",
+				"error", err,
+				"module_id", moduleID,
+			)
+			return invitationdto.InvitationDTO{}, fmt.Errorf("failed to get module: %w", err)
+		}
+	}
+
+	code := invitationrepo.NormalizeCode(req.Code)
+	if code != "" && !validCode(code) {
+		return invitationdto.InvitationDTO{}, ErrInvalidCode
+	}
+
+	invitation := &models.Invitation{
+		CreatedByID: creator.ID,
+		ModuleID:    req.ModuleID,
+		Role:        role,
+		MaxUses:     req.MaxUses,
+		ExpiresAt:   expiresAt,
+		Note:        req.Note,
+	}
+
+	// Un código elegido por el usuario se intenta una vez; uno generado se reintenta
+	// si choca con otro existente.
+	attempts := 1
+	if code == "" {
+		attempts = maxCodeAttempts
+	}
+	for i := 0; i < attempts; i++ {
+		invitation.Code = code
+		if invitation.Code == "" {
+			if invitation.Code, err = securetoken.Code(s.cfg.CodeLength); err != nil {
+				return invitationdto.InvitationDTO{}, err
+			}
+		}
+		invitation.ID = 0
+		_, err = s.invitationRepo.CreateInvitation(ctx, invitation)
+		if !errors.Is(err, invitationrepo.ErrCodeConflict) {
+			break
+		}
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to create invitation",
+			"error", err,
+			"created_by_id", creator.ID,
+		)
+		return invitationdto.InvitationDTO{}, fmt.Errorf("failed to create invitation: %w", err)
+	}
+	invitation.Module = module
+
+	s.logger.InfoContext(ctx, "Invitation created",
+		"invitation_id", invitation.ID,
+		"created_by_id", creator.ID,
+		"role", invitation.Role,
+		"module_id", req.GetModuleID(),
+		"max_uses", invitation.MaxUses,
+	)
+	return invitationdto.FromInvitationModel(invitation), nil
+}
+
+// RevokeInvitation implements IInvitationService.
+func (s *invitationService) RevokeInvitation(ctx context.Context, id, actorID uint) error {
+	actor, err := s.staff(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.InvitationByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get invitation",
+			"error", err,
+			"invitation_id", id,
+		)
+		return fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if actor.Role != "admin" && invitation.CreatedByID != actor.ID {
+		return ErrNotOwner
+	}
+
+	if err := s.invitationRepo.RevokeInvitation(ctx, id, actor.ID); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to revoke invitation",
+			"error", err,
+			"invitation_id", id,
+		)
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "Invitation revoked",
+		"invitation_id", id,
+		"revoked_by_id", actor.ID,
+	)
+	return nil
+}
+
+// GetInvitation implements IInvitationService.
+func (s *invitationService) GetInvitation(ctx context.Context, id uint) (invitationdto.InvitationDTO, error) {
+	invitation, err := s.invitationRepo.InvitationByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get invitation",
+			"error", err,
+			"invitation_id", id,
+		)
+		return invitationdto.InvitationDTO{}, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	return invitationdto.FromInvitationModel(invitation), nil
+}
+
+// ListInvitations implements IInvitationService.
+func (s *invitationService) ListInvitations(ctx context.Context, req invitationdto.ListInvitationsRequestDTO) ([]invitationdto.InvitationDTO, error) {
+	invitations, err := s.invitationRepo.ListInvitations(ctx, req.ToRepoFilter())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list invitations",
+			"error", err,
+			"created_by_id", req.CreatedByID,
+			"module_id", req.ModuleID,
+		)
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	return invitationdto.MakeInvitationList(invitations), nil
+}
+
+// AuditInvitation implements IInvitationService.
+func (s *invitationService) AuditInvitation(ctx context.Context, id uint) (invitationdto.InvitationAuditDTO, error) {
+	invitation, err := s.invitationRepo.InvitationByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get invitation",
+			"error", err,
+			"invitation_id", id,
+		)
+		return invitationdto.InvitationAuditDTO{}, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	redemptions, err := s.invitationRepo.RedemptionsByInvitation(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get invitation redemptions",
+			"error", err,
+			"invitation_id", id,
+		)
+		return invitationdto.InvitationAuditDTO{}, fmt.Errorf("failed to get invitation redemptions: %w", err)
+	}
+
+	return invitationdto.MakeInvitationAudit(invitation, redemptions), nil
+}
+
+// staff devuelve al usuario si es profesor o admin.
+func (s *invitationService) staff(ctx context.Context, userID uint) (*models.User, error) {
+	if userID == 0 {
+		return nil, invitationrepo.ErrInvalidUserID
+	}
+	user, err := s.userRepo.UserByID(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", userID,
+		)
+		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if user.Role != "teacher" && user.Role != "admin" {
+		return nil, ErrNotAllowed
+	}
+	return user, nil
+}
+
+// validCode acepta letras, números y guiones (el código ya viene en mayúsculas).
+func validCode(code string) bool {
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package memoryservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	"github.com/Dieg0Code/aiep-agent/src/llm"
+	"github.com/pgvector/pgvector-go"
+)
+
+// IMemoryService administra la memoria de largo plazo de una conversación:
+// resume los mensajes antiguos y arma el contexto que se envía al LLM.
+type IMemoryService interface {
+	// SummarizeIfNeeded resume los mensajes pendientes cuando superan el umbral.
+	// Devuelve true si se generó un nuevo resumen.
+	SummarizeIfNeeded(ctx context.Context, conversationID uint) (bool, error)
+	// BuildContext arma resumen + mensajes antiguos relevantes + últimos turnos dentro del presupuesto.
+	// query es el embedding del mensaje actual; vacío, no se recuperan mensajes antiguos.
+	BuildContext(ctx context.Context, conversationID uint, query pgvector.Vector) (ConversationContext, error)
+}
+
+// Config controla el tamaño de la memoria y el presupuesto de tokens.
+type Config struct {
+	RecentTurns        int     // Mensajes recientes que se envían siempre (si caben)
+	SummarizeThreshold int     // Mensajes sin resumir (fuera de los recientes) que disparan un resumen
+	MaxSummaryBatch    int     // Máximo de mensajes resumidos por pasada
+	SummaryModel       string  // Modelo usado para resumir ("" = el del proveedor)
+	SummaryMaxTokens   int     // Largo máximo del resumen generado
+	RetrievedLimit     int     // Máximo de mensajes antiguos recuperados por similitud
+	MinSimilarity      float32 // Umbral de similitud para la recuperación (0.0 a 1.0)
+	TokenBudget        int     // Presupuesto total de tokens para la memoria
+	SummaryShare       float64 // Fracción máxima del presupuesto para el resumen
+	RetrievedShare     float64 // Fracción máxima del presupuesto para mensajes recuperados
+}
+
+// DefaultConfig devuelve valores razonables para un modelo con contexto de 16k+.
+func DefaultConfig() Config {
+	return Config{
+		RecentTurns:        12,
+		SummarizeThreshold: 20,
+		MaxSummaryBatch:    100,
+		SummaryMaxTokens:   512,
+		RetrievedLimit:     5,
+		MinSimilarity:      0.75,
+		TokenBudget:        4000,
+		SummaryShare:       0.25,
+		RetrievedShare:     0.25,
+	}
+}
+
+// ConversationContext es la memoria ya recortada al presupuesto.
+type ConversationContext struct {
+	Summary   string               // Resumen acumulado (puede venir truncado)
+	Retrieved []models.ChatMessage // Mensajes antiguos relevantes, en orden cronológico
+	Recent    []models.ChatMessage // Últimos turnos, en orden cronológico
+	Tokens    int                  // Tokens estimados del contexto completo
+}
+
+// ToLLMMessages convierte el contexto en mensajes para el LLM. El resumen y los
+// mensajes recuperados se envían como mensajes de sistema antes de los turnos recientes.
+func (c ConversationContext) ToLLMMessages() []llm.Message {
+	messages := make([]llm.Message, 0, len(c.Recent)+2)
+
+	if c.Summary != "" {
+		messages = append(messages, llm.Message{
+			Role:    llm.RoleSystem,
+			Content: "Resumen de la conversación anterior:\n" + c.Summary,
+		})
+	}
+
+	if len(c.Retrieved) > 0 {
+		var b strings.Builder
+		b.WriteString("Mensajes anteriores relevantes:\n")
+		for _, m := range c.Retrieved {
+			fmt.Fprintf(&b, "- [%s] %s\n", m.Role, m.Content)
+		}
+		messages = append(messages, llm.Message{
+			Role:    llm.RoleSystem,
+			Content: strings.TrimRight(b.String(), "\n"),
+		})
+	}
+
+	for _, m := range c.Recent {
+		messages = append(messages, llm.Message{
+			Role:       m.Role,
+			Name:       m.Name,
+			Content:    m.Content,
+			ToolCallID: m.ToolCallID,
+		})
+	}
+
+	return messages
+}
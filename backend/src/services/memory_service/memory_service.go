@@ -0,0 +1,267 @@
+package memoryservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	chatrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/chat_repo"
+	"github.com/Dieg0Code/aiep-agent/src/llm"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/tokenizer"
+	"github.com/pgvector/pgvector-go"
+)
+
+const summarizePrompt = `Eres un asistente que mantiene la memoria de una conversación entre un estudiante y un agente educativo.
+Actualiza el resumen previo incorporando los nuevos mensajes. Conserva datos del estudiante, dudas abiertas,
+temas tratados y acuerdos. Escribe en español, en prosa breve, sin inventar información.`
+
+type memoryService struct {
+	chatRepo  chatrepo.ChatRepo
+	completer llm.ChatCompleter
+	tokenizer tokenizer.Tokenizer
+	cfg       Config
+	logger    *slog.Logger
+}
+
+// NewMemoryService crea una instancia de IMemoryService. El tokenizer debe ser el
+// del modelo de chat (ver tokenizer.Registry.ForModel).
+func NewMemoryService(
+	chatRepo chatrepo.ChatRepo,
+	completer llm.ChatCompleter,
+	tok tokenizer.Tokenizer,
+	cfg Config,
+	logger *slog.Logger,
+) IMemoryService {
+	if tok == nil {
+		tok = tokenizer.NewCharTokenizer(tokenizer.DefaultCharsPerToken)
+	}
+	return &memoryService{
+		chatRepo:  chatRepo,
+		completer: completer,
+		tokenizer: tok,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// SummarizeIfNeeded implements IMemoryService.
+func (m *memoryService) SummarizeIfNeeded(ctx context.Context, conversationID uint) (bool, error) {
+	if conversationID == 0 {
+		return false, chatrepo.ErrInvalidConversationID
+	}
+
+	previous, err := m.chatRepo.ChatSummaryByConversationID(ctx, conversationID)
+	if err != nil && !errors.Is(err, chatrepo.ErrChatSummaryNotFound) {
+		m.logger.ErrorContext(ctx, "Failed to get chat summary",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return false, fmt.Errorf("failed to get chat summary: %w", err)
+	}
+
+	var coveredUntil uint
+	var previousText string
+	var previousCount int
+	if previous != nil {
+		coveredUntil = previous.CoveredUntilMessageID
+		previousText = previous.Content
+		previousCount = previous.MessageCount
+	}
+
+	pending, err := m.chatRepo.CountMessagesAfterID(ctx, conversationID, coveredUntil)
+	if err != nil {
+		m.logger.ErrorContext(ctx, "Failed to count pending messages",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return false, fmt.Errorf("failed to count pending messages: %w", err)
+	}
+
+	// Los últimos RecentTurns siempre viajan completos, no se resumen todavía
+	eligible := int(pending) - m.cfg.RecentTurns
+	if eligible < m.cfg.SummarizeThreshold || eligible <= 0 {
+		return false, nil
+	}
+
+	batch := eligible
+	if m.cfg.MaxSummaryBatch > 0 && batch > m.cfg.MaxSummaryBatch {
+		batch = m.cfg.MaxSummaryBatch
+	}
+
+	messages, err := m.chatRepo.MessagesAfterID(ctx, conversationID, coveredUntil, batch)
+	if err != nil {
+		m.logger.ErrorContext(ctx, "Failed to get messages to summarize",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return false, fmt.Errorf("failed to get messages to summarize: %w", err)
+	}
+	if len(messages) == 0 {
+		return false, nil
+	}
+
+	resp, err := m.completer.Complete(llm.WithOperation(ctx, llm.OperationSummary), llm.CompletionRequest{
+		Model:     m.cfg.SummaryModel,
+		MaxTokens: m.cfg.SummaryMaxTokens,
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: summarizePrompt},
+			{Role: llm.RoleUser, Content: buildTranscript(previousText, messages)},
+		},
+	})
+	if err != nil {
+		m.logger.ErrorContext(ctx, "Failed to summarize conversation",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return false, fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+
+	content := strings.TrimSpace(resp.Content)
+	if content == "" {
+		return false, fmt.Errorf("summarizer returned an empty summary")
+	}
+
+	_, err = m.chatRepo.UpsertChatSummary(ctx, &models.ChatSummary{
+		ConversationID:        conversationID,
+		Content:               content,
+		CoveredUntilMessageID: messages[len(messages)-1].ID,
+		MessageCount:          previousCount + len(messages),
+	})
+	if err != nil {
+		m.logger.ErrorContext(ctx, "Failed to save chat summary",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return false, fmt.Errorf("failed to save chat summary: %w", err)
+	}
+
+	m.logger.InfoContext(ctx, "Conversation summarized",
+		"conversation_id", conversationID,
+		"summarized_messages", len(messages),
+		"covered_until_message_id", messages[len(messages)-1].ID,
+	)
+
+	return true, nil
+}
+
+// BuildContext implements IMemoryService.
+func (m *memoryService) BuildContext(ctx context.Context, conversationID uint, query pgvector.Vector) (ConversationContext, error) {
+	if conversationID == 0 {
+		return ConversationContext{}, chatrepo.ErrInvalidConversationID
+	}
+
+	var result ConversationContext
+	budget := m.cfg.TokenBudget
+
+	// 1. Resumen acumulado
+	summary, err := m.chatRepo.ChatSummaryByConversationID(ctx, conversationID)
+	if err != nil && !errors.Is(err, chatrepo.ErrChatSummaryNotFound) {
+		m.logger.ErrorContext(ctx, "Failed to get chat summary",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return ConversationContext{}, fmt.Errorf("failed to get chat summary: %w", err)
+	}
+	if summary != nil {
+		maxSummary := int(float64(budget) * m.cfg.SummaryShare)
+		result.Summary = m.tokenizer.Truncate(summary.Content, maxSummary)
+		result.Tokens += m.tokenizer.Count(result.Summary)
+	}
+
+	// 2. Últimos turnos (del más nuevo al más antiguo, hasta llenar lo que no es de recuperación)
+	recentIDs := map[uint]bool{}
+	history, err := m.chatRepo.GetConversationHistory(ctx, conversationID, m.cfg.RecentTurns)
+	if err != nil && !errors.Is(err, chatrepo.ErrChatMessageNotFound) {
+		m.logger.ErrorContext(ctx, "Failed to get conversation history",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return ConversationContext{}, fmt.Errorf("failed to get conversation history: %w", err)
+	}
+
+	recentBudget := budget - result.Tokens - int(float64(budget)*m.cfg.RetrievedShare)
+	used := 0
+	for i, msg := range history {
+		cost := m.tokenizer.Count(msg.Content)
+		// El mensaje más nuevo se incluye siempre
+		if i > 0 && used+cost > recentBudget {
+			break
+		}
+		used += cost
+		result.Recent = append(result.Recent, msg)
+		recentIDs[msg.ID] = true
+	}
+	result.Tokens += used
+
+	// GetConversationHistory devuelve DESC: invertir a orden cronológico
+	for i, j := 0, len(result.Recent)-1; i < j; i, j = i+1, j-1 {
+		result.Recent[i], result.Recent[j] = result.Recent[j], result.Recent[i]
+	}
+
+	// 3. Mensajes antiguos relevantes para la consulta actual (sin embedding no hay recuperación)
+	if len(query.Slice()) == 0 || m.cfg.RetrievedLimit <= 0 {
+		return result, nil
+	}
+
+	var beforeID uint
+	if len(result.Recent) > 0 {
+		beforeID = result.Recent[0].ID
+	}
+
+	retrieved, err := m.chatRepo.SearchMessagesByEmbeddingWithFilter(ctx, query, chatrepo.SemanticMessageFilter{
+		ConversationID: conversationID,
+		BeforeID:       beforeID,
+		Limit:          m.cfg.RetrievedLimit,
+		MinSimilarity:  m.cfg.MinSimilarity,
+	})
+	if err != nil && !errors.Is(err, chatrepo.ErrNoSimilarMessagesFound) {
+		m.logger.WarnContext(ctx, "Failed to retrieve related messages",
+			"error", err,
+			"conversation_id", conversationID,
+		)
+		return result, nil
+	}
+
+	remaining := budget - result.Tokens
+	for _, msg := range retrieved {
+		if recentIDs[msg.ID] {
+			continue
+		}
+		cost := m.tokenizer.Count(msg.Content)
+		if cost > remaining {
+			continue
+		}
+		remaining -= cost
+		result.Tokens += cost
+		result.Retrieved = append(result.Retrieved, msg)
+	}
+
+	sort.Slice(result.Retrieved, func(i, j int) bool {
+		return result.Retrieved[i].ID < result.Retrieved[j].ID
+	})
+
+	return result, nil
+}
+
+// buildTranscript arma el texto que recibe el resumidor: resumen previo + mensajes nuevos.
+func buildTranscript(previous string, messages []models.ChatMessage) string {
+	var b strings.Builder
+	if previous != "" {
+		b.WriteString("Resumen previo:\n")
+		b.WriteString(previous)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Nuevos mensajes:\n")
+	for _, msg := range messages {
+		// Las salidas de herramientas y mensajes de sistema no aportan al resumen
+		if msg.Role != chatrepo.RoleUser && msg.Role != chatrepo.RoleAssistant {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n", msg.Role, msg.Content)
+	}
+	return b.String()
+}
@@ -0,0 +1,17 @@
+package rosterservice
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	ErrMissingEmailColumn = apperror.New(apperror.Validation, "roster.missing_email_column", "roster error: falta la columna obligatoria email")
+	ErrTooManyRows        = apperror.New(apperror.Validation, "roster.too_many_rows", "roster error: el archivo supera el máximo de filas permitido")
+	ErrImportNotResumable = apperror.New(apperror.Conflict, "roster.import_not_resumable", "roster error: la importación ya terminó")
+)
+
+// rowError es un problema de la fila (no de la base de datos): la fila queda con
+// resultado error y la importación continúa.
+type rowError struct {
+	msg string
+}
+
+func (e *rowError) Error() string { return e.msg }
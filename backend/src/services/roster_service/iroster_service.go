@@ -0,0 +1,54 @@
+package rosterservice
+
+import (
+	"context"
+	"time"
+
+	rosterdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/roster_dto"
+)
+
+// RosterReader agrupa la consulta de importaciones de nómina.
+type RosterReader interface {
+	GetRosterImport(ctx context.Context, id uint) (rosterdto.RosterImportReportDTO, error)
+	ListRosterImports(ctx context.Context, req rosterdto.ListRosterImportsRequestDTO) ([]rosterdto.RosterImportReportDTO, error)
+}
+
+// RosterWriter agrupa la ejecución de importaciones de nómina.
+type RosterWriter interface {
+	// StartRosterImport crea los usuarios e inscripciones de la nómina. Subir de nuevo
+	// el mismo archivo para el mismo módulo reanuda (o devuelve) la importación existente.
+	// Si la ejecución falla a mitad de camino devuelve el error junto con el reporte
+	// parcial: trae las credenciales de los usuarios ya creados, que no se guardan.
+	StartRosterImport(ctx context.Context, req rosterdto.StartRosterImportRequestDTO, data []byte) (rosterdto.RosterImportReportDTO, error)
+	// ResumeRosterImport procesa las filas que quedaron pendientes de una importación
+	// interrumpida. Como StartRosterImport, ante un fallo devuelve también el reporte parcial.
+	ResumeRosterImport(ctx context.Context, id uint) (rosterdto.RosterImportReportDTO, error)
+}
+
+// IRosterService es la composición de lectura y escritura.
+type IRosterService interface {
+	RosterReader
+	RosterWriter
+}
+
+// Config agrupa los parámetros de la importación de nóminas.
+type Config struct {
+	InviteBaseURL  string        // URL de activación de cuenta; se le agrega ?token=<token>
+	InviteTTL      time.Duration // Vigencia del enlace de invitación
+	PasswordLength int           // Largo de las contraseñas generadas
+	MaxRows        int           // Filas máximas por archivo
+	BatchSize      int           // Filas pendientes leídas por consulta
+	StaleAfter     time.Duration // Una importación running sin avances por este tiempo se da por abandonada y se puede reanudar
+}
+
+// DefaultConfig devuelve la configuración por defecto.
+func DefaultConfig() Config {
+	return Config{
+		InviteBaseURL:  "http://localhost:8080/activar-cuenta",
+		InviteTTL:      7 * 24 * time.Hour,
+		PasswordLength: 12,
+		MaxRows:        2000,
+		BatchSize:      100,
+		StaleAfter:     15 * time.Minute,
+	}
+}
@@ -0,0 +1,201 @@
+package rosterservice
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	enrollementrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/enrollment_repo"
+	rosterrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/roster_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/spreadsheet"
+)
+
+// Columnas reconocidas en la cabecera
+const (
+	colEmail    = "email"
+	colUserName = "user_name"
+	colStatus   = "status"
+)
+
+// maxUserName es el largo máximo de un nombre de usuario generado o leído.
+const maxUserName = 50
+
+// headerAliases asocia los nombres de cabecera aceptados (normalizados) con cada columna.
+var headerAliases = map[string]string{
+	"email":              colEmail,
+	"e_mail":             colEmail,
+	"mail":               colEmail,
+	"correo":             colEmail,
+	"correo_electronico": colEmail,
+	"user_name":          colUserName,
+	"username":           colUserName,
+	"usuario":            colUserName,
+	"nombre_usuario":     colUserName,
+	"status":             colStatus,
+	"estado":             colStatus,
+}
+
+// statusAliases traduce los estados aceptados en la nómina al estado de la inscripción.
+var statusAliases = map[string]string{
+	"":           enrollementrepo.StatusActive,
+	"active":     enrollementrepo.StatusActive,
+	"activo":     enrollementrepo.StatusActive,
+	"activa":     enrollementrepo.StatusActive,
+	"inscrito":   enrollementrepo.StatusActive,
+	"dropped":    enrollementrepo.StatusDropped,
+	"retirado":   enrollementrepo.StatusDropped,
+	"retirada":   enrollementrepo.StatusDropped,
+	"baja":       enrollementrepo.StatusDropped,
+	"inactivo":   enrollementrepo.StatusDropped,
+	"completed":  enrollementrepo.StatusCompleted,
+	"completado": enrollementrepo.StatusCompleted,
+	"aprobado":   enrollementrepo.StatusCompleted,
+	"aprobada":   enrollementrepo.StatusCompleted,
+}
+
+// parseRoster convierte las filas del archivo en filas de importación. Las filas
+// inválidas se devuelven con resultado error para que queden en el reporte; un
+// error solo se devuelve si el archivo completo es inutilizable.
+func parseRoster(rows [][]string, maxRows int) ([]models.RosterImportRow, error) {
+	headerIdx := -1
+	for i, row := range rows {
+		if !spreadsheet.IsBlank(row) {
+			headerIdx = i
+			break
+		}
+	}
+	if headerIdx < 0 {
+		return nil, spreadsheet.ErrEmptyFile
+	}
+
+	columns := make(map[string]int)
+	for i, cell := range rows[headerIdx] {
+		if col, ok := headerAliases[normalizeHeader(cell)]; ok {
+			if _, seen := columns[col]; !seen {
+				columns[col] = i
+			}
+		}
+	}
+	if _, ok := columns[colEmail]; !ok {
+		return nil, ErrMissingEmailColumn
+	}
+
+	cell := func(row []string, col string) string {
+		idx, ok := columns[col]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[idx])
+	}
+
+	emails := make(map[string]int) // email -> fila
+	var parsed []models.RosterImportRow
+	for i := headerIdx + 1; i < len(rows); i++ {
+		row := rows[i]
+		if spreadsheet.IsBlank(row) {
+			continue
+		}
+		if maxRows > 0 && len(parsed) >= maxRows {
+			return nil, fmt.Errorf("%w (%d)", ErrTooManyRows, maxRows)
+		}
+
+		rawStatus := cell(row, colStatus)
+		r := models.RosterImportRow{
+			RowNumber: i + 1,
+			Email:     strings.ToLower(cell(row, colEmail)),
+			UserName:  cell(row, colUserName),
+			Status:    rawStatus,
+			Result:    rosterrepo.RowPending,
+		}
+
+		fail := func(msg string) {
+			r.Result = rosterrepo.RowError
+			r.Message = msg
+		}
+
+		status, ok := statusAliases[strings.ToLower(rawStatus)]
+		switch {
+		case r.Email == "":
+			fail("el email es obligatorio")
+		case !validEmail(r.Email):
+			fail(fmt.Sprintf("email inválido %q", r.Email))
+		case !ok:
+			fail(fmt.Sprintf("estado inválido %q (debe ser: active, dropped, completed)", rawStatus))
+		case len([]rune(r.UserName)) > maxUserName:
+			fail(fmt.Sprintf("el nombre de usuario supera %d caracteres", maxUserName))
+		default:
+			r.Status = status
+			if first, dup := emails[r.Email]; dup {
+				fail(fmt.Sprintf("email duplicado: coincide con la fila %d", first))
+			} else {
+				emails[r.Email] = r.RowNumber
+			}
+		}
+
+		parsed = append(parsed, r)
+	}
+
+	return parsed, nil
+}
+
+// validEmail acepta solo direcciones simples (sin nombre ni <>).
+func validEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s && strings.Contains(s[strings.LastIndex(s, "@")+1:], ".")
+}
+
+// baseUserName propone un nombre de usuario a partir de la parte local del email.
+func baseUserName(email string) string {
+	local, _, _ := strings.Cut(email, "@")
+	var b strings.Builder
+	for _, r := range strings.ToLower(local) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+	if len([]rune(name)) < 3 {
+		name = "estudiante"
+	}
+	if runes := []rune(name); len(runes) > maxUserName-4 {
+		name = string(runes[:maxUserName-4]) // espacio para el sufijo numérico
+	}
+	return name
+}
+
+// normalizeHeader pasa la cabecera a minúsculas, sin tildes y con guiones bajos.
+func normalizeHeader(s string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		r = stripAccent(r)
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			underscore = false
+		case b.Len() > 0 && !underscore:
+			b.WriteRune('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// stripAccent reemplaza las vocales con tilde y la ü por su forma simple.
+func stripAccent(r rune) rune {
+	switch r {
+	case 'á', 'à', 'ä', 'â':
+		return 'a'
+	case 'é', 'è', 'ë', 'ê':
+		return 'e'
+	case 'í', 'ì', 'ï', 'î':
+		return 'i'
+	case 'ó', 'ò', 'ö', 'ô':
+		return 'o'
+	case 'ú', 'ù', 'ü', 'û':
+		return 'u'
+	}
+	return r
+}
@@ -0,0 +1,600 @@
+package rosterservice
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/auth/password"
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
+	rosterdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/roster_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	accounttokenrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/account_token_repo"
+	enrollementrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/enrollment_repo"
+	modulerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/module_repo"
+	rosterrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/roster_repo"
+	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/spreadsheet"
+)
+
+// maxUserNameAttempts es la cantidad de sufijos probados al generar un nombre de usuario.
+const maxUserNameAttempts = 50
+
+type rosterService struct {
+	txm            database.TxManager
+	moduleRepo     modulerepo.ModuleRepo
+	rosterRepo     rosterrepo.RosterRepo
+	userRepo       userrepo.UserRepo
+	enrollmentRepo enrollementrepo.EnrollmentRepo
+	tokenRepo      accounttokenrepo.AccountTokenRepo
+	hasher         password.Hasher
+	cfg            Config
+	logger         *slog.Logger
+}
+
+// NewRosterService crea una instancia de IRosterService. txm procesa cada fila en
+// su propia transacción (usuario, inscripción y progreso juntos).
+func NewRosterService(
+	txm database.TxManager,
+	moduleRepo modulerepo.ModuleRepo,
+	rosterRepo rosterrepo.RosterRepo,
+	userRepo userrepo.UserRepo,
+	enrollmentRepo enrollementrepo.EnrollmentRepo,
+	tokenRepo accounttokenrepo.AccountTokenRepo,
+	hasher password.Hasher,
+	cfg Config,
+	logger *slog.Logger,
+) IRosterService {
+	return &rosterService{
+		txm:            txm,
+		moduleRepo:     moduleRepo,
+		rosterRepo:     rosterRepo,
+		userRepo:       userRepo,
+		enrollmentRepo: enrollmentRepo,
+		tokenRepo:      tokenRepo,
+		hasher:         hasher,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// rowSecrets son las credenciales generadas para una fila. Solo viajan en la
+// respuesta de la ejecución que creó al usuario; nunca se guardan.
+type rowSecrets struct {
+	inviteURL string
+	password  string
+}
+
+// StartRosterImport implements IRosterService.
+func (s *rosterService) StartRosterImport(ctx context.Context, req rosterdto.StartRosterImportRequestDTO, data []byte) (rosterdto.RosterImportReportDTO, error) {
+	if req.ModuleID == 0 {
+		return rosterdto.RosterImportReportDTO{}, rosterrepo.ErrInvalidModuleID
+	}
+	credentials := req.GetCredentials()
+	if credentials != rosterrepo.CredentialsInvite && credentials != rosterrepo.CredentialsPassword {
+		return rosterdto.RosterImportReportDTO{}, rosterrepo.ErrInvalidCredentials
+	}
+
+	if _, err := s.moduleRepo.ModuleByID(ctx, req.ModuleID); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get module for roster import",
+			"error", err,
+			"module_id", req.ModuleID,
+		)
+		return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to get module: %w", err)
+	}
+
+	rows, err := spreadsheet.Read(req.FileName, data)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to read roster file",
+			"error", err,
+			"file_name", req.FileName,
+		)
+		return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to read roster file: %w", err)
+	}
+	parsed, err := parseRoster(rows, s.cfg.MaxRows)
+	if err != nil {
+		return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to parse roster file: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	fileHash := hex.EncodeToString(sum[:])
+
+	job, err := s.rosterRepo.RosterImportByFileHash(ctx, req.ModuleID, fileHash)
+	if err != nil && !errors.Is(err, rosterrepo.ErrRosterImportNotFound) {
+		s.logger.ErrorContext(ctx, "Failed to get roster import by file hash",
+			"error", err,
+			"module_id", req.ModuleID,
+		)
+		return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to get roster import: %w", err)
+	}
+
+	if job == nil {
+		job = &models.RosterImport{
+			ModuleID:    req.ModuleID,
+			FileHash:    fileHash,
+			FileName:    req.FileName,
+			CreatedByID: req.CreatedByID,
+			Credentials: credentials,
+			DropMissing: req.DropMissing,
+			Status:      rosterrepo.ImportPending,
+			TotalRows:   len(parsed),
+		}
+		// Las filas inválidas quedan resueltas desde el inicio
+		for _, row := range parsed {
+			if row.Result == rosterrepo.RowError {
+				job.Errors++
+				job.ProcessedRows++
+			}
+		}
+
+		created, err := s.rosterRepo.CreateRosterImport(ctx, job, parsed)
+		switch {
+		case errors.Is(err, rosterrepo.ErrRosterImportExists):
+			// Otra petición subió el mismo archivo al mismo tiempo
+			job, err = s.rosterRepo.RosterImportByFileHash(ctx, req.ModuleID, fileHash)
+			if err != nil {
+				return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to get roster import: %w", err)
+			}
+		case err != nil:
+			s.logger.ErrorContext(ctx, "Failed to create roster import",
+				"error", err,
+				"module_id", req.ModuleID,
+				"file_name", req.FileName,
+			)
+			return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to create roster import: %w", err)
+		default:
+			job = created
+		}
+	} else {
+		s.logger.InfoContext(ctx, "Roster file already imported, reusing job",
+			"import_id", job.ID,
+			"status", job.Status,
+		)
+	}
+
+	if job.Status == rosterrepo.ImportCompleted {
+		return s.report(ctx, job, nil)
+	}
+	return s.run(ctx, job)
+}
+
+// ResumeRosterImport implements IRosterService.
+func (s *rosterService) ResumeRosterImport(ctx context.Context, id uint) (rosterdto.RosterImportReportDTO, error) {
+	job, err := s.rosterRepo.RosterImportByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get roster import",
+			"error", err,
+			"import_id", id,
+		)
+		return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to get roster import: %w", err)
+	}
+	if job.Status == rosterrepo.ImportCompleted {
+		return rosterdto.RosterImportReportDTO{}, ErrImportNotResumable
+	}
+	return s.run(ctx, job)
+}
+
+// GetRosterImport implements IRosterService.
+func (s *rosterService) GetRosterImport(ctx context.Context, id uint) (rosterdto.RosterImportReportDTO, error) {
+	job, err := s.rosterRepo.RosterImportByID(ctx, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get roster import",
+			"error", err,
+			"import_id", id,
+		)
+		return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to get roster import: %w", err)
+	}
+	return s.report(ctx, job, nil)
+}
+
+// ListRosterImports implements IRosterService.
+func (s *rosterService) ListRosterImports(ctx context.Context, req rosterdto.ListRosterImportsRequestDTO) ([]rosterdto.RosterImportReportDTO, error) {
+	jobs, err := s.rosterRepo.ListRosterImports(ctx, req.ToRepoFilter())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list roster imports",
+			"error", err,
+			"module_id", req.ModuleID,
+		)
+		return nil, fmt.Errorf("failed to list roster imports: %w", err)
+	}
+
+	result := make([]rosterdto.RosterImportReportDTO, 0, len(jobs))
+	for i := range jobs {
+		result = append(result, rosterdto.FromRosterImportModel(&jobs[i], nil))
+	}
+	return result, nil
+}
+
+// run procesa las filas pendientes y, si corresponde, da de baja a los ausentes.
+// Si falla la base de datos la importación queda en failed y se puede reanudar.
+func (s *rosterService) run(ctx context.Context, job *models.RosterImport) (rosterdto.RosterImportReportDTO, error) {
+	// El reclamo es atómico: una segunda petición sobre la misma importación recibe
+	// ErrRosterImportBusy en lugar de procesar las mismas filas
+	claimed, err := s.rosterRepo.ClaimRosterImport(ctx, job.ID, time.Now().Add(-s.cfg.StaleAfter))
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to claim roster import",
+			"error", err,
+			"import_id", job.ID,
+		)
+		return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to start roster import: %w", err)
+	}
+	job = claimed
+
+	// Las credenciales solo existen en memoria: se devuelven aunque la ejecución falle
+	secrets := make(map[uint]rowSecrets)
+	var issued []models.RosterImportRow
+	for {
+		pending, err := s.rosterRepo.PendingRosterRows(ctx, job.ID, s.cfg.BatchSize)
+		if err != nil {
+			return s.fail(ctx, job, issued, secrets, fmt.Errorf("getting pending rows: %w", err))
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		for i := range pending {
+			secret, err := s.processRow(ctx, job, &pending[i])
+			if err != nil {
+				return s.fail(ctx, job, issued, secrets, fmt.Errorf("processing row %d: %w", pending[i].RowNumber, err))
+			}
+			if secret != (rowSecrets{}) {
+				secrets[pending[i].ID] = secret
+				issued = append(issued, pending[i])
+			}
+		}
+	}
+
+	if job.DropMissing {
+		if job.Errors > 0 {
+			job.LastError = "no se aplicaron bajas: la nómina tiene filas con errores"
+		} else if err := s.dropMissing(ctx, job); err != nil {
+			return s.fail(ctx, job, issued, secrets, fmt.Errorf("dropping missing students: %w", err))
+		}
+	}
+
+	finished := time.Now()
+	job.Status = rosterrepo.ImportCompleted
+	job.FinishedAt = &finished
+	if err := s.rosterRepo.SaveRosterImport(ctx, job); err != nil {
+		return s.fail(ctx, job, issued, secrets, fmt.Errorf("completing import: %w", err))
+	}
+
+	s.logger.InfoContext(ctx, "Roster import completed",
+		"import_id", job.ID,
+		"module_id", job.ModuleID,
+		"rows", job.TotalRows,
+		"created_users", job.CreatedUsers,
+		"matched_users", job.MatchedUsers,
+		"enrolled", job.Enrolled,
+		"status_updated", job.StatusUpdated,
+		"dropped_missing", job.DroppedMissing,
+		"errors", job.Errors,
+	)
+	return s.report(ctx, job, secrets)
+}
+
+// fail deja la importación en failed con el motivo y devuelve el error original
+// junto con el reporte parcial, que trae las credenciales de issued. Si la base no
+// responde, el reporte se arma solo con esas filas.
+func (s *rosterService) fail(ctx context.Context, job *models.RosterImport, issued []models.RosterImportRow, secrets map[uint]rowSecrets, cause error) (rosterdto.RosterImportReportDTO, error) {
+	s.logger.ErrorContext(ctx, "Roster import failed",
+		"error", cause,
+		"import_id", job.ID,
+		"issued_credentials", len(issued),
+	)
+	job.Status = rosterrepo.ImportFailed
+	job.LastError = cause.Error()
+	if err := s.rosterRepo.SaveRosterImport(ctx, job); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to mark roster import as failed",
+			"error", err,
+			"import_id", job.ID,
+		)
+	}
+
+	report, err := s.report(ctx, job, secrets)
+	if err != nil {
+		report = withSecrets(rosterdto.FromRosterImportModel(job, issued), issued, secrets)
+	}
+	return report, fmt.Errorf("failed to run roster import: %w", cause)
+}
+
+// processRow crea o encuentra al usuario, ajusta su inscripción y guarda el
+// resultado de la fila junto con los contadores, todo en una transacción. Los
+// problemas propios de la fila se registran como error sin detener la importación.
+func (s *rosterService) processRow(ctx context.Context, job *models.RosterImport, row *models.RosterImportRow) (rowSecrets, error) {
+	var secret rowSecrets
+	updatedJob := *job
+	updatedRow := *row
+
+	err := s.txm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		secret, err = s.applyRow(ctx, &updatedJob, &updatedRow)
+		if err != nil {
+			return err
+		}
+		return s.saveProgress(ctx, &updatedJob, &updatedRow)
+	})
+
+	var rowErr *rowError
+	if errors.As(err, &rowErr) {
+		updatedJob, updatedRow = *job, *row
+		updatedRow.Result = rosterrepo.RowError
+		updatedRow.Message = rowErr.msg
+		updatedJob.Errors++
+		updatedJob.ProcessedRows++
+		secret = rowSecrets{}
+		err = s.txm.Transaction(ctx, func(ctx context.Context) error {
+			return s.saveProgress(ctx, &updatedJob, &updatedRow)
+		})
+	}
+	if err != nil {
+		return rowSecrets{}, err
+	}
+
+	*job, *row = updatedJob, updatedRow
+	return secret, nil
+}
+
+// applyRow resuelve el usuario y la inscripción de la fila dentro de la
+// transacción de ctx.
+func (s *rosterService) applyRow(ctx context.Context, job *models.RosterImport, row *models.RosterImportRow) (rowSecrets, error) {
+	user, err := s.userRepo.UserByEmail(ctx, row.Email)
+	if err != nil && !errors.Is(err, userrepo.ErrUserNotFound) {
+		return rowSecrets{}, fmt.Errorf("getting user by email: %w", err)
+	}
+
+	var secret rowSecrets
+	if user != nil {
+		row.Result = rosterrepo.RowMatched
+		job.MatchedUsers++
+	} else {
+		user, secret, err = s.createStudent(ctx, job, row)
+		if err != nil {
+			return rowSecrets{}, err
+		}
+		row.Result = rosterrepo.RowCreated
+		job.CreatedUsers++
+	}
+	row.UserID = &user.ID
+	row.UserName = user.UserName
+
+	enrollment, err := s.enrollmentRepo.GetUserEnrollment(ctx, user.ID, job.ModuleID)
+	if err != nil && !errors.Is(err, enrollementrepo.ErrEnrollmentNotFound) {
+		return rowSecrets{}, fmt.Errorf("getting enrollment: %w", err)
+	}
+
+	switch {
+	case enrollment == nil:
+		enrollment, err = s.enrollmentRepo.CreateEnrollment(ctx, &models.Enrollment{
+			UserID:   user.ID,
+			ModuleID: job.ModuleID,
+			Status:   row.Status,
+		})
+		if err != nil {
+			return rowSecrets{}, fmt.Errorf("creating enrollment: %w", err)
+		}
+		row.Action = rosterrepo.ActionEnrolled
+		job.Enrolled++
+	case enrollment.Status == row.Status:
+		row.Action = rosterrepo.ActionUnchanged
+		job.Unchanged++
+	default:
+		err = s.enrollmentRepo.UpdateEnrollmentStatus(ctx, enrollment.ID, row.Status)
+		if errors.Is(err, enrollementrepo.ErrCannotDropCompleted) {
+			return rowSecrets{}, &rowError{msg: "no se puede dar de baja una inscripción completada"}
+		}
+		if err != nil {
+			return rowSecrets{}, fmt.Errorf("updating enrollment status: %w", err)
+		}
+		row.Action = rosterrepo.ActionUpdated
+		job.StatusUpdated++
+	}
+	row.EnrollmentID = &enrollment.ID
+	job.ProcessedRows++
+
+	return secret, nil
+}
+
+// createStudent crea la cuenta de estudiante con una contraseña aleatoria y genera
+// la credencial según el modo de la importación.
+func (s *rosterService) createStudent(ctx context.Context, job *models.RosterImport, row *models.RosterImportRow) (*models.User, rowSecrets, error) {
+	userName, err := s.availableUserName(ctx, row)
+	if err != nil {
+		return nil, rowSecrets{}, err
+	}
+
+	password, err := securetoken.Password(s.cfg.PasswordLength)
+	if err != nil {
+		return nil, rowSecrets{}, err
+	}
+	hash, err := s.hasher.HashPassword(password)
+	if err != nil {
+		return nil, rowSecrets{}, fmt.Errorf("hashing password: %w", err)
+	}
+
+	// En modo contraseña el email se da por verificado: lo informa la institución
+	// y el estudiante recibe la contraseña por otro medio, no por correo. En modo
+	// invitación lo verifica el enlace al aceptarlo.
+	var verifiedAt *time.Time
+	if job.Credentials == rosterrepo.CredentialsPassword {
+		now := time.Now()
+		verifiedAt = &now
+	}
+
+	user, err := s.userRepo.CreateUser(ctx, &models.User{
+		UserName:        userName,
+		Email:           row.Email,
+		Role:            "student",
+		PasswordHash:    hash,
+		EmailVerifiedAt: verifiedAt,
+	})
+	if err != nil {
+		return nil, rowSecrets{}, fmt.Errorf("creating user: %w", err)
+	}
+
+	if job.Credentials == rosterrepo.CredentialsPassword {
+		return user, rowSecrets{password: password}, nil
+	}
+
+	// Modo invitación: la contraseña aleatoria no se entrega; el estudiante define
+	// la suya con el enlace.
+	token, tokenHash, err := securetoken.Generate(securetoken.DefaultBytes)
+	if err != nil {
+		return nil, rowSecrets{}, err
+	}
+	if _, err := s.tokenRepo.CreateAccountToken(ctx, &models.AccountToken{
+		UserID:    user.ID,
+		Purpose:   accounttokenrepo.PurposeInvite,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.cfg.InviteTTL),
+	}); err != nil {
+		return nil, rowSecrets{}, fmt.Errorf("creating invite token: %w", err)
+	}
+
+	return user, rowSecrets{inviteURL: s.cfg.InviteBaseURL + "?token=" + url.QueryEscape(token)}, nil
+}
+
+// availableUserName usa el nombre de la nómina si viene (debe estar libre) o lo
+// deriva del email, agregando un sufijo numérico si ya está en uso.
+func (s *rosterService) availableUserName(ctx context.Context, row *models.RosterImportRow) (string, error) {
+	taken := func(name string) (bool, error) {
+		_, err := s.userRepo.UserByUsername(ctx, name)
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("getting user by username: %w", err)
+		}
+		return true, nil
+	}
+
+	if row.UserName != "" {
+		used, err := taken(row.UserName)
+		if err != nil {
+			return "", err
+		}
+		if used {
+			return "", &rowError{msg: fmt.Sprintf("el nombre de usuario %q ya está en uso", row.UserName)}
+		}
+		return row.UserName, nil
+	}
+
+	base := baseUserName(row.Email)
+	candidate := base
+	for i := 2; i <= maxUserNameAttempts+1; i++ {
+		used, err := taken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return candidate, nil
+		}
+		candidate = base + strconv.Itoa(i)
+	}
+	return "", &rowError{msg: "no se pudo generar un nombre de usuario libre; indíquelo en la columna user_name"}
+}
+
+// saveProgress guarda la fila y los contadores de la importación dentro de la
+// transacción de ctx.
+func (s *rosterService) saveProgress(ctx context.Context, job *models.RosterImport, row *models.RosterImportRow) error {
+	if err := s.rosterRepo.SaveRosterRow(ctx, row); err != nil {
+		return fmt.Errorf("saving row result: %w", err)
+	}
+	if err := s.rosterRepo.SaveRosterImport(ctx, job); err != nil {
+		return fmt.Errorf("saving import progress: %w", err)
+	}
+	return nil
+}
+
+// dropMissing da de baja las inscripciones activas del módulo cuyos usuarios no
+// aparecen en la nómina. Cada baja queda como fila con RowNumber 0.
+func (s *rosterService) dropMissing(ctx context.Context, job *models.RosterImport) error {
+	rows, err := s.rosterRepo.RosterRows(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	listed := make(map[uint]bool, len(rows))
+	for _, row := range rows {
+		if row.UserID != nil {
+			listed[*row.UserID] = true
+		}
+	}
+
+	current, err := s.enrollmentRepo.EnrollmentsByModule(ctx, job.ModuleID)
+	if err != nil {
+		return err
+	}
+
+	for _, enrollment := range current {
+		if enrollment.Status != enrollementrepo.StatusActive || listed[enrollment.UserID] {
+			continue
+		}
+
+		updatedJob := *job
+		err := s.txm.Transaction(ctx, func(ctx context.Context) error {
+			user, err := s.userRepo.UserByID(ctx, enrollment.UserID)
+			if err != nil {
+				return fmt.Errorf("getting user %d: %w", enrollment.UserID, err)
+			}
+			if err := s.enrollmentRepo.UpdateEnrollmentStatus(ctx, enrollment.ID, enrollementrepo.StatusDropped); err != nil {
+				return fmt.Errorf("dropping enrollment %d: %w", enrollment.ID, err)
+			}
+
+			userID, enrollmentID := enrollment.UserID, enrollment.ID
+			if _, err := s.rosterRepo.CreateRosterRow(ctx, &models.RosterImportRow{
+				ImportID:     job.ID,
+				Email:        user.Email,
+				UserName:     user.UserName,
+				Status:       enrollementrepo.StatusDropped,
+				Result:       rosterrepo.RowDropped,
+				UserID:       &userID,
+				EnrollmentID: &enrollmentID,
+				Message:      "no aparece en la nómina",
+			}); err != nil {
+				return fmt.Errorf("recording dropped enrollment: %w", err)
+			}
+
+			updatedJob.DroppedMissing++
+			return s.rosterRepo.SaveRosterImport(ctx, &updatedJob)
+		})
+		if err != nil {
+			return err
+		}
+		*job = updatedJob
+	}
+	return nil
+}
+
+// report arma el reporte con todas las filas y agrega las credenciales generadas
+// en esta ejecución.
+func (s *rosterService) report(ctx context.Context, job *models.RosterImport, secrets map[uint]rowSecrets) (rosterdto.RosterImportReportDTO, error) {
+	rows, err := s.rosterRepo.RosterRows(ctx, job.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get roster rows",
+			"error", err,
+			"import_id", job.ID,
+		)
+		return rosterdto.RosterImportReportDTO{}, fmt.Errorf("failed to get roster rows: %w", err)
+	}
+
+	return withSecrets(rosterdto.FromRosterImportModel(job, rows), rows, secrets), nil
+}
+
+// withSecrets completa las filas del reporte (armado a partir de rows) con las
+// credenciales generadas.
+func withSecrets(report rosterdto.RosterImportReportDTO, rows []models.RosterImportRow, secrets map[uint]rowSecrets) rosterdto.RosterImportReportDTO {
+	for i := range rows {
+		if secret, ok := secrets[rows[i].ID]; ok {
+			report.Rows[i].InviteURL = secret.inviteURL
+			report.Rows[i].TemporaryPassword = secret.password
+		}
+	}
+	return report
+}
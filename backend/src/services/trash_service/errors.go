@@ -0,0 +1,8 @@
+package trashservice
+
+import "github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+
+var (
+	// Errores de validación
+	ErrUnknownEntity = apperror.New(apperror.Validation, "trash.unknown_entity", "trash error: la entidad no tiene papelera: debe ser users, modules o topics")
+)
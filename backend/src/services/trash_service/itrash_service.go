@@ -0,0 +1,37 @@
+package trashservice
+
+import (
+	"context"
+	"time"
+
+	trashdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/trash_dto"
+)
+
+// ITrashService administra la papelera: los registros con soft delete se
+// pueden listar y restaurar hasta que vence la retención y se purgan.
+type ITrashService interface {
+	ListTrash(ctx context.Context, req trashdto.ListTrashRequestDTO) (trashdto.ListTrashResponseDTO, error)
+	Restore(ctx context.Context, req trashdto.RestoreRequestDTO) (trashdto.TrashItemDTO, error)
+
+	// PurgeExpired borra definitivamente lo que lleva más de Retention en la
+	// papelera y devuelve cuántos registros se purgaron.
+	PurgeExpired(ctx context.Context) (int, error)
+	// Run purga periódicamente hasta que ctx se cancele.
+	Run(ctx context.Context)
+}
+
+// Config agrupa los parámetros de la papelera.
+type Config struct {
+	Retention     time.Duration // Tiempo en la papelera antes de la purga
+	PurgeInterval time.Duration // Espera entre purgas
+	BatchSize     int           // Registros leídos por consulta durante la purga
+}
+
+// DefaultConfig devuelve la configuración por defecto de la papelera.
+func DefaultConfig() Config {
+	return Config{
+		Retention:     30 * 24 * time.Hour,
+		PurgeInterval: 6 * time.Hour,
+		BatchSize:     100,
+	}
+}
@@ -0,0 +1,229 @@
+package trashservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	trashdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/trash_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	modulerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/module_repo"
+	topicrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/topic_repo"
+	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+	erasureservice "github.com/Dieg0Code/aiep-agent/src/services/erasure_service"
+)
+
+type trashService struct {
+	userRepo   userrepo.UserRepo
+	moduleRepo modulerepo.ModuleRepo
+	topicRepo  topicrepo.TopicRepo
+	eraser     erasureservice.IErasureService
+	cfg        Config
+	logger     *slog.Logger
+}
+
+// NewTrashService crea una instancia de ITrashService. Los usuarios vencidos se
+// purgan con eraser, que borra también sus datos personales en otras tablas.
+func NewTrashService(
+	userRepo userrepo.UserRepo,
+	moduleRepo modulerepo.ModuleRepo,
+	topicRepo topicrepo.TopicRepo,
+	eraser erasureservice.IErasureService,
+	cfg Config,
+	logger *slog.Logger,
+) ITrashService {
+	return &trashService{
+		userRepo:   userRepo,
+		moduleRepo: moduleRepo,
+		topicRepo:  topicRepo,
+		eraser:     eraser,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+// ListTrash implements ITrashService.
+func (t *trashService) ListTrash(ctx context.Context, req trashdto.ListTrashRequestDTO) (trashdto.ListTrashResponseDTO, error) {
+	var (
+		response trashdto.ListTrashResponseDTO
+		err      error
+	)
+
+	switch req.Entity {
+	case trashdto.EntityUsers:
+		page, listErr := t.userRepo.ListUsers(ctx, userrepo.UserFilter{
+			Search: req.GetSearch(),
+			Spec:   req.ToSpec(),
+			Page:   req.ToParams(),
+		})
+		err = listErr
+		response = trashdto.MakeListTrashResponse(page, &req, func(u *models.User) trashdto.TrashItemDTO {
+			return trashdto.FromUser(u, t.cfg.Retention)
+		})
+	case trashdto.EntityModules:
+		page, listErr := t.moduleRepo.ListModules(ctx, modulerepo.ModuleFilter{
+			Search: req.GetSearch(),
+			Spec:   req.ToSpec(),
+			Page:   req.ToParams(),
+		})
+		err = listErr
+		response = trashdto.MakeListTrashResponse(page, &req, func(m *models.Module) trashdto.TrashItemDTO {
+			return trashdto.FromModule(m, t.cfg.Retention)
+		})
+	case trashdto.EntityTopics:
+		page, listErr := t.topicRepo.ListTopics(ctx, topicrepo.TopicFilter{
+			Search: req.GetSearch(),
+			Spec:   req.ToSpec(),
+			Page:   req.ToParams(),
+		})
+		err = listErr
+		response = trashdto.MakeListTrashResponse(page, &req, func(tp *models.Topic) trashdto.TrashItemDTO {
+			return trashdto.FromTopic(tp, t.cfg.Retention)
+		})
+	default:
+		return trashdto.ListTrashResponseDTO{}, ErrUnknownEntity
+	}
+
+	if err != nil {
+		t.logger.ErrorContext(ctx, "Failed to list trash",
+			"error", err,
+			"entity", req.Entity,
+		)
+		return trashdto.ListTrashResponseDTO{}, fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	return response, nil
+}
+
+// Restore implements ITrashService.
+func (t *trashService) Restore(ctx context.Context, req trashdto.RestoreRequestDTO) (trashdto.TrashItemDTO, error) {
+	var (
+		item trashdto.TrashItemDTO
+		err  error
+	)
+
+	switch req.Entity {
+	case trashdto.EntityUsers:
+		var user *models.User
+		if user, err = t.userRepo.RestoreUser(ctx, req.ID); err == nil {
+			item = trashdto.FromUser(user, t.cfg.Retention)
+		}
+	case trashdto.EntityModules:
+		var module *models.Module
+		if module, err = t.moduleRepo.RestoreModule(ctx, req.ID); err == nil {
+			item = trashdto.FromModule(module, t.cfg.Retention)
+		}
+	case trashdto.EntityTopics:
+		var topic *models.Topic
+		if topic, err = t.topicRepo.RestoreTopic(ctx, req.ID); err == nil {
+			item = trashdto.FromTopic(topic, t.cfg.Retention)
+		}
+	default:
+		return trashdto.TrashItemDTO{}, ErrUnknownEntity
+	}
+
+	if err != nil {
+		t.logger.ErrorContext(ctx, "Failed to restore from trash",
+			"error", err,
+			"entity", req.Entity,
+			"id", req.ID,
+		)
+		return trashdto.TrashItemDTO{}, fmt.Errorf("failed to restore from trash: %w", err)
+	}
+
+	t.logger.InfoContext(ctx, "Restored from trash",
+		"entity", req.Entity,
+		"id", req.ID,
+	)
+	return item, nil
+}
+
+// Run implements ITrashService.
+func (t *trashService) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		// Los errores ya quedan registrados; se reintenta en la próxima vuelta
+		_, _ = t.PurgeExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTarget es una entidad con papelera, vista desde la purga.
+type purgeTarget struct {
+	entity string
+	ids    func(ctx context.Context, deletedBefore time.Time, afterID uint, limit int) ([]uint, error)
+	purge  func(ctx context.Context, id uint) error
+}
+
+// PurgeExpired implements ITrashService.
+func (t *trashService) PurgeExpired(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-t.cfg.Retention)
+
+	// Temas antes que módulos: los temas de un módulo purgado se van con él
+	targets := []purgeTarget{
+		{trashdto.EntityTopics, t.topicRepo.PurgeableTopicIDs, t.topicRepo.PurgeTopic},
+		{trashdto.EntityModules, t.moduleRepo.PurgeableModuleIDs, t.moduleRepo.PurgeModule},
+		{trashdto.EntityUsers, t.userRepo.PurgeableUserIDs, t.eraser.PurgeUser},
+	}
+
+	purged := 0
+	for _, target := range targets {
+		n, err := t.purgeEntity(ctx, target, cutoff)
+		purged += n
+		if err != nil {
+			return purged, err
+		}
+	}
+
+	if purged > 0 {
+		t.logger.InfoContext(ctx, "Trash purged",
+			"purged", purged,
+			"deleted_before", cutoff,
+		)
+	}
+	return purged, nil
+}
+
+// purgeEntity recorre por id lo vencido de una entidad. Un registro que no se
+// puede borrar (p. ej. porque otra tabla aún lo referencia) se registra y se
+// salta para no frenar al resto.
+func (t *trashService) purgeEntity(ctx context.Context, target purgeTarget, cutoff time.Time) (int, error) {
+	purged := 0
+	var afterID uint
+
+	for {
+		ids, err := target.ids(ctx, cutoff, afterID, t.cfg.BatchSize)
+		if err != nil {
+			t.logger.ErrorContext(ctx, "Failed to list expired trash",
+				"error", err,
+				"entity", target.entity,
+			)
+			return purged, fmt.Errorf("failed to list expired %s: %w", target.entity, err)
+		}
+
+		for _, id := range ids {
+			if err := target.purge(ctx, id); err != nil {
+				t.logger.WarnContext(ctx, "Failed to purge from trash",
+					"error", err,
+					"entity", target.entity,
+					"id", id,
+				)
+				continue
+			}
+			purged++
+		}
+
+		if len(ids) < t.cfg.BatchSize {
+			return purged, nil
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
@@ -0,0 +1,51 @@
+package usageservice
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+// ErrQuotaExceeded permite detectar con errors.Is cualquier cuota excedida.
+var ErrQuotaExceeded = apperror.New(apperror.RateLimited, "usage.quota_exceeded", "usage error: cuota de uso excedida")
+
+// Periodos de cuota
+const (
+	PeriodDaily   = "daily"
+	PeriodMonthly = "monthly"
+)
+
+// QuotaExceededError describe qué cuota se excedió y cuándo se restablece.
+type QuotaExceededError struct {
+	Period   string
+	Limit    int64
+	Used     int64
+	ResetsAt time.Time
+}
+
+// Error devuelve un mensaje apto para mostrar al estudiante.
+func (e *QuotaExceededError) Error() string {
+	periodo := "diaria"
+	if e.Period == PeriodMonthly {
+		periodo = "mensual"
+	}
+	return fmt.Sprintf("alcanzaste tu cuota %s de uso del asistente (%d de %d tokens); se restablece el %s",
+		periodo, e.Used, e.Limit, date.FormatDateTime(e.ResetsAt))
+}
+
+// Is permite que errors.Is(err, ErrQuotaExceeded) sea verdadero.
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// AppError implements apperror.Coder: conserva el detalle de la cuota en el mensaje.
+func (e *QuotaExceededError) AppError() *apperror.Error {
+	return ErrQuotaExceeded.WithDetail(e.Error(), e.Period, map[string]string{
+		"used":      strconv.FormatInt(e.Used, 10),
+		"limit":     strconv.FormatInt(e.Limit, 10),
+		"resets_at": date.FormatDateTime(e.ResetsAt),
+	})
+}
@@ -0,0 +1,81 @@
+package usageservice
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	usagedto "github.com/Dieg0Code/aiep-agent/src/data/dtos/usage_dto"
+)
+
+// IUsageService registra el consumo del LLM, aplica cuotas y genera reportes.
+type IUsageService interface {
+	// RecordUsage guarda una llamada atribuida al llm.Scope del contexto.
+	RecordUsage(ctx context.Context, rec UsageRecord) error
+	// CheckQuota devuelve un *QuotaExceededError si el usuario agotó su cuota.
+	CheckQuota(ctx context.Context, userID uint, role string) error
+	QuotaStatus(ctx context.Context, userID uint) (usagedto.QuotaStatusDTO, error)
+	UsageReport(ctx context.Context, req usagedto.UsageReportRequestDTO) (usagedto.UsageReportResponseDTO, error)
+}
+
+// UsageRecord es el consumo medido de una llamada.
+type UsageRecord struct {
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	Latency          time.Duration
+}
+
+// Quota limita los tokens por periodo. 0 = sin límite.
+type Quota struct {
+	DailyTokens   int64
+	MonthlyTokens int64
+}
+
+// Price es el costo en USD por millón de tokens.
+type Price struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// Config agrupa cuotas por rol y precios por modelo.
+type Config struct {
+	Quotas   map[string]Quota // Por rol: student | teacher | admin
+	Pricing  map[string]Price // Por prefijo de modelo (gana el prefijo más largo)
+	Location *time.Location   // Zona horaria para el corte diario/mensual
+}
+
+// DefaultConfig devuelve cuotas conservadoras para estudiantes y precios públicos de OpenAI.
+func DefaultConfig() Config {
+	return Config{
+		Quotas: map[string]Quota{
+			"student": {DailyTokens: 50_000, MonthlyTokens: 1_000_000},
+			"teacher": {DailyTokens: 200_000, MonthlyTokens: 4_000_000},
+			"admin":   {},
+		},
+		Pricing: map[string]Price{
+			"gpt-4o-mini":            {InputPerMillion: 0.15, OutputPerMillion: 0.60},
+			"gpt-4o":                 {InputPerMillion: 2.50, OutputPerMillion: 10.00},
+			"gpt-4.1-mini":           {InputPerMillion: 0.40, OutputPerMillion: 1.60},
+			"gpt-4.1":                {InputPerMillion: 2.00, OutputPerMillion: 8.00},
+			"text-embedding-3-small": {InputPerMillion: 0.02},
+			"text-embedding-3-large": {InputPerMillion: 0.13},
+		},
+		Location: time.UTC,
+	}
+}
+
+// Cost calcula el costo en USD de una llamada según el precio del modelo.
+func (c Config) Cost(model string, promptTokens, completionTokens int) float64 {
+	var best string
+	for prefix := range c.Pricing {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return 0
+	}
+	price := c.Pricing[best]
+	return (float64(promptTokens)*price.InputPerMillion + float64(completionTokens)*price.OutputPerMillion) / 1_000_000
+}
@@ -0,0 +1,107 @@
+package usageservice
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/llm"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/tokenizer"
+	"github.com/pgvector/pgvector-go"
+)
+
+type meteredCompleter struct {
+	inner  llm.ChatCompleter
+	usage  IUsageService
+	logger *slog.Logger
+}
+
+// NewMeteredCompleter envuelve un llm.ChatCompleter y registra el consumo de
+// cada llamada contra el llm.Scope del contexto. Un fallo al registrar no
+// interrumpe la respuesta.
+func NewMeteredCompleter(inner llm.ChatCompleter, usage IUsageService, logger *slog.Logger) llm.ChatCompleter {
+	return &meteredCompleter{
+		inner:  inner,
+		usage:  usage,
+		logger: logger,
+	}
+}
+
+// Complete implements llm.ChatCompleter.
+func (m *meteredCompleter) Complete(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	start := time.Now()
+	resp, err := m.inner.Complete(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+
+	ctx = ensureOperation(ctx, llm.OperationChat)
+	err = m.usage.RecordUsage(ctx, UsageRecord{
+		Model:            model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		Latency:          time.Since(start),
+	})
+	if err != nil {
+		m.logger.WarnContext(ctx, "LLM usage was not recorded", "error", err, "model", model)
+	}
+
+	return resp, nil
+}
+
+type meteredEmbedder struct {
+	inner      llm.Embedder
+	model      string
+	tokenizers *tokenizer.Registry
+	usage      IUsageService
+	logger     *slog.Logger
+}
+
+// NewMeteredEmbedder envuelve un llm.Embedder. Como la interfaz no expone el
+// consumo, los tokens se cuentan localmente con el tokenizer del modelo.
+func NewMeteredEmbedder(inner llm.Embedder, model string, tokenizers *tokenizer.Registry, usage IUsageService, logger *slog.Logger) llm.Embedder {
+	if tokenizers == nil {
+		tokenizers = tokenizer.NewRegistry(nil)
+	}
+	return &meteredEmbedder{
+		inner:      inner,
+		model:      model,
+		tokenizers: tokenizers,
+		usage:      usage,
+		logger:     logger,
+	}
+}
+
+// Embed implements llm.Embedder.
+func (m *meteredEmbedder) Embed(ctx context.Context, input string) (pgvector.Vector, error) {
+	start := time.Now()
+	embedding, err := m.inner.Embed(ctx, input)
+	if err != nil {
+		return embedding, err
+	}
+
+	ctx = llm.WithOperation(ctx, llm.OperationEmbedding)
+	err = m.usage.RecordUsage(ctx, UsageRecord{
+		Model:        m.model,
+		PromptTokens: m.tokenizers.ForModel(m.model).Count(input),
+		Latency:      time.Since(start),
+	})
+	if err != nil {
+		m.logger.WarnContext(ctx, "Embedding usage was not recorded", "error", err, "model", m.model)
+	}
+
+	return embedding, nil
+}
+
+// ensureOperation asigna operation al Scope si todavía no tiene una.
+func ensureOperation(ctx context.Context, operation string) context.Context {
+	if llm.ScopeFromContext(ctx).Operation != "" {
+		return ctx
+	}
+	return llm.WithOperation(ctx, operation)
+}
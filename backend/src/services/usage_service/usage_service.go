@@ -0,0 +1,191 @@
+package usageservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	usagedto "github.com/Dieg0Code/aiep-agent/src/data/dtos/usage_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	usagerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/usage_repo"
+	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+	"github.com/Dieg0Code/aiep-agent/src/llm"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+type usageService struct {
+	usageRepo usagerepo.UsageRepo
+	userRepo  userrepo.UserRepo
+	cfg       Config
+	logger    *slog.Logger
+}
+
+// NewUsageService crea una instancia de IUsageService.
+func NewUsageService(usageRepo usagerepo.UsageRepo, userRepo userrepo.UserRepo, cfg Config, logger *slog.Logger) IUsageService {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	return &usageService{
+		usageRepo: usageRepo,
+		userRepo:  userRepo,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// RecordUsage implements IUsageService.
+func (u *usageService) RecordUsage(ctx context.Context, rec UsageRecord) error {
+	scope := llm.ScopeFromContext(ctx)
+	if scope.UserID == 0 {
+		// Llamadas de sistema (jobs, importaciones) no se atribuyen a un usuario
+		u.logger.DebugContext(ctx, "Skipping usage record without user scope",
+			"model", rec.Model,
+			"operation", scope.Operation,
+		)
+		return nil
+	}
+
+	usage := &models.LLMUsage{
+		UserID:           scope.UserID,
+		ConversationID:   scope.ConversationID,
+		ModuleID:         scope.ModuleID,
+		Operation:        scope.Operation,
+		ModelName:        rec.Model,
+		PromptTokens:     rec.PromptTokens,
+		CompletionTokens: rec.CompletionTokens,
+		LatencyMs:        rec.Latency.Milliseconds(),
+		CostUSD:          u.cfg.Cost(rec.Model, rec.PromptTokens, rec.CompletionTokens),
+	}
+
+	if _, err := u.usageRepo.CreateUsage(ctx, usage); err != nil {
+		u.logger.ErrorContext(ctx, "Failed to record LLM usage",
+			"error", err,
+			"user_id", scope.UserID,
+			"model", rec.Model,
+		)
+		return fmt.Errorf("failed to record LLM usage: %w", err)
+	}
+
+	return nil
+}
+
+// CheckQuota implements IUsageService.
+func (u *usageService) CheckQuota(ctx context.Context, userID uint, role string) error {
+	if userID == 0 {
+		return usagerepo.ErrInvalidUserID
+	}
+
+	quota, ok := u.cfg.Quotas[role]
+	if !ok || (quota.DailyTokens == 0 && quota.MonthlyTokens == 0) {
+		return nil
+	}
+
+	now := time.Now().In(u.cfg.Location)
+	dayStart, monthStart := periodStarts(now)
+
+	if quota.DailyTokens > 0 {
+		used, err := u.usageRepo.SumTokensByUser(ctx, userID, dayStart)
+		if err != nil {
+			u.logger.ErrorContext(ctx, "Failed to sum daily usage",
+				"error", err,
+				"user_id", userID,
+			)
+			return fmt.Errorf("failed to sum daily usage: %w", err)
+		}
+		if used >= quota.DailyTokens {
+			u.logger.WarnContext(ctx, "Daily quota exceeded",
+				"user_id", userID,
+				"role", role,
+				"used", used,
+				"limit", quota.DailyTokens,
+			)
+			return &QuotaExceededError{Period: PeriodDaily, Limit: quota.DailyTokens, Used: used, ResetsAt: dayStart.AddDate(0, 0, 1)}
+		}
+	}
+
+	if quota.MonthlyTokens > 0 {
+		used, err := u.usageRepo.SumTokensByUser(ctx, userID, monthStart)
+		if err != nil {
+			u.logger.ErrorContext(ctx, "Failed to sum monthly usage",
+				"error", err,
+				"user_id", userID,
+			)
+			return fmt.Errorf("failed to sum monthly usage: %w", err)
+		}
+		if used >= quota.MonthlyTokens {
+			u.logger.WarnContext(ctx, "Monthly quota exceeded",
+				"user_id", userID,
+				"role", role,
+				"used", used,
+				"limit", quota.MonthlyTokens,
+			)
+			return &QuotaExceededError{Period: PeriodMonthly, Limit: quota.MonthlyTokens, Used: used, ResetsAt: monthStart.AddDate(0, 1, 0)}
+		}
+	}
+
+	return nil
+}
+
+// QuotaStatus implements IUsageService.
+func (u *usageService) QuotaStatus(ctx context.Context, userID uint) (usagedto.QuotaStatusDTO, error) {
+	user, err := u.userRepo.UserByID(ctx, userID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", userID,
+		)
+		return usagedto.QuotaStatusDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+
+	quota := u.cfg.Quotas[user.Role]
+	now := time.Now().In(u.cfg.Location)
+	dayStart, monthStart := periodStarts(now)
+
+	daily, err := u.usageRepo.SumTokensByUser(ctx, userID, dayStart)
+	if err != nil {
+		return usagedto.QuotaStatusDTO{}, fmt.Errorf("failed to sum daily usage: %w", err)
+	}
+	monthly, err := u.usageRepo.SumTokensByUser(ctx, userID, monthStart)
+	if err != nil {
+		return usagedto.QuotaStatusDTO{}, fmt.Errorf("failed to sum monthly usage: %w", err)
+	}
+
+	return usagedto.QuotaStatusDTO{
+		Role:            user.Role,
+		DailyUsed:       daily,
+		DailyLimit:      quota.DailyTokens,
+		DailyResetsAt:   date.FormatDateTime(dayStart.AddDate(0, 0, 1)),
+		MonthlyUsed:     monthly,
+		MonthlyLimit:    quota.MonthlyTokens,
+		MonthlyResetsAt: date.FormatDateTime(monthStart.AddDate(0, 1, 0)),
+	}, nil
+}
+
+// UsageReport implements IUsageService.
+func (u *usageService) UsageReport(ctx context.Context, req usagedto.UsageReportRequestDTO) (usagedto.UsageReportResponseDTO, error) {
+	filter, err := req.ToRepoFilter()
+	if err != nil {
+		return usagedto.UsageReportResponseDTO{}, err
+	}
+
+	rows, err := u.usageRepo.AggregateUsage(ctx, filter)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to aggregate usage",
+			"error", err,
+			"group_by", req.GroupBy,
+			"user_id", req.UserID,
+			"module_id", req.ModuleID,
+		)
+		return usagedto.UsageReportResponseDTO{}, fmt.Errorf("failed to aggregate usage: %w", err)
+	}
+
+	return usagedto.MakeUsageReportResponse(rows, &req), nil
+}
+
+// periodStarts devuelve el inicio del día y del mes de now, en su zona horaria.
+func periodStarts(now time.Time) (time.Time, time.Time) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return day, month
+}
@@ -0,0 +1,84 @@
+package userservice
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/pkg/apperror"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/date"
+)
+
+var (
+	// ErrInvalidCredentials es el único error de un login fallido: no distingue entre
+	// email inexistente y contraseña incorrecta.
+	ErrInvalidCredentials = apperror.New(apperror.Unauthorized, "auth.invalid_credentials", "user error: email o contraseña incorrectos")
+
+	// ErrLoginLocked permite detectar con errors.Is cualquier bloqueo por intentos fallidos.
+	ErrLoginLocked = apperror.New(apperror.RateLimited, "auth.login_locked", "user error: demasiados intentos fallidos, intenta más tarde")
+
+	// ErrEmailNotVerified se devuelve al iniciar sesión sin haber verificado el email
+	// cuando la política lo exige; el login le reenvía el enlace.
+	ErrEmailNotVerified = apperror.New(apperror.Forbidden, "auth.email_not_verified", "user error: debes verificar tu email antes de continuar")
+
+	// ErrTwoFactorRequired indica que la contraseña es correcta pero falta el código
+	// de la app de autenticación.
+	ErrTwoFactorRequired      = apperror.New(apperror.Unauthorized, "auth.two_factor_required", "user error: ingresa el código de tu app de autenticación")
+	ErrInvalidTwoFactorCode   = apperror.New(apperror.Unauthorized, "auth.invalid_two_factor_code", "user error: el código de verificación no es válido")
+	ErrTwoFactorMandatory     = apperror.New(apperror.Forbidden, "auth.two_factor_mandatory", "user error: tu rol exige doble factor, no se puede desactivar")
+	ErrTwoFactorNotConfigured = apperror.New(apperror.Unavailable, "auth.two_factor_not_configured", "user error: el doble factor no está configurado en el servidor")
+	ErrTwoFactorNotEnabled    = apperror.New(apperror.Conflict, "auth.two_factor_not_enabled", "user error: la cuenta no tiene doble factor activado")
+	ErrTwoFactorSetupRequired = apperror.New(apperror.Forbidden, "auth.two_factor_setup_required", "user error: tu rol exige doble factor, actívalo antes de continuar")
+
+	// ErrInvalidSession indica un token de sesión inexistente, vencido o revocado.
+	ErrInvalidSession = apperror.New(apperror.Unauthorized, "auth.invalid_session", "user error: la sesión no es válida o expiró, inicia sesión nuevamente")
+
+	// Errores del login con el proveedor institucional (OpenID Connect)
+	ErrSSONotConfigured    = apperror.New(apperror.Unavailable, "auth.sso_not_configured", "user error: el inicio de sesión institucional no está configurado")
+	ErrSSODenied           = apperror.New(apperror.Forbidden, "auth.sso_denied", "user error: el proveedor institucional rechazó el inicio de sesión")
+	ErrSSOInvalidState     = apperror.New(apperror.Validation, "auth.sso_invalid_state", "user error: el inicio de sesión expiró o no es válido, vuelve a intentarlo")
+	ErrSSOEmailNotVerified = apperror.New(apperror.Forbidden, "auth.sso_email_not_verified", "user error: el proveedor institucional no informó un email verificado")
+	ErrSSODomainNotAllowed = apperror.New(apperror.Forbidden, "auth.sso_domain_not_allowed", "user error: el dominio del email no está habilitado para el inicio de sesión institucional")
+	ErrSSONoAccount        = apperror.New(apperror.Forbidden, "auth.sso_no_account", "user error: no hay una cuenta asociada a este usuario institucional")
+
+	// Errores de validación de las solicitudes
+	ErrInvitationCodeRequired = apperror.New(apperror.Validation, "auth.invitation_code_required", "user error: debes ingresar un código de invitación")
+	ErrInviteFieldsRequired   = apperror.New(apperror.Validation, "auth.invite_fields_required", "user error: el enlace de invitación y la contraseña son obligatorios")
+	ErrPasswordRequired       = apperror.New(apperror.Validation, "auth.password_required", "user error: la contraseña no puede estar vacía")
+	ErrUnlockTargetRequired   = apperror.New(apperror.Validation, "auth.unlock_target_required", "user error: indica un email o una dirección IP para desbloquear")
+
+	ErrEmailAlreadyVerified      = apperror.New(apperror.Conflict, "auth.email_already_verified", "user error: el email ya está verificado")
+	ErrInvalidVerificationToken  = apperror.New(apperror.Validation, "auth.invalid_verification_token", "user error: el enlace de verificación no es válido o está vencido")
+	ErrVerificationNotConfigured = apperror.New(apperror.Unavailable, "auth.verification_not_configured", "user error: la verificación de email no está configurada")
+)
+
+// LoginLockedError indica hasta cuándo están bloqueados los intentos de inicio de
+// sesión. Se devuelve igual para emails registrados y no registrados.
+type LoginLockedError struct {
+	Until time.Time
+}
+
+// Error devuelve un mensaje apto para mostrar en la pantalla de login.
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("demasiados intentos fallidos; podrás intentarlo de nuevo después de %s", date.FormatDateTime(e.Until))
+}
+
+// Is permite que errors.Is(err, ErrLoginLocked) sea verdadero.
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
+
+// AppError implements apperror.Coder: el código es el de ErrLoginLocked, con el
+// mensaje que indica hasta cuándo dura el bloqueo.
+func (e *LoginLockedError) AppError() *apperror.Error {
+	return ErrLoginLocked.WithDetail(e.Error(), "until", map[string]string{
+		"until": date.FormatDateTime(e.Until),
+	})
+}
+
+// RetryAfter devuelve cuánto falta para poder reintentar (para la cabecera Retry-After).
+func (e *LoginLockedError) RetryAfter(now time.Time) time.Duration {
+	if d := e.Until.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,254 @@
+package userservice
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	externalidentityrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/external_identity_repo"
+	loginthrottlerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/login_throttle_repo"
+	sessionrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/session_repo"
+	twofactorrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/two_factor_repo"
+	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+)
+
+// Repos en memoria para las pruebas. Embeben la interfaz: un método no
+// implementado hace panic y delata que el flujo usa algo inesperado.
+
+type fakeTx struct{}
+
+func (fakeTx) Transaction(ctx context.Context, fn func(ctx context.Context) error, _ ...*sql.TxOptions) error {
+	return fn(ctx)
+}
+
+type fakeUsers struct {
+	userrepo.UserRepo
+	mu    sync.Mutex
+	users map[uint]*models.User
+}
+
+func newFakeUsers() *fakeUsers {
+	return &fakeUsers{users: map[uint]*models.User{}}
+}
+
+func (f *fakeUsers) add(user models.User) *models.User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user.ID = uint(len(f.users) + 1)
+	f.users[user.ID] = &user
+	return &user
+}
+
+func (f *fakeUsers) find(match func(*models.User) bool) (*models.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if match(u) {
+			c := *u
+			return &c, nil
+		}
+	}
+	return nil, userrepo.ErrUserNotFound
+}
+
+func (f *fakeUsers) UserByID(_ context.Context, id uint) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.ID == id })
+}
+
+func (f *fakeUsers) UserByEmail(_ context.Context, email string) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return strings.EqualFold(u.Email, email) })
+}
+
+func (f *fakeUsers) UserByUsername(_ context.Context, username string) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.UserName == username })
+}
+
+func (f *fakeUsers) CreateUser(_ context.Context, user *models.User) (*models.User, error) {
+	created := f.add(*user)
+	user.ID = created.ID
+	return user, nil
+}
+
+func (f *fakeUsers) UpdateRole(_ context.Context, id uint, role string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[id].Role = role
+	return nil
+}
+
+func (f *fakeUsers) MarkEmailVerified(_ context.Context, id uint, email string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.users[id].Email != email {
+		return userrepo.ErrEmailChanged
+	}
+	now := time.Now()
+	f.users[id].EmailVerifiedAt = &now
+	return nil
+}
+
+type fakeIdentities struct {
+	externalidentityrepo.ExternalIdentityRepo
+	mu         sync.Mutex
+	identities []models.ExternalIdentity
+}
+
+func (f *fakeIdentities) IdentityBySubject(_ context.Context, issuer, subject string) (*models.ExternalIdentity, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, i := range f.identities {
+		if i.Issuer == issuer && i.Subject == subject {
+			return &i, nil
+		}
+	}
+	return nil, externalidentityrepo.ErrIdentityNotFound
+}
+
+func (f *fakeIdentities) CreateIdentity(_ context.Context, identity *models.ExternalIdentity) (*models.ExternalIdentity, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	identity.ID = uint(len(f.identities) + 1)
+	f.identities = append(f.identities, *identity)
+	return identity, nil
+}
+
+func (f *fakeIdentities) TouchIdentity(context.Context, uint, string) error { return nil }
+
+type fakeSessions struct {
+	sessionrepo.SessionRepo
+	mu       sync.Mutex
+	sessions []models.UserSession
+}
+
+func (f *fakeSessions) CreateSession(_ context.Context, session *models.UserSession) (*models.UserSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	session.ID = uint(len(f.sessions) + 1)
+	session.CreatedAt = time.Now()
+	f.sessions = append(f.sessions, *session)
+	return session, nil
+}
+
+func (f *fakeSessions) ActiveSessionByHash(_ context.Context, hash string) (*models.UserSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range f.sessions {
+		if s.TokenHash == hash && s.RevokedAt == nil && s.ExpiresAt.After(time.Now()) {
+			return &s, nil
+		}
+	}
+	return nil, sessionrepo.ErrSessionNotFound
+}
+
+func (f *fakeSessions) TouchSession(context.Context, uint, string, time.Time) error { return nil }
+
+type fakeTwoFactors struct {
+	twofactorrepo.TwoFactorRepo
+	mu       sync.Mutex
+	factors  map[uint]*models.TwoFactor
+	recovery map[string]bool // hash -> usado
+}
+
+func (f *fakeTwoFactors) SavePendingTwoFactor(_ context.Context, userID uint, secretSealed string) (*models.TwoFactor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t, ok := f.factors[userID]; ok && t.ConfirmedAt != nil {
+		return nil, twofactorrepo.ErrAlreadyConfirmed
+	}
+	t := &models.TwoFactor{UserID: userID, SecretSealed: secretSealed}
+	f.factors[userID] = t
+	c := *t
+	return &c, nil
+}
+
+func (f *fakeTwoFactors) ConfirmTwoFactor(_ context.Context, userID uint, counter int64, recoveryHashes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.factors[userID]
+	if !ok {
+		return twofactorrepo.ErrTwoFactorNotFound
+	}
+	if t.ConfirmedAt != nil {
+		return twofactorrepo.ErrAlreadyConfirmed
+	}
+	now := time.Now()
+	t.ConfirmedAt = &now
+	t.LastUsedCounter = counter
+	f.replaceRecovery(recoveryHashes)
+	return nil
+}
+
+func (f *fakeTwoFactors) ReplaceRecoveryCodes(_ context.Context, _ uint, recoveryHashes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.replaceRecovery(recoveryHashes)
+	return nil
+}
+
+func (f *fakeTwoFactors) replaceRecovery(hashes []string) {
+	f.recovery = map[string]bool{}
+	for _, h := range hashes {
+		f.recovery[h] = false
+	}
+}
+
+func (f *fakeTwoFactors) TwoFactorByUser(_ context.Context, userID uint) (*models.TwoFactor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t, ok := f.factors[userID]; ok {
+		c := *t
+		return &c, nil
+	}
+	return nil, twofactorrepo.ErrTwoFactorNotFound
+}
+
+func (f *fakeTwoFactors) UseCounter(_ context.Context, userID uint, counter int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := f.factors[userID]
+	if counter <= t.LastUsedCounter {
+		return twofactorrepo.ErrCodeReused
+	}
+	t.LastUsedCounter = counter
+	return nil
+}
+
+func (f *fakeTwoFactors) UseRecoveryCode(_ context.Context, _ uint, codeHash string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	used, ok := f.recovery[codeHash]
+	if !ok || used {
+		return twofactorrepo.ErrRecoveryCodeInvalid
+	}
+	f.recovery[codeHash] = true
+	return nil
+}
+
+type fakeThrottles struct {
+	loginthrottlerepo.LoginThrottleRepo
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+func (f *fakeThrottles) ThrottlesByKeys(context.Context, []string) ([]models.LoginThrottle, error) {
+	return nil, nil
+}
+
+func (f *fakeThrottles) RegisterFailure(_ context.Context, key string, now, _ time.Time) (*models.LoginThrottle, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[key]++
+	return &models.LoginThrottle{Key: key, Failures: f.failures[key], LastFailureAt: now}, nil
+}
+
+func (f *fakeThrottles) LockThrottle(context.Context, string, time.Time) error { return nil }
+
+func (f *fakeThrottles) ClearFailures(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.failures, key)
+	return nil
+}
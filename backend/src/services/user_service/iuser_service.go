@@ -2,7 +2,9 @@ package userservice
 
 import (
 	"context"
+	"time"
 
+	"github.com/Dieg0Code/aiep-agent/src/auth/password"
 	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
 )
 
@@ -19,12 +21,162 @@ type UserWriter interface {
 	CreateUser(ctx context.Context, req userdto.CreateUserDTO) (userdto.UserDetailDTO, error)
 	UpdatePassword(ctx context.Context, req userdto.UpdatePasswordRequestDTO) error
 	UpdateRole(ctx context.Context, req userdto.UpdateRoleRequestDTO) error
+	// UpdateLocale guarda el idioma preferido; vacío vuelve a usar el del navegador.
+	UpdateLocale(ctx context.Context, req userdto.UpdateLocaleRequestDTO) (userdto.UserDetailDTO, error)
 	DeleteUser(ctx context.Context, id uint) error
 	Authenticate(ctx context.Context, req userdto.LoginRequestDTO) (userdto.UserDetailDTO, error)
+	// AcceptInvite define la contraseña de una cuenta invitada y consume el token.
+	AcceptInvite(ctx context.Context, req userdto.AcceptInviteRequestDTO) (userdto.UserDetailDTO, error)
+	// SignUp crea la cuenta con un código de invitación y, si el código tiene módulo,
+	// la inscripción; todo en una transacción junto con el uso del código.
+	SignUp(ctx context.Context, req userdto.SignUpRequestDTO) (userdto.UserDetailDTO, error)
 }
 
-// IUserService es la composición de lectura y escritura.
+// EmailVerifier agrupa la verificación del email de la cuenta.
+type EmailVerifier interface {
+	// SendVerificationEmail reenvía el enlace de verificación (con límite de frecuencia).
+	SendVerificationEmail(ctx context.Context, userID uint) error
+	// ResendVerificationEmail es SendVerificationEmail sin sesión, para quien no puede
+	// entrar sin verificar: responde igual exista o no la cuenta.
+	ResendVerificationEmail(ctx context.Context, req userdto.ResendVerificationRequestDTO) error
+	VerifyEmail(ctx context.Context, req userdto.VerifyEmailRequestDTO) (userdto.UserDetailDTO, error)
+	// UpdateEmail cambia el email (pide la contraseña) y vuelve a exigir su verificación.
+	UpdateEmail(ctx context.Context, req userdto.UpdateEmailRequestDTO) (userdto.UserDetailDTO, error)
+}
+
+// LoginGuard agrupa la administración de bloqueos por intentos fallidos.
+type LoginGuard interface {
+	ListLockouts(ctx context.Context) ([]userdto.LockoutDTO, error)
+	// UnlockLogin borra los fallos y el bloqueo del email y/o la IP indicados.
+	UnlockLogin(ctx context.Context, req userdto.UnlockLoginRequestDTO) error
+}
+
+// TwoFactorManager agrupa el doble factor (TOTP de RFC 6238 y códigos de recuperación).
+type TwoFactorManager interface {
+	TwoFactorStatus(ctx context.Context, userID uint) (userdto.TwoFactorStatusDTO, error)
+	// BeginTwoFactor genera un secreto pendiente; se activa al confirmar el primer código.
+	BeginTwoFactor(ctx context.Context, userID uint) (userdto.TwoFactorSetupDTO, error)
+	ConfirmTwoFactor(ctx context.Context, req userdto.ConfirmTwoFactorRequestDTO) (userdto.RecoveryCodesDTO, error)
+	RegenerateRecoveryCodes(ctx context.Context, req userdto.RegenerateRecoveryCodesRequestDTO) (userdto.RecoveryCodesDTO, error)
+	DisableTwoFactor(ctx context.Context, req userdto.DisableTwoFactorRequestDTO) error
+	// ResetTwoFactor lo borra sin pedir códigos (admin, ante un teléfono perdido).
+	ResetTwoFactor(ctx context.Context, userID uint) error
+}
+
+// SessionManager agrupa las sesiones abiertas con Authenticate (una por dispositivo).
+type SessionManager interface {
+	// ValidateSession resuelve el token de una petición y registra la actividad.
+	// Rechaza con ErrTwoFactorSetupRequired las sesiones que solo sirven para
+	// activar el doble factor.
+	ValidateSession(ctx context.Context, token string, ipAddress string) (userdto.SessionDTO, error)
+	// ValidateSetupSession es ValidateSession para las rutas de activación del doble
+	// factor (TwoFactorStatus, BeginTwoFactor, ConfirmTwoFactor): acepta también
+	// las sesiones limitadas a eso.
+	ValidateSetupSession(ctx context.Context, token string, ipAddress string) (userdto.SessionDTO, error)
+	ListSessions(ctx context.Context, req userdto.ListSessionsRequestDTO) ([]userdto.SessionDTO, error)
+	RevokeSession(ctx context.Context, req userdto.RevokeSessionRequestDTO) error
+	// RevokeAllSessions cierra la sesión en todos los dispositivos.
+	RevokeAllSessions(ctx context.Context, req userdto.RevokeAllSessionsRequestDTO) error
+	// ForceRevokeSessions cierra todas las sesiones de un usuario (admin).
+	ForceRevokeSessions(ctx context.Context, userID uint) error
+}
+
+// SSOLogin agrupa el inicio de sesión con el proveedor institucional (OpenID
+// Connect, authorization code + PKCE).
+type SSOLogin interface {
+	// BeginSSO devuelve la URL del proveedor y la transacción que el handler guarda en una cookie.
+	BeginSSO(ctx context.Context) (userdto.SSOStartDTO, error)
+	// CompleteSSO valida el retorno, vincula o crea la cuenta y abre una sesión. Si
+	// el rol exige doble factor y el proveedor no informó haberlo pedido, devuelve un
+	// desafío en lugar de la sesión.
+	CompleteSSO(ctx context.Context, req userdto.SSOCallbackRequestDTO) (userdto.UserDetailDTO, error)
+	// CompleteSSOTwoFactor abre la sesión tras validar el código del desafío.
+	CompleteSSOTwoFactor(ctx context.Context, req userdto.SSOTwoFactorRequestDTO) (userdto.UserDetailDTO, error)
+}
+
+// IUserService es la composición de lectura, escritura, verificación, bloqueos,
+// doble factor, sesiones y SSO.
 type IUserService interface {
 	UserReader
 	UserWriter
+	EmailVerifier
+	LoginGuard
+	TwoFactorManager
+	SessionManager
+	SSOLogin
+}
+
+// Config agrupa los parámetros de las cuentas.
+type Config struct {
+	VerificationSecret   string        // Clave HMAC de los enlaces de verificación
+	VerificationURL      string        // URL de la pantalla de verificación; se le agrega ?token=<token>
+	VerificationTTL      time.Duration // Vigencia del enlace
+	ResendInterval       time.Duration // Tiempo mínimo entre dos envíos al mismo usuario
+	RequireVerifiedEmail bool          // Authenticate rechaza cuentas sin email verificado
+
+	// Protección contra fuerza bruta
+	MaxFailures     int           // Fallos por email antes de bloquear
+	MaxIPFailures   int           // Fallos por IP antes de bloquear (cubre ataques a muchos emails)
+	FailureWindow   time.Duration // Los fallos más antiguos que esto no cuentan
+	LockDuration    time.Duration // Duración del primer bloqueo; cada bloqueo siguiente dura el doble
+	MaxLockDuration time.Duration // Tope de la duración del bloqueo
+	DelayAfter      int           // Fallos a partir de los cuales cada respuesta se demora
+	BaseDelay       time.Duration // Demora del primer fallo con espera; se duplica en cada fallo
+	MaxDelay        time.Duration // Tope de la demora
+
+	// Sesiones
+	SessionTTL           time.Duration // Vigencia de una sesión desde el login
+	SetupSessionTTL      time.Duration // Vigencia de la sesión que solo permite activar el doble factor
+	SessionTouchInterval time.Duration // Cada cuánto se guarda la última actividad
+
+	// Inicio de sesión institucional (OpenID Connect)
+	SSOKey            string            // Clave con que se cifra la transacción entre ida y vuelta
+	SSOTransactionTTL time.Duration     // Tiempo máximo para volver del proveedor
+	SSOAutoProvision  bool              // Crear la cuenta si el email no existe
+	SSOAllowedDomains []string          // Dominios de email aceptados; vacío = todos
+	SSORoleClaim      string            // Claim con los grupos del usuario (p. ej. "groups")
+	SSORoleMapping    map[string]string // Valor del claim -> student | teacher | admin
+	SSODefaultRole    string            // Rol si ningún valor coincide
+	SSOSyncRole       bool              // Actualizar el rol en cada login, no solo al crear la cuenta
+	SSOTrustedMFA     []string          // Valores de amr o acr con que el proveedor acredita su doble factor; vacío = se pide el local
+
+	// Contraseñas nuevas (alta, registro, invitación y cambio)
+	PasswordPolicy password.Policy
+
+	// Doble factor
+	TwoFactorKey      string   // Clave con que se cifran los secretos TOTP
+	TwoFactorIssuer   string   // Nombre que muestra la app de autenticación
+	TwoFactorRoles    []string // Roles que deben activarlo
+	TOTPSkew          int      // Pasos de 30 s de tolerancia antes y después
+	RecoveryCodeCount int      // Códigos de recuperación generados
+}
+
+// DefaultConfig devuelve la configuración por defecto.
+func DefaultConfig() Config {
+	return Config{
+		VerificationURL:      "http://localhost:8080/verificar-email",
+		VerificationTTL:      48 * time.Hour,
+		ResendInterval:       2 * time.Minute,
+		RequireVerifiedEmail: false,
+		MaxFailures:          5,
+		MaxIPFailures:        50,
+		FailureWindow:        15 * time.Minute,
+		LockDuration:         15 * time.Minute,
+		MaxLockDuration:      24 * time.Hour,
+		DelayAfter:           3,
+		BaseDelay:            500 * time.Millisecond,
+		MaxDelay:             5 * time.Second,
+		TwoFactorIssuer:      "AIEP Agent",
+		TwoFactorRoles:       []string{"teacher", "admin"},
+		TOTPSkew:             1,
+		RecoveryCodeCount:    10,
+		PasswordPolicy:       password.DefaultPolicy(),
+		SessionTTL:           30 * 24 * time.Hour,
+		SetupSessionTTL:      15 * time.Minute,
+		SessionTouchInterval: 5 * time.Minute,
+		SSOTransactionTTL:    10 * time.Minute,
+		SSOAutoProvision:     true,
+		SSORoleClaim:         "groups",
+		SSODefaultRole:       "student",
+	}
 }
@@ -0,0 +1,221 @@
+package userservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	loginthrottlerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/login_throttle_repo"
+)
+
+// maxListedLockouts limita el listado de bloqueos vigentes.
+const maxListedLockouts = 200
+
+// loginKeys devuelve las claves de conteo del intento: el email (exista o no) y la IP.
+func loginKeys(req userdto.LoginRequestDTO) []string {
+	keys := []string{loginthrottlerepo.KeyAccount + req.GetEmail()}
+	if ip := strings.TrimSpace(req.IPAddress); ip != "" {
+		keys = append(keys, loginthrottlerepo.KeyIP+ip)
+	}
+	return keys
+}
+
+// checkLockout devuelve LoginLockedError si alguna de las claves sigue bloqueada.
+func (u *userService) checkLockout(ctx context.Context, keys []string) error {
+	throttles, err := u.throttleRepo.ThrottlesByKeys(ctx, keys)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get login throttles", "error", err)
+		return fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	now := time.Now()
+	var until time.Time
+	for _, t := range throttles {
+		if t.LockedUntil != nil && t.LockedUntil.After(now) && t.LockedUntil.After(until) {
+			until = *t.LockedUntil
+		}
+	}
+	if !until.IsZero() {
+		return &LoginLockedError{Until: until}
+	}
+	return nil
+}
+
+// loginFailed registra el fallo en cada clave, bloquea las que alcanzan su umbral y
+// demora la respuesta de forma progresiva. Devuelve el error que ve el cliente.
+func (u *userService) loginFailed(ctx context.Context, keys []string) error {
+	now := time.Now()
+	windowStart := now.Add(-u.cfg.FailureWindow)
+
+	var (
+		lockedUntil time.Time
+		maxFailures int
+	)
+	for _, key := range keys {
+		limit := u.cfg.MaxFailures
+		if strings.HasPrefix(key, loginthrottlerepo.KeyIP) {
+			limit = u.cfg.MaxIPFailures
+		}
+
+		throttle, err := u.throttleRepo.RegisterFailure(ctx, key, now, windowStart)
+		if err != nil {
+			u.logger.ErrorContext(ctx, "Failed to register login failure", "error", err)
+			continue // El intento ya falló; no se cambia el error que ve el cliente
+		}
+		if throttle.Failures > maxFailures && !strings.HasPrefix(key, loginthrottlerepo.KeyIP) {
+			maxFailures = throttle.Failures
+		}
+
+		if limit > 0 && throttle.Failures >= limit {
+			until := now.Add(u.lockDuration(throttle.Lockouts))
+			if err := u.throttleRepo.LockThrottle(ctx, key, until); err != nil {
+				u.logger.ErrorContext(ctx, "Failed to lock login key", "error", err)
+				continue
+			}
+			u.logger.WarnContext(ctx, "Login locked after repeated failures",
+				"key", key,
+				"failures", throttle.Failures,
+				"locked_until", until,
+			)
+			if until.After(lockedUntil) {
+				lockedUntil = until
+			}
+		}
+	}
+
+	if !lockedUntil.IsZero() {
+		return &LoginLockedError{Until: lockedUntil}
+	}
+
+	u.delay(ctx, maxFailures)
+	return ErrInvalidCredentials
+}
+
+// loginSucceeded borra los fallos del email. Los de la IP se mantienen: una cuenta
+// válida no debe servir para reiniciar el contador de un atacante. Los bloqueos
+// anteriores del email también se conservan, así que alternar con un login
+// correcto no evita que el siguiente bloqueo dure más.
+func (u *userService) loginSucceeded(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if !strings.HasPrefix(key, loginthrottlerepo.KeyAccount) {
+			continue
+		}
+		if err := u.throttleRepo.ClearFailures(ctx, key); err != nil {
+			u.logger.WarnContext(ctx, "Failed to reset login failures", "error", err)
+		}
+	}
+}
+
+// lockDuration duplica la duración por cada bloqueo anterior, hasta MaxLockDuration.
+func (u *userService) lockDuration(previousLockouts int) time.Duration {
+	d := u.cfg.LockDuration
+	for i := 0; i < previousLockouts && d < u.cfg.MaxLockDuration; i++ {
+		d *= 2
+	}
+	if u.cfg.MaxLockDuration > 0 && d > u.cfg.MaxLockDuration {
+		d = u.cfg.MaxLockDuration
+	}
+	return d
+}
+
+// delay espera antes de responder un login fallido cuando ya hubo DelayAfter fallos.
+func (u *userService) delay(ctx context.Context, failures int) {
+	if u.cfg.BaseDelay <= 0 || failures < u.cfg.DelayAfter {
+		return
+	}
+	d := u.cfg.BaseDelay
+	for i := u.cfg.DelayAfter; i < failures && d < u.cfg.MaxDelay; i++ {
+		d *= 2
+	}
+	if u.cfg.MaxDelay > 0 && d > u.cfg.MaxDelay {
+		d = u.cfg.MaxDelay
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// placeholderHash genera (una vez) el hash con que se compara cuando el email no existe.
+func (u *userService) placeholderHash() string {
+	u.placeholderOnce.Do(func() {
+		hash, err := u.hasher.HashPassword("placeholder-password-for-timing")
+		if err != nil {
+			u.logger.Error("Failed to generate placeholder password hash", "error", err)
+		}
+		u.placeholder = hash
+	})
+	return u.placeholder
+}
+
+// rehashBestEffort regenera el hash si usa otro algoritmo o parámetros antiguos.
+// Solo se puede tras un login correcto, que es cuando se conoce la contraseña;
+// si falla, el login sigue y se reintenta en el siguiente.
+func (u *userService) rehashBestEffort(ctx context.Context, user *models.User, password string) {
+	if !u.hasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	hash, err := u.hasher.HashPassword(password)
+	if err == nil {
+		err = u.userRepo.UpdatePassword(ctx, user.ID, hash)
+	}
+	if err != nil {
+		u.logger.WarnContext(ctx, "Failed to upgrade password hash",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return
+	}
+
+	user.PasswordHash = hash
+	u.logger.InfoContext(ctx, "Password hash upgraded", "user_id", user.ID)
+}
+
+// ListLockouts implements IUserService.
+func (u *userService) ListLockouts(ctx context.Context) ([]userdto.LockoutDTO, error) {
+	throttles, err := u.throttleRepo.ListLockedThrottles(ctx, time.Now(), maxListedLockouts)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to list login lockouts", "error", err)
+		return nil, fmt.Errorf("failed to list login lockouts: %w", err)
+	}
+
+	result := make([]userdto.LockoutDTO, 0, len(throttles))
+	for i := range throttles {
+		result = append(result, userdto.FromThrottleModel(&throttles[i]))
+	}
+	return result, nil
+}
+
+// UnlockLogin implements IUserService.
+func (u *userService) UnlockLogin(ctx context.Context, req userdto.UnlockLoginRequestDTO) error {
+	var keys []string
+	if email := strings.TrimSpace(strings.ToLower(req.Email)); email != "" {
+		keys = append(keys, loginthrottlerepo.KeyAccount+email)
+	}
+	if ip := strings.TrimSpace(req.IPAddress); ip != "" {
+		keys = append(keys, loginthrottlerepo.KeyIP+ip)
+	}
+	if len(keys) == 0 {
+		return ErrUnlockTargetRequired
+	}
+
+	for _, key := range keys {
+		if err := u.throttleRepo.ResetThrottle(ctx, key); err != nil {
+			u.logger.ErrorContext(ctx, "Failed to unlock login",
+				"error", err,
+				"key", key,
+			)
+			return fmt.Errorf("failed to unlock login: %w", err)
+		}
+	}
+
+	u.logger.InfoContext(ctx, "Login unlocked by admin", "keys", keys)
+	return nil
+}
@@ -0,0 +1,224 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	sessionrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/session_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+)
+
+// sessionTokenBytes es la entropía del token de sesión.
+const sessionTokenBytes = 32
+
+// ValidateSession implements IUserService.
+func (u *userService) ValidateSession(ctx context.Context, token string, ipAddress string) (userdto.SessionDTO, error) {
+	session, err := u.ValidateSetupSession(ctx, token, ipAddress)
+	if err != nil {
+		return userdto.SessionDTO{}, err
+	}
+	if session.Scope == sessionrepo.ScopeTwoFactorSetup {
+		return userdto.SessionDTO{}, ErrTwoFactorSetupRequired
+	}
+	return session, nil
+}
+
+// ValidateSetupSession implements IUserService.
+func (u *userService) ValidateSetupSession(ctx context.Context, token string, ipAddress string) (userdto.SessionDTO, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return userdto.SessionDTO{}, ErrInvalidSession
+	}
+
+	session, err := u.sessionRepo.ActiveSessionByHash(ctx, securetoken.Hash(token))
+	if errors.Is(err, sessionrepo.ErrSessionNotFound) {
+		return userdto.SessionDTO{}, ErrInvalidSession
+	}
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get session", "error", err)
+		return userdto.SessionDTO{}, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	// La última actividad es informativa: si no se puede guardar la sesión sigue valiendo
+	if err := u.sessionRepo.TouchSession(ctx, session.ID, ipAddress, time.Now().Add(-u.cfg.SessionTouchInterval)); err != nil {
+		u.logger.WarnContext(ctx, "Failed to update session last seen",
+			"error", err,
+			"session_id", session.ID,
+		)
+	}
+
+	return userdto.FromSessionModel(session, session.ID), nil
+}
+
+// ListSessions implements IUserService.
+func (u *userService) ListSessions(ctx context.Context, req userdto.ListSessionsRequestDTO) ([]userdto.SessionDTO, error) {
+	sessions, err := u.sessionRepo.ActiveSessionsByUser(ctx, req.UserID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to list sessions",
+			"error", err,
+			"user_id", req.UserID,
+		)
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	result := make([]userdto.SessionDTO, 0, len(sessions))
+	for i := range sessions {
+		result = append(result, userdto.FromSessionModel(&sessions[i], req.CurrentSessionID))
+	}
+	return result, nil
+}
+
+// RevokeSession implements IUserService.
+func (u *userService) RevokeSession(ctx context.Context, req userdto.RevokeSessionRequestDTO) error {
+	if err := u.sessionRepo.RevokeSession(ctx, req.UserID, req.SessionID, sessionrepo.ReasonLogout); err != nil {
+		u.logger.WarnContext(ctx, "Failed to revoke session",
+			"error", err,
+			"user_id", req.UserID,
+			"session_id", req.SessionID,
+		)
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "Session revoked",
+		"user_id", req.UserID,
+		"session_id", req.SessionID,
+	)
+	return nil
+}
+
+// RevokeAllSessions implements IUserService.
+func (u *userService) RevokeAllSessions(ctx context.Context, req userdto.RevokeAllSessionsRequestDTO) error {
+	var keep uint
+	if req.KeepCurrent {
+		keep = req.CurrentSessionID
+	}
+	return u.revokeSessions(ctx, req.UserID, keep, sessionrepo.ReasonLogoutAll)
+}
+
+// ForceRevokeSessions implements IUserService.
+func (u *userService) ForceRevokeSessions(ctx context.Context, userID uint) error {
+	return u.revokeSessions(ctx, userID, 0, sessionrepo.ReasonAdmin)
+}
+
+// revokeSessions cierra las sesiones del usuario salvo keepID (0 = todas).
+func (u *userService) revokeSessions(ctx context.Context, userID, keepID uint, reason string) error {
+	revoked, err := u.sessionRepo.RevokeUserSessions(ctx, userID, keepID, reason)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to revoke user sessions",
+			"error", err,
+			"user_id", userID,
+			"reason", reason,
+		)
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "User sessions revoked",
+		"user_id", userID,
+		"reason", reason,
+		"revoked", revoked,
+	)
+	return nil
+}
+
+// openSession crea la sesión de un login correcto y devuelve el token en claro.
+// Con scope ScopeTwoFactorSetup la sesión dura SetupSessionTTL.
+func (u *userService) openSession(ctx context.Context, user *models.User, scope, device, userAgent, ipAddress string) (*models.UserSession, string, error) {
+	token, hash, err := securetoken.Generate(sessionTokenBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+
+	device = strings.TrimSpace(device)
+	if device == "" {
+		device = describeDevice(userAgent)
+	}
+
+	ttl := u.cfg.SessionTTL
+	if scope == sessionrepo.ScopeTwoFactorSetup {
+		ttl = u.cfg.SetupSessionTTL
+	}
+
+	now := time.Now()
+	session, err := u.sessionRepo.CreateSession(ctx, &models.UserSession{
+		UserID:     user.ID,
+		TokenHash:  hash,
+		Device:     truncate(device, 100),
+		UserAgent:  truncate(userAgent, 500),
+		IPAddress:  ipAddress,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ttl),
+		Scope:      scope,
+	})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to create session",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return nil, "", fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return session, token, nil
+}
+
+// describeDevice resume el user agent en "Navegador en Sistema" para la lista de sesiones.
+// Es una heurística: el orden importa porque muchos navegadores se anuncian como otros.
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Dispositivo desconocido"
+	}
+
+	browser := firstMatch(userAgent, [][2]string{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"SamsungBrowser/", "Samsung Internet"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"CriOS/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"okhttp", "App Android"},
+		{"Dart/", "App"},
+	})
+	system := firstMatch(userAgent, [][2]string{
+		{"Windows", "Windows"},
+		{"Android", "Android"},
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	})
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " en " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Dispositivo desconocido"
+	}
+}
+
+func firstMatch(s string, candidates [][2]string) string {
+	for _, c := range candidates {
+		if strings.Contains(s, c[0]) {
+			return c[1]
+		}
+	}
+	return ""
+}
+
+// truncate corta s a limit runas para que quepa en la columna.
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,87 @@
+package userservice
+
+import (
+	"context"
+	"fmt"
+
+	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	enrollementrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/enrollment_repo"
+)
+
+// SignUp implements IUserService.
+func (u *userService) SignUp(ctx context.Context, req userdto.SignUpRequestDTO) (userdto.UserDetailDTO, error) {
+	if req.Code == "" {
+		return userdto.UserDetailDTO{}, ErrInvitationCodeRequired
+	}
+
+	if err := u.cfg.PasswordPolicy.Validate(req.Password, req.UserName, req.Email); err != nil {
+		return userdto.UserDetailDTO{}, err
+	}
+
+	// Se hashea fuera de la transacción para no mantenerla abierta durante el hashing
+	hashedPassword, err := u.hasher.HashPassword(req.Password)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to hash password",
+			"error", err,
+			"username", req.UserName,
+		)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	var (
+		created    *models.User
+		invitation *models.Invitation
+	)
+	err = u.txm.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		invitation, err = u.invitationRepo.InvitationByCode(ctx, req.Code)
+		if err != nil {
+			return err
+		}
+		if err := u.invitationRepo.ConsumeInvitation(ctx, invitation.ID); err != nil {
+			return err
+		}
+
+		created, err = u.userRepo.CreateUser(ctx, req.ToModelWithHash(invitation.Role, hashedPassword))
+		if err != nil {
+			return err
+		}
+
+		redemption := &models.InvitationRedemption{
+			InvitationID: invitation.ID,
+			UserID:       created.ID,
+			IPAddress:    req.IPAddress,
+		}
+		if invitation.ModuleID != nil {
+			enrollment, err := u.enrollmentRepo.CreateEnrollment(ctx, &models.Enrollment{
+				UserID:   created.ID,
+				ModuleID: *invitation.ModuleID,
+				Status:   enrollementrepo.StatusActive,
+			})
+			if err != nil {
+				return err
+			}
+			redemption.EnrollmentID = &enrollment.ID
+		}
+
+		_, err = u.invitationRepo.CreateRedemption(ctx, redemption)
+		return err
+	})
+	if err != nil {
+		u.logger.WarnContext(ctx, "Failed to sign up with invitation code",
+			"error", err,
+			"username", req.UserName,
+			"email", req.Email,
+		)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to sign up: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "User signed up with invitation",
+		"user_id", created.ID,
+		"invitation_id", invitation.ID,
+		"role", created.Role,
+	)
+	u.sendVerificationBestEffort(ctx, created)
+	return userdto.FromModelToDetail(created), nil
+}
@@ -0,0 +1,487 @@
+package userservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	externalidentityrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/external_identity_repo"
+	sessionrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/session_repo"
+	twofactorrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/two_factor_repo"
+	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/oidc"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+)
+
+// ssoTransaction es lo que se guarda (cifrado) entre la redirección al proveedor y
+// el retorno. Va en una cookie del navegador, así no hace falta una tabla.
+type ssoTransaction struct {
+	State     string `json:"s"`
+	Nonce     string `json:"n"`
+	Verifier  string `json:"v"`
+	ExpiresAt int64  `json:"e"` // Unix, segundos
+}
+
+// ssoChallenge es lo que se guarda (cifrado) entre el retorno del proveedor y el
+// código del doble factor local. El cliente lo devuelve en CompleteSSOTwoFactor.
+type ssoChallenge struct {
+	UserID    uint   `json:"u"`
+	Issuer    string `json:"i"`
+	ExpiresAt int64  `json:"e"` // Unix, segundos
+}
+
+// roleRank ordena los roles para quedarse con el mayor cuando varios claims coinciden.
+var roleRank = map[string]int{"student": 1, "teacher": 2, "admin": 3}
+
+var userNameUnsafe = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// BeginSSO implements IUserService.
+func (u *userService) BeginSSO(ctx context.Context) (userdto.SSOStartDTO, error) {
+	if u.sso == nil || u.cfg.SSOKey == "" {
+		return userdto.SSOStartDTO{}, ErrSSONotConfigured
+	}
+
+	state, _, err := securetoken.Generate(securetoken.DefaultBytes)
+	if err != nil {
+		return userdto.SSOStartDTO{}, err
+	}
+	nonce, _, err := securetoken.Generate(securetoken.DefaultBytes)
+	if err != nil {
+		return userdto.SSOStartDTO{}, err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return userdto.SSOStartDTO{}, err
+	}
+
+	payload, err := json.Marshal(ssoTransaction{
+		State:     state,
+		Nonce:     nonce,
+		Verifier:  verifier,
+		ExpiresAt: time.Now().Add(u.cfg.SSOTransactionTTL).Unix(),
+	})
+	if err != nil {
+		return userdto.SSOStartDTO{}, err
+	}
+	transaction, err := securetoken.Seal(u.cfg.SSOKey, string(payload))
+	if err != nil {
+		return userdto.SSOStartDTO{}, fmt.Errorf("failed to seal SSO transaction: %w", err)
+	}
+
+	return userdto.SSOStartDTO{
+		AuthURL:     u.sso.AuthCodeURL(state, nonce, oidc.CodeChallenge(verifier)),
+		Transaction: transaction,
+	}, nil
+}
+
+// CompleteSSO implements IUserService.
+func (u *userService) CompleteSSO(ctx context.Context, req userdto.SSOCallbackRequestDTO) (userdto.UserDetailDTO, error) {
+	if u.sso == nil || u.cfg.SSOKey == "" {
+		return userdto.UserDetailDTO{}, ErrSSONotConfigured
+	}
+	if req.Error != "" {
+		u.logger.WarnContext(ctx, "Identity provider returned an error",
+			"error", req.Error,
+			"description", req.ErrorDescription,
+		)
+		return userdto.UserDetailDTO{}, ErrSSODenied
+	}
+
+	transaction, err := u.openSSOTransaction(req.Transaction)
+	if err != nil || req.Code == "" || !securetoken.Equal(transaction.State, req.State) {
+		return userdto.UserDetailDTO{}, ErrSSOInvalidState
+	}
+
+	claims, err := u.sso.Exchange(ctx, req.Code, transaction.Verifier, transaction.Nonce)
+	if err != nil {
+		u.logger.WarnContext(ctx, "Failed to exchange SSO authorization code", "error", err)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to complete SSO login: %w", err)
+	}
+
+	user, identity, err := u.resolveSSOUser(ctx, claims)
+	if err != nil {
+		return userdto.UserDetailDTO{}, err
+	}
+
+	u.syncSSOAccount(ctx, user, identity, claims)
+
+	// El proveedor solo reemplaza el doble factor local si informa que lo pidió
+	// (amr/acr configurados). Si no, se pide el código de la cuenta local: vincular
+	// por email no debe abrir una sesión de admin con un solo factor.
+	scope := ""
+	if u.requiresTwoFactor(user.Role) && !u.ssoMFASatisfied(claims) {
+		twoFactor, err := u.twoFactorRepo.TwoFactorByUser(ctx, user.ID)
+		if err != nil && !errors.Is(err, twofactorrepo.ErrTwoFactorNotFound) {
+			u.logger.ErrorContext(ctx, "Failed to get two-factor during SSO login",
+				"error", err,
+				"user_id", user.ID,
+			)
+			return userdto.UserDetailDTO{}, fmt.Errorf("failed to complete SSO login: %w", err)
+		}
+		if twoFactor != nil && twoFactor.ConfirmedAt != nil {
+			challenge, err := u.sealSSOChallenge(user.ID, identity.Issuer)
+			if err != nil {
+				return userdto.UserDetailDTO{}, err
+			}
+			u.logger.InfoContext(ctx, "SSO login waiting for two-factor code", "user_id", user.ID)
+			detail := userdto.FromModelToDetail(user)
+			detail.TwoFactorChallenge = challenge
+			return detail, nil
+		}
+		scope = sessionrepo.ScopeTwoFactorSetup
+		u.logger.InfoContext(ctx, "Login limited to two-factor setup", "user_id", user.ID)
+	}
+
+	return u.ssoSession(ctx, user, identity.Issuer, scope, req.Device, req.UserAgent, req.IPAddress)
+}
+
+// CompleteSSOTwoFactor implements IUserService.
+func (u *userService) CompleteSSOTwoFactor(ctx context.Context, req userdto.SSOTwoFactorRequestDTO) (userdto.UserDetailDTO, error) {
+	if u.sso == nil || u.cfg.SSOKey == "" {
+		return userdto.UserDetailDTO{}, ErrSSONotConfigured
+	}
+
+	challenge, err := u.openSSOChallenge(req.Challenge)
+	if err != nil {
+		return userdto.UserDetailDTO{}, ErrSSOInvalidState
+	}
+	if strings.TrimSpace(req.Code) == "" {
+		return userdto.UserDetailDTO{}, ErrTwoFactorRequired
+	}
+
+	user, err := u.userRepo.UserByID(ctx, challenge.UserID)
+	if err != nil {
+		u.logger.WarnContext(ctx, "SSO challenge points to a missing user",
+			"error", err,
+			"user_id", challenge.UserID,
+		)
+		return userdto.UserDetailDTO{}, ErrSSOInvalidState
+	}
+
+	// Los códigos erróneos cuentan como intentos fallidos, igual que en Authenticate
+	keys := loginKeys(userdto.LoginRequestDTO{Email: user.Email, IPAddress: req.IPAddress})
+	if err := u.checkLockout(ctx, keys); err != nil {
+		return userdto.UserDetailDTO{}, err
+	}
+	twoFactor, err := u.enabledTwoFactor(ctx, user.ID)
+	if err != nil {
+		return userdto.UserDetailDTO{}, err
+	}
+	if err := u.verifySecondFactor(ctx, twoFactor, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			if failErr := u.loginFailed(ctx, keys); errors.Is(failErr, ErrLoginLocked) {
+				return userdto.UserDetailDTO{}, failErr
+			}
+		}
+		return userdto.UserDetailDTO{}, err
+	}
+	u.loginSucceeded(ctx, keys)
+
+	return u.ssoSession(ctx, user, challenge.Issuer, "", req.Device, req.UserAgent, req.IPAddress)
+}
+
+// ssoSession abre la sesión de un login institucional completo.
+func (u *userService) ssoSession(ctx context.Context, user *models.User, issuer, scope, device, userAgent, ipAddress string) (userdto.UserDetailDTO, error) {
+	session, token, err := u.openSession(ctx, user, scope, device, userAgent, ipAddress)
+	if err != nil {
+		return userdto.UserDetailDTO{}, err
+	}
+
+	u.logger.InfoContext(ctx, "User authenticated with SSO",
+		"user_id", user.ID,
+		"issuer", issuer,
+		"session_id", session.ID,
+	)
+
+	detail := userdto.FromModelToDetail(user)
+	detail.TwoFactorSetupRequired = scope == sessionrepo.ScopeTwoFactorSetup
+	detail.SessionToken = token
+	detail.SessionID = session.ID
+	return detail, nil
+}
+
+// ssoMFASatisfied indica si el proveedor informa (amr o acr) uno de los métodos
+// configurados como doble factor.
+func (u *userService) ssoMFASatisfied(claims *oidc.Claims) bool {
+	if len(u.cfg.SSOTrustedMFA) == 0 {
+		return false
+	}
+	for _, value := range append(claims.Values("amr"), claims.Values("acr")...) {
+		if slices.Contains(u.cfg.SSOTrustedMFA, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sealSSOChallenge cifra el desafío del doble factor de un login institucional.
+func (u *userService) sealSSOChallenge(userID uint, issuer string) (string, error) {
+	payload, err := json.Marshal(ssoChallenge{
+		UserID:    userID,
+		Issuer:    issuer,
+		ExpiresAt: time.Now().Add(u.cfg.SSOTransactionTTL).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	challenge, err := securetoken.Seal(u.cfg.SSOKey, string(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to seal SSO challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// openSSOChallenge descifra el desafío de CompleteSSO y revisa que no haya vencido.
+func (u *userService) openSSOChallenge(sealed string) (ssoChallenge, error) {
+	if sealed == "" {
+		return ssoChallenge{}, ErrSSOInvalidState
+	}
+	payload, err := securetoken.Open(u.cfg.SSOKey, sealed)
+	if err != nil {
+		return ssoChallenge{}, err
+	}
+
+	var challenge ssoChallenge
+	if err := json.Unmarshal([]byte(payload), &challenge); err != nil {
+		return ssoChallenge{}, err
+	}
+	// Una transacción de BeginSSO también se cifra con SSOKey, pero no trae usuario
+	if challenge.UserID == 0 || time.Now().Unix() >= challenge.ExpiresAt {
+		return ssoChallenge{}, ErrSSOInvalidState
+	}
+	return challenge, nil
+}
+
+// openSSOTransaction descifra la cookie de BeginSSO y revisa que no haya vencido.
+func (u *userService) openSSOTransaction(sealed string) (ssoTransaction, error) {
+	if sealed == "" {
+		return ssoTransaction{}, ErrSSOInvalidState
+	}
+	payload, err := securetoken.Open(u.cfg.SSOKey, sealed)
+	if err != nil {
+		return ssoTransaction{}, err
+	}
+
+	var transaction ssoTransaction
+	if err := json.Unmarshal([]byte(payload), &transaction); err != nil {
+		return ssoTransaction{}, err
+	}
+	if time.Now().Unix() >= transaction.ExpiresAt {
+		return ssoTransaction{}, ErrSSOInvalidState
+	}
+	return transaction, nil
+}
+
+// resolveSSOUser busca la cuenta vinculada al sujeto; si no hay, vincula la cuenta
+// con el mismo email o crea una nueva.
+func (u *userService) resolveSSOUser(ctx context.Context, claims *oidc.Claims) (*models.User, *models.ExternalIdentity, error) {
+	identity, err := u.identityRepo.IdentityBySubject(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		user, err := u.userRepo.UserByID(ctx, identity.UserID)
+		if err != nil {
+			u.logger.WarnContext(ctx, "SSO identity points to a missing user",
+				"error", err,
+				"user_id", identity.UserID,
+			)
+			return nil, nil, fmt.Errorf("failed to get user by ID: %w", err)
+		}
+		return user, identity, nil
+	}
+	if !errors.Is(err, externalidentityrepo.ErrIdentityNotFound) {
+		u.logger.ErrorContext(ctx, "Failed to get SSO identity", "error", err)
+		return nil, nil, fmt.Errorf("failed to get SSO identity: %w", err)
+	}
+
+	// Sin un email verificado por el proveedor, vincular por email permitiría tomar
+	// cuentas ajenas.
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, nil, ErrSSOEmailNotVerified
+	}
+	if !u.ssoDomainAllowed(claims.Email) {
+		return nil, nil, ErrSSODomainNotAllowed
+	}
+
+	user, err := u.userRepo.UserByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		identity, err = u.identityRepo.CreateIdentity(ctx, &models.ExternalIdentity{
+			UserID:  user.ID,
+			Issuer:  claims.Issuer,
+			Subject: claims.Subject,
+			Email:   claims.Email,
+		})
+		if err != nil {
+			u.logger.WarnContext(ctx, "Failed to link SSO identity",
+				"error", err,
+				"user_id", user.ID,
+			)
+			return nil, nil, fmt.Errorf("failed to link SSO identity: %w", err)
+		}
+		u.logger.InfoContext(ctx, "SSO identity linked to existing user", "user_id", user.ID)
+		return user, identity, nil
+	case !errors.Is(err, userrepo.ErrUserNotFound):
+		u.logger.ErrorContext(ctx, "Failed to get user by email", "error", err)
+		return nil, nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	if !u.cfg.SSOAutoProvision {
+		return nil, nil, ErrSSONoAccount
+	}
+	return u.provisionSSOUser(ctx, claims)
+}
+
+// provisionSSOUser crea la cuenta y su vínculo en una sola transacción.
+func (u *userService) provisionSSOUser(ctx context.Context, claims *oidc.Claims) (*models.User, *models.ExternalIdentity, error) {
+	userName, err := u.freeUserName(ctx, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// La cuenta no tiene contraseña conocida: solo entra por SSO
+	randomPassword, err := securetoken.Password(32)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashedPassword, err := u.hasher.HashPassword(randomPassword)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	verifiedAt := time.Now()
+	user := &models.User{
+		UserName:        userName,
+		Email:           claims.Email,
+		PasswordHash:    hashedPassword,
+		Role:            u.ssoRole(claims),
+		EmailVerifiedAt: &verifiedAt,
+	}
+	var identity *models.ExternalIdentity
+
+	err = u.txm.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := u.userRepo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		var err error
+		identity, err = u.identityRepo.CreateIdentity(ctx, &models.ExternalIdentity{
+			UserID:  user.ID,
+			Issuer:  claims.Issuer,
+			Subject: claims.Subject,
+			Email:   claims.Email,
+		})
+		return err
+	})
+	if err != nil {
+		u.logger.WarnContext(ctx, "Failed to provision SSO user",
+			"error", err,
+			"email", claims.Email,
+		)
+		return nil, nil, fmt.Errorf("failed to provision SSO user: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "User provisioned from SSO",
+		"user_id", user.ID,
+		"role", user.Role,
+	)
+	return user, identity, nil
+}
+
+// syncSSOAccount registra el login y, según la configuración, alinea el rol y la
+// verificación del email con lo que informa el proveedor. Los fallos no impiden entrar.
+func (u *userService) syncSSOAccount(ctx context.Context, user *models.User, identity *models.ExternalIdentity, claims *oidc.Claims) {
+	if err := u.identityRepo.TouchIdentity(ctx, identity.ID, claims.Email); err != nil {
+		u.logger.WarnContext(ctx, "Failed to update SSO identity",
+			"error", err,
+			"identity_id", identity.ID,
+		)
+	}
+
+	if u.cfg.SSOSyncRole {
+		if role := u.ssoRole(claims); role != user.Role {
+			if err := u.userRepo.UpdateRole(ctx, user.ID, role); err != nil {
+				u.logger.WarnContext(ctx, "Failed to sync role from SSO",
+					"error", err,
+					"user_id", user.ID,
+				)
+			} else {
+				u.logger.InfoContext(ctx, "Role synced from SSO",
+					"user_id", user.ID,
+					"from", user.Role,
+					"to", role,
+				)
+				user.Role = role
+			}
+		}
+	}
+
+	if user.EmailVerifiedAt == nil && claims.EmailVerified && strings.EqualFold(claims.Email, user.Email) {
+		if err := u.userRepo.MarkEmailVerified(ctx, user.ID, user.Email); err != nil {
+			u.logger.WarnContext(ctx, "Failed to mark email verified from SSO",
+				"error", err,
+				"user_id", user.ID,
+			)
+		} else {
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+		}
+	}
+}
+
+// ssoRole traduce los valores del claim configurado a un rol local; si varios
+// coinciden gana el de mayor privilegio y, si ninguno, el rol por defecto.
+func (u *userService) ssoRole(claims *oidc.Claims) string {
+	role := u.cfg.SSODefaultRole
+	for _, value := range claims.Values(u.cfg.SSORoleClaim) {
+		mapped, ok := u.cfg.SSORoleMapping[value]
+		if ok && roleRank[mapped] > roleRank[role] {
+			role = mapped
+		}
+	}
+	return role
+}
+
+func (u *userService) ssoDomainAllowed(email string) bool {
+	if len(u.cfg.SSOAllowedDomains) == 0 {
+		return true
+	}
+	_, domain, _ := strings.Cut(email, "@")
+	return slices.ContainsFunc(u.cfg.SSOAllowedDomains, func(allowed string) bool {
+		return strings.EqualFold(allowed, domain)
+	})
+}
+
+// freeUserName deriva un nombre de usuario del proveedor y le agrega un número si
+// ya está tomado.
+func (u *userService) freeUserName(ctx context.Context, claims *oidc.Claims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base = claims.Email
+	}
+	base, _, _ = strings.Cut(strings.ToLower(base), "@")
+	base = strings.Trim(userNameUnsafe.ReplaceAllString(base, ""), ".-_")
+	if len(base) < 3 {
+		base = "usuario"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for i := 2; i < 100; i++ {
+		_, err := u.userRepo.UserByUsername(ctx, candidate)
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to check username: %w", err)
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+	return "", fmt.Errorf("failed to find a free username for %q", base)
+}
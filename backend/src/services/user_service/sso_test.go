@@ -0,0 +1,346 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Dieg0Code/aiep-agent/src/auth/password"
+	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	loginthrottlerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/login_throttle_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/oidc"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/oidc/oidctest"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/totp"
+)
+
+const (
+	testClientID     = "aiep-agent"
+	testClientSecret = "s3cr3t"
+	testKey          = "0123456789abcdef0123456789abcdef"
+)
+
+type ssoFixture struct {
+	srv        *oidctest.Server
+	svc        *userService
+	users      *fakeUsers
+	identities *fakeIdentities
+	twoFactors *fakeTwoFactors
+	throttles  *fakeThrottles
+}
+
+func newSSOFixture(t *testing.T, configure func(*Config)) *ssoFixture {
+	t.Helper()
+	srv, err := oidctest.NewServer(testClientID, testClientSecret)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	client, err := oidc.NewClient(context.Background(), oidc.Config{
+		Issuer:       srv.Issuer(),
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  "http://localhost:8080/auth/sso/callback",
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.SSOKey = testKey
+	cfg.TwoFactorKey = testKey
+	cfg.DelayAfter = 100 // Sin demoras en las pruebas
+	cfg.SSORoleMapping = map[string]string{"docentes": "teacher", "staff": "admin"}
+	if configure != nil {
+		configure(&cfg)
+	}
+
+	f := &ssoFixture{
+		srv:        srv,
+		users:      newFakeUsers(),
+		identities: &fakeIdentities{},
+		twoFactors: &fakeTwoFactors{factors: map[uint]*models.TwoFactor{}},
+		throttles:  &fakeThrottles{failures: map[string]int{}},
+	}
+	f.svc = NewUserService(
+		fakeTx{},
+		f.users,
+		nil,
+		f.throttles,
+		f.twoFactors,
+		&fakeSessions{},
+		f.identities,
+		nil,
+		nil,
+		password.NewHasher(password.NewBcrypt(4)),
+		nil,
+		client,
+		cfg,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	).(*userService)
+	return f
+}
+
+// login recorre BeginSSO, el proveedor y CompleteSSO con los claims indicados.
+func (f *ssoFixture) login(t *testing.T, claims map[string]any) (userdto.UserDetailDTO, error) {
+	t.Helper()
+	f.srv.SetClaims(claims)
+
+	start, err := f.svc.BeginSSO(context.Background())
+	if err != nil {
+		t.Fatalf("BeginSSO: %v", err)
+	}
+	code, state, err := f.srv.Authorize(start.AuthURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	return f.svc.CompleteSSO(context.Background(), userdto.SSOCallbackRequestDTO{
+		Code:        code,
+		State:       state,
+		Transaction: start.Transaction,
+		IPAddress:   "203.0.113.7",
+	})
+}
+
+// enableTwoFactor deja el doble factor confirmado y devuelve el secreto.
+func (f *ssoFixture) enableTwoFactor(t *testing.T, userID uint) string {
+	t.Helper()
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	sealed, err := securetoken.Seal(testKey, secret)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	now := time.Now()
+	f.twoFactors.factors[userID] = &models.TwoFactor{UserID: userID, SecretSealed: sealed, ConfirmedAt: &now}
+	return secret
+}
+
+func ssoClaims(sub, email string) map[string]any {
+	return map[string]any{
+		"sub":                sub,
+		"email":              email,
+		"email_verified":     true,
+		"preferred_username": "ana.perez",
+		"groups":             []any{"alumnos"},
+	}
+}
+
+func TestCompleteSSOProvisionsAndReusesIdentity(t *testing.T) {
+	f := newSSOFixture(t, nil)
+
+	first, err := f.login(t, ssoClaims("sub-1", "ana@example.com"))
+	if err != nil {
+		t.Fatalf("first CompleteSSO: %v", err)
+	}
+	if first.SessionToken == "" || first.Role != "student" || first.UserName != "ana.perez" || !first.EmailVerified {
+		t.Fatalf("unexpected detail %+v", first)
+	}
+	if _, err := f.svc.ValidateSession(context.Background(), first.SessionToken, ""); err != nil {
+		t.Fatalf("ValidateSession: %v", err)
+	}
+
+	// El segundo login entra por el sujeto aunque el proveedor cambie el email
+	second, err := f.login(t, ssoClaims("sub-1", "ana.perez@example.com"))
+	if err != nil {
+		t.Fatalf("second CompleteSSO: %v", err)
+	}
+	if second.ID != first.ID || len(f.users.users) != 1 || len(f.identities.identities) != 1 {
+		t.Fatalf("second login created a new account: %d users, %d identities", len(f.users.users), len(f.identities.identities))
+	}
+}
+
+func TestCompleteSSOLinksExistingAccountByEmail(t *testing.T) {
+	f := newSSOFixture(t, nil)
+	existing := f.users.add(models.User{UserName: "ana", Email: "ana@example.com", Role: "student"})
+
+	detail, err := f.login(t, ssoClaims("sub-1", "Ana@Example.com"))
+	if err != nil {
+		t.Fatalf("CompleteSSO: %v", err)
+	}
+	if detail.ID != existing.ID || detail.UserName != "ana" {
+		t.Fatalf("logged in as %d (%s), want %d", detail.ID, detail.UserName, existing.ID)
+	}
+	if got := f.identities.identities; len(got) != 1 || got[0].UserID != existing.ID || got[0].Issuer != f.srv.Issuer() {
+		t.Fatalf("identities = %+v", got)
+	}
+	if f.users.users[existing.ID].EmailVerifiedAt == nil {
+		t.Error("email not marked verified")
+	}
+}
+
+func TestCompleteSSORejectsAccount(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*Config)
+		claims    map[string]any
+		want      error
+	}{
+		{"unverified email", nil, map[string]any{"sub": "s", "email": "ana@example.com", "email_verified": false}, ErrSSOEmailNotVerified},
+		{"missing email", nil, map[string]any{"sub": "s"}, ErrSSOEmailNotVerified},
+		{"domain not allowed", func(c *Config) { c.SSOAllowedDomains = []string{"aiep.cl"} }, ssoClaims("s", "ana@example.com"), ErrSSODomainNotAllowed},
+		{"no account without provisioning", func(c *Config) { c.SSOAutoProvision = false }, ssoClaims("s", "ana@example.com"), ErrSSONoAccount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSSOFixture(t, tt.configure)
+			if _, err := f.login(t, tt.claims); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if len(f.users.users) != 0 {
+				t.Fatalf("account created: %+v", f.users.users)
+			}
+		})
+	}
+}
+
+func TestCompleteSSORejectsInvalidState(t *testing.T) {
+	f := newSSOFixture(t, nil)
+	start, err := f.svc.BeginSSO(context.Background())
+	if err != nil {
+		t.Fatalf("BeginSSO: %v", err)
+	}
+	code, state, err := f.srv.Authorize(start.AuthURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  userdto.SSOCallbackRequestDTO
+		want error
+	}{
+		{"other state", userdto.SSOCallbackRequestDTO{Code: code, State: "other", Transaction: start.Transaction}, ErrSSOInvalidState},
+		{"missing transaction", userdto.SSOCallbackRequestDTO{Code: code, State: state}, ErrSSOInvalidState},
+		{"tampered transaction", userdto.SSOCallbackRequestDTO{Code: code, State: state, Transaction: start.Transaction + "x"}, ErrSSOInvalidState},
+		{"provider error", userdto.SSOCallbackRequestDTO{State: state, Error: "access_denied", Transaction: start.Transaction}, ErrSSODenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.svc.CompleteSSO(context.Background(), tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteSSORejectsInvalidToken(t *testing.T) {
+	f := newSSOFixture(t, nil)
+	f.srv.OverrideTokenClaims(map[string]any{"aud": "other-client"})
+
+	if _, err := f.login(t, ssoClaims("sub-1", "ana@example.com")); !errors.Is(err, oidc.ErrInvalidAudience) {
+		t.Fatalf("err = %v, want ErrInvalidAudience", err)
+	}
+	if len(f.users.users) != 0 {
+		t.Fatal("account created from a rejected token")
+	}
+}
+
+func TestCompleteSSORequiresLocalTwoFactor(t *testing.T) {
+	f := newSSOFixture(t, nil)
+	admin := f.users.add(models.User{UserName: "admin", Email: "admin@example.com", Role: "admin"})
+	secret := f.enableTwoFactor(t, admin.ID)
+
+	detail, err := f.login(t, ssoClaims("sub-admin", "admin@example.com"))
+	if err != nil {
+		t.Fatalf("CompleteSSO: %v", err)
+	}
+	if detail.TwoFactorChallenge == "" || detail.SessionToken != "" {
+		t.Fatalf("want a challenge and no session, got %+v", detail)
+	}
+
+	// Un código erróneo no abre sesión y cuenta como fallo
+	_, err = f.svc.CompleteSSOTwoFactor(context.Background(), userdto.SSOTwoFactorRequestDTO{
+		Challenge: detail.TwoFactorChallenge,
+		Code:      "000000",
+	})
+	if !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("wrong code: err = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	if f.throttles.failures[loginthrottlerepo.KeyAccount+"admin@example.com"] != 1 {
+		t.Errorf("failures = %v", f.throttles.failures)
+	}
+
+	code, err := totp.CodeAt(secret, totp.Counter(time.Now()))
+	if err != nil {
+		t.Fatalf("CodeAt: %v", err)
+	}
+	// Un desafío alterado se rechaza antes de mirar el código
+	if _, err := f.svc.CompleteSSOTwoFactor(context.Background(), userdto.SSOTwoFactorRequestDTO{
+		Challenge: detail.TwoFactorChallenge + "x",
+		Code:      code,
+	}); !errors.Is(err, ErrSSOInvalidState) {
+		t.Fatalf("tampered challenge: err = %v, want ErrSSOInvalidState", err)
+	}
+
+	session, err := f.svc.CompleteSSOTwoFactor(context.Background(), userdto.SSOTwoFactorRequestDTO{
+		Challenge: detail.TwoFactorChallenge,
+		Code:      code,
+	})
+	if err != nil {
+		t.Fatalf("CompleteSSOTwoFactor: %v", err)
+	}
+	if session.ID != admin.ID || session.SessionToken == "" || session.TwoFactorSetupRequired {
+		t.Fatalf("unexpected detail %+v", session)
+	}
+	if _, err := f.svc.ValidateSession(context.Background(), session.SessionToken, ""); err != nil {
+		t.Fatalf("ValidateSession: %v", err)
+	}
+}
+
+func TestCompleteSSOLimitsSessionWithoutTwoFactor(t *testing.T) {
+	f := newSSOFixture(t, nil)
+	f.users.add(models.User{UserName: "admin", Email: "admin@example.com", Role: "admin"})
+
+	detail, err := f.login(t, ssoClaims("sub-admin", "admin@example.com"))
+	if err != nil {
+		t.Fatalf("CompleteSSO: %v", err)
+	}
+	if !detail.TwoFactorSetupRequired || detail.SessionToken == "" {
+		t.Fatalf("want a setup session, got %+v", detail)
+	}
+	if _, err := f.svc.ValidateSession(context.Background(), detail.SessionToken, ""); !errors.Is(err, ErrTwoFactorSetupRequired) {
+		t.Fatalf("ValidateSession: err = %v, want ErrTwoFactorSetupRequired", err)
+	}
+	if _, err := f.svc.ValidateSetupSession(context.Background(), detail.SessionToken, ""); err != nil {
+		t.Fatalf("ValidateSetupSession: %v", err)
+	}
+}
+
+func TestCompleteSSOTrustsProviderMFA(t *testing.T) {
+	f := newSSOFixture(t, func(c *Config) { c.SSOTrustedMFA = []string{"mfa"} })
+	admin := f.users.add(models.User{UserName: "admin", Email: "admin@example.com", Role: "admin"})
+	f.enableTwoFactor(t, admin.ID)
+
+	claims := ssoClaims("sub-admin", "admin@example.com")
+	claims["amr"] = []any{"pwd", "mfa"}
+	detail, err := f.login(t, claims)
+	if err != nil {
+		t.Fatalf("CompleteSSO: %v", err)
+	}
+	if detail.TwoFactorChallenge != "" || detail.TwoFactorSetupRequired || detail.SessionToken == "" {
+		t.Fatalf("want a full session, got %+v", detail)
+	}
+}
+
+func TestCompleteSSOMapsRole(t *testing.T) {
+	f := newSSOFixture(t, func(c *Config) { c.SSOTrustedMFA = []string{"mfa"} })
+
+	claims := ssoClaims("sub-1", "ana@example.com")
+	claims["groups"] = []any{"alumnos", "docentes"}
+	claims["amr"] = []any{"mfa"}
+	detail, err := f.login(t, claims)
+	if err != nil {
+		t.Fatalf("CompleteSSO: %v", err)
+	}
+	if detail.Role != "teacher" {
+		t.Fatalf("role = %q, want teacher", detail.Role)
+	}
+}
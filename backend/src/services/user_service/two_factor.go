@@ -0,0 +1,300 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	twofactorrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/two_factor_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/totp"
+)
+
+// recoveryCodeLength es el largo de un código de recuperación sin el guion.
+const recoveryCodeLength = 10
+
+// BeginTwoFactor implements IUserService.
+func (u *userService) BeginTwoFactor(ctx context.Context, userID uint) (userdto.TwoFactorSetupDTO, error) {
+	if u.cfg.TwoFactorKey == "" {
+		return userdto.TwoFactorSetupDTO{}, ErrTwoFactorNotConfigured
+	}
+
+	user, err := u.userRepo.UserByID(ctx, userID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", userID,
+		)
+		return userdto.TwoFactorSetupDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return userdto.TwoFactorSetupDTO{}, err
+	}
+	sealed, err := securetoken.Seal(u.cfg.TwoFactorKey, secret)
+	if err != nil {
+		return userdto.TwoFactorSetupDTO{}, fmt.Errorf("failed to seal two-factor secret: %w", err)
+	}
+
+	if _, err := u.twoFactorRepo.SavePendingTwoFactor(ctx, user.ID, sealed); err != nil {
+		u.logger.WarnContext(ctx, "Failed to start two-factor enrollment",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return userdto.TwoFactorSetupDTO{}, fmt.Errorf("failed to start two-factor enrollment: %w", err)
+	}
+
+	return userdto.TwoFactorSetupDTO{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(u.cfg.TwoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor implements IUserService.
+func (u *userService) ConfirmTwoFactor(ctx context.Context, req userdto.ConfirmTwoFactorRequestDTO) (userdto.RecoveryCodesDTO, error) {
+	twoFactor, err := u.twoFactorRepo.TwoFactorByUser(ctx, req.UserID)
+	if err != nil {
+		u.logger.WarnContext(ctx, "Failed to get two-factor enrollment",
+			"error", err,
+			"user_id", req.UserID,
+		)
+		return userdto.RecoveryCodesDTO{}, fmt.Errorf("failed to get two-factor enrollment: %w", err)
+	}
+	if twoFactor.ConfirmedAt != nil {
+		return userdto.RecoveryCodesDTO{}, twofactorrepo.ErrAlreadyConfirmed
+	}
+
+	secret, err := securetoken.Open(u.cfg.TwoFactorKey, twoFactor.SecretSealed)
+	if err != nil {
+		return userdto.RecoveryCodesDTO{}, fmt.Errorf("failed to open two-factor secret: %w", err)
+	}
+	counter, ok := totp.Validate(secret, req.Code, time.Now(), u.cfg.TOTPSkew)
+	if !ok {
+		return userdto.RecoveryCodesDTO{}, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := u.newRecoveryCodes(req.UserID)
+	if err != nil {
+		return userdto.RecoveryCodesDTO{}, err
+	}
+	if err := u.twoFactorRepo.ConfirmTwoFactor(ctx, req.UserID, counter, hashes); err != nil {
+		u.logger.ErrorContext(ctx, "Failed to confirm two-factor",
+			"error", err,
+			"user_id", req.UserID,
+		)
+		return userdto.RecoveryCodesDTO{}, fmt.Errorf("failed to confirm two-factor: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "Two-factor enabled", "user_id", req.UserID)
+	return userdto.RecoveryCodesDTO{Codes: codes}, nil
+}
+
+// RegenerateRecoveryCodes implements IUserService.
+func (u *userService) RegenerateRecoveryCodes(ctx context.Context, req userdto.RegenerateRecoveryCodesRequestDTO) (userdto.RecoveryCodesDTO, error) {
+	twoFactor, err := u.enabledTwoFactor(ctx, req.UserID)
+	if err != nil {
+		return userdto.RecoveryCodesDTO{}, err
+	}
+	if err := u.verifySecondFactor(ctx, twoFactor, req.Code); err != nil {
+		return userdto.RecoveryCodesDTO{}, err
+	}
+
+	codes, hashes, err := u.newRecoveryCodes(req.UserID)
+	if err != nil {
+		return userdto.RecoveryCodesDTO{}, err
+	}
+	if err := u.twoFactorRepo.ReplaceRecoveryCodes(ctx, req.UserID, hashes); err != nil {
+		u.logger.ErrorContext(ctx, "Failed to replace recovery codes",
+			"error", err,
+			"user_id", req.UserID,
+		)
+		return userdto.RecoveryCodesDTO{}, fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "Recovery codes regenerated", "user_id", req.UserID)
+	return userdto.RecoveryCodesDTO{Codes: codes}, nil
+}
+
+// DisableTwoFactor implements IUserService.
+func (u *userService) DisableTwoFactor(ctx context.Context, req userdto.DisableTwoFactorRequestDTO) error {
+	user, err := u.userRepo.UserByID(ctx, req.UserID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", req.UserID,
+		)
+		return fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if u.requiresTwoFactor(user.Role) {
+		return ErrTwoFactorMandatory
+	}
+	if err := u.hasher.CompareHashAndPassword(user.PasswordHash, req.Password); err != nil {
+		return fmt.Errorf("password is incorrect: %w", err)
+	}
+
+	twoFactor, err := u.enabledTwoFactor(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := u.verifySecondFactor(ctx, twoFactor, req.Code); err != nil {
+		return err
+	}
+
+	if err := u.twoFactorRepo.DeleteTwoFactor(ctx, user.ID); err != nil {
+		u.logger.ErrorContext(ctx, "Failed to disable two-factor",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return fmt.Errorf("failed to disable two-factor: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "Two-factor disabled", "user_id", user.ID)
+	return nil
+}
+
+// ResetTwoFactor implements IUserService.
+func (u *userService) ResetTwoFactor(ctx context.Context, userID uint) error {
+	if err := u.twoFactorRepo.DeleteTwoFactor(ctx, userID); err != nil {
+		u.logger.ErrorContext(ctx, "Failed to reset two-factor",
+			"error", err,
+			"user_id", userID,
+		)
+		return fmt.Errorf("failed to reset two-factor: %w", err)
+	}
+
+	u.logger.WarnContext(ctx, "Two-factor reset by admin", "user_id", userID)
+	return nil
+}
+
+// TwoFactorStatus implements IUserService.
+func (u *userService) TwoFactorStatus(ctx context.Context, userID uint) (userdto.TwoFactorStatusDTO, error) {
+	user, err := u.userRepo.UserByID(ctx, userID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", userID,
+		)
+		return userdto.TwoFactorStatusDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+
+	status := userdto.TwoFactorStatusDTO{Required: u.requiresTwoFactor(user.Role)}
+	twoFactor, err := u.twoFactorRepo.TwoFactorByUser(ctx, userID)
+	if err != nil && !errors.Is(err, twofactorrepo.ErrTwoFactorNotFound) {
+		return userdto.TwoFactorStatusDTO{}, fmt.Errorf("failed to get two-factor: %w", err)
+	}
+	if twoFactor == nil || twoFactor.ConfirmedAt == nil {
+		return status, nil
+	}
+
+	status.Enabled = true
+	status.RecoveryCodesLeft, err = u.twoFactorRepo.CountUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return userdto.TwoFactorStatusDTO{}, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return status, nil
+}
+
+// secondFactor aplica el doble factor en el login. Devuelve si el rol lo exige y la
+// cuenta todavía no lo configuró.
+func (u *userService) secondFactor(ctx context.Context, user *models.User, code string) (setupRequired bool, err error) {
+	twoFactor, err := u.twoFactorRepo.TwoFactorByUser(ctx, user.ID)
+	if err != nil && !errors.Is(err, twofactorrepo.ErrTwoFactorNotFound) {
+		u.logger.ErrorContext(ctx, "Failed to get two-factor during authentication",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return false, fmt.Errorf("failed to authenticate: %w", err)
+	}
+	if twoFactor == nil || twoFactor.ConfirmedAt == nil {
+		return u.requiresTwoFactor(user.Role), nil
+	}
+
+	if strings.TrimSpace(code) == "" {
+		return false, ErrTwoFactorRequired
+	}
+	return false, u.verifySecondFactor(ctx, twoFactor, code)
+}
+
+// verifySecondFactor acepta un código TOTP no usado antes o un código de recuperación.
+func (u *userService) verifySecondFactor(ctx context.Context, twoFactor *models.TwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if len(strings.ReplaceAll(code, " ", "")) == totp.Digits {
+		secret, err := securetoken.Open(u.cfg.TwoFactorKey, twoFactor.SecretSealed)
+		if err != nil {
+			return fmt.Errorf("failed to open two-factor secret: %w", err)
+		}
+		counter, ok := totp.Validate(secret, code, time.Now(), u.cfg.TOTPSkew)
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		if err := u.twoFactorRepo.UseCounter(ctx, twoFactor.UserID, counter); err != nil {
+			if errors.Is(err, twofactorrepo.ErrCodeReused) {
+				return ErrInvalidTwoFactorCode
+			}
+			return fmt.Errorf("failed to register two-factor code: %w", err)
+		}
+		return nil
+	}
+
+	err := u.twoFactorRepo.UseRecoveryCode(ctx, twoFactor.UserID, recoveryHash(twoFactor.UserID, code))
+	if errors.Is(err, twofactorrepo.ErrRecoveryCodeInvalid) {
+		return ErrInvalidTwoFactorCode
+	}
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	u.logger.InfoContext(ctx, "Recovery code used", "user_id", twoFactor.UserID)
+	return nil
+}
+
+// enabledTwoFactor devuelve la configuración confirmada del usuario.
+func (u *userService) enabledTwoFactor(ctx context.Context, userID uint) (*models.TwoFactor, error) {
+	twoFactor, err := u.twoFactorRepo.TwoFactorByUser(ctx, userID)
+	if errors.Is(err, twofactorrepo.ErrTwoFactorNotFound) {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two-factor: %w", err)
+	}
+	if twoFactor.ConfirmedAt == nil {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return twoFactor, nil
+}
+
+// newRecoveryCodes genera los códigos (formato XXXXX-XXXXX) y sus hashes.
+func (u *userService) newRecoveryCodes(userID uint) ([]string, []string, error) {
+	count := u.cfg.RecoveryCodeCount
+	if count <= 0 {
+		count = 10
+	}
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	for range count {
+		raw, err := securetoken.Code(recoveryCodeLength)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, raw[:recoveryCodeLength/2]+"-"+raw[recoveryCodeLength/2:])
+		hashes = append(hashes, recoveryHash(userID, raw))
+	}
+	return codes, hashes, nil
+}
+
+// requiresTwoFactor indica si la política exige doble factor para el rol.
+func (u *userService) requiresTwoFactor(role string) bool {
+	return slices.Contains(u.cfg.TwoFactorRoles, role)
+}
+
+// recoveryHash normaliza el código (sin guiones ni espacios, en mayúsculas) y lo
+// hashea junto al usuario, para que dos cuentas nunca compartan hash.
+func recoveryHash(userID uint, code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return securetoken.Hash(fmt.Sprintf("%d:%s", userID, normalized))
+}
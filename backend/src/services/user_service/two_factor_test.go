@@ -0,0 +1,110 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	twofactorrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/two_factor_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/totp"
+)
+
+// enrollTwoFactor recorre BeginTwoFactor y ConfirmTwoFactor con un código del paso
+// anterior y devuelve el secreto y los códigos de recuperación.
+func enrollTwoFactor(t *testing.T, f *ssoFixture, userID uint) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	setup, err := f.svc.BeginTwoFactor(ctx, userID)
+	if err != nil {
+		t.Fatalf("BeginTwoFactor: %v", err)
+	}
+	code, err := totp.CodeAt(setup.Secret, totp.Counter(time.Now())-1)
+	if err != nil {
+		t.Fatalf("CodeAt: %v", err)
+	}
+	recovery, err := f.svc.ConfirmTwoFactor(ctx, userdto.ConfirmTwoFactorRequestDTO{UserID: userID, Code: code})
+	if err != nil {
+		t.Fatalf("ConfirmTwoFactor: %v", err)
+	}
+	return setup.Secret, recovery.Codes
+}
+
+func TestBeginTwoFactorKeepsConfirmedFactor(t *testing.T) {
+	f := newSSOFixture(t, nil)
+	user := f.users.add(models.User{UserName: "ana", Email: "ana@example.com", Role: "teacher"})
+	enrollTwoFactor(t, f, user.ID)
+	sealed := f.twoFactors.factors[user.ID].SecretSealed
+
+	if _, err := f.svc.BeginTwoFactor(context.Background(), user.ID); !errors.Is(err, twofactorrepo.ErrAlreadyConfirmed) {
+		t.Fatalf("err = %v, want ErrAlreadyConfirmed", err)
+	}
+	if got := f.twoFactors.factors[user.ID]; got.SecretSealed != sealed || got.ConfirmedAt == nil {
+		t.Fatal("confirmed factor was overwritten")
+	}
+}
+
+func TestVerifySecondFactorTOTP(t *testing.T) {
+	f := newSSOFixture(t, nil)
+	user := f.users.add(models.User{UserName: "ana", Email: "ana@example.com", Role: "teacher"})
+	secret, _ := enrollTwoFactor(t, f, user.ID)
+	current := totp.Counter(time.Now())
+
+	// Confirmar usó el paso anterior: ese ya no vale, los siguientes sí una sola vez
+	tests := []struct {
+		name   string
+		offset int64
+		want   error
+	}{
+		{"step used to confirm", -1, ErrInvalidTwoFactorCode},
+		{"current step", 0, nil},
+		{"current step reused", 0, ErrInvalidTwoFactorCode},
+		{"next step", 1, nil},
+		{"outside the window", 3, ErrInvalidTwoFactorCode},
+	}
+	for _, tt := range tests {
+		code, err := totp.CodeAt(secret, current+tt.offset)
+		if err != nil {
+			t.Fatalf("CodeAt: %v", err)
+		}
+		twoFactor := f.twoFactors.factors[user.ID]
+		if err := f.svc.verifySecondFactor(context.Background(), twoFactor, code); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestVerifySecondFactorRecoveryCodeSingleUse(t *testing.T) {
+	f := newSSOFixture(t, nil)
+	user := f.users.add(models.User{UserName: "ana", Email: "ana@example.com", Role: "teacher"})
+	_, codes := enrollTwoFactor(t, f, user.ID)
+	if len(codes) != 10 {
+		t.Fatalf("got %d recovery codes, want 10", len(codes))
+	}
+	twoFactor := f.twoFactors.factors[user.ID]
+	ctx := context.Background()
+
+	// El código se normaliza: sin guion y en minúsculas también vale
+	first := strings.ToLower(strings.ReplaceAll(codes[0], "-", ""))
+	if err := f.svc.verifySecondFactor(ctx, twoFactor, first); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := f.svc.verifySecondFactor(ctx, twoFactor, codes[0]); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("second use: err = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	if err := f.svc.verifySecondFactor(ctx, twoFactor, "AAAAA-BBBBB"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("unknown code: err = %v, want ErrInvalidTwoFactorCode", err)
+	}
+
+	// Otra cuenta no puede usar el código: el hash incluye el usuario
+	other := f.users.add(models.User{UserName: "juan", Email: "juan@example.com", Role: "teacher"})
+	if err := f.svc.verifySecondFactor(ctx, &models.TwoFactor{UserID: other.ID}, codes[1]); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("other user: err = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	if err := f.svc.verifySecondFactor(ctx, twoFactor, codes[1]); err != nil {
+		t.Fatalf("second code: %v", err)
+	}
+}
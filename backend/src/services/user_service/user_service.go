@@ -2,68 +2,250 @@ package userservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/Dieg0Code/aiep-agent/src/auth/bcrypt"
+	"github.com/Dieg0Code/aiep-agent/src/auth/password"
+	"github.com/Dieg0Code/aiep-agent/src/data/database"
 	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
+	accounttokenrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/account_token_repo"
+	enrollementrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/enrollment_repo"
+	externalidentityrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/external_identity_repo"
+	invitationrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/invitation_repo"
+	loginthrottlerepo "github.com/Dieg0Code/aiep-agent/src/data/repository/login_throttle_repo"
+	sessionrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/session_repo"
+	twofactorrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/two_factor_repo"
 	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/mailer"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/oidc"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
 )
 
 type userService struct {
-	userRepo userrepo.UserRepo
-	bcrypt   bcrypt.Bcrypt
-	logger   *slog.Logger
+	txm            database.TxManager
+	userRepo       userrepo.UserRepo
+	tokenRepo      accounttokenrepo.AccountTokenRepo
+	throttleRepo   loginthrottlerepo.LoginThrottleRepo
+	twoFactorRepo  twofactorrepo.TwoFactorRepo
+	sessionRepo    sessionrepo.SessionRepo
+	identityRepo   externalidentityrepo.ExternalIdentityRepo
+	invitationRepo invitationrepo.InvitationRepo
+	enrollmentRepo enrollementrepo.EnrollmentRepo
+	hasher         password.Hasher
+	mailer         mailer.Mailer
+	sso            oidc.Client
+	cfg            Config
+	logger         *slog.Logger
+
+	placeholderOnce sync.Once
+	placeholder     string // Hash para comparar cuando el email no existe
 }
 
-// NewUserService crea una instancia de IUserService con el repositorio inyectado.
-func NewUserService(userRepo userrepo.UserRepo, bcrypt bcrypt.Bcrypt, logger *slog.Logger) IUserService {
+// NewUserService crea una instancia de IUserService con los repositorios inyectados.
+// txm agrupa en una transacción las operaciones de varios repos (SignUp, alta por
+// SSO); los repos deben compartir la conexión con la que se creó. sso es
+// opcional: sin él el inicio de sesión institucional queda deshabilitado.
+func NewUserService(
+	txm database.TxManager,
+	userRepo userrepo.UserRepo,
+	tokenRepo accounttokenrepo.AccountTokenRepo,
+	throttleRepo loginthrottlerepo.LoginThrottleRepo,
+	twoFactorRepo twofactorrepo.TwoFactorRepo,
+	sessionRepo sessionrepo.SessionRepo,
+	identityRepo externalidentityrepo.ExternalIdentityRepo,
+	invitationRepo invitationrepo.InvitationRepo,
+	enrollmentRepo enrollementrepo.EnrollmentRepo,
+	hasher password.Hasher,
+	mailer mailer.Mailer,
+	sso oidc.Client,
+	cfg Config,
+	logger *slog.Logger,
+) IUserService {
 	return &userService{
-		userRepo: userRepo,
-		bcrypt:   bcrypt,
-		logger:   logger,
+		txm:            txm,
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		throttleRepo:   throttleRepo,
+		twoFactorRepo:  twoFactorRepo,
+		sessionRepo:    sessionRepo,
+		identityRepo:   identityRepo,
+		invitationRepo: invitationRepo,
+		enrollmentRepo: enrollmentRepo,
+		hasher:         hasher,
+		mailer:         mailer,
+		sso:            sso,
+		cfg:            cfg,
+		logger:         logger,
 	}
 }
 
-// Authenticate implements IUserService.
-func (u *userService) Authenticate(ctx context.Context, req userdto.LoginRequestDTO) (userdto.UserDetailDTO, error) {
+// AcceptInvite implements IUserService.
+func (u *userService) AcceptInvite(ctx context.Context, req userdto.AcceptInviteRequestDTO) (userdto.UserDetailDTO, error) {
+	if req.GetToken() == "" || req.GetPassword() == "" {
+		return userdto.UserDetailDTO{}, ErrInviteFieldsRequired
+	}
 
-	if req.Email == "" && req.Password == "" {
-		u.logger.ErrorContext(ctx, "Email and password must be provided",
-			"email", req.Email,
-			"password", req.Password,
-		)
-		return userdto.UserDetailDTO{}, fmt.Errorf("email and password must be provided")
+	token, err := u.tokenRepo.ActiveAccountTokenByHash(ctx, accounttokenrepo.PurposeInvite, securetoken.Hash(req.Token))
+	if err != nil {
+		u.logger.WarnContext(ctx, "Invalid or expired invite token", "error", err)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to get invite token: %w", err)
 	}
 
-	user, err := u.userRepo.UserByEmail(ctx, req.Email)
+	user, err := u.userRepo.UserByID(ctx, token.UserID)
 	if err != nil {
-		u.logger.ErrorContext(ctx, "Failed to get user by email during authentication",
+		u.logger.ErrorContext(ctx, "Failed to get user by ID",
 			"error", err,
-			"email", req.Email,
+			"user_id", token.UserID,
 		)
-		return userdto.UserDetailDTO{}, fmt.Errorf("failed to get user by email: %w", err)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if err := u.cfg.PasswordPolicy.Validate(req.Password, user.UserName, user.Email); err != nil {
+		return userdto.UserDetailDTO{}, err
 	}
 
-	if err := u.bcrypt.CompareHashAndPassword(user.PasswordHash, req.Password); err != nil {
-		u.logger.ErrorContext(ctx, "Invalid password during authentication",
+	hashedPassword, err := u.hasher.HashPassword(req.Password)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to hash password",
 			"error", err,
-			"email", req.Email,
+			"user_id", token.UserID,
 		)
-		return userdto.UserDetailDTO{}, fmt.Errorf("invalid password: %w", err)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// El consumo del token y la contraseña van en una transacción: si dos peticiones
+	// usan el mismo enlace solo una lo consume, y el token no se gasta si la
+	// contraseña no se llega a guardar.
+	err = u.txm.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.tokenRepo.MarkAccountTokenUsed(ctx, token.ID); err != nil {
+			u.logger.WarnContext(ctx, "Failed to consume invite token",
+				"error", err,
+				"user_id", token.UserID,
+			)
+			return fmt.Errorf("failed to consume invite token: %w", err)
+		}
+
+		if err := u.userRepo.UpdatePassword(ctx, token.UserID, hashedPassword); err != nil {
+			u.logger.ErrorContext(ctx, "Failed to set password from invite",
+				"error", err,
+				"user_id", token.UserID,
+			)
+			return fmt.Errorf("failed to update user password: %w", err)
+		}
+
+		// El enlace llegó al email de la cuenta: usarlo ya demuestra que el buzón es
+		// suyo. Va en un savepoint para que un fallo no deshaga lo anterior.
+		if user.EmailVerifiedAt == nil {
+			err := u.txm.Transaction(ctx, func(ctx context.Context) error {
+				return u.userRepo.MarkEmailVerified(ctx, user.ID, user.Email)
+			})
+			if err != nil {
+				u.logger.WarnContext(ctx, "Failed to mark email verified from invite",
+					"error", err,
+					"user_id", user.ID,
+				)
+			} else {
+				now := time.Now()
+				user.EmailVerifiedAt = &now
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return userdto.UserDetailDTO{}, err
+	}
+
+	u.logger.InfoContext(ctx, "Invite accepted", "user_id", user.ID)
+	return userdto.FromModelToDetail(user), nil
+}
+
+// Authenticate implements IUserService. Cualquier combinación incorrecta de email y
+// contraseña devuelve ErrInvalidCredentials, exista o no la cuenta.
+func (u *userService) Authenticate(ctx context.Context, req userdto.LoginRequestDTO) (userdto.UserDetailDTO, error) {
+	email := strings.TrimSpace(req.Email)
+	if email == "" || req.Password == "" {
+		return userdto.UserDetailDTO{}, ErrInvalidCredentials
+	}
+
+	keys := loginKeys(req)
+	if err := u.checkLockout(ctx, keys); err != nil {
+		return userdto.UserDetailDTO{}, err
+	}
+
+	user, err := u.userRepo.UserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, userrepo.ErrUserNotFound) {
+		u.logger.ErrorContext(ctx, "Failed to get user by email during authentication", "error", err)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	if user == nil {
+		// Se compara igual contra un hash de relleno para que el tiempo de respuesta
+		// no delate que el email no existe.
+		_ = u.hasher.CompareHashAndPassword(u.placeholderHash(), req.Password)
+		return userdto.UserDetailDTO{}, u.loginFailed(ctx, keys)
+	}
+	if err := u.hasher.CompareHashAndPassword(user.PasswordHash, req.Password); err != nil {
+		return userdto.UserDetailDTO{}, u.loginFailed(ctx, keys)
+	}
+
+	setupRequired, err := u.secondFactor(ctx, user, req.OTP)
+	switch {
+	case errors.Is(err, ErrInvalidTwoFactorCode):
+		// Los códigos erróneos cuentan como intentos fallidos
+		if failErr := u.loginFailed(ctx, keys); errors.Is(failErr, ErrLoginLocked) {
+			return userdto.UserDetailDTO{}, failErr
+		}
+		return userdto.UserDetailDTO{}, ErrInvalidTwoFactorCode
+	case err != nil:
+		return userdto.UserDetailDTO{}, err
+	}
+
+	u.loginSucceeded(ctx, keys)
+	u.rehashBestEffort(ctx, user, req.Password)
+
+	// Se revisa después de la contraseña para no revelar el estado de cuentas ajenas
+	if u.cfg.RequireVerifiedEmail && user.EmailVerifiedAt == nil {
+		u.logger.InfoContext(ctx, "Login blocked until email is verified", "user_id", user.ID)
+		// Sin sesión no puede pedir el reenvío: se manda aquí (respeta ResendInterval)
+		u.sendVerificationBestEffort(ctx, user)
+		return userdto.UserDetailDTO{}, ErrEmailNotVerified
+	}
+
+	// Sin el doble factor que exige su rol, la sesión solo sirve para activarlo
+	scope := ""
+	if setupRequired {
+		scope = sessionrepo.ScopeTwoFactorSetup
+		u.logger.InfoContext(ctx, "Login limited to two-factor setup", "user_id", user.ID)
+	}
+
+	session, token, err := u.openSession(ctx, user, scope, req.Device, req.UserAgent, req.IPAddress)
+	if err != nil {
+		return userdto.UserDetailDTO{}, err
 	}
 
 	u.logger.InfoContext(ctx, "User authenticated successfully",
 		"user_id", user.ID,
 		"email", user.Email,
+		"session_id", session.ID,
 	)
 
-	return userdto.FromModelToDetail(user), nil
+	detail := userdto.FromModelToDetail(user)
+	detail.TwoFactorSetupRequired = setupRequired
+	detail.SessionToken = token
+	detail.SessionID = session.ID
+	return detail, nil
 }
 
 // CreateUser implements IUserService.
 func (u *userService) CreateUser(ctx context.Context, req userdto.CreateUserDTO) (userdto.UserDetailDTO, error) {
-	hashedPassword, err := u.bcrypt.HashPassword(req.Password)
+	if err := u.cfg.PasswordPolicy.Validate(req.Password, req.UserName, req.Email); err != nil {
+		return userdto.UserDetailDTO{}, err
+	}
+
+	hashedPassword, err := u.hasher.HashPassword(req.Password)
 	if err != nil {
 		u.logger.ErrorContext(ctx, "Failed to hash password",
 			"error", err,
@@ -88,6 +270,7 @@ func (u *userService) CreateUser(ctx context.Context, req userdto.CreateUserDTO)
 		"username", createdUser.UserName,
 		"role", createdUser.Role,
 	)
+	u.sendVerificationBestEffort(ctx, createdUser)
 
 	return userdto.FromModelToDetail(createdUser), nil
 }
@@ -96,7 +279,7 @@ func (u *userService) CreateUser(ctx context.Context, req userdto.CreateUserDTO)
 func (u *userService) DeleteUser(ctx context.Context, id uint) error {
 
 	if id == 0 {
-		return fmt.Errorf("invalid user ID")
+		return userrepo.ErrInvalidUserID
 	}
 
 	if err := u.userRepo.DeleteUser(ctx, id); err != nil {
@@ -106,6 +289,9 @@ func (u *userService) DeleteUser(ctx context.Context, id uint) error {
 		)
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
+	if err := u.revokeSessions(ctx, id, 0, sessionrepo.ReasonAdmin); err != nil {
+		return err
+	}
 
 	u.logger.InfoContext(ctx, "User deleted successfully", "user_id", id)
 	return nil
@@ -129,7 +315,7 @@ func (u *userService) GetByEmail(ctx context.Context, email string) (userdto.Use
 // GetByID implements IUserService.
 func (u *userService) GetByID(ctx context.Context, id uint) (userdto.UserDetailDTO, error) {
 	if id == 0 {
-		return userdto.UserDetailDTO{}, fmt.Errorf("invalid user ID")
+		return userdto.UserDetailDTO{}, userrepo.ErrInvalidUserID
 	}
 
 	user, err := u.userRepo.UserByID(ctx, id)
@@ -160,28 +346,21 @@ func (u *userService) GetByUsername(ctx context.Context, username string) (userd
 
 // ListUsers implements IUserService.
 func (s *userService) ListUsers(ctx context.Context, req userdto.ListUsersRequestDTO) (userdto.ListUsersResponseDTO, error) {
-	// Validar y establecer valores por defecto
-	if req.Limit <= 0 {
-		req.Limit = 20
-	}
-	if req.Offset < 0 {
-		req.Offset = 0
-	}
-
-	users, err := s.userRepo.ListUsers(ctx, req.ToRepoFilter())
+	page, err := s.userRepo.ListUsers(ctx, req.ToRepoFilter())
 	if err != nil {
 
 		s.logger.ErrorContext(ctx, "Failed to list users",
 			"error", err,
-			"page_size", req.Limit,
+			"page_size", req.ToParams().PageSize(),
 			"search", req.Search,
-			"role", req.Role,
+			"roles", req.Roles,
+			"sort", req.Sort,
 		)
 		return userdto.ListUsersResponseDTO{}, fmt.Errorf("failed to list users: %w", err)
 	}
 
 	// Usar la función helper del DTO
-	response := userdto.MakeListUsersResponse(users, &req)
+	response := userdto.MakeListUsersResponse(page, &req)
 
 	return response, nil
 }
@@ -193,19 +372,19 @@ func (u *userService) UpdatePassword(ctx context.Context, req userdto.UpdatePass
 		u.logger.ErrorContext(ctx, "Invalid user ID",
 			"user_id", req.UserID,
 		)
-		return fmt.Errorf("invalid user ID")
+		return userrepo.ErrInvalidUserID
 	}
 	if req.OldPassword == "" {
 		u.logger.ErrorContext(ctx, "Old password cannot be empty",
 			"user_id", req.UserID,
 		)
-		return fmt.Errorf("old password cannot be empty")
+		return ErrPasswordRequired
 	}
 	if req.NewPassword == "" {
 		u.logger.ErrorContext(ctx, "New password cannot be empty",
 			"user_id", req.UserID,
 		)
-		return fmt.Errorf("new password cannot be empty")
+		return ErrPasswordRequired
 	}
 
 	// Validar contraseña actual
@@ -218,14 +397,17 @@ func (u *userService) UpdatePassword(ctx context.Context, req userdto.UpdatePass
 		return fmt.Errorf("failed to get user by ID: %w", err)
 	}
 
-	if err := u.bcrypt.CompareHashAndPassword(user.PasswordHash, req.OldPassword); err != nil {
-		u.logger.ErrorContext(ctx, "Old password is incorrect",
+	if err := u.hasher.CompareHashAndPassword(user.PasswordHash, req.OldPassword); err != nil {
+		u.logger.WarnContext(ctx, "Old password is incorrect",
 			"user_id", req.UserID,
 		)
 		return fmt.Errorf("old password is incorrect: %w", err)
 	}
+	if err := u.cfg.PasswordPolicy.Validate(req.NewPassword, user.UserName, user.Email); err != nil {
+		return err
+	}
 
-	hashedPassword, err := u.bcrypt.HashPassword(req.NewPassword)
+	hashedPassword, err := u.hasher.HashPassword(req.NewPassword)
 	if err != nil {
 		u.logger.ErrorContext(ctx, "Failed to hash new password",
 			"error", err,
@@ -242,6 +424,11 @@ func (u *userService) UpdatePassword(ctx context.Context, req userdto.UpdatePass
 		return fmt.Errorf("failed to update user password: %w", err)
 	}
 
+	// Quien conocía la contraseña anterior no debe seguir dentro en otro dispositivo
+	if err := u.revokeSessions(ctx, req.UserID, req.CurrentSessionID, sessionrepo.ReasonPasswordChange); err != nil {
+		return err
+	}
+
 	u.logger.InfoContext(ctx, "User password updated successfully", "user_id", req.UserID)
 	return nil
 }
@@ -253,13 +440,13 @@ func (u *userService) UpdateRole(ctx context.Context, req userdto.UpdateRoleRequ
 		u.logger.ErrorContext(ctx, "Invalid user ID",
 			"user_id", req.UserID,
 		)
-		return fmt.Errorf("invalid user ID")
+		return userrepo.ErrInvalidUserID
 	}
 	if req.NewRole == "" {
 		u.logger.ErrorContext(ctx, "New role cannot be empty",
 			"user_id", req.UserID,
 		)
-		return fmt.Errorf("new role cannot be empty")
+		return userrepo.ErrRoleEmpty
 	}
 
 	if err := u.userRepo.UpdateRole(ctx, req.UserID, req.NewRole); err != nil {
@@ -274,3 +461,29 @@ func (u *userService) UpdateRole(ctx context.Context, req userdto.UpdateRoleRequ
 	u.logger.InfoContext(ctx, "User role updated successfully", "user_id", req.UserID, "new_role", req.NewRole)
 	return nil
 }
+
+// UpdateLocale implements IUserService.
+func (u *userService) UpdateLocale(ctx context.Context, req userdto.UpdateLocaleRequestDTO) (userdto.UserDetailDTO, error) {
+	if req.UserID == 0 {
+		return userdto.UserDetailDTO{}, userrepo.ErrInvalidUserID
+	}
+
+	locale := req.GetLocale()
+	if err := u.userRepo.UpdateLocale(ctx, req.UserID, locale); err != nil {
+		u.logger.ErrorContext(ctx, "Failed to update user locale",
+			"error", err,
+			"user_id", req.UserID,
+			"locale", locale,
+		)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to update user locale: %w", err)
+	}
+
+	user, err := u.userRepo.UserByID(ctx, req.UserID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get user by ID", "error", err, "user_id", req.UserID)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "User locale updated", "user_id", req.UserID, "locale", locale)
+	return userdto.FromModelToDetail(user), nil
+}
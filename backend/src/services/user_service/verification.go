@@ -0,0 +1,199 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	userdto "github.com/Dieg0Code/aiep-agent/src/data/dtos/user_dto"
+	"github.com/Dieg0Code/aiep-agent/src/data/models"
+	userrepo "github.com/Dieg0Code/aiep-agent/src/data/repository/user_repo"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/mailer"
+	"github.com/Dieg0Code/aiep-agent/src/pkg/securetoken"
+)
+
+// purposeVerifyEmail distingue los tokens de verificación de otros tokens firmados.
+const purposeVerifyEmail = "verify_email"
+
+// SendVerificationEmail implements IUserService.
+func (u *userService) SendVerificationEmail(ctx context.Context, userID uint) error {
+	if userID == 0 {
+		return userrepo.ErrInvalidUserID
+	}
+
+	user, err := u.userRepo.UserByID(ctx, userID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", userID,
+		)
+		return fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	return u.sendVerification(ctx, user)
+}
+
+// ResendVerificationEmail implements IUserService.
+func (u *userService) ResendVerificationEmail(ctx context.Context, req userdto.ResendVerificationRequestDTO) error {
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return userrepo.ErrEmailEmpty
+	}
+	if u.mailer == nil || u.cfg.VerificationSecret == "" {
+		return ErrVerificationNotConfigured
+	}
+
+	// Cuentas inexistentes, ya verificadas o con un envío reciente responden igual
+	user, err := u.userRepo.UserByEmail(ctx, email)
+	if errors.Is(err, userrepo.ErrUserNotFound) {
+		u.logger.InfoContext(ctx, "Verification resend requested for unknown email")
+		return nil
+	}
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get user by email", "error", err)
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	u.sendVerificationBestEffort(ctx, user)
+	return nil
+}
+
+// VerifyEmail implements IUserService.
+func (u *userService) VerifyEmail(ctx context.Context, req userdto.VerifyEmailRequestDTO) (userdto.UserDetailDTO, error) {
+	claims, err := securetoken.Verify([]byte(u.cfg.VerificationSecret), req.Token, time.Now())
+	if err != nil || claims.Purpose != purposeVerifyEmail {
+		u.logger.WarnContext(ctx, "Invalid email verification token", "error", err)
+		return userdto.UserDetailDTO{}, ErrInvalidVerificationToken
+	}
+
+	// El token va ligado al email: si la cuenta lo cambió, el enlace anterior no sirve
+	if err := u.userRepo.MarkEmailVerified(ctx, claims.Subject, claims.Binding); err != nil {
+		u.logger.WarnContext(ctx, "Failed to mark email as verified",
+			"error", err,
+			"user_id", claims.Subject,
+		)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	user, err := u.userRepo.UserByID(ctx, claims.Subject)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", claims.Subject,
+		)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "Email verified", "user_id", user.ID)
+	return userdto.FromModelToDetail(user), nil
+}
+
+// UpdateEmail implements IUserService.
+func (u *userService) UpdateEmail(ctx context.Context, req userdto.UpdateEmailRequestDTO) (userdto.UserDetailDTO, error) {
+	if req.UserID == 0 {
+		return userdto.UserDetailDTO{}, userrepo.ErrInvalidUserID
+	}
+	newEmail := strings.TrimSpace(req.NewEmail)
+	if newEmail == "" {
+		return userdto.UserDetailDTO{}, userrepo.ErrEmailEmpty
+	}
+
+	user, err := u.userRepo.UserByID(ctx, req.UserID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Failed to get user by ID",
+			"error", err,
+			"user_id", req.UserID,
+		)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+	if err := u.hasher.CompareHashAndPassword(user.PasswordHash, req.Password); err != nil {
+		u.logger.WarnContext(ctx, "Password is incorrect when changing email", "user_id", req.UserID)
+		return userdto.UserDetailDTO{}, fmt.Errorf("password is incorrect: %w", err)
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return userdto.FromModelToDetail(user), nil
+	}
+
+	if err := u.userRepo.UpdateEmail(ctx, user.ID, newEmail); err != nil {
+		u.logger.ErrorContext(ctx, "Failed to update user email",
+			"error", err,
+			"user_id", user.ID,
+		)
+		return userdto.UserDetailDTO{}, fmt.Errorf("failed to update user email: %w", err)
+	}
+
+	user.Email = newEmail
+	user.EmailVerifiedAt = nil
+	user.VerificationSentAt = nil
+	u.logger.InfoContext(ctx, "User email changed, verification required", "user_id", user.ID)
+	u.sendVerificationBestEffort(ctx, user)
+
+	return userdto.FromModelToDetail(user), nil
+}
+
+// sendVerificationBestEffort envía la verificación sin hacer fallar la operación
+// que la originó; el usuario siempre puede pedir un reenvío.
+func (u *userService) sendVerificationBestEffort(ctx context.Context, user *models.User) {
+	if err := u.sendVerification(ctx, user); err != nil {
+		u.logger.WarnContext(ctx, "Failed to send verification email",
+			"error", err,
+			"user_id", user.ID,
+		)
+	}
+}
+
+// sendVerification firma un enlace ligado al email actual y lo envía, respetando
+// el intervalo mínimo entre envíos.
+func (u *userService) sendVerification(ctx context.Context, user *models.User) error {
+	if u.mailer == nil || u.cfg.VerificationSecret == "" {
+		return ErrVerificationNotConfigured
+	}
+
+	now := time.Now()
+	token, err := securetoken.Sign([]byte(u.cfg.VerificationSecret), securetoken.Claims{
+		Purpose:   purposeVerifyEmail,
+		Subject:   user.ID,
+		Binding:   user.Email,
+		ExpiresAt: now.Add(u.cfg.VerificationTTL).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sign verification token: %w", err)
+	}
+
+	if err := u.userRepo.MarkVerificationSent(ctx, user.ID, now.Add(-u.cfg.ResendInterval)); err != nil {
+		return fmt.Errorf("failed to register verification email: %w", err)
+	}
+
+	link := u.cfg.VerificationURL + "?token=" + url.QueryEscape(token)
+	err = u.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verifica tu email en AIEP Agent",
+		Body: fmt.Sprintf("Hola %s:\n\nPara confirmar tu email abre este enlace:\n\n%s\n\n"+
+			"El enlace vence en %s. Si no creaste esta cuenta, ignora este mensaje.\n",
+			user.UserName, link, formatTTL(u.cfg.VerificationTTL)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	u.logger.InfoContext(ctx, "Verification email sent", "user_id", user.ID)
+	return nil
+}
+
+// formatTTL describe la vigencia en horas para el cuerpo del correo.
+func formatTTL(d time.Duration) string {
+	hours := int(d.Hours())
+	if hours == 1 {
+		return "1 hora"
+	}
+	return fmt.Sprintf("%d horas", hours)
+}